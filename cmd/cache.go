@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/blontic/awsc/internal/aws"
+	"github.com/spf13/cobra"
+)
+
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "ElastiCache connections",
+	Long:  `Connect to ElastiCache Redis, Valkey and Memcached clusters via EC2 bastion hosts using SSM port forwarding`,
+}
+
+var cacheConnectCmd = &cobra.Command{
+	Use:   "connect",
+	Short: "Connect to an ElastiCache cluster via bastion host",
+	Long:  `List ElastiCache replication groups and cache clusters, find suitable bastion hosts, and establish SSM port forwarding connection`,
+	Run:   runCacheConnect,
+}
+
+var cacheLocalPort int
+var cacheName string
+var cacheSwitchAccount bool
+
+func init() {
+	rootCmd.AddCommand(cacheCmd)
+	cacheCmd.AddCommand(cacheConnectCmd)
+	cacheConnectCmd.Flags().IntVar(&cacheLocalPort, "local-port", 0, "Local port for port forwarding (defaults to the cache port)")
+	cacheConnectCmd.Flags().StringVar(&cacheName, "name", "", "Name of the ElastiCache endpoint to connect to directly")
+	cacheConnectCmd.Flags().BoolVarP(&cacheSwitchAccount, "switch-account", "s", false, "Switch AWS account before connecting")
+}
+
+func runCacheConnect(cmd *cobra.Command, args []string) {
+	ctx := context.Background()
+
+	// Track if we just authenticated (to avoid double-login with -s flag)
+	justAuthenticated := false
+
+	// Create cache manager
+	cacheManager, err := aws.NewCacheManager(ctx)
+	if err != nil {
+		// Check if this is a "no active session" error
+		if aws.IsAuthError(err) {
+			shouldReauth, reAuthErr := aws.PromptForReauth(ctx)
+			if reAuthErr != nil {
+				fmt.Printf("Error during re-authentication: %v\n", reAuthErr)
+				os.Exit(1)
+			}
+			if !shouldReauth {
+				fmt.Printf("Authentication cancelled\n")
+				os.Exit(1)
+			}
+			justAuthenticated = true
+			// Retry creating manager after successful login
+			cacheManager, err = aws.NewCacheManager(ctx)
+			if err != nil {
+				fmt.Printf("Error creating ElastiCache manager after re-authentication: %v\n", err)
+				os.Exit(1)
+			}
+		} else {
+			fmt.Printf("Error creating ElastiCache manager: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	// Handle account switching if requested (skip if we just authenticated)
+	if cacheSwitchAccount && !justAuthenticated {
+		if err := handleAccountSwitch(ctx); err != nil {
+			fmt.Printf("%v\n", err)
+			os.Exit(1)
+		}
+		// Recreate cache manager with new credentials
+		cacheManager, err = aws.NewCacheManager(ctx)
+		if err != nil {
+			fmt.Printf("Error creating ElastiCache manager after account switch: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	// Run the ElastiCache connect workflow
+	if err := cacheManager.RunConnect(ctx, cacheName, int32(cacheLocalPort)); err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+}
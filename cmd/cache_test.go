@@ -0,0 +1,25 @@
+package cmd
+
+import (
+	"testing"
+)
+
+func TestCacheCommand(t *testing.T) {
+	if cacheCmd.Use != "cache" {
+		t.Errorf("Expected cache command use to be 'cache', got %s", cacheCmd.Use)
+	}
+
+	if cacheConnectCmd.Use != "connect" {
+		t.Errorf("Expected connect subcommand use to be 'connect', got %s", cacheConnectCmd.Use)
+	}
+
+	if cacheConnectCmd.Run == nil {
+		t.Error("cacheConnectCmd should have Run function")
+	}
+
+	for _, name := range []string{"name", "local-port", "switch-account"} {
+		if cacheConnectCmd.Flags().Lookup(name) == nil {
+			t.Errorf("cacheConnectCmd should have --%s flag", name)
+		}
+	}
+}
@@ -0,0 +1,35 @@
+package cmd
+
+import (
+	"github.com/blontic/awsc/internal/aws"
+	"github.com/spf13/cobra"
+)
+
+var docdbCmd = &cobra.Command{
+	Use:   "docdb",
+	Short: "DocumentDB cluster connections",
+	Long:  `Connect to DocumentDB clusters via EC2 bastion hosts using SSM port forwarding`,
+}
+
+var docdbConnectCmd = &cobra.Command{
+	Use:   "connect",
+	Short: "Connect to a DocumentDB cluster via bastion host",
+	Long:  `List DocumentDB clusters, find suitable bastion hosts, and establish SSM port forwarding connection`,
+	Run:   runDocDBConnect,
+}
+
+var docdbLocalPort int
+var docdbName string
+var docdbSwitchAccount bool
+
+func init() {
+	rootCmd.AddCommand(docdbCmd)
+	docdbCmd.AddCommand(docdbConnectCmd)
+	docdbConnectCmd.Flags().IntVar(&docdbLocalPort, "local-port", 0, "Local port for port forwarding (defaults to the cluster port, 27017)")
+	docdbConnectCmd.Flags().StringVar(&docdbName, "name", "", "Name of the DocumentDB cluster endpoint to connect to directly")
+	docdbConnectCmd.Flags().BoolVarP(&docdbSwitchAccount, "switch-account", "s", false, "Switch AWS account before connecting")
+}
+
+func runDocDBConnect(cmd *cobra.Command, args []string) {
+	runRDSEngineConnect(aws.DocumentDBEngine, docdbName, docdbLocalPort, docdbSwitchAccount)
+}
@@ -4,6 +4,9 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"runtime"
+	"strconv"
+	"strings"
 
 	"github.com/blontic/awsc/internal/aws"
 	"github.com/spf13/cobra"
@@ -29,23 +32,208 @@ var ec2RdpCmd = &cobra.Command{
 	Run:   runEC2RDP,
 }
 
+var ec2RunCmd = &cobra.Command{
+	Use:   "run [flags] -- <command>",
+	Short: "Run a command on EC2 instances via SSM Run Command",
+	Long: `Run a shell command non-interactively on one or more instances using SSM Run Command.
+Linux instances use AWS-RunShellScript and Windows instances AWS-RunPowerShellScript.
+Output is printed per instance with a [name instance-id] prefix; the exit code is non-zero if any instance failed.`,
+	Args: cobra.MinimumNArgs(1),
+	Run:  runEC2Run,
+}
+
+var ec2CpCmd = &cobra.Command{
+	Use:   "cp <source> <destination>",
+	Short: "Copy a file to or from an EC2 instance via SSM",
+	Long: `Copy a single file between this machine and a Linux instance using SSM Run Command.
+One side must be an instance path like i-0123456789abcdef0:/tmp/file. The file is sent in
+chunks and verified with a SHA-256 checksum; no S3 bucket or inbound ports are needed.
+Downloads are limited to 8 MiB.
+
+Don't copy secrets this way: uploaded content is stored in the SSM command history and
+in CloudTrail SendCommand events, and downloaded content in the command history.`,
+	Args: cobra.ExactArgs(2),
+	Run:  runEC2Cp,
+}
+
+var ec2SSHProxyCmd = &cobra.Command{
+	Use:   "ssh-proxy <host> <port>",
+	Short: "Tunnel SSH to an EC2 instance over SSM (for ProxyCommand)",
+	Long: `Connect stdin/stdout to the SSH port of an instance through an AWS-StartSSHSession session.
+Use it as an OpenSSH ProxyCommand: ProxyCommand awsc ec2 ssh-proxy %h %p
+The host can be an instance ID or a Name tag. With --push-key the public key is sent
+with EC2 Instance Connect first, so the instance accepts it for 60 seconds.`,
+	Args: cobra.ExactArgs(2),
+	Run:  runEC2SSHProxy,
+}
+
+var ec2SSHConfigCmd = &cobra.Command{
+	Use:   "ssh-config",
+	Short: "Print ssh_config Host blocks for SSM-managed EC2 instances",
+	Long: `Print an OpenSSH Host block for every running instance with the SSM agent, using
+'awsc ec2 ssh-proxy' as the ProxyCommand pinned to the current profile and region.
+Save the output and Include it from ~/.ssh/config, e.g.
+  awsc ec2 ssh-config --user ec2-user > ~/.ssh/config.d/awsc-prod`,
+	Run: runEC2SSHConfig,
+}
+
+var ec2ForwardCmd = &cobra.Command{
+	Use:   "forward",
+	Short: "Forward local ports to ports on an EC2 instance",
+	Long: `Forward local ports to ports on the instance itself, such as admin UIs or debuggers,
+over SSM or through the VPC's EC2 Instance Connect Endpoint. Use --remote-port (and
+--local-port) for one port, or repeat -p local:remote for several.`,
+	Run: runEC2Forward,
+}
+
+var ec2StartCmd = newEC2InstanceActionCmd(aws.InstanceActionStart, "Start a stopped EC2 instance",
+	`Start a stopped instance, selecting it from a list unless --instance-id is given.
+With --wait, waits until the instance is running and its SSM agent is online.`)
+
+var ec2StopCmd = newEC2InstanceActionCmd(aws.InstanceActionStop, "Stop a running EC2 instance",
+	`Stop a running instance, selecting it from a list unless --instance-id is given.
+With --wait, waits until the instance is stopped.`)
+
+var ec2RebootCmd = newEC2InstanceActionCmd(aws.InstanceActionReboot, "Reboot a running EC2 instance",
+	`Reboot a running instance, selecting it from a list unless --instance-id is given.
+With --wait, waits until its SSM agent is back online.`)
+
+func newEC2InstanceActionCmd(action aws.InstanceAction, short, long string) *cobra.Command {
+	return &cobra.Command{
+		Use:   string(action),
+		Short: short,
+		Long:  long,
+		Run: func(cmd *cobra.Command, args []string) {
+			runEC2InstanceAction(cmd, action)
+		},
+	}
+}
+
 var instanceId string
+var ec2SSHPushKey string
+var ec2SSHOSUser string
+var ec2SSHConfigPrefix string
+var ec2SSHConfigUser string
+var ec2RunInstanceIds []string
+var ec2RunTags []string
 var rdpLocalPort int32
+var rdpOpen bool
+var rdpPassword bool
+var rdpKeyPath string
+var rdpPrintPassword bool
 var ec2SwitchAccount bool
+var ec2ActionWait bool
+var ec2ActionYes bool
+var ec2Record bool
+var ec2ForwardRemotePort int
+var ec2Filters []string
+var ec2FilterVpc string
+var ec2FilterAZ string
+var ec2FilterStates []string
+var ec2FilterName string
+var ec2TagColumns []string
+var ec2ConnectASG string
+var ec2ConnectRandom bool
+var ec2ConnectTags []string
+var ec2ConnectTmux bool
+var ec2ConnectSync bool
+var ec2ForwardLocalPort int
+var ec2ForwardPorts []string
+var ec2SessionDocument string
+var ec2SessionParameters []string
 
 func init() {
 	rootCmd.AddCommand(ec2Cmd)
 	ec2Cmd.AddCommand(ec2ConnectCmd)
 	ec2Cmd.AddCommand(ec2RdpCmd)
+	ec2Cmd.AddCommand(ec2RunCmd)
+	ec2Cmd.AddCommand(ec2CpCmd)
+	ec2Cmd.AddCommand(ec2SSHProxyCmd)
+	ec2Cmd.AddCommand(ec2SSHConfigCmd)
+	ec2Cmd.AddCommand(ec2ForwardCmd)
+	ec2Cmd.AddCommand(ec2StartCmd)
+	ec2Cmd.AddCommand(ec2StopCmd)
+	ec2Cmd.AddCommand(ec2RebootCmd)
 
 	// Add instance-id flag to both commands
 	ec2ConnectCmd.Flags().StringVar(&instanceId, "instance-id", "", "EC2 instance ID to connect to (optional)")
 	ec2RdpCmd.Flags().StringVar(&instanceId, "instance-id", "", "EC2 instance ID to connect to (optional)")
+	ec2RunCmd.Flags().StringArrayVar(&ec2RunInstanceIds, "instance-id", nil, "EC2 instance ID to run the command on (repeatable)")
+	ec2RunCmd.Flags().StringArrayVar(&ec2RunTags, "tag", nil, "Run on running instances with this Key=Value tag (repeatable, all must match)")
 	ec2RdpCmd.Flags().Int32Var(&rdpLocalPort, "local-port", 3389, "Local port for RDP forwarding (default: 3389)")
+	ec2RdpCmd.Flags().BoolVar(&rdpOpen, "open", false, "Launch an RDP client once the forward is listening")
+	ec2RdpCmd.Flags().BoolVar(&rdpPassword, "password", false, "Decrypt the Administrator password and copy it to the clipboard (requires --key)")
+	ec2RdpCmd.Flags().StringVar(&rdpKeyPath, "key", "", "Private key of the instance's key pair, for --password")
+	ec2RdpCmd.Flags().BoolVar(&rdpPrintPassword, "print-password", false, "Print the password when it can't be copied to the clipboard, for --password")
 
 	// Add switch-account flag to both commands
 	ec2ConnectCmd.Flags().BoolVarP(&ec2SwitchAccount, "switch-account", "s", false, "Switch AWS account before connecting")
 	ec2RdpCmd.Flags().BoolVarP(&ec2SwitchAccount, "switch-account", "s", false, "Switch AWS account before connecting")
+	ec2RunCmd.Flags().BoolVarP(&ec2SwitchAccount, "switch-account", "s", false, "Switch AWS account before running the command")
+	ec2CpCmd.Flags().BoolVarP(&ec2SwitchAccount, "switch-account", "s", false, "Switch AWS account before copying")
+	ec2SSHConfigCmd.Flags().BoolVarP(&ec2SwitchAccount, "switch-account", "s", false, "Switch AWS account before listing instances")
+
+	ec2ForwardCmd.Flags().StringVar(&instanceId, "instance-id", "", "EC2 instance ID to forward to (optional)")
+	ec2ForwardCmd.Flags().IntVar(&ec2ForwardRemotePort, "remote-port", 0, "Port on the instance to forward to")
+	ec2ForwardCmd.Flags().IntVar(&ec2ForwardLocalPort, "local-port", 0, "Local port for --remote-port (defaults to the remote port)")
+	ec2ForwardCmd.Flags().StringArrayVarP(&ec2ForwardPorts, "port", "p", nil, "Additional forward as local:remote, or a single port for both (repeatable)")
+	ec2ForwardCmd.Flags().BoolVarP(&ec2SwitchAccount, "switch-account", "s", false, "Switch AWS account before connecting")
+
+	for _, listCmd := range []*cobra.Command{ec2ConnectCmd, ec2RdpCmd, ec2ForwardCmd, ec2SSHConfigCmd, ec2StartCmd, ec2StopCmd, ec2RebootCmd} {
+		addEC2ListFlags(listCmd)
+	}
+	ec2ConnectCmd.Flags().StringVar(&ec2ConnectASG, "asg", "", "Connect to a healthy member of this Auto Scaling group")
+	ec2ConnectCmd.Flags().BoolVar(&ec2ConnectRandom, "random", false, "With --asg, pick a random healthy member instead of the first")
+	ec2ConnectCmd.Flags().StringArrayVar(&ec2ConnectTags, "tag", nil, "With --tmux, connect to running instances with this Key=Value tag (repeatable, all must match)")
+	ec2ConnectCmd.Flags().BoolVar(&ec2ConnectTmux, "tmux", false, "Open a tmux window with a session to every instance matching --tag")
+	ec2ConnectCmd.Flags().BoolVar(&ec2ConnectSync, "sync", false, "With --tmux, send what you type to every pane")
+
+	for _, actionCmd := range []*cobra.Command{ec2StartCmd, ec2StopCmd, ec2RebootCmd} {
+		actionCmd.Flags().StringVar(&instanceId, "instance-id", "", "EC2 instance ID (optional)")
+		actionCmd.Flags().BoolVar(&ec2ActionWait, "wait", false, "Wait for the instance to finish changing state")
+		actionCmd.Flags().BoolVarP(&ec2ActionYes, "yes", "y", false, "Skip the confirmation prompt")
+		actionCmd.Flags().BoolVarP(&ec2SwitchAccount, "switch-account", "s", false, "Switch AWS account first")
+	}
+
+	ec2ConnectCmd.Flags().StringVar(&ec2SSHOSUser, "user", "", "User to run the session as, or the SSH user through an EC2 Instance Connect Endpoint (default: ec2-user)")
+	ec2ConnectCmd.Flags().StringVar(&ec2SessionDocument, "document", "", "Session document to start instead of the default shell, e.g. AWS-StartInteractiveCommand")
+	ec2ConnectCmd.Flags().StringArrayVar(&ec2SessionParameters, "parameter", nil, "Session document parameter as key=value (repeatable)")
+	ec2ConnectCmd.Flags().StringVar(&ec2SSHPushKey, "push-key", "", "Public key to push with EC2 Instance Connect for connections through an endpoint")
+	ec2ConnectCmd.Flags().BoolVar(&ec2Record, "record", false, "Record the session to ~/.awsc/recordings (replay with 'awsc recordings play')")
+	ec2SSHProxyCmd.Flags().StringVar(&ec2SSHPushKey, "push-key", "", "Public key to push with EC2 Instance Connect before connecting")
+	ec2SSHProxyCmd.Flags().StringVar(&ec2SSHOSUser, "os-user", "", "OS user the pushed key is valid for (use %r in ProxyCommand)")
+	ec2SSHConfigCmd.Flags().StringVar(&ec2SSHConfigPrefix, "prefix", "", "Prefix for every Host alias")
+	ec2SSHConfigCmd.Flags().StringVar(&ec2SSHConfigUser, "user", "", "User to set in every Host block")
+	ec2SSHConfigCmd.Flags().StringVar(&ec2SSHPushKey, "push-key", "", "Public key the ProxyCommand pushes with EC2 Instance Connect")
+}
+
+// addEC2ListFlags adds the instance filter and tag column flags to a command that lists instances
+func addEC2ListFlags(cmd *cobra.Command) {
+	cmd.Flags().StringArrayVar(&ec2Filters, "filter", nil, "DescribeInstances filter as Name=Value[,Value], e.g. tag:Env=prod (repeatable)")
+	cmd.Flags().StringVar(&ec2FilterVpc, "vpc", "", "Only list instances in this VPC")
+	cmd.Flags().StringVar(&ec2FilterAZ, "az", "", "Only list instances in this availability zone")
+	cmd.Flags().StringSliceVar(&ec2FilterStates, "state", nil, "Only list instances in these states, e.g. running,stopped")
+	cmd.Flags().StringVar(&ec2FilterName, "name", "", "Only list instances whose Name tag matches, * and ? are wildcards")
+	cmd.Flags().StringSliceVar(&ec2TagColumns, "tag-columns", nil, "Tags to show for each instance, e.g. Env,Role")
+}
+
+// applyEC2ListFlags sets the instance filters and tag columns from the flags, or from
+// the ec2.lists config when none are given
+func applyEC2ListFlags(ec2Manager *aws.EC2Manager) {
+	opts := aws.InstanceListOptions{
+		Filter: aws.InstanceFilter{
+			Filters:          ec2Filters,
+			VpcId:            ec2FilterVpc,
+			AvailabilityZone: ec2FilterAZ,
+			States:           ec2FilterStates,
+			Name:             ec2FilterName,
+		},
+		TagColumns: ec2TagColumns,
+	}
+	if err := ec2Manager.SetListOptions(opts); err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
 }
 
 func createEC2Manager() (*aws.EC2Manager, error) {
@@ -53,9 +241,69 @@ func createEC2Manager() (*aws.EC2Manager, error) {
 	return aws.NewEC2Manager(ctx)
 }
 
+// validateEC2ConnectTarget checks that at most one way of picking the instance is given
+func validateEC2ConnectTarget(instanceId, name, group string, random bool) error {
+	given := 0
+	for _, value := range []string{instanceId, name, group} {
+		if value != "" {
+			given++
+		}
+	}
+	if given > 1 {
+		return fmt.Errorf("use only one of --instance-id, --name and --asg")
+	}
+	if random && group == "" {
+		return fmt.Errorf("--random requires --asg")
+	}
+	return nil
+}
+
+// validateEC2ConnectTmux checks that --tag and --sync come with --tmux, which opens
+// sessions to every tagged instance instead of picking one
+func validateEC2ConnectTmux(useTmux, sync bool, tags []string, instanceId, name, group string) error {
+	if !useTmux {
+		if len(tags) > 0 {
+			return fmt.Errorf("--tag requires --tmux")
+		}
+		if sync {
+			return fmt.Errorf("--sync requires --tmux")
+		}
+		return nil
+	}
+	if len(tags) == 0 {
+		return fmt.Errorf("--tmux requires at least one --tag")
+	}
+	if instanceId != "" || name != "" || group != "" {
+		return fmt.Errorf("--tmux can't be combined with --instance-id, --name or --asg")
+	}
+	return nil
+}
+
+// validateEC2ConnectRecord rejects --record where sessions can't be recorded, before the
+// recording file is created or a session is started
+func validateEC2ConnectRecord(record bool, goos string) error {
+	if record && goos == "windows" {
+		return fmt.Errorf("session recording is not supported on Windows")
+	}
+	return nil
+}
+
 func runEC2Connect(cmd *cobra.Command, args []string) {
 	ctx := context.Background()
 
+	if err := validateEC2ConnectTarget(instanceId, ec2FilterName, ec2ConnectASG, ec2ConnectRandom); err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+	if err := validateEC2ConnectTmux(ec2ConnectTmux, ec2ConnectSync, ec2ConnectTags, instanceId, ec2FilterName, ec2ConnectASG); err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+	if err := validateEC2ConnectRecord(ec2Record, runtime.GOOS); err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+
 	// Track if we just authenticated (to avoid double-login with -s flag)
 	justAuthenticated := false
 
@@ -101,8 +349,43 @@ func runEC2Connect(cmd *cobra.Command, args []string) {
 
 	// Get instance-id flag value
 	instanceIdFlag, _ := cmd.Flags().GetString("instance-id")
+	userFlag, _ := cmd.Flags().GetString("user")
+	pushKeyFlag, _ := cmd.Flags().GetString("push-key")
+
+	recordFlag, _ := cmd.Flags().GetBool("record")
+	nameFlag, _ := cmd.Flags().GetString("name")
+	asgFlag, _ := cmd.Flags().GetString("asg")
+	randomFlag, _ := cmd.Flags().GetBool("random")
+	documentFlag, _ := cmd.Flags().GetString("document")
+	parameterFlags, _ := cmd.Flags().GetStringArray("parameter")
 
-	if err := ec2Manager.RunConnect(ctx, instanceIdFlag); err != nil {
+	parameters, err := aws.ParseSessionParameters(parameterFlags)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	opts := aws.ConnectOptions{
+		Key:        aws.SSHKeyOptions{PublicKeyPath: pushKeyFlag},
+		Record:     recordFlag,
+		Document:   documentFlag,
+		Parameters: parameters,
+		User:       userFlag,
+		Name:       nameFlag,
+		Group:      asgFlag,
+		Random:     randomFlag,
+	}
+	// --name also narrows the listing on the EC2 side before the match is resolved
+	applyEC2ListFlags(ec2Manager)
+	if ec2ConnectTmux {
+		tmuxOpts := aws.TmuxOptions{Tags: ec2ConnectTags, Sync: ec2ConnectSync, Connect: opts}
+		if err := ec2Manager.RunTmux(ctx, tmuxOpts); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+	if err := ec2Manager.RunConnect(ctx, instanceIdFlag, opts); err != nil {
 		fmt.Printf("Error: %v\n", err)
 		os.Exit(1)
 	}
@@ -157,9 +440,220 @@ func runEC2RDP(cmd *cobra.Command, args []string) {
 	// Get flag values
 	instanceIdFlag, _ := cmd.Flags().GetString("instance-id")
 	localPortFlag, _ := cmd.Flags().GetInt32("local-port")
+	openFlag, _ := cmd.Flags().GetBool("open")
+	passwordFlag, _ := cmd.Flags().GetBool("password")
+	keyFlag, _ := cmd.Flags().GetString("key")
+	printPasswordFlag, _ := cmd.Flags().GetBool("print-password")
+
+	if passwordFlag && keyFlag == "" {
+		fmt.Printf("Error: --password requires --key with the instance's private key\n")
+		os.Exit(1)
+	}
+
+	opts := aws.RDPOptions{Open: openFlag}
+	if passwordFlag {
+		opts.KeyPath = keyFlag
+		opts.PrintPassword = printPasswordFlag
+	}
+
+	applyEC2ListFlags(ec2Manager)
+	if err := ec2Manager.RunRDP(ctx, instanceIdFlag, localPortFlag, opts); err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func runEC2Run(cmd *cobra.Command, args []string) {
+	ctx := context.Background()
+
+	ec2Manager := createEC2ManagerWithAuth(ctx)
+
+	targets := aws.RunTargets{
+		InstanceIds: ec2RunInstanceIds,
+		Tags:        ec2RunTags,
+	}
+
+	if err := ec2Manager.RunCommand(ctx, targets, strings.Join(args, " "), os.Stdout, os.Stderr); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func runEC2Cp(cmd *cobra.Command, args []string) {
+	ctx := context.Background()
+
+	ec2Manager := createEC2ManagerWithAuth(ctx)
+
+	if err := ec2Manager.RunCopy(ctx, args[0], args[1]); err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func runEC2SSHProxy(cmd *cobra.Command, args []string) {
+	ctx := context.Background()
+
+	port, err := strconv.Atoi(args[1])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: invalid port %q\n", args[1])
+		os.Exit(1)
+	}
+
+	// ssh runs this non-interactively with stdout as the connection, so don't prompt for login
+	ec2Manager, err := createEC2Manager()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error creating EC2 manager: %v\n", err)
+		if aws.IsAuthError(err) {
+			fmt.Fprintf(os.Stderr, "Run 'awsc login' and try again\n")
+		}
+		os.Exit(1)
+	}
+	ec2Manager.SetNonInteractive()
+
+	key := aws.SSHKeyOptions{
+		PublicKeyPath: ec2SSHPushKey,
+		OSUser:        ec2SSHOSUser,
+	}
+
+	if err := ec2Manager.RunSSHProxy(ctx, args[0], port, key); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		if aws.IsAuthError(err) {
+			fmt.Fprintf(os.Stderr, "Run 'awsc login' and try again\n")
+		}
+		os.Exit(1)
+	}
+}
+
+func runEC2SSHConfig(cmd *cobra.Command, args []string) {
+	ctx := context.Background()
+
+	ec2Manager := createEC2ManagerWithAuth(ctx)
+
+	opts := aws.SSHConfigOptions{
+		Prefix:        ec2SSHConfigPrefix,
+		User:          ec2SSHConfigUser,
+		PublicKeyPath: ec2SSHPushKey,
+	}
+
+	applyEC2ListFlags(ec2Manager)
+	if err := ec2Manager.WriteSSHConfig(ctx, os.Stdout, opts); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func runEC2InstanceAction(cmd *cobra.Command, action aws.InstanceAction) {
+	ctx := context.Background()
+
+	ec2Manager := createEC2ManagerWithAuth(ctx)
 
-	if err := ec2Manager.RunRDP(ctx, instanceIdFlag, localPortFlag); err != nil {
+	instanceIdFlag, _ := cmd.Flags().GetString("instance-id")
+	waitFlag, _ := cmd.Flags().GetBool("wait")
+	yesFlag, _ := cmd.Flags().GetBool("yes")
+
+	applyEC2ListFlags(ec2Manager)
+	if err := ec2Manager.RunInstanceAction(ctx, action, instanceIdFlag, aws.InstanceActionOptions{Wait: waitFlag, Yes: yesFlag}); err != nil {
 		fmt.Printf("Error: %v\n", err)
 		os.Exit(1)
 	}
 }
+
+// buildEC2ForwardSpecs combines --remote-port/--local-port with any -p pairs
+func buildEC2ForwardSpecs(remotePort, localPort int, pairs []string) ([]aws.ForwardSpec, error) {
+	var result []aws.ForwardSpec
+
+	if remotePort != 0 {
+		if localPort == 0 {
+			localPort = remotePort
+		}
+		spec, err := aws.ParsePortPair(fmt.Sprintf("%d:%d", localPort, remotePort))
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, spec)
+	} else if localPort != 0 {
+		return nil, fmt.Errorf("--local-port requires --remote-port")
+	}
+
+	for _, pair := range pairs {
+		spec, err := aws.ParsePortPair(pair)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, spec)
+	}
+
+	if len(result) == 0 {
+		return nil, fmt.Errorf("specify --remote-port or at least one -p local:remote")
+	}
+
+	return result, nil
+}
+
+func runEC2Forward(cmd *cobra.Command, args []string) {
+	ctx := context.Background()
+
+	specs, err := buildEC2ForwardSpecs(ec2ForwardRemotePort, ec2ForwardLocalPort, ec2ForwardPorts)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	ec2Manager := createEC2ManagerWithAuth(ctx)
+
+	instanceIdFlag, _ := cmd.Flags().GetString("instance-id")
+	applyEC2ListFlags(ec2Manager)
+	if err := ec2Manager.RunForward(ctx, instanceIdFlag, specs); err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// createEC2ManagerWithAuth creates the EC2 manager, prompting for login and
+// switching accounts first when requested
+func createEC2ManagerWithAuth(ctx context.Context) *aws.EC2Manager {
+	// Track if we just authenticated (to avoid double-login with -s flag)
+	justAuthenticated := false
+
+	ec2Manager, err := createEC2Manager()
+	if err != nil {
+		// Check if this is a "no active session" error
+		if aws.IsAuthError(err) {
+			shouldReauth, reAuthErr := aws.PromptForReauth(ctx)
+			if reAuthErr != nil {
+				fmt.Printf("Error during re-authentication: %v\n", reAuthErr)
+				os.Exit(1)
+			}
+			if !shouldReauth {
+				fmt.Printf("Authentication cancelled\n")
+				os.Exit(1)
+			}
+			justAuthenticated = true
+			// Retry creating manager after successful login
+			ec2Manager, err = createEC2Manager()
+			if err != nil {
+				fmt.Printf("Error creating EC2 manager after re-authentication: %v\n", err)
+				os.Exit(1)
+			}
+		} else {
+			fmt.Printf("Error creating EC2 manager: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	// Handle account switching if requested (skip if we just authenticated)
+	if ec2SwitchAccount && !justAuthenticated {
+		if err := handleAccountSwitch(ctx); err != nil {
+			fmt.Printf("%v\n", err)
+			os.Exit(1)
+		}
+		// Recreate manager with new credentials
+		ec2Manager, err = createEC2Manager()
+		if err != nil {
+			fmt.Printf("Error creating EC2 manager after account switch: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	return ec2Manager
+}
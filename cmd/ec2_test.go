@@ -2,6 +2,8 @@ package cmd
 
 import (
 	"testing"
+
+	"github.com/spf13/cobra"
 )
 
 func TestEC2Commands(t *testing.T) {
@@ -167,4 +169,246 @@ func TestEC2FlagsStillPresent(t *testing.T) {
 	if localPortFlag == nil {
 		t.Error("--local-port flag should still be defined for EC2 RDP command")
 	}
+
+	for _, name := range []string{"open", "password", "key"} {
+		if ec2RdpCmd.Flags().Lookup(name) == nil {
+			t.Errorf("--%s flag should be defined for EC2 RDP command", name)
+		}
+	}
+}
+
+func TestEC2ConnectInstanceConnectFlags(t *testing.T) {
+	for _, name := range []string{"user", "push-key"} {
+		flag := ec2ConnectCmd.Flags().Lookup(name)
+		if flag == nil {
+			t.Errorf("--%s flag should be defined for EC2 connect command", name)
+			continue
+		}
+		if flag.DefValue != "" {
+			t.Errorf("Expected --%s default to be empty, got '%s'", name, flag.DefValue)
+		}
+	}
+}
+
+func TestEC2RunCommand(t *testing.T) {
+	if ec2RunCmd.Run == nil {
+		t.Error("ec2RunCmd should have a Run function")
+	}
+
+	if err := ec2RunCmd.Args(ec2RunCmd, []string{}); err == nil {
+		t.Error("ec2 run should require a command")
+	}
+
+	for _, name := range []string{"instance-id", "tag", "switch-account"} {
+		if ec2RunCmd.Flags().Lookup(name) == nil {
+			t.Errorf("ec2RunCmd should have --%s flag", name)
+		}
+	}
+}
+
+func TestEC2CpCommand(t *testing.T) {
+	if ec2CpCmd.Run == nil {
+		t.Error("ec2CpCmd should have a Run function")
+	}
+
+	if err := ec2CpCmd.Args(ec2CpCmd, []string{"./local"}); err == nil {
+		t.Error("ec2 cp should require a source and destination")
+	}
+
+	if ec2CpCmd.Flags().Lookup("switch-account") == nil {
+		t.Error("ec2CpCmd should have --switch-account flag")
+	}
+}
+
+func TestEC2SSHCommands(t *testing.T) {
+	if err := ec2SSHProxyCmd.Args(ec2SSHProxyCmd, []string{"i-123"}); err == nil {
+		t.Error("ec2 ssh-proxy should require a host and port")
+	}
+
+	for _, name := range []string{"push-key", "os-user"} {
+		if ec2SSHProxyCmd.Flags().Lookup(name) == nil {
+			t.Errorf("ec2SSHProxyCmd should have --%s flag", name)
+		}
+	}
+
+	for _, name := range []string{"prefix", "user", "push-key", "switch-account"} {
+		if ec2SSHConfigCmd.Flags().Lookup(name) == nil {
+			t.Errorf("ec2SSHConfigCmd should have --%s flag", name)
+		}
+	}
+}
+
+func TestEC2InstanceActionCommands(t *testing.T) {
+	for use, command := range map[string]*cobra.Command{"start": ec2StartCmd, "stop": ec2StopCmd, "reboot": ec2RebootCmd} {
+		if command.Use != use {
+			t.Errorf("Expected Use '%s', got '%s'", use, command.Use)
+		}
+		if command.Run == nil {
+			t.Errorf("ec2 %s should have a Run function", use)
+		}
+		for _, name := range []string{"instance-id", "wait", "yes", "switch-account"} {
+			if command.Flags().Lookup(name) == nil {
+				t.Errorf("ec2 %s should have --%s flag", use, name)
+			}
+		}
+	}
+}
+
+func TestEC2ConnectRecordFlag(t *testing.T) {
+	flag := ec2ConnectCmd.Flags().Lookup("record")
+	if flag == nil {
+		t.Fatal("--record flag should be defined for EC2 connect command")
+	}
+	if flag.DefValue != "false" {
+		t.Errorf("Expected --record to default to false, got %s", flag.DefValue)
+	}
+}
+
+func TestEC2ConnectSessionDocumentFlags(t *testing.T) {
+	if flag := ec2ConnectCmd.Flags().Lookup("document"); flag == nil {
+		t.Error("--document flag should be defined for EC2 connect command")
+	}
+
+	flag := ec2ConnectCmd.Flags().Lookup("parameter")
+	if flag == nil {
+		t.Fatal("--parameter flag should be defined for EC2 connect command")
+	}
+	if flag.Value.Type() != "stringArray" {
+		t.Errorf("Expected --parameter to be repeatable, got type %s", flag.Value.Type())
+	}
+}
+
+func TestEC2ForwardCommand(t *testing.T) {
+	if ec2ForwardCmd.Run == nil {
+		t.Error("ec2ForwardCmd should have Run function")
+	}
+
+	for _, name := range []string{"instance-id", "remote-port", "local-port", "port", "switch-account"} {
+		if ec2ForwardCmd.Flags().Lookup(name) == nil {
+			t.Errorf("ec2ForwardCmd should have --%s flag", name)
+		}
+	}
+
+	if flag := ec2ForwardCmd.Flags().Lookup("port"); flag != nil && flag.Shorthand != "p" {
+		t.Errorf("Expected shorthand 'p' for port flag, got '%s'", flag.Shorthand)
+	}
+}
+
+func TestBuildEC2ForwardSpecs(t *testing.T) {
+	specs, err := buildEC2ForwardSpecs(8080, 0, []string{"15005:5005", "9090"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(specs) != 3 {
+		t.Fatalf("Expected 3 specs, got %d", len(specs))
+	}
+	if specs[0].LocalPort != 8080 || specs[0].RemotePort != 8080 || specs[0].RemoteHost != "localhost" {
+		t.Errorf("Expected the local port to default to the remote port, got %+v", specs[0])
+	}
+	if specs[1].LocalPort != 15005 || specs[1].RemotePort != 5005 {
+		t.Errorf("Unexpected -p spec: %+v", specs[1])
+	}
+
+	if specs, _ := buildEC2ForwardSpecs(8080, 18080, nil); len(specs) != 1 || specs[0].LocalPort != 18080 {
+		t.Errorf("Expected --local-port to set the local port, got %+v", specs)
+	}
+
+	if _, err := buildEC2ForwardSpecs(0, 0, nil); err == nil {
+		t.Error("Expected error when no ports are given")
+	}
+
+	if _, err := buildEC2ForwardSpecs(0, 8080, nil); err == nil {
+		t.Error("Expected error when --local-port is given without --remote-port")
+	}
+}
+
+func TestEC2ListFlags(t *testing.T) {
+	for _, listCmd := range []*cobra.Command{ec2ConnectCmd, ec2RdpCmd, ec2ForwardCmd, ec2SSHConfigCmd, ec2StartCmd, ec2StopCmd, ec2RebootCmd} {
+		for _, name := range []string{"filter", "vpc", "az", "state", "name", "tag-columns"} {
+			if listCmd.Flags().Lookup(name) == nil {
+				t.Errorf("%s should have --%s flag", listCmd.Name(), name)
+			}
+		}
+	}
+
+	if flag := ec2ConnectCmd.Flags().Lookup("filter"); flag != nil && flag.Value.Type() != "stringArray" {
+		t.Errorf("Expected --filter to be repeatable, got type %s", flag.Value.Type())
+	}
+}
+
+func TestEC2ConnectTargetFlags(t *testing.T) {
+	for _, name := range []string{"name", "asg", "random"} {
+		if ec2ConnectCmd.Flags().Lookup(name) == nil {
+			t.Errorf("--%s flag should be defined for EC2 connect command", name)
+		}
+	}
+
+	tests := []struct {
+		instanceId, name, group string
+		random                  bool
+		wantErr                 bool
+	}{
+		{"", "", "", false, false},
+		{"i-123", "", "", false, false},
+		{"", "web-*", "", false, false},
+		{"", "", "kafka", true, false},
+		{"i-123", "web-1", "", false, true},
+		{"", "web-1", "kafka", false, true},
+		{"", "web-1", "", true, true},
+	}
+	for _, tt := range tests {
+		err := validateEC2ConnectTarget(tt.instanceId, tt.name, tt.group, tt.random)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("validateEC2ConnectTarget(%q, %q, %q, %v) = %v, want error %v", tt.instanceId, tt.name, tt.group, tt.random, err, tt.wantErr)
+		}
+	}
+}
+
+func TestEC2ConnectTmuxFlags(t *testing.T) {
+	for _, name := range []string{"tag", "tmux", "sync"} {
+		if ec2ConnectCmd.Flags().Lookup(name) == nil {
+			t.Errorf("--%s flag should be defined for EC2 connect command", name)
+		}
+	}
+
+	tags := []string{"Role=kafka"}
+	tests := []struct {
+		useTmux, sync           bool
+		tags                    []string
+		instanceId, name, group string
+		wantErr                 bool
+	}{
+		{false, false, nil, "", "", "", false},
+		{true, true, tags, "", "", "", false},
+		{true, false, nil, "", "", "", true},
+		{false, false, tags, "", "", "", true},
+		{false, true, nil, "", "", "", true},
+		{true, false, tags, "i-123", "", "", true},
+		{true, false, tags, "", "", "kafka", true},
+	}
+	for _, tt := range tests {
+		err := validateEC2ConnectTmux(tt.useTmux, tt.sync, tt.tags, tt.instanceId, tt.name, tt.group)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("validateEC2ConnectTmux(%v, %v, %v, %q, %q, %q) = %v, want error %v", tt.useTmux, tt.sync, tt.tags, tt.instanceId, tt.name, tt.group, err, tt.wantErr)
+		}
+	}
+}
+
+func TestValidateEC2ConnectRecord(t *testing.T) {
+	tests := []struct {
+		record  bool
+		goos    string
+		wantErr bool
+	}{
+		{false, "windows", false},
+		{true, "linux", false},
+		{true, "darwin", false},
+		{true, "windows", true},
+	}
+	for _, tt := range tests {
+		err := validateEC2ConnectRecord(tt.record, tt.goos)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("validateEC2ConnectRecord(%v, %q) = %v, want error %v", tt.record, tt.goos, err, tt.wantErr)
+		}
+	}
 }
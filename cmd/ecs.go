@@ -0,0 +1,138 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/blontic/awsc/internal/aws"
+	"github.com/spf13/cobra"
+)
+
+var ecsCmd = &cobra.Command{
+	Use:   "ecs",
+	Short: "ECS container sessions",
+	Long:  `Open shells in and forward ports to ECS task containers using ECS Exec and Session Manager`,
+}
+
+var ecsExecCmd = &cobra.Command{
+	Use:   "exec",
+	Short: "Open an interactive shell in an ECS container",
+	Long: `Select a cluster, service, task and container and start an interactive ECS Exec session.
+Services and tasks without execute command enabled are shown but cannot be selected.`,
+	Run: runECSExec,
+}
+
+var ecsForwardCmd = &cobra.Command{
+	Use:   "forward",
+	Short: "Forward a local port to a port inside an ECS container",
+	Long:  `Select a cluster, service, task and container and forward a local port to a port inside the task using Session Manager`,
+	Run:   runECSForward,
+}
+
+var ecsCluster string
+var ecsService string
+var ecsTask string
+var ecsContainer string
+var ecsCommand string
+var ecsRemotePort int
+var ecsLocalPort int
+var ecsSwitchAccount bool
+
+func init() {
+	rootCmd.AddCommand(ecsCmd)
+	ecsCmd.AddCommand(ecsExecCmd)
+	ecsCmd.AddCommand(ecsForwardCmd)
+	for _, c := range []*cobra.Command{ecsExecCmd, ecsForwardCmd} {
+		c.Flags().StringVar(&ecsCluster, "cluster", "", "Name of the ECS cluster")
+		c.Flags().StringVar(&ecsService, "service", "", "Name of the ECS service")
+		c.Flags().StringVar(&ecsTask, "task", "", "ID of the ECS task; without --service this also reaches standalone tasks")
+		c.Flags().StringVar(&ecsContainer, "container", "", "Name of the container")
+		c.Flags().BoolVarP(&ecsSwitchAccount, "switch-account", "s", false, "Switch AWS account before connecting")
+	}
+	ecsExecCmd.Flags().StringVar(&ecsCommand, "command", "/bin/sh", "Command to run in the container")
+	ecsForwardCmd.Flags().IntVar(&ecsRemotePort, "remote-port", 0, "Port inside the container")
+	ecsForwardCmd.Flags().IntVar(&ecsLocalPort, "local-port", 0, "Local port to listen on (defaults to the remote port)")
+	ecsForwardCmd.MarkFlagRequired("remote-port")
+}
+
+func ecsSelector() aws.ECSTarget {
+	return aws.ECSTarget{
+		Cluster:   ecsCluster,
+		Service:   ecsService,
+		TaskId:    ecsTask,
+		Container: ecsContainer,
+	}
+}
+
+func runECSExec(cmd *cobra.Command, args []string) {
+	ctx := context.Background()
+
+	ecsManager := createECSManagerWithAuth(ctx)
+
+	if err := ecsManager.RunExec(ctx, ecsSelector(), ecsCommand); err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func runECSForward(cmd *cobra.Command, args []string) {
+	ctx := context.Background()
+
+	ecsManager := createECSManagerWithAuth(ctx)
+
+	if err := ecsManager.RunForward(ctx, ecsSelector(), ecsRemotePort, ecsLocalPort); err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// createECSManagerWithAuth creates the ECS manager, prompting for login and
+// switching account when requested
+func createECSManagerWithAuth(ctx context.Context) *aws.ECSManager {
+	// Track if we just authenticated (to avoid double-login with -s flag)
+	justAuthenticated := false
+
+	// Create ECS manager
+	ecsManager, err := aws.NewECSManager(ctx)
+	if err != nil {
+		// Check if this is a "no active session" error
+		if aws.IsAuthError(err) {
+			shouldReauth, reAuthErr := aws.PromptForReauth(ctx)
+			if reAuthErr != nil {
+				fmt.Printf("Error during re-authentication: %v\n", reAuthErr)
+				os.Exit(1)
+			}
+			if !shouldReauth {
+				fmt.Printf("Authentication cancelled\n")
+				os.Exit(1)
+			}
+			justAuthenticated = true
+			// Retry creating manager after successful login
+			ecsManager, err = aws.NewECSManager(ctx)
+			if err != nil {
+				fmt.Printf("Error creating ECS manager after re-authentication: %v\n", err)
+				os.Exit(1)
+			}
+		} else {
+			fmt.Printf("Error creating ECS manager: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	// Handle account switching if requested (skip if we just authenticated)
+	if ecsSwitchAccount && !justAuthenticated {
+		if err := handleAccountSwitch(ctx); err != nil {
+			fmt.Printf("%v\n", err)
+			os.Exit(1)
+		}
+		// Recreate ECS manager with new credentials
+		ecsManager, err = aws.NewECSManager(ctx)
+		if err != nil {
+			fmt.Printf("Error creating ECS manager after account switch: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	return ecsManager
+}
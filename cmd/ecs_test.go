@@ -0,0 +1,27 @@
+package cmd
+
+import (
+	"testing"
+)
+
+func TestECSCommand(t *testing.T) {
+	if ecsCmd.Use != "ecs" {
+		t.Errorf("Expected ecs command use to be 'ecs', got %s", ecsCmd.Use)
+	}
+
+	if ecsExecCmd.Run == nil || ecsForwardCmd.Run == nil {
+		t.Error("ecs subcommands should have Run functions")
+	}
+
+	for _, name := range []string{"cluster", "service", "task", "container", "command", "switch-account"} {
+		if ecsExecCmd.Flags().Lookup(name) == nil {
+			t.Errorf("ecsExecCmd should have --%s flag", name)
+		}
+	}
+
+	for _, name := range []string{"cluster", "service", "task", "container", "remote-port", "local-port", "switch-account"} {
+		if ecsForwardCmd.Flags().Lookup(name) == nil {
+			t.Errorf("ecsForwardCmd should have --%s flag", name)
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/blontic/awsc/internal/aws"
+	"github.com/spf13/cobra"
+)
+
+var eksCmd = &cobra.Command{
+	Use:   "eks",
+	Short: "EKS cluster connections",
+	Long:  `Connect kubectl to EKS clusters with private API endpoints via EC2 bastion hosts using SSM port forwarding`,
+}
+
+var eksConnectCmd = &cobra.Command{
+	Use:   "connect",
+	Short: "Connect to an EKS cluster's private API endpoint via bastion host",
+	Long: `List EKS clusters, find a bastion host that can reach the cluster security group on 443,
+write a kubeconfig context pointing at the local tunnel and establish SSM port forwarding.
+The context authenticates with 'awsc eks token', so it keeps working while the tunnel is up.`,
+	Run: runEKSConnect,
+}
+
+var eksTokenCmd = &cobra.Command{
+	Use:   "token",
+	Short: "Print an EKS authentication token for kubectl",
+	Long:  `Print an ExecCredential with a presigned STS token for the cluster. Used as the kubeconfig exec credential plugin written by 'awsc eks connect'.`,
+	Run:   runEKSToken,
+}
+
+var eksLocalPort int
+var eksName string
+var eksKubeconfig string
+var eksSwitchAccount bool
+var eksTokenClusterName string
+
+func init() {
+	rootCmd.AddCommand(eksCmd)
+	eksCmd.AddCommand(eksConnectCmd)
+	eksCmd.AddCommand(eksTokenCmd)
+	eksConnectCmd.Flags().IntVar(&eksLocalPort, "local-port", 0, "Local port for the API server tunnel (defaults to 8443)")
+	eksConnectCmd.Flags().StringVar(&eksName, "name", "", "Name of the EKS cluster to connect to directly")
+	eksConnectCmd.Flags().StringVar(&eksKubeconfig, "kubeconfig", "", "Kubeconfig file to update (defaults to $KUBECONFIG or ~/.kube/config)")
+	eksConnectCmd.Flags().BoolVarP(&eksSwitchAccount, "switch-account", "s", false, "Switch AWS account before connecting")
+	eksTokenCmd.Flags().StringVar(&eksTokenClusterName, "cluster-name", "", "Name of the EKS cluster")
+	eksTokenCmd.MarkFlagRequired("cluster-name")
+}
+
+func runEKSConnect(cmd *cobra.Command, args []string) {
+	ctx := context.Background()
+
+	// Track if we just authenticated (to avoid double-login with -s flag)
+	justAuthenticated := false
+
+	// Create EKS manager
+	eksManager, err := aws.NewEKSManager(ctx)
+	if err != nil {
+		// Check if this is a "no active session" error
+		if aws.IsAuthError(err) {
+			shouldReauth, reAuthErr := aws.PromptForReauth(ctx)
+			if reAuthErr != nil {
+				fmt.Printf("Error during re-authentication: %v\n", reAuthErr)
+				os.Exit(1)
+			}
+			if !shouldReauth {
+				fmt.Printf("Authentication cancelled\n")
+				os.Exit(1)
+			}
+			justAuthenticated = true
+			// Retry creating manager after successful login
+			eksManager, err = aws.NewEKSManager(ctx)
+			if err != nil {
+				fmt.Printf("Error creating EKS manager after re-authentication: %v\n", err)
+				os.Exit(1)
+			}
+		} else {
+			fmt.Printf("Error creating EKS manager: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	// Handle account switching if requested (skip if we just authenticated)
+	if eksSwitchAccount && !justAuthenticated {
+		if err := handleAccountSwitch(ctx); err != nil {
+			fmt.Printf("%v\n", err)
+			os.Exit(1)
+		}
+		// Recreate EKS manager with new credentials
+		eksManager, err = aws.NewEKSManager(ctx)
+		if err != nil {
+			fmt.Printf("Error creating EKS manager after account switch: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	// Run the EKS connect workflow
+	if err := eksManager.RunConnect(ctx, eksName, int32(eksLocalPort), eksKubeconfig); err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func runEKSToken(cmd *cobra.Command, args []string) {
+	ctx := context.Background()
+
+	// kubectl runs this non-interactively, so don't prompt for login; stdout is only the credential
+	if err := aws.WriteToken(ctx, os.Stdout, eksTokenClusterName); err != nil {
+		fmt.Fprintf(os.Stderr, "Error generating EKS token: %v\n", err)
+		if aws.IsAuthError(err) {
+			fmt.Fprintf(os.Stderr, "Run 'awsc login' and try again\n")
+		}
+		os.Exit(1)
+	}
+}
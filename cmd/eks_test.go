@@ -0,0 +1,25 @@
+package cmd
+
+import (
+	"testing"
+)
+
+func TestEKSCommand(t *testing.T) {
+	if eksCmd.Use != "eks" {
+		t.Errorf("Expected eks command use to be 'eks', got %s", eksCmd.Use)
+	}
+
+	if eksConnectCmd.Run == nil || eksTokenCmd.Run == nil {
+		t.Error("eks subcommands should have Run functions")
+	}
+
+	for _, name := range []string{"name", "local-port", "kubeconfig", "switch-account"} {
+		if eksConnectCmd.Flags().Lookup(name) == nil {
+			t.Errorf("eksConnectCmd should have --%s flag", name)
+		}
+	}
+
+	if eksTokenCmd.Flags().Lookup("cluster-name") == nil {
+		t.Error("eksTokenCmd should have --cluster-name flag")
+	}
+}
@@ -0,0 +1,131 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/blontic/awsc/internal/aws"
+	"github.com/spf13/cobra"
+)
+
+var forwardCmd = &cobra.Command{
+	Use:   "forward",
+	Short: "Forward local ports to any host reachable from an SSM instance",
+	Long: `Forward local ports to arbitrary hosts (internal load balancers, MSK brokers, on-prem hosts)
+through an SSM-managed EC2 instance. Use --via auto to pick an instance in the VPC that
+contains the host's private IP, and repeat -L to forward several targets at once.`,
+	Run: runForward,
+}
+
+var forwardVia string
+var forwardVpc string
+var forwardHost string
+var forwardLocalPort int
+var forwardSpecs []string
+var forwardSwitchAccount bool
+
+func init() {
+	rootCmd.AddCommand(forwardCmd)
+	forwardCmd.Flags().StringVar(&forwardVia, "via", "", "Instance ID, Name tag or 'auto' to forward through (prompts if empty)")
+	forwardCmd.Flags().StringVar(&forwardVpc, "vpc", "", "VPC to pick the instance from with --via auto when the host's IP is in several")
+	forwardCmd.Flags().StringVar(&forwardHost, "host", "", "Remote host:port to forward to")
+	forwardCmd.Flags().IntVar(&forwardLocalPort, "local-port", 0, "Local port for --host (defaults to the remote port)")
+	forwardCmd.Flags().StringArrayVarP(&forwardSpecs, "forward", "L", nil, "Additional forward as [local-port:]host:port (repeatable)")
+	forwardCmd.Flags().BoolVarP(&forwardSwitchAccount, "switch-account", "s", false, "Switch AWS account before connecting")
+}
+
+// buildForwardSpecs combines --host/--local-port with any -L specs
+func buildForwardSpecs(host string, localPort int, specs []string) ([]aws.ForwardSpec, error) {
+	var result []aws.ForwardSpec
+
+	if host != "" {
+		spec, err := aws.ParseForwardSpec(host)
+		if err != nil {
+			return nil, err
+		}
+		if localPort != 0 {
+			spec.LocalPort = localPort
+		}
+		result = append(result, spec)
+	} else if localPort != 0 {
+		return nil, fmt.Errorf("--local-port requires --host")
+	}
+
+	for _, s := range specs {
+		spec, err := aws.ParseForwardSpec(s)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, spec)
+	}
+
+	if len(result) == 0 {
+		return nil, fmt.Errorf("specify --host host:port or at least one -L [local-port:]host:port")
+	}
+
+	return result, nil
+}
+
+func runForward(cmd *cobra.Command, args []string) {
+	ctx := context.Background()
+
+	specs, err := buildForwardSpecs(forwardHost, forwardLocalPort, forwardSpecs)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+	if forwardVpc != "" && forwardVia != aws.ViaAuto {
+		fmt.Printf("Error: --vpc only applies to --via auto\n")
+		os.Exit(1)
+	}
+
+	// Track if we just authenticated (to avoid double-login with -s flag)
+	justAuthenticated := false
+
+	// Create forward manager
+	forwardManager, err := aws.NewForwardManager(ctx)
+	if err != nil {
+		// Check if this is a "no active session" error
+		if aws.IsAuthError(err) {
+			shouldReauth, reAuthErr := aws.PromptForReauth(ctx)
+			if reAuthErr != nil {
+				fmt.Printf("Error during re-authentication: %v\n", reAuthErr)
+				os.Exit(1)
+			}
+			if !shouldReauth {
+				fmt.Printf("Authentication cancelled\n")
+				os.Exit(1)
+			}
+			justAuthenticated = true
+			// Retry creating manager after successful login
+			forwardManager, err = aws.NewForwardManager(ctx)
+			if err != nil {
+				fmt.Printf("Error creating forward manager after re-authentication: %v\n", err)
+				os.Exit(1)
+			}
+		} else {
+			fmt.Printf("Error creating forward manager: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	// Handle account switching if requested (skip if we just authenticated)
+	if forwardSwitchAccount && !justAuthenticated {
+		if err := handleAccountSwitch(ctx); err != nil {
+			fmt.Printf("%v\n", err)
+			os.Exit(1)
+		}
+		// Recreate forward manager with new credentials
+		forwardManager, err = aws.NewForwardManager(ctx)
+		if err != nil {
+			fmt.Printf("Error creating forward manager after account switch: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	if err := forwardManager.RunForward(ctx, forwardVia, forwardVpc, specs); err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+}
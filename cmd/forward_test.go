@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"testing"
+)
+
+func TestForwardCommand(t *testing.T) {
+	if forwardCmd.Use != "forward" {
+		t.Errorf("Expected Use 'forward', got '%s'", forwardCmd.Use)
+	}
+
+	if forwardCmd.Run == nil {
+		t.Error("forwardCmd should have Run function")
+	}
+
+	for _, name := range []string{"via", "vpc", "host", "local-port", "forward", "switch-account"} {
+		if forwardCmd.Flags().Lookup(name) == nil {
+			t.Errorf("forwardCmd should have --%s flag", name)
+		}
+	}
+
+	if flag := forwardCmd.Flags().Lookup("forward"); flag != nil && flag.Shorthand != "L" {
+		t.Errorf("Expected shorthand 'L' for forward flag, got '%s'", flag.Shorthand)
+	}
+}
+
+func TestBuildForwardSpecs(t *testing.T) {
+	specs, err := buildForwardSpecs("internal.example:8443", 9443, []string{"19092:10.0.1.5:9092"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(specs) != 2 {
+		t.Fatalf("Expected 2 specs, got %d", len(specs))
+	}
+	if specs[0].LocalPort != 9443 || specs[0].RemotePort != 8443 {
+		t.Errorf("Expected --local-port to override the local port, got %+v", specs[0])
+	}
+	if specs[1].LocalPort != 19092 || specs[1].RemoteHost != "10.0.1.5" {
+		t.Errorf("Unexpected -L spec: %+v", specs[1])
+	}
+
+	if _, err := buildForwardSpecs("", 0, nil); err == nil {
+		t.Error("Expected error when no forwards are given")
+	}
+
+	if _, err := buildForwardSpecs("", 8443, []string{"a:1"}); err == nil {
+		t.Error("Expected error when --local-port is given without --host")
+	}
+}
+
+func TestProxyCommand(t *testing.T) {
+	if proxyCmd.Use != "proxy" {
+		t.Errorf("Expected Use 'proxy', got '%s'", proxyCmd.Use)
+	}
+
+	if proxyCmd.Run == nil {
+		t.Error("proxyCmd should have Run function")
+	}
+
+	for _, name := range []string{"via", "port", "http", "switch-account"} {
+		if proxyCmd.Flags().Lookup(name) == nil {
+			t.Errorf("proxyCmd should have --%s flag", name)
+		}
+	}
+
+	if flag := proxyCmd.Flags().Lookup("port"); flag != nil && flag.DefValue != "1080" {
+		t.Errorf("Expected default proxy port 1080, got %s", flag.DefValue)
+	}
+}
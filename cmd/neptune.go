@@ -0,0 +1,35 @@
+package cmd
+
+import (
+	"github.com/blontic/awsc/internal/aws"
+	"github.com/spf13/cobra"
+)
+
+var neptuneCmd = &cobra.Command{
+	Use:   "neptune",
+	Short: "Neptune cluster connections",
+	Long:  `Connect to Neptune clusters via EC2 bastion hosts using SSM port forwarding`,
+}
+
+var neptuneConnectCmd = &cobra.Command{
+	Use:   "connect",
+	Short: "Connect to a Neptune cluster via bastion host",
+	Long:  `List Neptune clusters, find suitable bastion hosts, and establish SSM port forwarding connection`,
+	Run:   runNeptuneConnect,
+}
+
+var neptuneLocalPort int
+var neptuneName string
+var neptuneSwitchAccount bool
+
+func init() {
+	rootCmd.AddCommand(neptuneCmd)
+	neptuneCmd.AddCommand(neptuneConnectCmd)
+	neptuneConnectCmd.Flags().IntVar(&neptuneLocalPort, "local-port", 0, "Local port for port forwarding (defaults to the cluster port, 8182)")
+	neptuneConnectCmd.Flags().StringVar(&neptuneName, "name", "", "Name of the Neptune cluster endpoint to connect to directly")
+	neptuneConnectCmd.Flags().BoolVarP(&neptuneSwitchAccount, "switch-account", "s", false, "Switch AWS account before connecting")
+}
+
+func runNeptuneConnect(cmd *cobra.Command, args []string) {
+	runRDSEngineConnect(aws.NeptuneEngine, neptuneName, neptuneLocalPort, neptuneSwitchAccount)
+}
@@ -12,7 +12,7 @@ import (
 var opensearchCmd = &cobra.Command{
 	Use:   "opensearch",
 	Short: "OpenSearch domain connections",
-	Long:  `Connect to OpenSearch domains via EC2 bastion hosts using SSM port forwarding`,
+	Long:  `Connect to OpenSearch domains and OpenSearch Serverless collections via EC2 bastion hosts using SSM port forwarding`,
 }
 
 var opensearchConnectCmd = &cobra.Command{
@@ -25,12 +25,14 @@ var opensearchConnectCmd = &cobra.Command{
 var opensearchLocalPort int
 var opensearchDomainName string
 var opensearchSwitchAccount bool
+var opensearchSign bool
 
 func init() {
 	rootCmd.AddCommand(opensearchCmd)
 	opensearchCmd.AddCommand(opensearchConnectCmd)
-	opensearchConnectCmd.Flags().IntVar(&opensearchLocalPort, "local-port", 443, "Local port for port forwarding (defaults to 443)")
+	opensearchConnectCmd.Flags().IntVar(&opensearchLocalPort, "local-port", 0, "Local port for port forwarding (defaults to 443, or 9200 for the signing proxy)")
 	opensearchConnectCmd.Flags().StringVar(&opensearchDomainName, "name", "", "Name of the OpenSearch domain to connect to directly")
+	opensearchConnectCmd.Flags().BoolVar(&opensearchSign, "sign", false, "Serve a local HTTP proxy that signs requests with SigV4 (local port defaults to 9200)")
 	opensearchConnectCmd.Flags().BoolVarP(&opensearchSwitchAccount, "switch-account", "s", false, "Switch AWS account before connecting")
 }
 
@@ -82,7 +84,7 @@ func runOpenSearchConnect(cmd *cobra.Command, args []string) {
 	}
 
 	// Run the OpenSearch connect workflow
-	if err := opensearchManager.RunConnect(ctx, opensearchDomainName, int32(opensearchLocalPort)); err != nil {
+	if err := opensearchManager.RunConnect(ctx, opensearchDomainName, int32(opensearchLocalPort), opensearchSign); err != nil {
 		fmt.Printf("Error: %v\n", err)
 		os.Exit(1)
 	}
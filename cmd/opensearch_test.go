@@ -20,3 +20,13 @@ func TestOpenSearchCommand(t *testing.T) {
 		t.Errorf("Expected connect subcommand use to be 'connect', got %s", connectCmd.Use)
 	}
 }
+
+func TestOpenSearchConnectSignFlag(t *testing.T) {
+	flag := opensearchConnectCmd.Flags().Lookup("sign")
+	if flag == nil {
+		t.Fatal("opensearchConnectCmd should have --sign flag")
+	}
+	if flag.DefValue != "false" {
+		t.Errorf("Expected --sign to default to false, got %s", flag.DefValue)
+	}
+}
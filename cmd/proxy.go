@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/blontic/awsc/internal/aws"
+	"github.com/spf13/cobra"
+)
+
+var proxyCmd = &cobra.Command{
+	Use:   "proxy",
+	Short: "Run a local SOCKS5 proxy through an SSM instance",
+	Long: `Run a local SOCKS5 proxy (and optionally HTTP CONNECT) that reaches private hosts through
+an SSM-managed EC2 instance. Each client connection opens its own SSM port forwarding session
+to the requested host and port, so a browser profile pointed at the proxy can browse several
+private web UIs at once.`,
+	Run: runProxy,
+}
+
+var proxyVia string
+var proxyPort int
+var proxyHTTP bool
+var proxySwitchAccount bool
+
+func init() {
+	rootCmd.AddCommand(proxyCmd)
+	proxyCmd.Flags().StringVar(&proxyVia, "via", "", "Instance ID or Name tag to proxy through (prompts if empty)")
+	proxyCmd.Flags().IntVar(&proxyPort, "port", 1080, "Local port for the proxy")
+	proxyCmd.Flags().BoolVar(&proxyHTTP, "http", false, "Also accept HTTP CONNECT requests on the same port")
+	proxyCmd.Flags().BoolVarP(&proxySwitchAccount, "switch-account", "s", false, "Switch AWS account before connecting")
+}
+
+func runProxy(cmd *cobra.Command, args []string) {
+	ctx := context.Background()
+
+	// Track if we just authenticated (to avoid double-login with -s flag)
+	justAuthenticated := false
+
+	// Create forward manager
+	forwardManager, err := aws.NewForwardManager(ctx)
+	if err != nil {
+		// Check if this is a "no active session" error
+		if aws.IsAuthError(err) {
+			shouldReauth, reAuthErr := aws.PromptForReauth(ctx)
+			if reAuthErr != nil {
+				fmt.Printf("Error during re-authentication: %v\n", reAuthErr)
+				os.Exit(1)
+			}
+			if !shouldReauth {
+				fmt.Printf("Authentication cancelled\n")
+				os.Exit(1)
+			}
+			justAuthenticated = true
+			// Retry creating manager after successful login
+			forwardManager, err = aws.NewForwardManager(ctx)
+			if err != nil {
+				fmt.Printf("Error creating forward manager after re-authentication: %v\n", err)
+				os.Exit(1)
+			}
+		} else {
+			fmt.Printf("Error creating forward manager: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	// Handle account switching if requested (skip if we just authenticated)
+	if proxySwitchAccount && !justAuthenticated {
+		if err := handleAccountSwitch(ctx); err != nil {
+			fmt.Printf("%v\n", err)
+			os.Exit(1)
+		}
+		// Recreate forward manager with new credentials
+		forwardManager, err = aws.NewForwardManager(ctx)
+		if err != nil {
+			fmt.Printf("Error creating forward manager after account switch: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	if err := forwardManager.RunProxy(ctx, proxyVia, proxyPort, proxyHTTP); err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+}
@@ -22,16 +22,50 @@ var rdsConnectCmd = &cobra.Command{
 	Run:   runRDSConnect,
 }
 
+var rdsTokenCmd = &cobra.Command{
+	Use:   "token",
+	Short: "Generate an IAM database authentication token",
+	Long:  `Generate a signed IAM authentication token for an RDS instance and print it to stdout`,
+	Run:   runRDSToken,
+}
+
+var rdsQueryCmd = &cobra.Command{
+	Use:   "query [sql]",
+	Short: "Run a SQL statement through the RDS Data API",
+	Long:  `Run a SQL statement against an Aurora cluster with the Data API enabled, without a tunnel or bastion host`,
+	Args:  cobra.ExactArgs(1),
+	Run:   runRDSQuery,
+}
+
 var localPort int
 var rdsInstanceName string
 var switchAccount bool
+var rdsIAMAuth bool
+var rdsDBUser string
+var rdsLaunchClient bool
+var rdsWithCredentials bool
+var rdsDatabase string
 
 func init() {
 	rootCmd.AddCommand(rdsCmd)
 	rdsCmd.AddCommand(rdsConnectCmd)
+	rdsCmd.AddCommand(rdsTokenCmd)
+	rdsCmd.AddCommand(rdsQueryCmd)
 	rdsConnectCmd.Flags().IntVar(&localPort, "local-port", 0, "Local port for port forwarding (defaults to RDS port)")
 	rdsConnectCmd.Flags().StringVar(&rdsInstanceName, "name", "", "Name of the RDS instance to connect to directly")
 	rdsConnectCmd.Flags().BoolVarP(&switchAccount, "switch-account", "s", false, "Switch AWS account before connecting")
+	rdsConnectCmd.Flags().BoolVar(&rdsIAMAuth, "iam-auth", false, "Generate an IAM authentication token for --db-user")
+	rdsConnectCmd.Flags().StringVar(&rdsDBUser, "db-user", "", "Database user for IAM authentication and launched clients")
+	rdsConnectCmd.Flags().BoolVar(&rdsLaunchClient, "client", false, "Launch psql/mysql through the tunnel once it is ready")
+	rdsConnectCmd.Flags().BoolVar(&rdsWithCredentials, "with-credentials", false, "Fetch database credentials from Secrets Manager")
+
+	rdsTokenCmd.Flags().StringVar(&rdsInstanceName, "name", "", "Name of the RDS instance to generate a token for")
+	rdsTokenCmd.Flags().StringVar(&rdsDBUser, "db-user", "", "Database user to generate the token for")
+	rdsTokenCmd.Flags().BoolVarP(&switchAccount, "switch-account", "s", false, "Switch AWS account before generating the token")
+
+	rdsQueryCmd.Flags().StringVar(&rdsInstanceName, "name", "", "Name of the Aurora cluster to query")
+	rdsQueryCmd.Flags().StringVar(&rdsDatabase, "database", "", "Database to run the statement in")
+	rdsQueryCmd.Flags().BoolVarP(&switchAccount, "switch-account", "s", false, "Switch AWS account before running the query")
 }
 
 func runRDSConnect(cmd *cobra.Command, args []string) {
@@ -82,7 +116,175 @@ func runRDSConnect(cmd *cobra.Command, args []string) {
 	}
 
 	// Run the RDS connect workflow
-	if err := rdsManager.RunConnect(ctx, rdsInstanceName, int32(localPort)); err != nil {
+	if err := rdsManager.RunConnect(ctx, rdsInstanceName, int32(localPort), aws.RDSConnectOptions{
+		IAMAuth:      rdsIAMAuth,
+		DBUser:       rdsDBUser,
+		LaunchClient: rdsLaunchClient,
+		Credentials:  rdsWithCredentials,
+	}); err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func runRDSToken(cmd *cobra.Command, args []string) {
+	ctx := context.Background()
+
+	// Track if we just authenticated (to avoid double-login with -s flag)
+	justAuthenticated := false
+
+	// Create RDS manager
+	rdsManager, err := aws.NewRDSManager(ctx)
+	if err != nil {
+		// Check if this is a "no active session" error
+		if aws.IsAuthError(err) {
+			shouldReauth, reAuthErr := aws.PromptForReauth(ctx)
+			if reAuthErr != nil {
+				fmt.Fprintf(os.Stderr, "Error during re-authentication: %v\n", reAuthErr)
+				os.Exit(1)
+			}
+			if !shouldReauth {
+				fmt.Fprintf(os.Stderr, "Authentication cancelled\n")
+				os.Exit(1)
+			}
+			justAuthenticated = true
+			// Retry creating manager after successful login
+			rdsManager, err = aws.NewRDSManager(ctx)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error creating RDS manager after re-authentication: %v\n", err)
+				os.Exit(1)
+			}
+		} else {
+			fmt.Fprintf(os.Stderr, "Error creating RDS manager: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	// Handle account switching if requested (skip if we just authenticated)
+	if switchAccount && !justAuthenticated {
+		if err := handleAccountSwitch(ctx); err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			os.Exit(1)
+		}
+		// Recreate RDS manager with new credentials
+		rdsManager, err = aws.NewRDSManager(ctx)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error creating RDS manager after account switch: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	// Errors go to stderr so stdout only ever contains the token
+	if err := rdsManager.RunToken(ctx, rdsInstanceName, rdsDBUser); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func runRDSQuery(cmd *cobra.Command, args []string) {
+	ctx := context.Background()
+
+	// Track if we just authenticated (to avoid double-login with -s flag)
+	justAuthenticated := false
+
+	// Create RDS manager
+	rdsManager, err := aws.NewRDSManager(ctx)
+	if err != nil {
+		// Check if this is a "no active session" error
+		if aws.IsAuthError(err) {
+			shouldReauth, reAuthErr := aws.PromptForReauth(ctx)
+			if reAuthErr != nil {
+				fmt.Fprintf(os.Stderr, "Error during re-authentication: %v\n", reAuthErr)
+				os.Exit(1)
+			}
+			if !shouldReauth {
+				fmt.Fprintf(os.Stderr, "Authentication cancelled\n")
+				os.Exit(1)
+			}
+			justAuthenticated = true
+			// Retry creating manager after successful login
+			rdsManager, err = aws.NewRDSManager(ctx)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error creating RDS manager after re-authentication: %v\n", err)
+				os.Exit(1)
+			}
+		} else {
+			fmt.Fprintf(os.Stderr, "Error creating RDS manager: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	// Handle account switching if requested (skip if we just authenticated)
+	if switchAccount && !justAuthenticated {
+		if err := handleAccountSwitch(ctx); err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			os.Exit(1)
+		}
+		// Recreate RDS manager with new credentials
+		rdsManager, err = aws.NewRDSManager(ctx)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error creating RDS manager after account switch: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	// Errors go to stderr so stdout only contains the result
+	if err := rdsManager.RunQuery(ctx, rdsInstanceName, args[0], rdsDatabase); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// runRDSEngineConnect runs the connect workflow for an engine managed through the
+// RDS API that has its own command, such as DocumentDB or Neptune
+func runRDSEngineConnect(engine aws.DBEngine, name string, localPort int, switchAcct bool) {
+	ctx := context.Background()
+
+	// Track if we just authenticated (to avoid double-login with -s flag)
+	justAuthenticated := false
+
+	// Create RDS manager
+	rdsManager, err := aws.NewRDSManager(ctx)
+	if err != nil {
+		// Check if this is a "no active session" error
+		if aws.IsAuthError(err) {
+			shouldReauth, reAuthErr := aws.PromptForReauth(ctx)
+			if reAuthErr != nil {
+				fmt.Printf("Error during re-authentication: %v\n", reAuthErr)
+				os.Exit(1)
+			}
+			if !shouldReauth {
+				fmt.Printf("Authentication cancelled\n")
+				os.Exit(1)
+			}
+			justAuthenticated = true
+			// Retry creating manager after successful login
+			rdsManager, err = aws.NewRDSManager(ctx)
+			if err != nil {
+				fmt.Printf("Error creating %s manager after re-authentication: %v\n", engine.Name, err)
+				os.Exit(1)
+			}
+		} else {
+			fmt.Printf("Error creating %s manager: %v\n", engine.Name, err)
+			os.Exit(1)
+		}
+	}
+
+	// Handle account switching if requested (skip if we just authenticated)
+	if switchAcct && !justAuthenticated {
+		if err := handleAccountSwitch(ctx); err != nil {
+			fmt.Printf("%v\n", err)
+			os.Exit(1)
+		}
+		// Recreate RDS manager with new credentials
+		rdsManager, err = aws.NewRDSManager(ctx)
+		if err != nil {
+			fmt.Printf("Error creating %s manager after account switch: %v\n", engine.Name, err)
+			os.Exit(1)
+		}
+	}
+
+	if err := rdsManager.RunConnectEngine(ctx, engine, name, int32(localPort)); err != nil {
 		fmt.Printf("Error: %v\n", err)
 		os.Exit(1)
 	}
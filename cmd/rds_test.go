@@ -125,3 +125,52 @@ func TestRDSConnectFlagUsage(t *testing.T) {
 		}
 	}
 }
+
+func TestRDSConnectIAMFlags(t *testing.T) {
+	for _, name := range []string{"iam-auth", "db-user", "client", "with-credentials"} {
+		flag := rdsConnectCmd.Flags().Lookup(name)
+		if flag == nil {
+			t.Errorf("rdsConnectCmd should have --%s flag", name)
+			continue
+		}
+		if flag.Usage == "" {
+			t.Errorf("--%s flag should have usage description", name)
+		}
+	}
+}
+
+func TestRDSTokenCommand(t *testing.T) {
+	if rdsTokenCmd.Use != "token" {
+		t.Errorf("Expected Use 'token', got '%s'", rdsTokenCmd.Use)
+	}
+
+	if rdsTokenCmd.Run == nil {
+		t.Error("rdsTokenCmd should have Run function")
+	}
+
+	for _, name := range []string{"name", "db-user", "switch-account"} {
+		if rdsTokenCmd.Flags().Lookup(name) == nil {
+			t.Errorf("rdsTokenCmd should have --%s flag", name)
+		}
+	}
+}
+
+func TestRDSQueryCommand(t *testing.T) {
+	if rdsQueryCmd.Use != "query [sql]" {
+		t.Errorf("Expected Use 'query [sql]', got '%s'", rdsQueryCmd.Use)
+	}
+
+	if rdsQueryCmd.Run == nil {
+		t.Error("rdsQueryCmd should have Run function")
+	}
+
+	if err := rdsQueryCmd.Args(rdsQueryCmd, []string{}); err == nil {
+		t.Error("rdsQueryCmd should require a SQL statement")
+	}
+
+	for _, name := range []string{"name", "database", "switch-account"} {
+		if rdsQueryCmd.Flags().Lookup(name) == nil {
+			t.Errorf("rdsQueryCmd should have --%s flag", name)
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/blontic/awsc/internal/recording"
+	"github.com/blontic/awsc/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var recordingsCmd = &cobra.Command{
+	Use:   "recordings",
+	Short: "Replay recorded sessions",
+	Long:  `Replay sessions recorded with 'awsc ec2 connect --record' from ~/.awsc/recordings`,
+}
+
+var recordingsPlayCmd = &cobra.Command{
+	Use:   "play [file]",
+	Short: "Replay a recorded session in this terminal",
+	Long: `Replay an asciicast recording, selecting it from ~/.awsc/recordings unless a file is given.
+Recordings are asciicast v2 files, so they also play with 'asciinema play'.`,
+	Args: cobra.MaximumNArgs(1),
+	PreRun: func(cmd *cobra.Command, args []string) {
+		// Replaying doesn't need SSO configuration
+	},
+	Run: runRecordingsPlay,
+}
+
+var recordingsSpeed float64
+var recordingsIdleLimit time.Duration
+
+func init() {
+	rootCmd.AddCommand(recordingsCmd)
+	recordingsCmd.AddCommand(recordingsPlayCmd)
+	recordingsPlayCmd.Flags().Float64Var(&recordingsSpeed, "speed", 1, "Playback speed multiplier")
+	recordingsPlayCmd.Flags().DurationVar(&recordingsIdleLimit, "idle-limit", 2*time.Second, "Shorten pauses longer than this (0 to keep them)")
+}
+
+func runRecordingsPlay(cmd *cobra.Command, args []string) {
+	path := ""
+	if len(args) > 0 {
+		path = args[0]
+	} else {
+		selected, err := selectRecording()
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		path = selected
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+	defer file.Close()
+
+	header, events, err := recording.Read(file)
+	if err != nil {
+		fmt.Printf("Error reading %s: %v\n", path, err)
+		os.Exit(1)
+	}
+
+	printRecordingHeader(header)
+	if err := recording.Play(os.Stdout, events, recordingsSpeed, recordingsIdleLimit, nil); err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("\n--- End of recording ---\n")
+}
+
+func selectRecording() (string, error) {
+	recordings, err := recording.List()
+	if err != nil {
+		return "", err
+	}
+	if len(recordings) == 0 {
+		return "", fmt.Errorf("no recordings found, record a session with 'awsc ec2 connect --record'")
+	}
+
+	choices := make([]string, len(recordings))
+	for i, info := range recordings {
+		choices[i] = recordingLabel(info)
+	}
+
+	selectedIndex, err := ui.RunSelector("Select recording:", choices)
+	if err != nil {
+		return "", fmt.Errorf("error selecting recording: %v", err)
+	}
+	if selectedIndex == -1 {
+		return "", fmt.Errorf("no recording selected")
+	}
+
+	return recordings[selectedIndex].Path, nil
+}
+
+// recordingLabel describes a recording in the selector
+func recordingLabel(info recording.Info) string {
+	label := time.Unix(info.Header.Timestamp, 0).Format("2006-01-02 15:04:05")
+	if metadata := info.Header.Awsc; metadata != nil {
+		target := metadata.InstanceId
+		if metadata.InstanceName != "" {
+			target = fmt.Sprintf("%s (%s)", metadata.InstanceName, metadata.InstanceId)
+		}
+		label += "  " + target
+		if metadata.AccountName != "" {
+			label += "  " + metadata.AccountName
+		}
+	} else {
+		label += "  " + filepath.Base(info.Path)
+	}
+	if info.Header.Duration > 0 {
+		label += "  " + time.Duration(info.Header.Duration*float64(time.Second)).Round(time.Second).String()
+	}
+	return label
+}
+
+func printRecordingHeader(header recording.Header) {
+	metadata := header.Awsc
+	if metadata == nil {
+		fmt.Printf("--- Recording from %s ---\n", time.Unix(header.Timestamp, 0).Format(time.RFC3339))
+		return
+	}
+
+	fmt.Printf("--- Recording of %s (%s)", metadata.InstanceName, metadata.InstanceId)
+	if metadata.User != "" {
+		fmt.Printf(" as %s", metadata.User)
+	}
+	fmt.Printf(" ---\n")
+	if metadata.AccountId != "" {
+		fmt.Printf("Account: %s (%s), role %s\n", metadata.AccountName, metadata.AccountId, metadata.RoleName)
+	}
+	if metadata.Profile != "" {
+		fmt.Printf("Profile: %s, region %s\n", metadata.Profile, metadata.Region)
+	}
+	fmt.Printf("Started: %s\n", metadata.Start.Format(time.RFC3339))
+	if !metadata.End.IsZero() {
+		fmt.Printf("Ended:   %s\n", metadata.End.Format(time.RFC3339))
+	}
+	fmt.Printf("\n")
+}
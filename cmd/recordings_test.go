@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/blontic/awsc/internal/recording"
+)
+
+func TestRecordingsCommands(t *testing.T) {
+	if recordingsCmd.Use != "recordings" {
+		t.Errorf("Expected Use 'recordings', got '%s'", recordingsCmd.Use)
+	}
+
+	found := false
+	for _, sub := range recordingsCmd.Commands() {
+		if sub == recordingsPlayCmd {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("play should be a subcommand of recordings")
+	}
+
+	if recordingsPlayCmd.Run == nil {
+		t.Error("recordingsPlayCmd should have Run function")
+	}
+	if recordingsPlayCmd.Args(recordingsPlayCmd, []string{"a.cast", "b.cast"}) == nil {
+		t.Error("play should accept at most one file")
+	}
+
+	for _, name := range []string{"speed", "idle-limit"} {
+		if recordingsPlayCmd.Flags().Lookup(name) == nil {
+			t.Errorf("--%s flag should be defined for recordings play", name)
+		}
+	}
+}
+
+func TestRecordingLabel(t *testing.T) {
+	info := recording.Info{
+		Path: "/home/dev/.awsc/recordings/20260101-120000-i-123.cast",
+		Header: recording.Header{
+			Timestamp: time.Date(2026, 1, 1, 12, 0, 0, 0, time.Local).Unix(),
+			Duration:  95.4,
+			Awsc: &recording.Metadata{
+				InstanceId:   "i-123",
+				InstanceName: "web-1",
+				AccountName:  "Production",
+			},
+		},
+	}
+
+	label := recordingLabel(info)
+	for _, want := range []string{"2026-01-01 12:00:00", "web-1 (i-123)", "Production", "1m35s"} {
+		if !strings.Contains(label, want) {
+			t.Errorf("Expected label to contain %q, got %q", want, label)
+		}
+	}
+
+	info.Header.Awsc = nil
+	if label := recordingLabel(info); !strings.Contains(label, "20260101-120000-i-123.cast") {
+		t.Errorf("Expected file name without metadata, got %q", label)
+	}
+}
@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/blontic/awsc/internal/aws"
+	"github.com/spf13/cobra"
+)
+
+var redshiftCmd = &cobra.Command{
+	Use:   "redshift",
+	Short: "Redshift cluster connections",
+	Long:  `Connect to Redshift clusters and Redshift Serverless workgroups via EC2 bastion hosts using SSM port forwarding`,
+}
+
+var redshiftConnectCmd = &cobra.Command{
+	Use:   "connect",
+	Short: "Connect to a Redshift cluster or workgroup via bastion host",
+	Long:  `List Redshift clusters and serverless workgroups, find suitable bastion hosts, and establish SSM port forwarding connection`,
+	Run:   runRedshiftConnect,
+}
+
+var redshiftLocalPort int
+var redshiftName string
+var redshiftSwitchAccount bool
+
+func init() {
+	rootCmd.AddCommand(redshiftCmd)
+	redshiftCmd.AddCommand(redshiftConnectCmd)
+	redshiftConnectCmd.Flags().IntVar(&redshiftLocalPort, "local-port", 0, "Local port for port forwarding (defaults to the cluster port, 5439)")
+	redshiftConnectCmd.Flags().StringVar(&redshiftName, "name", "", "Name of the Redshift cluster or workgroup to connect to directly")
+	redshiftConnectCmd.Flags().BoolVarP(&redshiftSwitchAccount, "switch-account", "s", false, "Switch AWS account before connecting")
+}
+
+func runRedshiftConnect(cmd *cobra.Command, args []string) {
+	ctx := context.Background()
+
+	// Track if we just authenticated (to avoid double-login with -s flag)
+	justAuthenticated := false
+
+	// Create Redshift manager
+	redshiftManager, err := aws.NewRedshiftManager(ctx)
+	if err != nil {
+		// Check if this is a "no active session" error
+		if aws.IsAuthError(err) {
+			shouldReauth, reAuthErr := aws.PromptForReauth(ctx)
+			if reAuthErr != nil {
+				fmt.Printf("Error during re-authentication: %v\n", reAuthErr)
+				os.Exit(1)
+			}
+			if !shouldReauth {
+				fmt.Printf("Authentication cancelled\n")
+				os.Exit(1)
+			}
+			justAuthenticated = true
+			// Retry creating manager after successful login
+			redshiftManager, err = aws.NewRedshiftManager(ctx)
+			if err != nil {
+				fmt.Printf("Error creating Redshift manager after re-authentication: %v\n", err)
+				os.Exit(1)
+			}
+		} else {
+			fmt.Printf("Error creating Redshift manager: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	// Handle account switching if requested (skip if we just authenticated)
+	if redshiftSwitchAccount && !justAuthenticated {
+		if err := handleAccountSwitch(ctx); err != nil {
+			fmt.Printf("%v\n", err)
+			os.Exit(1)
+		}
+		// Recreate Redshift manager with new credentials
+		redshiftManager, err = aws.NewRedshiftManager(ctx)
+		if err != nil {
+			fmt.Printf("Error creating Redshift manager after account switch: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	// Run the Redshift connect workflow
+	if err := redshiftManager.RunConnect(ctx, redshiftName, int32(redshiftLocalPort)); err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestRedshiftCommand(t *testing.T) {
+	if redshiftCmd.Use != "redshift" {
+		t.Errorf("Expected redshift command use to be 'redshift', got %s", redshiftCmd.Use)
+	}
+
+	if redshiftConnectCmd.Run == nil {
+		t.Error("redshiftConnectCmd should have Run function")
+	}
+
+	for _, name := range []string{"name", "local-port", "switch-account"} {
+		if redshiftConnectCmd.Flags().Lookup(name) == nil {
+			t.Errorf("redshiftConnectCmd should have --%s flag", name)
+		}
+	}
+}
+
+func TestDocDBAndNeptuneCommands(t *testing.T) {
+	if docdbCmd.Use != "docdb" {
+		t.Errorf("Expected docdb command use to be 'docdb', got %s", docdbCmd.Use)
+	}
+	if neptuneCmd.Use != "neptune" {
+		t.Errorf("Expected neptune command use to be 'neptune', got %s", neptuneCmd.Use)
+	}
+
+	for _, connectCmd := range []*cobra.Command{docdbConnectCmd, neptuneConnectCmd} {
+		if connectCmd.Run == nil {
+			t.Errorf("%s connect should have Run function", connectCmd.Parent().Use)
+		}
+		for _, name := range []string{"name", "local-port", "switch-account"} {
+			if connectCmd.Flags().Lookup(name) == nil {
+				t.Errorf("%s connect should have --%s flag", connectCmd.Parent().Use, name)
+			}
+		}
+	}
+}
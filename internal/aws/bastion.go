@@ -0,0 +1,267 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	awscconfig "github.com/blontic/awsc/internal/config"
+	"github.com/blontic/awsc/internal/debug"
+)
+
+// BastionFinder finds running EC2 instances whose security groups are allowed
+// to reach a target's security groups
+type BastionFinder struct {
+	ec2Client      EC2Client
+	region         string
+	nonInteractive bool // Fail on auth errors instead of prompting
+}
+
+// BastionTarget describes the private resource a bastion needs to reach
+type BastionTarget struct {
+	Name             string   // Resource name used in messages
+	Service          string   // Service name used in messages, e.g. "ElastiCache"
+	SecurityGroupIds []string // Security groups attached to the resource
+	Port             int32
+}
+
+func NewBastionFinder(ec2Client EC2Client, region string) *BastionFinder {
+	return &BastionFinder{
+		ec2Client: ec2Client,
+		region:    region,
+	}
+}
+
+func (b *BastionFinder) FindBastionHosts(ctx context.Context, target BastionTarget) ([]BastionHost, error) {
+	debug.Printf("%s %s security groups: %v\n", target.Service, target.Name, target.SecurityGroupIds)
+
+	// Find all EC2 instances (running and stopped) that can connect to the target
+	var allReservations []types.Reservation
+	var nextToken *string
+
+	for {
+		result, err := b.ec2Client.DescribeInstances(ctx, &ec2.DescribeInstancesInput{
+			NextToken: nextToken,
+		})
+		if err != nil {
+			if IsAuthError(err) {
+				if shouldReauth, reAuthErr := b.promptForReauth(ctx); shouldReauth && reAuthErr == nil {
+					if reloadErr := b.reloadClients(ctx); reloadErr != nil {
+						return nil, reloadErr
+					}
+					result, err = b.ec2Client.DescribeInstances(ctx, &ec2.DescribeInstancesInput{
+						NextToken: nextToken,
+					})
+					if err != nil {
+						return nil, err
+					}
+				} else {
+					return nil, err
+				}
+			} else {
+				return nil, err
+			}
+		}
+
+		allReservations = append(allReservations, result.Reservations...)
+
+		if result.NextToken == nil {
+			break
+		}
+		nextToken = result.NextToken
+	}
+
+	// Count and categorize instances
+	runningInstances := 0
+	stoppedInstances := 0
+	var stoppedInstanceNames []string
+
+	for _, reservation := range allReservations {
+		for _, instance := range reservation.Instances {
+			if instance.State != nil {
+				if instance.State.Name == "running" {
+					runningInstances++
+				} else if instance.State.Name == "stopped" {
+					stoppedInstances++
+					stoppedInstanceNames = append(stoppedInstanceNames, b.getInstanceName(instance.Tags))
+				}
+			}
+		}
+	}
+	debug.Printf("Found %d running and %d stopped EC2 instances\n", runningInstances, stoppedInstances)
+
+	var bastions []BastionHost
+	for _, reservation := range allReservations {
+		for _, instance := range reservation.Instances {
+			// Only check running instances for bastion capability
+			if instance.State == nil || instance.State.Name != "running" {
+				continue
+			}
+
+			name := b.getInstanceName(instance.Tags)
+			ec2SgIds := b.getSecurityGroupIds(instance.SecurityGroups)
+			debug.Printf("Checking instance %s (%s) with security groups: %v\n", name, *instance.InstanceId, ec2SgIds)
+
+			if b.canConnect(ctx, instance.SecurityGroups, target.SecurityGroupIds, target.Port) {
+				debug.Printf("✓ Instance %s can connect to %s\n", name, target.Service)
+				bastions = append(bastions, BastionHost{
+					InstanceId:       *instance.InstanceId,
+					Name:             name,
+					SecurityGroupIds: ec2SgIds,
+				})
+			} else {
+				debug.Printf("✗ Instance %s cannot connect to %s\n", name, target.Service)
+			}
+		}
+	}
+
+	if len(bastions) == 0 {
+		// Show stopped instances if any exist
+		if stoppedInstances > 0 {
+			fmt.Printf("\nFound %d stopped EC2 instance(s):\n", stoppedInstances)
+			for _, name := range stoppedInstanceNames {
+				fmt.Printf("- %s (stopped)\n", name)
+			}
+			fmt.Printf("\n")
+		}
+
+		if runningInstances == 0 {
+			fmt.Printf("No running EC2 instances found in region %s.\n", b.region)
+			fmt.Printf("To use %s port forwarding, you need a running EC2 instance with:\n", target.Service)
+			fmt.Printf("- SSM agent installed and configured\n")
+			fmt.Printf("- Network access to %s\n", target.Name)
+			if stoppedInstances > 0 {
+				fmt.Printf("\nYou can start one of the stopped instances above and try again.\n")
+				return nil, fmt.Errorf("no running bastion hosts found - %d stopped instances available", stoppedInstances)
+			}
+			return nil, fmt.Errorf("no running EC2 instances found in region %s", b.region)
+		}
+
+		fmt.Printf("Found %d running EC2 instances but none can connect to %s %s.\n", runningInstances, target.Service, target.Name)
+		fmt.Printf("This usually means the security groups don't allow the connection.\n")
+		return nil, fmt.Errorf("no suitable bastion hosts found - security groups may not allow connection")
+	}
+
+	return bastions, nil
+}
+
+func (b *BastionFinder) canConnect(ctx context.Context, ec2SecurityGroups []types.GroupIdentifier, targetSecurityGroups []string, port int32) bool {
+	ec2SgIds := make(map[string]bool)
+	for _, sg := range ec2SecurityGroups {
+		ec2SgIds[*sg.GroupId] = true
+	}
+
+	// Check if any target security group allows inbound from EC2 security groups
+	for _, targetSgId := range targetSecurityGroups {
+		if b.checkSecurityGroupRules(ctx, targetSgId, ec2SgIds, port) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (b *BastionFinder) checkSecurityGroupRules(ctx context.Context, targetSgId string, ec2SgIds map[string]bool, port int32) bool {
+	result, err := b.ec2Client.DescribeSecurityGroups(ctx, &ec2.DescribeSecurityGroupsInput{
+		GroupIds: []string{targetSgId},
+	})
+	if err != nil {
+		if IsAuthError(err) {
+			if shouldReauth, reAuthErr := b.promptForReauth(ctx); shouldReauth && reAuthErr == nil {
+				if reloadErr := b.reloadClients(ctx); reloadErr != nil {
+					debug.Printf("  Error reloading clients: %v\n", reloadErr)
+					return false
+				}
+				result, err = b.ec2Client.DescribeSecurityGroups(ctx, &ec2.DescribeSecurityGroupsInput{
+					GroupIds: []string{targetSgId},
+				})
+				if err != nil {
+					debug.Printf("  Error describing security group %s after retry: %v\n", targetSgId, err)
+					return false
+				}
+			} else {
+				return false
+			}
+		} else {
+			debug.Printf("  Error describing security group %s: %v\n", targetSgId, err)
+			return false
+		}
+	}
+
+	if len(result.SecurityGroups) == 0 {
+		debug.Printf("  No security group found for %s\n", targetSgId)
+		return false
+	}
+
+	debug.Printf("  Checking security group %s rules for port %d\n", targetSgId, port)
+
+	for _, rule := range result.SecurityGroups[0].IpPermissions {
+		if !b.ruleMatchesPort(rule, port) {
+			debug.Printf("    Rule does not match port %d (from:%v to:%v)\n", port, rule.FromPort, rule.ToPort)
+			continue
+		}
+
+		debug.Printf("    Rule matches port %d\n", port)
+		// Check if rule allows access from EC2 security groups
+		for _, userIdGroupPair := range rule.UserIdGroupPairs {
+			if userIdGroupPair.GroupId != nil && ec2SgIds[*userIdGroupPair.GroupId] {
+				debug.Printf("      ✓ Match found: %s\n", *userIdGroupPair.GroupId)
+				return true
+			}
+		}
+		// Check for open access (0.0.0.0/0)
+		for _, ipRange := range rule.IpRanges {
+			if ipRange.CidrIp != nil && *ipRange.CidrIp == "0.0.0.0/0" {
+				debug.Printf("      ✓ Open access found!\n")
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func (b *BastionFinder) ruleMatchesPort(rule types.IpPermission, port int32) bool {
+	if rule.FromPort == nil || rule.ToPort == nil {
+		return false
+	}
+	return *rule.FromPort <= port && port <= *rule.ToPort
+}
+
+func (b *BastionFinder) getInstanceName(tags []types.Tag) string {
+	for _, tag := range tags {
+		if tag.Key != nil && *tag.Key == "Name" && tag.Value != nil {
+			return *tag.Value
+		}
+	}
+	return "Unnamed"
+}
+
+func (b *BastionFinder) getSecurityGroupIds(sgs []types.GroupIdentifier) []string {
+	var ids []string
+	for _, sg := range sgs {
+		ids = append(ids, *sg.GroupId)
+	}
+	return ids
+}
+
+// promptForReauth prompts to log in again unless the finder is non-interactive
+func (b *BastionFinder) promptForReauth(ctx context.Context) (bool, error) {
+	if b.nonInteractive {
+		return false, nil
+	}
+	return PromptForReauth(ctx)
+}
+
+func (b *BastionFinder) reloadClients(ctx context.Context) error {
+	cfg, err := awscconfig.LoadAWSConfigWithProfile(ctx)
+	if err != nil {
+		return err
+	}
+
+	b.ec2Client = ec2.NewFromConfig(cfg)
+	b.region = cfg.Region
+
+	return nil
+}
@@ -0,0 +1,391 @@
+package aws
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/blontic/awsc/internal/aws/mocks"
+	"go.uber.org/mock/gomock"
+)
+
+func TestBastionFinder_FindBastionHosts(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEC2 := mocks.NewMockEC2Client(ctrl)
+	finder := NewBastionFinder(mockEC2, "us-east-1")
+
+	mockEC2.EXPECT().
+		DescribeInstances(gomock.Any(), gomock.Any()).
+		Return(&ec2.DescribeInstancesOutput{
+			Reservations: []types.Reservation{
+				{
+					Instances: []types.Instance{
+						{
+							InstanceId:     aws.String("i-bastion"),
+							State:          &types.InstanceState{Name: types.InstanceStateNameRunning},
+							Tags:           []types.Tag{{Key: aws.String("Name"), Value: aws.String("bastion")}},
+							SecurityGroups: []types.GroupIdentifier{{GroupId: aws.String("sg-bastion")}},
+						},
+						{
+							InstanceId:     aws.String("i-web"),
+							State:          &types.InstanceState{Name: types.InstanceStateNameRunning},
+							Tags:           []types.Tag{{Key: aws.String("Name"), Value: aws.String("web")}},
+							SecurityGroups: []types.GroupIdentifier{{GroupId: aws.String("sg-web")}},
+						},
+					},
+				},
+			},
+		}, nil).
+		Times(1)
+
+	// Target security group allows the bastion security group on the port
+	mockEC2.EXPECT().
+		DescribeSecurityGroups(gomock.Any(), &ec2.DescribeSecurityGroupsInput{
+			GroupIds: []string{"sg-target"},
+		}).
+		Return(&ec2.DescribeSecurityGroupsOutput{
+			SecurityGroups: []types.SecurityGroup{
+				{
+					IpPermissions: []types.IpPermission{
+						{
+							FromPort:         aws.Int32(6379),
+							ToPort:           aws.Int32(6379),
+							UserIdGroupPairs: []types.UserIdGroupPair{{GroupId: aws.String("sg-bastion")}},
+						},
+					},
+				},
+			},
+		}, nil).
+		Times(2)
+
+	bastions, err := finder.FindBastionHosts(context.Background(), BastionTarget{
+		Name:             "sessions",
+		Service:          "ElastiCache",
+		SecurityGroupIds: []string{"sg-target"},
+		Port:             6379,
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(bastions) != 1 {
+		t.Fatalf("Expected 1 bastion, got %d", len(bastions))
+	}
+	if bastions[0].InstanceId != "i-bastion" || bastions[0].Name != "bastion" {
+		t.Errorf("Unexpected bastion: %+v", bastions[0])
+	}
+}
+
+func TestBastionFinder_FindBastionHosts_NoRunningInstances(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEC2 := mocks.NewMockEC2Client(ctrl)
+	finder := NewBastionFinder(mockEC2, "us-east-1")
+
+	mockEC2.EXPECT().
+		DescribeInstances(gomock.Any(), gomock.Any()).
+		Return(&ec2.DescribeInstancesOutput{}, nil).
+		Times(1)
+
+	_, err := finder.FindBastionHosts(context.Background(), BastionTarget{
+		Name:             "sessions",
+		Service:          "ElastiCache",
+		SecurityGroupIds: []string{"sg-target"},
+		Port:             6379,
+	})
+	if err == nil || !strings.Contains(err.Error(), "no running EC2 instances found") {
+		t.Errorf("Expected error about no running instances, got: %v", err)
+	}
+}
+
+func TestBastionFinder_checkSecurityGroupRules(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEC2 := mocks.NewMockEC2Client(ctrl)
+	finder := NewBastionFinder(mockEC2, "us-east-1")
+
+	tests := []struct {
+		name         string
+		targetSgId   string
+		ec2SgIds     map[string]bool
+		port         int32
+		mockResponse *ec2.DescribeSecurityGroupsOutput
+		mockError    error
+		expected     bool
+	}{
+		{
+			name:       "security group allows access from EC2 SG",
+			targetSgId: "sg-rds-123",
+			ec2SgIds:   map[string]bool{"sg-ec2-456": true},
+			port:       3306,
+			mockResponse: &ec2.DescribeSecurityGroupsOutput{
+				SecurityGroups: []types.SecurityGroup{
+					{
+						IpPermissions: []types.IpPermission{
+							{
+								FromPort: aws.Int32(3306),
+								ToPort:   aws.Int32(3306),
+								UserIdGroupPairs: []types.UserIdGroupPair{
+									{GroupId: aws.String("sg-ec2-456")},
+								},
+							},
+						},
+					},
+				},
+			},
+			expected: true,
+		},
+		{
+			name:       "security group allows open access",
+			targetSgId: "sg-rds-123",
+			ec2SgIds:   map[string]bool{"sg-ec2-456": true},
+			port:       3306,
+			mockResponse: &ec2.DescribeSecurityGroupsOutput{
+				SecurityGroups: []types.SecurityGroup{
+					{
+						IpPermissions: []types.IpPermission{
+							{
+								FromPort: aws.Int32(3306),
+								ToPort:   aws.Int32(3306),
+								IpRanges: []types.IpRange{
+									{CidrIp: aws.String("0.0.0.0/0")},
+								},
+							},
+						},
+					},
+				},
+			},
+			expected: true,
+		},
+		{
+			name:       "security group denies access",
+			targetSgId: "sg-rds-123",
+			ec2SgIds:   map[string]bool{"sg-ec2-456": true},
+			port:       3306,
+			mockResponse: &ec2.DescribeSecurityGroupsOutput{
+				SecurityGroups: []types.SecurityGroup{
+					{
+						IpPermissions: []types.IpPermission{
+							{
+								FromPort: aws.Int32(5432),
+								ToPort:   aws.Int32(5432),
+								UserIdGroupPairs: []types.UserIdGroupPair{
+									{GroupId: aws.String("sg-ec2-different")},
+								},
+							},
+						},
+					},
+				},
+			},
+			expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockEC2.EXPECT().
+				DescribeSecurityGroups(gomock.Any(), &ec2.DescribeSecurityGroupsInput{
+					GroupIds: []string{tt.targetSgId},
+				}).
+				Return(tt.mockResponse, tt.mockError).
+				Times(1)
+
+			result := finder.checkSecurityGroupRules(context.Background(), tt.targetSgId, tt.ec2SgIds, tt.port)
+
+			if result != tt.expected {
+				t.Errorf("Expected %v, got %v", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestBastionFinder_ruleMatchesPort(t *testing.T) {
+	finder := &BastionFinder{}
+
+	tests := []struct {
+		name     string
+		rule     types.IpPermission
+		port     int32
+		expected bool
+	}{
+		{
+			name: "port matches exactly",
+			rule: types.IpPermission{
+				FromPort: aws.Int32(3306),
+				ToPort:   aws.Int32(3306),
+			},
+			port:     3306,
+			expected: true,
+		},
+		{
+			name: "port within range",
+			rule: types.IpPermission{
+				FromPort: aws.Int32(3000),
+				ToPort:   aws.Int32(4000),
+			},
+			port:     3306,
+			expected: true,
+		},
+		{
+			name: "port outside range",
+			rule: types.IpPermission{
+				FromPort: aws.Int32(5000),
+				ToPort:   aws.Int32(6000),
+			},
+			port:     3306,
+			expected: false,
+		},
+		{
+			name: "nil ports",
+			rule: types.IpPermission{
+				FromPort: nil,
+				ToPort:   nil,
+			},
+			port:     3306,
+			expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := finder.ruleMatchesPort(tt.rule, tt.port)
+			if result != tt.expected {
+				t.Errorf("Expected %v, got %v", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestBastionFinder_getInstanceName(t *testing.T) {
+	finder := &BastionFinder{}
+
+	tests := []struct {
+		name     string
+		tags     []types.Tag
+		expected string
+	}{
+		{
+			name: "has name tag",
+			tags: []types.Tag{
+				{Key: aws.String("Name"), Value: aws.String("MyInstance")},
+				{Key: aws.String("Environment"), Value: aws.String("prod")},
+			},
+			expected: "MyInstance",
+		},
+		{
+			name: "no name tag",
+			tags: []types.Tag{
+				{Key: aws.String("Environment"), Value: aws.String("prod")},
+			},
+			expected: "Unnamed",
+		},
+		{
+			name:     "nil tags",
+			tags:     nil,
+			expected: "Unnamed",
+		},
+		{
+			name:     "empty tags",
+			tags:     []types.Tag{},
+			expected: "Unnamed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := finder.getInstanceName(tt.tags)
+			if result != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestBastionFinder_getSecurityGroupIds(t *testing.T) {
+	finder := &BastionFinder{}
+
+	tests := []struct {
+		name     string
+		sgs      []types.GroupIdentifier
+		expected []string
+	}{
+		{
+			name: "multiple security groups",
+			sgs: []types.GroupIdentifier{
+				{GroupId: aws.String("sg-123")},
+				{GroupId: aws.String("sg-456")},
+			},
+			expected: []string{"sg-123", "sg-456"},
+		},
+		{
+			name:     "nil security groups",
+			sgs:      nil,
+			expected: []string{},
+		},
+		{
+			name:     "empty security groups",
+			sgs:      []types.GroupIdentifier{},
+			expected: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := finder.getSecurityGroupIds(tt.sgs)
+			if len(result) != len(tt.expected) {
+				t.Errorf("Expected %d security groups, got %d", len(tt.expected), len(result))
+			}
+			for i, sg := range result {
+				if i < len(tt.expected) && sg != tt.expected[i] {
+					t.Errorf("Expected security group %s, got %s", tt.expected[i], sg)
+				}
+			}
+		})
+	}
+}
+
+func TestBastionFinder_canConnect(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEC2 := mocks.NewMockEC2Client(ctrl)
+	finder := NewBastionFinder(mockEC2, "us-east-1")
+
+	ec2SecurityGroups := []types.GroupIdentifier{
+		{GroupId: aws.String("sg-ec2-123")},
+	}
+	rdsSecurityGroups := []string{"sg-rds-456"}
+
+	// Mock the security group rules check
+	mockEC2.EXPECT().
+		DescribeSecurityGroups(gomock.Any(), &ec2.DescribeSecurityGroupsInput{
+			GroupIds: []string{"sg-rds-456"},
+		}).
+		Return(&ec2.DescribeSecurityGroupsOutput{
+			SecurityGroups: []types.SecurityGroup{
+				{
+					IpPermissions: []types.IpPermission{
+						{
+							FromPort: aws.Int32(3306),
+							ToPort:   aws.Int32(3306),
+							UserIdGroupPairs: []types.UserIdGroupPair{
+								{GroupId: aws.String("sg-ec2-123")},
+							},
+						},
+					},
+				},
+			},
+		}, nil).
+		Times(1)
+
+	result := finder.canConnect(context.Background(), ec2SecurityGroups, rdsSecurityGroups, 3306)
+	if !result {
+		t.Error("Expected canConnect to return true")
+	}
+}
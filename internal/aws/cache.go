@@ -0,0 +1,426 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/elasticache"
+	elasticachetypes "github.com/aws/aws-sdk-go-v2/service/elasticache/types"
+	awscconfig "github.com/blontic/awsc/internal/config"
+	"github.com/blontic/awsc/internal/ui"
+)
+
+// ElastiCacheClient interface for mocking
+type ElastiCacheClient interface {
+	DescribeReplicationGroups(ctx context.Context, params *elasticache.DescribeReplicationGroupsInput, optFns ...func(*elasticache.Options)) (*elasticache.DescribeReplicationGroupsOutput, error)
+	DescribeCacheClusters(ctx context.Context, params *elasticache.DescribeCacheClustersInput, optFns ...func(*elasticache.Options)) (*elasticache.DescribeCacheClustersOutput, error)
+}
+
+type CacheManager struct {
+	elasticacheClient ElastiCacheClient
+	bastionFinder     *BastionFinder
+	region            string
+}
+
+type CacheEndpoint struct {
+	Identifier        string
+	Endpoint          string
+	Port              int32
+	Engine            string // "redis", "valkey" or "memcached"
+	EndpointType      string // "primary", "reader", "configuration", "node"
+	Status            string
+	TransitEncryption bool     // Clients must connect with TLS
+	ClusterMode       bool     // Redis/Valkey cluster mode, clients receive MOVED redirects to shard nodes
+	SecurityGroupIds  []string // From the member cache clusters
+}
+
+// IsAvailable reports whether the endpoint can currently accept connections
+func (e CacheEndpoint) IsAvailable() bool {
+	return e.Status == "available"
+}
+
+type CacheManagerOptions struct {
+	ElastiCacheClient ElastiCacheClient
+	EC2Client         EC2Client
+	Region            string
+}
+
+func NewCacheManager(ctx context.Context, opts ...CacheManagerOptions) (*CacheManager, error) {
+	if len(opts) > 0 && opts[0].ElastiCacheClient != nil {
+		// Use provided clients (for testing)
+		return &CacheManager{
+			elasticacheClient: opts[0].ElastiCacheClient,
+			bastionFinder:     NewBastionFinder(opts[0].EC2Client, opts[0].Region),
+			region:            opts[0].Region,
+		}, nil
+	}
+
+	// Production path
+	cfg, err := awscconfig.LoadAWSConfigWithProfile(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &CacheManager{
+		elasticacheClient: elasticache.NewFromConfig(cfg),
+		bastionFinder:     NewBastionFinder(ec2.NewFromConfig(cfg), cfg.Region),
+		region:            cfg.Region,
+	}, nil
+}
+
+func (c *CacheManager) RunConnect(ctx context.Context, name string, localPort int32) error {
+	endpoints, err := c.ListCacheEndpoints(ctx)
+	if err != nil {
+		return fmt.Errorf("error listing ElastiCache clusters: %v", err)
+	}
+
+	if len(endpoints) == 0 {
+		return fmt.Errorf("no ElastiCache clusters found")
+	}
+
+	selectedEndpoint, err := c.selectCacheEndpoint(endpoints, name)
+	if err != nil {
+		return err
+	}
+
+	// Find bastion hosts
+	bastions, err := c.bastionFinder.FindBastionHosts(ctx, BastionTarget{
+		Name:             selectedEndpoint.Identifier,
+		Service:          "ElastiCache",
+		SecurityGroupIds: selectedEndpoint.SecurityGroupIds,
+		Port:             selectedEndpoint.Port,
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(bastions) == 0 {
+		return fmt.Errorf("no bastion hosts available for %s", selectedEndpoint.Identifier)
+	}
+
+	// Use first available bastion
+	bastion := bastions[0]
+	fmt.Printf("Using bastion: %s\n", bastion.Name)
+
+	// Use default local port if not specified
+	if localPort == 0 {
+		localPort = selectedEndpoint.Port
+	}
+
+	printCacheConnectionNotes(selectedEndpoint, localPort)
+
+	return c.StartPortForwarding(ctx, bastion.InstanceId, selectedEndpoint.Endpoint, selectedEndpoint.Port, localPort)
+}
+
+// printCacheConnectionNotes tells users how to connect to the local end of the tunnel
+func printCacheConnectionNotes(endpoint CacheEndpoint, localPort int32) {
+	if endpoint.TransitEncryption {
+		fmt.Printf("\nIn-transit encryption is enabled: connect with TLS, using the endpoint as the server name, e.g.\n")
+		if endpoint.Engine == "memcached" {
+			fmt.Printf("  openssl s_client -connect localhost:%d -servername %s\n", localPort, endpoint.Endpoint)
+		} else {
+			fmt.Printf("  redis-cli --tls --sni %s -h localhost -p %d\n", endpoint.Endpoint, localPort)
+		}
+	}
+	if endpoint.ClusterMode {
+		fmt.Printf("\nCluster mode is enabled: redirects to other shards are not reachable through this tunnel.\n")
+	}
+	fmt.Println()
+}
+
+func (c *CacheManager) selectCacheEndpoint(endpoints []CacheEndpoint, name string) (CacheEndpoint, error) {
+	// If name provided, try to connect directly
+	if name != "" {
+		var target *CacheEndpoint
+		for _, endpoint := range endpoints {
+			if endpoint.Identifier == name {
+				target = &endpoint
+				break
+			}
+		}
+
+		if target == nil {
+			fmt.Printf("ElastiCache cluster '%s' not found. Available clusters:\n\n", name)
+		} else if !target.IsAvailable() {
+			fmt.Printf("ElastiCache cluster '%s' is not available (status: %s). Available clusters:\n\n", name, target.Status)
+		} else {
+			fmt.Printf("Connecting to ElastiCache cluster: %s\n", target.Identifier)
+			fmt.Printf("✓ Selected: %s\n", target.Identifier)
+			return *target, nil
+		}
+	}
+
+	options := make([]string, len(endpoints))
+	selectable := make([]bool, len(endpoints))
+	hasSelectable := false
+	for i, endpoint := range endpoints {
+		options[i] = fmt.Sprintf("%s (%s:%d)", endpoint.Identifier, endpoint.Engine, endpoint.Port)
+		switch endpoint.EndpointType {
+		case "primary":
+			options[i] += " [Primary]"
+		case "reader":
+			options[i] += " [Reader]"
+		case "configuration":
+			options[i] += " [Configuration]"
+		}
+		if endpoint.TransitEncryption {
+			options[i] += " [TLS]"
+		}
+		if !endpoint.IsAvailable() {
+			options[i] += fmt.Sprintf(" - %s", endpoint.Status)
+		}
+		selectable[i] = endpoint.IsAvailable()
+		hasSelectable = hasSelectable || selectable[i]
+	}
+
+	if !hasSelectable {
+		return CacheEndpoint{}, fmt.Errorf("no available ElastiCache clusters found")
+	}
+
+	selectedIndex, err := ui.RunSelectorWithSelectability("Select ElastiCache Cluster:", options, selectable)
+	if err != nil {
+		return CacheEndpoint{}, fmt.Errorf("error selecting cluster: %v", err)
+	}
+	if selectedIndex == -1 {
+		return CacheEndpoint{}, fmt.Errorf("no cluster selected")
+	}
+
+	selected := endpoints[selectedIndex]
+	fmt.Printf("✓ Selected: %s\n", selected.Identifier)
+	return selected, nil
+}
+
+// ListCacheEndpoints returns the endpoints of replication groups (primary and reader, or
+// configuration in cluster mode) and of cache clusters that are not part of a replication group
+func (c *CacheManager) ListCacheEndpoints(ctx context.Context) ([]CacheEndpoint, error) {
+	cacheClusters, err := c.getCacheClusters(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	replicationGroups, err := c.getReplicationGroups(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	clustersById := make(map[string]elasticachetypes.CacheCluster)
+	for _, cluster := range cacheClusters {
+		if cluster.CacheClusterId != nil {
+			clustersById[*cluster.CacheClusterId] = cluster
+		}
+	}
+
+	var endpoints []CacheEndpoint
+	for _, group := range replicationGroups {
+		if group.ReplicationGroupId == nil {
+			continue
+		}
+
+		// Replication groups don't report security groups or engine, their member clusters do
+		var securityGroupIds []string
+		engine := aws.ToString(group.Engine)
+		for _, memberId := range group.MemberClusters {
+			if member, ok := clustersById[memberId]; ok {
+				securityGroupIds = cacheSecurityGroupIds(member.SecurityGroups)
+				if engine == "" {
+					engine = aws.ToString(member.Engine)
+				}
+				break
+			}
+		}
+
+		base := CacheEndpoint{
+			Engine:            engine,
+			Status:            aws.ToString(group.Status),
+			TransitEncryption: aws.ToBool(group.TransitEncryptionEnabled),
+			ClusterMode:       aws.ToBool(group.ClusterEnabled),
+			SecurityGroupIds:  securityGroupIds,
+		}
+		groupId := *group.ReplicationGroupId
+
+		if group.ConfigurationEndpoint != nil {
+			endpoint := base
+			endpoint.Identifier = groupId
+			endpoint.EndpointType = "configuration"
+			endpoint.Endpoint = aws.ToString(group.ConfigurationEndpoint.Address)
+			endpoint.Port = aws.ToInt32(group.ConfigurationEndpoint.Port)
+			endpoints = append(endpoints, endpoint)
+			continue
+		}
+
+		for _, nodeGroup := range group.NodeGroups {
+			if nodeGroup.PrimaryEndpoint != nil {
+				endpoint := base
+				endpoint.Identifier = groupId + " (primary)"
+				endpoint.EndpointType = "primary"
+				endpoint.Endpoint = aws.ToString(nodeGroup.PrimaryEndpoint.Address)
+				endpoint.Port = aws.ToInt32(nodeGroup.PrimaryEndpoint.Port)
+				endpoints = append(endpoints, endpoint)
+			}
+			if nodeGroup.ReaderEndpoint != nil {
+				endpoint := base
+				endpoint.Identifier = groupId + " (reader)"
+				endpoint.EndpointType = "reader"
+				endpoint.Endpoint = aws.ToString(nodeGroup.ReaderEndpoint.Address)
+				endpoint.Port = aws.ToInt32(nodeGroup.ReaderEndpoint.Port)
+				endpoints = append(endpoints, endpoint)
+			}
+		}
+	}
+
+	for _, cluster := range cacheClusters {
+		// Members of replication groups are reached through the group endpoints
+		if cluster.CacheClusterId == nil || cluster.ReplicationGroupId != nil {
+			continue
+		}
+
+		endpoint := CacheEndpoint{
+			Identifier:        *cluster.CacheClusterId,
+			Engine:            aws.ToString(cluster.Engine),
+			Status:            aws.ToString(cluster.CacheClusterStatus),
+			TransitEncryption: aws.ToBool(cluster.TransitEncryptionEnabled),
+			SecurityGroupIds:  cacheSecurityGroupIds(cluster.SecurityGroups),
+		}
+
+		// Memcached clusters have a configuration endpoint, single node Redis clusters only a node endpoint
+		if cluster.ConfigurationEndpoint != nil {
+			endpoint.EndpointType = "configuration"
+			endpoint.Endpoint = aws.ToString(cluster.ConfigurationEndpoint.Address)
+			endpoint.Port = aws.ToInt32(cluster.ConfigurationEndpoint.Port)
+		} else if len(cluster.CacheNodes) > 0 && cluster.CacheNodes[0].Endpoint != nil {
+			endpoint.EndpointType = "node"
+			endpoint.Endpoint = aws.ToString(cluster.CacheNodes[0].Endpoint.Address)
+			endpoint.Port = aws.ToInt32(cluster.CacheNodes[0].Endpoint.Port)
+		} else {
+			// Still being created, listed so the status is visible
+			endpoint.EndpointType = "node"
+		}
+
+		endpoints = append(endpoints, endpoint)
+	}
+
+	sort.SliceStable(endpoints, func(i, j int) bool {
+		return endpoints[i].Identifier < endpoints[j].Identifier
+	})
+
+	return endpoints, nil
+}
+
+func (c *CacheManager) getCacheClusters(ctx context.Context) ([]elasticachetypes.CacheCluster, error) {
+	var allClusters []elasticachetypes.CacheCluster
+	var marker *string
+
+	for {
+		input := &elasticache.DescribeCacheClustersInput{
+			Marker:            marker,
+			ShowCacheNodeInfo: aws.Bool(true),
+		}
+		result, err := c.elasticacheClient.DescribeCacheClusters(ctx, input)
+		if err != nil {
+			if IsAuthError(err) {
+				if shouldReauth, reAuthErr := PromptForReauth(ctx); shouldReauth && reAuthErr == nil {
+					if reloadErr := c.reloadClients(ctx); reloadErr != nil {
+						return nil, reloadErr
+					}
+					result, err = c.elasticacheClient.DescribeCacheClusters(ctx, input)
+					if err != nil {
+						return nil, err
+					}
+				} else {
+					return nil, err
+				}
+			} else {
+				return nil, err
+			}
+		}
+
+		allClusters = append(allClusters, result.CacheClusters...)
+
+		if result.Marker == nil {
+			break
+		}
+		marker = result.Marker
+	}
+
+	return allClusters, nil
+}
+
+func (c *CacheManager) getReplicationGroups(ctx context.Context) ([]elasticachetypes.ReplicationGroup, error) {
+	var allGroups []elasticachetypes.ReplicationGroup
+	var marker *string
+
+	for {
+		result, err := c.elasticacheClient.DescribeReplicationGroups(ctx, &elasticache.DescribeReplicationGroupsInput{
+			Marker: marker,
+		})
+		if err != nil {
+			if IsAuthError(err) {
+				if shouldReauth, reAuthErr := PromptForReauth(ctx); shouldReauth && reAuthErr == nil {
+					if reloadErr := c.reloadClients(ctx); reloadErr != nil {
+						return nil, reloadErr
+					}
+					result, err = c.elasticacheClient.DescribeReplicationGroups(ctx, &elasticache.DescribeReplicationGroupsInput{
+						Marker: marker,
+					})
+					if err != nil {
+						return nil, err
+					}
+				} else {
+					return nil, err
+				}
+			} else {
+				return nil, err
+			}
+		}
+
+		allGroups = append(allGroups, result.ReplicationGroups...)
+
+		if result.Marker == nil {
+			break
+		}
+		marker = result.Marker
+	}
+
+	return allGroups, nil
+}
+
+func cacheSecurityGroupIds(memberships []elasticachetypes.SecurityGroupMembership) []string {
+	var ids []string
+	for _, sg := range memberships {
+		if sg.SecurityGroupId != nil {
+			ids = append(ids, *sg.SecurityGroupId)
+		}
+	}
+	return ids
+}
+
+func (c *CacheManager) StartPortForwarding(ctx context.Context, bastionId, cacheEndpoint string, cachePort, localPort int32) error {
+	// Create port forwarder
+	cfg, err := awscconfig.LoadAWSConfigWithProfile(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load AWS config: %w", err)
+	}
+
+	pf := NewExternalPluginForwarder(cfg)
+
+	fmt.Printf("Starting port forwarding via %s...\n", bastionId)
+
+	// Start port forwarding to remote host through bastion
+	return pf.StartPortForwardingToRemoteHost(ctx, bastionId, cacheEndpoint, int(cachePort), int(localPort))
+}
+
+func (c *CacheManager) reloadClients(ctx context.Context) error {
+	cfg, err := awscconfig.LoadAWSConfigWithProfile(ctx)
+	if err != nil {
+		return err
+	}
+
+	c.elasticacheClient = elasticache.NewFromConfig(cfg)
+	c.bastionFinder = NewBastionFinder(ec2.NewFromConfig(cfg), cfg.Region)
+	c.region = cfg.Region
+
+	return nil
+}
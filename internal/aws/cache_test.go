@@ -0,0 +1,179 @@
+package aws
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/elasticache"
+	elasticachetypes "github.com/aws/aws-sdk-go-v2/service/elasticache/types"
+	"github.com/blontic/awsc/internal/aws/mocks"
+	"go.uber.org/mock/gomock"
+)
+
+func TestNewCacheManager(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	manager, err := NewCacheManager(context.Background(), CacheManagerOptions{
+		ElastiCacheClient: mocks.NewMockElastiCacheClient(ctrl),
+		EC2Client:         mocks.NewMockEC2Client(ctrl),
+		Region:            "us-east-1",
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if manager.region != "us-east-1" {
+		t.Errorf("Expected region us-east-1, got %s", manager.region)
+	}
+	if manager.bastionFinder == nil {
+		t.Error("Expected bastion finder to be created")
+	}
+}
+
+func TestCacheManager_ListCacheEndpoints(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCache := mocks.NewMockElastiCacheClient(ctrl)
+	manager, err := NewCacheManager(context.Background(), CacheManagerOptions{
+		ElastiCacheClient: mockCache,
+		Region:            "us-east-1",
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error creating manager: %v", err)
+	}
+
+	mockCache.EXPECT().
+		DescribeCacheClusters(gomock.Any(), gomock.Any()).
+		Return(&elasticache.DescribeCacheClustersOutput{
+			CacheClusters: []elasticachetypes.CacheCluster{
+				{
+					CacheClusterId:     aws.String("sessions-001"),
+					ReplicationGroupId: aws.String("sessions"),
+					Engine:             aws.String("valkey"),
+					CacheClusterStatus: aws.String("available"),
+					SecurityGroups: []elasticachetypes.SecurityGroupMembership{
+						{SecurityGroupId: aws.String("sg-sessions")},
+					},
+				},
+				{
+					CacheClusterId:     aws.String("memcache"),
+					Engine:             aws.String("memcached"),
+					CacheClusterStatus: aws.String("available"),
+					ConfigurationEndpoint: &elasticachetypes.Endpoint{
+						Address: aws.String("memcache.cfg.use1.cache.amazonaws.com"),
+						Port:    aws.Int32(11211),
+					},
+					SecurityGroups: []elasticachetypes.SecurityGroupMembership{
+						{SecurityGroupId: aws.String("sg-memcache")},
+					},
+				},
+				{
+					CacheClusterId:     aws.String("new-redis"),
+					Engine:             aws.String("redis"),
+					CacheClusterStatus: aws.String("creating"),
+				},
+			},
+		}, nil).
+		Times(1)
+
+	mockCache.EXPECT().
+		DescribeReplicationGroups(gomock.Any(), gomock.Any()).
+		Return(&elasticache.DescribeReplicationGroupsOutput{
+			ReplicationGroups: []elasticachetypes.ReplicationGroup{
+				{
+					ReplicationGroupId:       aws.String("sessions"),
+					Status:                   aws.String("available"),
+					MemberClusters:           []string{"sessions-001"},
+					TransitEncryptionEnabled: aws.Bool(true),
+					NodeGroups: []elasticachetypes.NodeGroup{
+						{
+							PrimaryEndpoint: &elasticachetypes.Endpoint{
+								Address: aws.String("master.sessions.use1.cache.amazonaws.com"),
+								Port:    aws.Int32(6379),
+							},
+							ReaderEndpoint: &elasticachetypes.Endpoint{
+								Address: aws.String("replica.sessions.use1.cache.amazonaws.com"),
+								Port:    aws.Int32(6379),
+							},
+						},
+					},
+				},
+				{
+					ReplicationGroupId: aws.String("queue"),
+					Engine:             aws.String("redis"),
+					Status:             aws.String("available"),
+					ClusterEnabled:     aws.Bool(true),
+					ConfigurationEndpoint: &elasticachetypes.Endpoint{
+						Address: aws.String("clustercfg.queue.use1.cache.amazonaws.com"),
+						Port:    aws.Int32(6379),
+					},
+				},
+			},
+		}, nil).
+		Times(1)
+
+	endpoints, err := manager.ListCacheEndpoints(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := []struct {
+		identifier   string
+		endpointType string
+		engine       string
+	}{
+		{"memcache", "configuration", "memcached"},
+		{"new-redis", "node", "redis"},
+		{"queue", "configuration", "redis"},
+		{"sessions (primary)", "primary", "valkey"},
+		{"sessions (reader)", "reader", "valkey"},
+	}
+
+	if len(endpoints) != len(expected) {
+		t.Fatalf("Expected %d endpoints, got %d: %+v", len(expected), len(endpoints), endpoints)
+	}
+
+	for i, e := range expected {
+		if endpoints[i].Identifier != e.identifier || endpoints[i].EndpointType != e.endpointType || endpoints[i].Engine != e.engine {
+			t.Errorf("Endpoint %d: expected %s/%s/%s, got %s/%s/%s", i, e.identifier, e.endpointType, e.engine,
+				endpoints[i].Identifier, endpoints[i].EndpointType, endpoints[i].Engine)
+		}
+	}
+
+	primary := endpoints[3]
+	if !primary.TransitEncryption {
+		t.Error("Expected transit encryption on sessions replication group")
+	}
+	if len(primary.SecurityGroupIds) != 1 || primary.SecurityGroupIds[0] != "sg-sessions" {
+		t.Errorf("Expected security groups from member cluster, got %v", primary.SecurityGroupIds)
+	}
+	if !endpoints[2].ClusterMode {
+		t.Error("Expected cluster mode on queue replication group")
+	}
+	if endpoints[1].IsAvailable() {
+		t.Error("Expected creating cluster to be unavailable")
+	}
+}
+
+func TestCacheManager_selectCacheEndpoint_ByName(t *testing.T) {
+	manager := &CacheManager{}
+
+	endpoints := []CacheEndpoint{
+		{Identifier: "sessions (primary)", Engine: "valkey", Port: 6379, EndpointType: "primary", Status: "available"},
+		{Identifier: "stopped", Engine: "redis", Port: 6379, EndpointType: "node", Status: "snapshotting"},
+	}
+
+	selected, err := manager.selectCacheEndpoint(endpoints, "sessions (primary)")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if selected.Identifier != "sessions (primary)" {
+		t.Errorf("Expected sessions (primary), got %s", selected.Identifier)
+	}
+
+	if _, err := manager.selectCacheEndpoint(endpoints[1:], ""); err == nil {
+		t.Error("Expected error when no endpoints are available")
+	}
+}
@@ -7,47 +7,69 @@ import (
 	"strings"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/autoscaling"
 	"github.com/aws/aws-sdk-go-v2/service/ec2"
 	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/aws-sdk-go-v2/service/ec2instanceconnect"
 	"github.com/aws/aws-sdk-go-v2/service/ssm"
-	ssmtypes "github.com/aws/aws-sdk-go-v2/service/ssm/types"
 	awscconfig "github.com/blontic/awsc/internal/config"
+	"github.com/blontic/awsc/internal/recording"
 	"github.com/blontic/awsc/internal/ui"
 )
 
 // SSMClient interface for mocking
 type SSMClient interface {
 	DescribeInstanceInformation(ctx context.Context, params *ssm.DescribeInstanceInformationInput, optFns ...func(*ssm.Options)) (*ssm.DescribeInstanceInformationOutput, error)
+	SendCommand(ctx context.Context, params *ssm.SendCommandInput, optFns ...func(*ssm.Options)) (*ssm.SendCommandOutput, error)
+	GetCommandInvocation(ctx context.Context, params *ssm.GetCommandInvocationInput, optFns ...func(*ssm.Options)) (*ssm.GetCommandInvocationOutput, error)
 }
 
 type EC2Manager struct {
-	ec2Client EC2Client
-	ssmClient SSMClient
-	region    string
+	ec2Client             EC2Client
+	ssmClient             SSMClient
+	instanceConnectClient EC2InstanceConnectClient
+	autoScalingClient     AutoScalingClient
+	region                string
+	imageCache            map[string]imageOS
+	filters               []types.Filter // Applied to every instance list, see SetListOptions
+	tagColumns            []string
+	nonInteractive        bool // Fail on auth errors instead of prompting, see SetNonInteractive
 }
 
 type EC2Instance struct {
-	InstanceId   string
-	Name         string
-	InstanceType string
-	State        string
-	Platform     string
-	IsSelectable bool
+	InstanceId       string
+	Name             string
+	InstanceType     string
+	State            string
+	Platform         string
+	OSName           string // e.g. "Ubuntu 22.04", from the SSM agent or the AMI when known
+	ImageId          string
+	PrivateIp        string
+	VpcId            string
+	SecurityGroupIds []string
+	Transport        string // TransportEICE when reached through an EC2 Instance Connect Endpoint
+	Tags             map[string]string
+	PingStatus       string // SSM agent status, e.g. Online or ConnectionLost
+	IsSelectable     bool
 }
 
 type EC2ManagerOptions struct {
-	EC2Client EC2Client
-	SSMClient SSMClient
-	Region    string
+	EC2Client             EC2Client
+	SSMClient             SSMClient
+	InstanceConnectClient EC2InstanceConnectClient
+	AutoScalingClient     AutoScalingClient
+	Region                string
 }
 
 func NewEC2Manager(ctx context.Context, opts ...EC2ManagerOptions) (*EC2Manager, error) {
 	if len(opts) > 0 && opts[0].EC2Client != nil {
 		// Use provided clients (for testing)
 		return &EC2Manager{
-			ec2Client: opts[0].EC2Client,
-			ssmClient: opts[0].SSMClient,
-			region:    opts[0].Region,
+			ec2Client:             opts[0].EC2Client,
+			ssmClient:             opts[0].SSMClient,
+			instanceConnectClient: opts[0].InstanceConnectClient,
+			autoScalingClient:     opts[0].AutoScalingClient,
+			region:                opts[0].Region,
 		}, nil
 	}
 
@@ -58,21 +80,48 @@ func NewEC2Manager(ctx context.Context, opts ...EC2ManagerOptions) (*EC2Manager,
 	}
 
 	return &EC2Manager{
-		ec2Client: ec2.NewFromConfig(cfg),
-		ssmClient: ssm.NewFromConfig(cfg),
-		region:    cfg.Region,
+		ec2Client:             ec2.NewFromConfig(cfg),
+		ssmClient:             ssm.NewFromConfig(cfg),
+		instanceConnectClient: ec2instanceconnect.NewFromConfig(cfg),
+		autoScalingClient:     autoscaling.NewFromConfig(cfg),
+		region:                cfg.Region,
 	}, nil
 }
 
-func (e *EC2Manager) RunConnect(ctx context.Context, instanceId string) error {
+// ConnectOptions controls optional behaviour of RunConnect. Document, Parameters and User
+// override the ec2.sessions config defaults.
+type ConnectOptions struct {
+	Key        SSHKeyOptions       // Used for connections through an EC2 Instance Connect Endpoint
+	Record     bool                // Record the session to an asciicast file in ~/.awsc/recordings
+	Document   string              // Session document to start instead of the default shell
+	Parameters map[string][]string // Parameters for Document
+	User       string              // Run-as user for SSM sessions, or the SSH user through an endpoint
+	Name       string              // Connect to the instance with this Name tag, or matching it as a glob
+	Group      string              // Connect to a healthy member of this Auto Scaling group
+	Random     bool                // Pick a random healthy group member instead of the first
+}
+
+// RunConnect starts a shell on the instance: an SSM session, or SSH through an EC2
+// Instance Connect Endpoint for instances without the SSM agent
+func (e *EC2Manager) RunConnect(ctx context.Context, instanceId string, opts ...ConnectOptions) error {
+	var connectOpts ConnectOptions
+	if len(opts) > 0 {
+		connectOpts = opts[0]
+	}
+
 	// Get all instances first
 	allInstances, err := e.ListAllInstances(ctx)
 	if err != nil {
 		return fmt.Errorf("error listing EC2 instances: %v", err)
 	}
+	endpoints := e.markInstanceConnectInstances(ctx, allInstances)
 
 	if len(allInstances) == 0 {
-		return fmt.Errorf("no EC2 instances found")
+		return fmt.Errorf("no EC2 instances found%s", e.filterHint())
+	}
+
+	if connectOpts.Name != "" || connectOpts.Group != "" {
+		return e.connectMatching(ctx, allInstances, endpoints, connectOpts)
 	}
 
 	// If instance ID provided, try to connect directly
@@ -87,9 +136,12 @@ func (e *EC2Manager) RunConnect(ctx context.Context, instanceId string) error {
 
 		if targetInstance != nil && targetInstance.IsSelectable {
 			fmt.Printf("Connecting to instance: %s (%s)\n", targetInstance.Name, targetInstance.InstanceId)
+			return e.connectInstance(ctx, *targetInstance, endpoints, connectOpts)
+		}
 
-			// Start SSM session for all instances
-			return e.StartSSMSession(ctx, targetInstance.InstanceId)
+		if targetInstance != nil && targetInstance.State == "stopped" {
+			fmt.Printf("Instance %s (%s) is stopped.\n", targetInstance.Name, targetInstance.InstanceId)
+			return e.startAndConnect(ctx, *targetInstance, connectOpts)
 		}
 
 		// Instance not found or not selectable - show error and fall through to list
@@ -105,16 +157,16 @@ func (e *EC2Manager) RunConnect(ctx context.Context, instanceId string) error {
 	if err != nil {
 		return fmt.Errorf("error listing EC2 instances: %v", err)
 	}
+	applyInstanceConnect(instances, endpoints)
 
 	if len(instances) == 0 {
-		return fmt.Errorf("no EC2 instances found")
+		return fmt.Errorf("no EC2 instances found%s", e.filterHint())
 	}
 
-	// Check if any instances are selectable and categorize them
+	// Check if any instances are selectable or can be started
 	hasSelectable := false
 	runningInstances := 0
 	stoppedInstances := 0
-	var stoppedInstanceNames []string
 
 	for _, instance := range instances {
 		if instance.IsSelectable {
@@ -124,55 +176,90 @@ func (e *EC2Manager) RunConnect(ctx context.Context, instanceId string) error {
 			runningInstances++
 		} else if instance.State == "stopped" {
 			stoppedInstances++
-			stoppedInstanceNames = append(stoppedInstanceNames, instance.Name)
 		}
 	}
 
 	if !hasSelectable {
-		// Show stopped instances if any exist
 		if stoppedInstances > 0 {
-			fmt.Printf("\nFound %d stopped EC2 instance(s):\n", stoppedInstances)
-			for _, name := range stoppedInstanceNames {
-				fmt.Printf("- %s (stopped)\n", name)
-			}
-			fmt.Printf("\n")
-		}
-
-		if runningInstances == 0 {
+			// The selector can still start one of them
+			fmt.Printf("No running EC2 instances with SSM agent found, but %d stopped instance(s) can be started.\n\n", stoppedInstances)
+		} else if runningInstances == 0 {
 			fmt.Printf("No running EC2 instances found in region %s.\n", e.region)
 			fmt.Printf("To use EC2 sessions, you need a running EC2 instance with:\n")
 			fmt.Printf("- SSM agent installed and configured\n")
 			fmt.Printf("- Proper IAM permissions for SSM\n")
-			if stoppedInstances > 0 {
-				return fmt.Errorf("no running EC2 instances with SSM agent found - %d stopped instances available", stoppedInstances)
-			}
 			return fmt.Errorf("no running EC2 instances found in region %s", e.region)
 		} else {
 			fmt.Printf("Found %d running EC2 instances but none have SSM agent configured.\n", runningInstances)
 			fmt.Printf("Please ensure your instances have:\n")
 			fmt.Printf("- SSM agent installed and running\n")
 			fmt.Printf("- Proper IAM role with SSM permissions\n")
+			fmt.Printf("Or an EC2 Instance Connect Endpoint in their VPC.\n")
 			return fmt.Errorf("no running EC2 instances with SSM agent found")
 		}
 	}
 
-	// Select instance
-	selectedInstance, err := e.selectInstance("Select EC2 Instance:", instances)
+	// Select instance, or press s (ctrl+s while filtering) on a stopped one to start it
+	selectedInstance, start, err := e.selectInstanceOrStart("Select EC2 Instance:", instances)
 	if err != nil {
 		return err
 	}
+	if start {
+		return e.startAndConnect(ctx, *selectedInstance, connectOpts)
+	}
+
+	return e.connectInstance(ctx, *selectedInstance, endpoints, connectOpts)
+}
 
-	// Start SSM session for all instances
-	return e.StartSSMSession(ctx, selectedInstance.InstanceId)
+func (e *EC2Manager) connectInstance(ctx context.Context, instance EC2Instance, endpoints map[string][]InstanceConnectEndpoint, opts ConnectOptions) error {
+	document, user, err := e.sessionDocument(instance, opts)
+	if err != nil {
+		return err
+	}
+
+	var endpoint InstanceConnectEndpoint
+	if instance.Transport == TransportEICE {
+		if endpoint, err = e.instanceConnectEndpoint(ctx, instance, endpoints, 22); err != nil {
+			return err
+		}
+	}
+
+	var rec *recording.Recorder
+	if opts.Record {
+		if rec, err = e.startRecording(instance, user); err != nil {
+			return err
+		}
+		defer e.stopRecording(rec)
+	}
+
+	if instance.Transport == TransportEICE {
+		key := opts.Key
+		key.OSUser = user
+		return e.startInstanceConnectSSH(ctx, instance, endpoint, key, rec)
+	}
+
+	if document.Name != "" {
+		fmt.Printf("Starting session with %s\n", describeDocument(document))
+	}
+	return e.startSession(ctx, instance.InstanceId, document, rec)
 }
 
-func (e *EC2Manager) RunRDP(ctx context.Context, instanceId string, localPort int32) error {
+// RunRDP forwards localPort to RDP on a Windows instance, optionally decrypting the
+// Administrator password and launching an RDP client first
+func (e *EC2Manager) RunRDP(ctx context.Context, instanceId string, localPort int32, opts ...RDPOptions) error {
+	var rdpOpts RDPOptions
+	if len(opts) > 0 {
+		rdpOpts = opts[0]
+	}
+
 	// Get all instances first
 	allInstances, err := e.ListAllInstances(ctx)
 	if err != nil {
 		return fmt.Errorf("error listing EC2 instances: %v", err)
 	}
 
+	endpoints := e.markInstanceConnectInstances(ctx, allInstances)
+
 	// Filter for Windows instances (include stopped ones but mark as non-selectable)
 	var windowsInstances []EC2Instance
 	for _, instance := range allInstances {
@@ -184,7 +271,7 @@ func (e *EC2Manager) RunRDP(ctx context.Context, instanceId string, localPort in
 	}
 
 	if len(windowsInstances) == 0 {
-		return fmt.Errorf("no Windows EC2 instances found")
+		return fmt.Errorf("no Windows EC2 instances found%s", e.filterHint())
 	}
 
 	// If instance ID provided, try to connect directly
@@ -199,7 +286,7 @@ func (e *EC2Manager) RunRDP(ctx context.Context, instanceId string, localPort in
 
 		if targetInstance != nil && targetInstance.IsSelectable {
 			fmt.Printf("Starting RDP to instance: %s (%s)\n", targetInstance.Name, targetInstance.InstanceId)
-			return e.startRDPPortForwarding(ctx, targetInstance.InstanceId, localPort)
+			return e.startRDP(ctx, *targetInstance, endpoints, localPort, rdpOpts)
 		}
 
 		// Instance not found or not selectable - show error and fall through to list
@@ -217,7 +304,16 @@ func (e *EC2Manager) RunRDP(ctx context.Context, instanceId string, localPort in
 	}
 
 	// Start RDP port forwarding
-	return e.startRDPPortForwarding(ctx, selectedInstance.InstanceId, localPort)
+	return e.startRDP(ctx, *selectedInstance, endpoints, localPort, rdpOpts)
+}
+
+func (e *EC2Manager) startRDP(ctx context.Context, instance EC2Instance, endpoints map[string][]InstanceConnectEndpoint, localPort int32, opts RDPOptions) error {
+	if err := e.prepareRDP(ctx, instance, localPort, opts); err != nil {
+		return err
+	}
+
+	fmt.Printf("Starting RDP port forwarding on localhost:%d...\n", localPort)
+	return e.forwardPorts(ctx, instance, endpoints, []ForwardSpec{{LocalPort: int(localPort), RemoteHost: "localhost", RemotePort: 3389}})
 }
 
 func (e *EC2Manager) ListAllInstances(ctx context.Context) ([]EC2Instance, error) {
@@ -226,17 +322,19 @@ func (e *EC2Manager) ListAllInstances(ctx context.Context) ([]EC2Instance, error
 
 	for {
 		result, err := e.ec2Client.DescribeInstances(ctx, &ec2.DescribeInstancesInput{
+			Filters:   e.filters,
 			NextToken: nextToken,
 		})
 		if err != nil {
 			if IsAuthError(err) {
-				if shouldReauth, reAuthErr := PromptForReauth(ctx); shouldReauth && reAuthErr == nil {
+				if shouldReauth, reAuthErr := e.promptForReauth(ctx); shouldReauth && reAuthErr == nil {
 					// Reload all clients with fresh credentials
 					if reloadErr := e.reloadClients(ctx); reloadErr != nil {
 						return nil, reloadErr
 					}
 					// Retry after re-authentication
 					result, err = e.ec2Client.DescribeInstances(ctx, &ec2.DescribeInstancesInput{
+						Filters:   e.filters,
 						NextToken: nextToken,
 					})
 					if err != nil {
@@ -262,21 +360,34 @@ func (e *EC2Manager) ListAllInstances(ctx context.Context) ([]EC2Instance, error
 	var instances []EC2Instance
 	for _, reservation := range allReservations {
 		for _, inst := range reservation.Instances {
-			isRunning := string(inst.State.Name) == "running"
+			instance := EC2Instance{
+				InstanceId:       *inst.InstanceId,
+				Name:             e.getInstanceName(inst.Tags),
+				InstanceType:     string(inst.InstanceType),
+				State:            string(inst.State.Name),
+				Platform:         e.getPlatform(inst),
+				ImageId:          aws.ToString(inst.ImageId),
+				PrivateIp:        aws.ToString(inst.PrivateIpAddress),
+				VpcId:            aws.ToString(inst.VpcId),
+				SecurityGroupIds: groupIds(inst.SecurityGroups),
+				Tags:             tagMap(inst.Tags),
+			}
+
 			// Only check SSM for running instances to avoid unnecessary API calls
-			hasSSM := isRunning && e.hasSSMAgent(ctx, *inst.InstanceId)
-
-			instances = append(instances, EC2Instance{
-				InstanceId:   *inst.InstanceId,
-				Name:         e.getInstanceName(inst.Tags),
-				InstanceType: string(inst.InstanceType),
-				State:        string(inst.State.Name),
-				Platform:     e.getPlatform(inst),
-				IsSelectable: hasSSM, // Only running instances with SSM are selectable
-			})
+			if instance.State == "running" {
+				if info := e.ssmInstanceInfo(ctx, instance.InstanceId); info != nil {
+					applySSMPlatform(&instance, info)
+					instance.PingStatus = string(info.PingStatus)
+					instance.IsSelectable = true // Only running instances with SSM are selectable
+				}
+			}
+
+			instances = append(instances, instance)
 		}
 	}
 
+	e.applyImagePlatforms(ctx, instances)
+
 	// Sort instances by name
 	sort.Slice(instances, func(i, j int) bool {
 		return instances[i].Name < instances[j].Name
@@ -286,6 +397,11 @@ func (e *EC2Manager) ListAllInstances(ctx context.Context) ([]EC2Instance, error
 }
 
 func (e *EC2Manager) StartSSMSession(ctx context.Context, instanceId string) error {
+	return e.startSession(ctx, instanceId, SessionDocument{}, nil)
+}
+
+// startSession opens an interactive session with document, recording it when rec isn't nil
+func (e *EC2Manager) startSession(ctx context.Context, instanceId string, document SessionDocument, rec *recording.Recorder) error {
 	// Start SSM session using external plugin
 	cfg, err := awscconfig.LoadAWSConfigWithProfile(ctx)
 	if err != nil {
@@ -294,48 +410,33 @@ func (e *EC2Manager) StartSSMSession(ctx context.Context, instanceId string) err
 
 	pf := NewExternalPluginForwarder(cfg)
 
+	if rec != nil {
+		return pf.StartRecordedSession(ctx, instanceId, document, rec)
+	}
+
 	// Start interactive session
-	return pf.StartInteractiveSession(ctx, instanceId)
+	return pf.StartInteractiveSession(ctx, instanceId, document)
 }
 
 func (e *EC2Manager) hasSSMAgent(ctx context.Context, instanceId string) bool {
 	// Check if instance is managed by SSM
-	result, err := e.ssmClient.DescribeInstanceInformation(ctx, &ssm.DescribeInstanceInformationInput{
-		Filters: []ssmtypes.InstanceInformationStringFilter{
-			{
-				Key:    aws.String("InstanceIds"),
-				Values: []string{instanceId},
-			},
-		},
-	})
-	if err != nil {
-		if IsAuthError(err) {
-			if shouldReauth, reAuthErr := PromptForReauth(ctx); shouldReauth && reAuthErr == nil {
-				// Reload all clients with fresh credentials
-				if reloadErr := e.reloadClients(ctx); reloadErr != nil {
-					return false
-				}
-				// Retry after re-authentication
-				result, err = e.ssmClient.DescribeInstanceInformation(ctx, &ssm.DescribeInstanceInformationInput{
-					Filters: []ssmtypes.InstanceInformationStringFilter{
-						{
-							Key:    aws.String("InstanceIds"),
-							Values: []string{instanceId},
-						},
-					},
-				})
-				if err != nil {
-					return false
-				}
-			} else {
-				return false
-			}
-		} else {
-			return false
-		}
+	return e.ssmInstanceInfo(ctx, instanceId) != nil
+}
+
+func groupIds(groups []types.GroupIdentifier) []string {
+	ids := make([]string, 0, len(groups))
+	for _, group := range groups {
+		ids = append(ids, aws.ToString(group.GroupId))
 	}
+	return ids
+}
 
-	return len(result.InstanceInformationList) > 0
+func tagMap(tags []types.Tag) map[string]string {
+	result := make(map[string]string, len(tags))
+	for _, tag := range tags {
+		result[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+	}
+	return result
 }
 
 func (e *EC2Manager) getInstanceName(tags []types.Tag) string {
@@ -353,10 +454,9 @@ func (e *EC2Manager) getPlatform(instance types.Instance) string {
 		return string(instance.Platform)
 	}
 
-	// Check AMI name for Windows indicators
-	if instance.ImageId != nil {
-		// This is a simplified check - in practice you might want to describe the AMI
-		// But we can also check instance metadata or tags
+	// Covers license-included variants like "Windows BYOL" and "Windows with SQL Server Standard"
+	if strings.Contains(strings.ToLower(aws.ToString(instance.PlatformDetails)), "windows") {
+		return "Windows"
 	}
 
 	// Check for Windows-specific instance types or other indicators
@@ -376,21 +476,6 @@ func (e *EC2Manager) getPlatform(instance types.Instance) string {
 	return "Linux"
 }
 
-func (e *EC2Manager) startRDPPortForwarding(ctx context.Context, instanceId string, localPort int32) error {
-	cfg, err := awscconfig.LoadAWSConfigWithProfile(ctx)
-	if err != nil {
-		return fmt.Errorf("failed to load AWS config: %w", err)
-	}
-
-	pf := NewExternalPluginForwarder(cfg)
-	remotePort := 3389
-
-	fmt.Printf("Starting RDP port forwarding on localhost:%d...\n", localPort)
-
-	// Start port forwarding for RDP
-	return pf.StartPortForwardingToRemoteHost(ctx, instanceId, "localhost", int(remotePort), int(localPort))
-}
-
 func (e *EC2Manager) reloadClients(ctx context.Context) error {
 	cfg, err := awscconfig.LoadAWSConfigWithProfile(ctx)
 	if err != nil {
@@ -399,23 +484,36 @@ func (e *EC2Manager) reloadClients(ctx context.Context) error {
 
 	e.ec2Client = ec2.NewFromConfig(cfg)
 	e.ssmClient = ssm.NewFromConfig(cfg)
+	e.instanceConnectClient = ec2instanceconnect.NewFromConfig(cfg)
+	e.autoScalingClient = autoscaling.NewFromConfig(cfg)
 	e.region = cfg.Region
 
 	return nil
 }
 
-func (e *EC2Manager) selectInstance(title string, instances []EC2Instance) (*EC2Instance, error) {
-	// Create instance options for selection
-	instanceOptions := make([]string, len(instances))
-	for i, instance := range instances {
-		instanceOptions[i] = fmt.Sprintf("%s (%s) - %s - %s", instance.Name, instance.InstanceId, instance.Platform, instance.State)
-	}
+// SetNonInteractive makes auth errors fail instead of prompting to log in, for commands
+// whose stdin and stdout belong to another program, like ec2 ssh-proxy
+func (e *EC2Manager) SetNonInteractive() {
+	e.nonInteractive = true
+}
 
-	// Create selectability array
-	selectableOptions := make([]bool, len(instances))
-	for i, instance := range instances {
-		selectableOptions[i] = instance.IsSelectable
+// promptForReauth prompts to log in again unless the manager is non-interactive
+func (e *EC2Manager) promptForReauth(ctx context.Context) (bool, error) {
+	if e.nonInteractive {
+		return false, nil
 	}
+	return PromptForReauth(ctx)
+}
+
+// bastionFinder returns a BastionFinder using the manager's EC2 client and interactivity
+func (e *EC2Manager) bastionFinder() *BastionFinder {
+	finder := NewBastionFinder(e.ec2Client, e.region)
+	finder.nonInteractive = e.nonInteractive
+	return finder
+}
+
+func (e *EC2Manager) selectInstance(title string, instances []EC2Instance) (*EC2Instance, error) {
+	instanceOptions, selectableOptions := instanceChoices(instances, e.tagColumns)
 
 	// Interactive instance selection
 	selectedIndex, err := ui.RunSelectorWithSelectability(title, instanceOptions, selectableOptions)
@@ -430,3 +528,46 @@ func (e *EC2Manager) selectInstance(title string, instances []EC2Instance) (*EC2
 	fmt.Printf("✓ Selected: %s\n", selectedInstance.Name)
 	return &selectedInstance, nil
 }
+
+// selectInstanceOrStart is selectInstance with an s key that picks a stopped instance
+// to start instead; start reports whether it was used
+func (e *EC2Manager) selectInstanceOrStart(title string, instances []EC2Instance) (*EC2Instance, bool, error) {
+	instanceOptions, selectableOptions := instanceChoices(instances, e.tagColumns)
+
+	stopped := make([]bool, len(instances))
+	for i, instance := range instances {
+		stopped[i] = instance.State == "stopped"
+	}
+
+	selectedIndex, action, err := ui.RunSelectorWithActions(title, instanceOptions, selectableOptions, []ui.SelectorAction{
+		{Key: startInstanceKey, Label: "start a stopped instance", Enabled: stopped},
+		{Key: startInstanceFilteredKey, Label: "start it after typing a filter", Enabled: stopped},
+	})
+	if err != nil {
+		return nil, false, fmt.Errorf("error selecting instance: %v", err)
+	}
+	if selectedIndex == -1 {
+		return nil, false, fmt.Errorf("no instance selected")
+	}
+
+	selectedInstance := instances[selectedIndex]
+	fmt.Printf("✓ Selected: %s\n", selectedInstance.Name)
+	return &selectedInstance, action == startInstanceKey || action == startInstanceFilteredKey, nil
+}
+
+// instanceChoices returns the selector labels and selectability for instances
+func instanceChoices(instances []EC2Instance, tagColumns []string) ([]string, []bool) {
+	instanceOptions := make([]string, len(instances))
+	selectableOptions := make([]bool, len(instances))
+	for i, instance := range instances {
+		instanceOptions[i] = fmt.Sprintf("%s (%s) - %s - %s", instance.Name, instance.InstanceId, instance.platformLabel(), instance.State)
+		if tags := tagColumnsLabel(instance, tagColumns); tags != "" {
+			instanceOptions[i] += " - " + tags
+		}
+		if instance.Transport == TransportEICE {
+			instanceOptions[i] += " [EICE]"
+		}
+		selectableOptions[i] = instance.IsSelectable
+	}
+	return instanceOptions, selectableOptions
+}
@@ -394,6 +394,21 @@ func TestEC2Manager_getPlatform(t *testing.T) {
 			},
 			expected: "Windows",
 		},
+		{
+			name:     "Windows BYOL detected from PlatformDetails",
+			instance: types.Instance{PlatformDetails: aws.String("Windows BYOL")},
+			expected: "Windows",
+		},
+		{
+			name:     "SQL Server detected from PlatformDetails",
+			instance: types.Instance{PlatformDetails: aws.String("Windows with SQL Server Standard")},
+			expected: "Windows",
+		},
+		{
+			name:     "Linux PlatformDetails",
+			instance: types.Instance{PlatformDetails: aws.String("Red Hat Enterprise Linux")},
+			expected: "Linux",
+		},
 		{
 			name: "Windows detected from OS tag",
 			instance: types.Instance{
@@ -552,15 +567,17 @@ func TestEC2Manager_RunConnect_WithStoppedInstances(t *testing.T) {
 		}, nil).
 		Times(2) // Called twice - once for initial list, once for final list
 
+	// Stopped instances can be started from the list, so it is shown instead of an error
+	// (reading the choice fails without a terminal)
 	err = manager.RunConnect(context.Background(), "")
 	if err == nil {
-		t.Error("Expected error when only stopped instances found")
+		t.Fatal("Expected error without a terminal to select from")
 	}
-	if !strings.Contains(err.Error(), "no running EC2 instances with SSM agent found") {
-		t.Errorf("Expected error about stopped instances, got: %v", err)
+	if strings.Contains(err.Error(), "no running EC2 instances") {
+		t.Errorf("Expected the instance list to be offered, got: %v", err)
 	}
-	if !strings.Contains(err.Error(), "2 stopped instances available") {
-		t.Errorf("Expected error to mention stopped instances count, got: %v", err)
+	if !strings.Contains(err.Error(), "error selecting instance") {
+		t.Errorf("Expected error from the instance selector, got: %v", err)
 	}
 }
 
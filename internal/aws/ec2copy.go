@@ -0,0 +1,351 @@
+package aws
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	ssmtypes "github.com/aws/aws-sdk-go-v2/service/ssm/types"
+)
+
+// copyChunkSize is the number of file bytes sent per Run Command. Base64 encoded it stays
+// well inside the SendCommand parameter limit and the 24,000 character output limit.
+var copyChunkSize = 16 * 1024
+
+// copyUploadBatch is the number of chunks written per Run Command when uploading
+var copyUploadBatch = 4
+
+// copyMaxDownloadSize is the largest file downloaded. Each chunk needs its own Run Command
+// to stay inside the output limit, so larger files would take too many invocations.
+var copyMaxDownloadSize int64 = 8 << 20
+
+var remotePathPattern = regexp.MustCompile(`^(i-[0-9a-f]+):(.+)$`)
+
+// CopyPath is one side of a copy: a local path, or a path on an instance
+type CopyPath struct {
+	InstanceId string // Empty for local paths
+	Path       string
+}
+
+// ParseCopyPath parses "i-0123456789abcdef0:/remote/path" or a local path
+func ParseCopyPath(s string) CopyPath {
+	if m := remotePathPattern.FindStringSubmatch(s); m != nil {
+		return CopyPath{InstanceId: m[1], Path: m[2]}
+	}
+	return CopyPath{Path: s}
+}
+
+// RunCopy copies a file between this machine and an instance in either direction using
+// SSM Run Command, so no S3 bucket or inbound ports are needed
+func (e *EC2Manager) RunCopy(ctx context.Context, src, dst string) error {
+	source := ParseCopyPath(src)
+	destination := ParseCopyPath(dst)
+
+	switch {
+	case source.InstanceId == "" && destination.InstanceId != "":
+		if err := e.checkCopyTarget(ctx, destination.InstanceId); err != nil {
+			return err
+		}
+		return e.upload(ctx, source.Path, destination)
+	case source.InstanceId != "" && destination.InstanceId == "":
+		if err := e.checkCopyTarget(ctx, source.InstanceId); err != nil {
+			return err
+		}
+		return e.download(ctx, source, destination.Path)
+	default:
+		return fmt.Errorf("exactly one of source and destination must be an instance path like i-0123456789abcdef0:/tmp/file")
+	}
+}
+
+func (e *EC2Manager) checkCopyTarget(ctx context.Context, instanceId string) error {
+	instances, err := e.findRunTargets(ctx, RunTargets{InstanceIds: []string{instanceId}})
+	if err != nil {
+		return fmt.Errorf("error describing instance %s: %v", instanceId, err)
+	}
+	if len(instances) == 0 {
+		return fmt.Errorf("instance %s is not running", instanceId)
+	}
+	info := e.ssmInstanceInfo(ctx, instanceId)
+	if info == nil {
+		return fmt.Errorf("instance %s does not have the SSM agent available", instanceId)
+	}
+	instance := instances[0]
+	applySSMPlatform(&instance, info)
+	if strings.EqualFold(instance.Platform, "windows") {
+		return fmt.Errorf("ec2 cp supports Linux instances only")
+	}
+	return nil
+}
+
+func (e *EC2Manager) upload(ctx context.Context, localPath string, remote CopyPath) error {
+	file, err := os.Open(localPath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+	if !info.Mode().IsRegular() {
+		return fmt.Errorf("%s is not a regular file", localPath)
+	}
+
+	// Copying onto a directory keeps the local file name
+	remotePath, err := e.runShellScript(ctx, remote.InstanceId, fmt.Sprintf(
+		`if [ -d %[1]s ]; then printf '%%s' %[1]s/%[2]s; else printf '%%s' %[1]s; fi`,
+		shellQuote(strings.TrimSuffix(remote.Path, "/")), shellQuote(filepath.Base(localPath))))
+	if err != nil {
+		return fmt.Errorf("failed to resolve remote path: %w", err)
+	}
+	if remotePath == "" {
+		return fmt.Errorf("remote path %q does not name a file", remote.Path)
+	}
+	partPath := shellQuote(remotePath + ".awsc-part")
+
+	fmt.Printf("Uploading %s to %s:%s\n", localPath, remote.InstanceId, remotePath)
+	fmt.Printf("Note: the file content is stored in the SSM command history and CloudTrail\n")
+
+	hash := sha256.New()
+	progress := newCopyProgress(info.Size())
+	buf := make([]byte, copyChunkSize)
+	redirect := ">"
+
+	// Several chunks go in one Run Command, and set -e stops at the first that fails
+	batch := []string{"set -e"}
+	batchBytes := 0
+
+	for {
+		n, readErr := io.ReadFull(file, buf)
+		eof := readErr == io.EOF || readErr == io.ErrUnexpectedEOF
+		if readErr != nil && !eof {
+			return readErr
+		}
+		if n > 0 || redirect == ">" {
+			hash.Write(buf[:n])
+			batch = append(batch, fmt.Sprintf("printf '%%s' '%s' | base64 -d %s %s", base64.StdEncoding.EncodeToString(buf[:n]), redirect, partPath))
+			batchBytes += n
+			redirect = ">>"
+		}
+		if len(batch) > copyUploadBatch || (eof && len(batch) > 1) {
+			if _, err := e.runShellScript(ctx, remote.InstanceId, batch...); err != nil {
+				e.runShellScript(ctx, remote.InstanceId, "rm -f "+partPath)
+				return fmt.Errorf("failed to upload chunk: %w", err)
+			}
+			progress.add(batchBytes)
+			batch, batchBytes = batch[:1], 0
+		}
+		if eof {
+			break
+		}
+	}
+	progress.done()
+
+	checksum := hex.EncodeToString(hash.Sum(nil))
+	_, err = e.runShellScript(ctx, remote.InstanceId, fmt.Sprintf(
+		`sum=$(sha256sum %[1]s | cut -d' ' -f1); if [ "$sum" = %[2]s ]; then mv -f %[1]s %[3]s; else rm -f %[1]s; echo "checksum mismatch: $sum" >&2; exit 1; fi`,
+		partPath, checksum, shellQuote(remotePath)))
+	if err != nil {
+		return fmt.Errorf("checksum verification failed: %w", err)
+	}
+
+	fmt.Printf("✓ Uploaded %s (sha256 %s)\n", formatBytes(info.Size()), checksum)
+	return nil
+}
+
+func (e *EC2Manager) download(ctx context.Context, remote CopyPath, localPath string) error {
+	quoted := shellQuote(remote.Path)
+	output, err := e.runShellScript(ctx, remote.InstanceId, fmt.Sprintf(
+		`test -f %[1]s || { echo "not a regular file" >&2; exit 1; }; stat -c %%s %[1]s; sha256sum %[1]s | cut -d' ' -f1`, quoted))
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", remote.Path, err)
+	}
+
+	fields := strings.Fields(output)
+	if len(fields) != 2 {
+		return fmt.Errorf("unexpected response reading %s: %q", remote.Path, output)
+	}
+	size, err := strconv.ParseInt(fields[0], 10, 64)
+	if err != nil {
+		return fmt.Errorf("unexpected file size %q", fields[0])
+	}
+	expected := fields[1]
+	if size > copyMaxDownloadSize {
+		return fmt.Errorf("%s is %s, larger than the %s ec2 cp downloads", remote.Path, formatBytes(size), formatBytes(copyMaxDownloadSize))
+	}
+
+	// Copying into a directory keeps the remote file name
+	if info, err := os.Stat(localPath); err == nil && info.IsDir() {
+		localPath = filepath.Join(localPath, path.Base(remote.Path))
+	}
+
+	fmt.Printf("Downloading %s:%s to %s\n", remote.InstanceId, remote.Path, localPath)
+
+	partPath := localPath + ".awsc-part"
+	file, err := os.Create(partPath)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(partPath)
+	defer file.Close()
+
+	hash := sha256.New()
+	progress := newCopyProgress(size)
+	chunks := (size + int64(copyChunkSize) - 1) / int64(copyChunkSize)
+
+	for i := int64(0); i < chunks; i++ {
+		encoded, err := e.runShellScript(ctx, remote.InstanceId, fmt.Sprintf(
+			"dd if=%s bs=%d skip=%d count=1 2>/dev/null | base64 -w0", quoted, copyChunkSize, i))
+		if err != nil {
+			return fmt.Errorf("failed to download chunk: %w", err)
+		}
+		data, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+		if err != nil {
+			return fmt.Errorf("failed to decode chunk: %w", err)
+		}
+		if _, err := file.Write(data); err != nil {
+			return err
+		}
+		hash.Write(data)
+		progress.add(len(data))
+	}
+	progress.done()
+
+	if err := file.Close(); err != nil {
+		return err
+	}
+
+	checksum := hex.EncodeToString(hash.Sum(nil))
+	if checksum != expected {
+		return fmt.Errorf("checksum mismatch: expected %s, got %s (was the file modified during the copy?)", expected, checksum)
+	}
+	if err := os.Rename(partPath, localPath); err != nil {
+		return err
+	}
+
+	fmt.Printf("✓ Downloaded %s (sha256 %s)\n", formatBytes(size), checksum)
+	return nil
+}
+
+// runShellScript runs commands as one script on a Linux instance with AWS-RunShellScript and
+// returns its stdout
+func (e *EC2Manager) runShellScript(ctx context.Context, instanceId string, commands ...string) (string, error) {
+	input := &ssm.SendCommandInput{
+		DocumentName: aws.String("AWS-RunShellScript"),
+		InstanceIds:  []string{instanceId},
+		Parameters:   map[string][]string{"commands": commands},
+		Comment:      aws.String("awsc ec2 cp"),
+	}
+	result, err := e.ssmClient.SendCommand(ctx, input)
+	if err != nil {
+		if IsAuthError(err) {
+			if shouldReauth, reAuthErr := e.promptForReauth(ctx); shouldReauth && reAuthErr == nil {
+				if reloadErr := e.reloadClients(ctx); reloadErr != nil {
+					return "", reloadErr
+				}
+				result, err = e.ssmClient.SendCommand(ctx, input)
+				if err != nil {
+					return "", err
+				}
+			} else {
+				return "", err
+			}
+		} else {
+			return "", err
+		}
+	}
+
+	invocationInput := &ssm.GetCommandInvocationInput{
+		CommandId:  result.Command.CommandId,
+		InstanceId: aws.String(instanceId),
+	}
+	for {
+		invocation, err := e.ssmClient.GetCommandInvocation(ctx, invocationInput)
+		if err != nil && IsAuthError(err) {
+			// A long copy can outlive the credentials, so check on every poll
+			if shouldReauth, reAuthErr := e.promptForReauth(ctx); shouldReauth && reAuthErr == nil {
+				if reloadErr := e.reloadClients(ctx); reloadErr != nil {
+					return "", reloadErr
+				}
+				invocation, err = e.ssmClient.GetCommandInvocation(ctx, invocationInput)
+			}
+		}
+		if err != nil {
+			// The invocation isn't visible for a moment after SendCommand returns
+			var notYet *ssmtypes.InvocationDoesNotExist
+			if !errors.As(err, &notYet) {
+				return "", err
+			}
+		} else if isTerminalInvocationStatus(invocation.Status) {
+			if invocation.Status != ssmtypes.CommandInvocationStatusSuccess {
+				return "", fmt.Errorf("%s (exit code %d): %s", invocation.Status, invocation.ResponseCode, strings.TrimSpace(aws.ToString(invocation.StandardErrorContent)))
+			}
+			return aws.ToString(invocation.StandardOutputContent), nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case <-time.After(commandPollInterval):
+		}
+	}
+}
+
+// shellQuote quotes s for a POSIX shell
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// copyProgress prints a single updating progress line
+type copyProgress struct {
+	total int64
+	sent  int64
+}
+
+func newCopyProgress(total int64) *copyProgress {
+	p := &copyProgress{total: total}
+	p.print()
+	return p
+}
+
+func (p *copyProgress) add(n int) {
+	p.sent += int64(n)
+	p.print()
+}
+
+func (p *copyProgress) print() {
+	percent := 100
+	if p.total > 0 {
+		percent = int(p.sent * 100 / p.total)
+	}
+	fmt.Printf("\r%s / %s (%d%%)", formatBytes(p.sent), formatBytes(p.total), percent)
+}
+
+func (p *copyProgress) done() {
+	fmt.Printf("\n")
+}
+
+func formatBytes(n int64) string {
+	switch {
+	case n >= 1<<20:
+		return fmt.Sprintf("%.1f MiB", float64(n)/(1<<20))
+	case n >= 1<<10:
+		return fmt.Sprintf("%.1f KiB", float64(n)/(1<<10))
+	default:
+		return fmt.Sprintf("%d B", n)
+	}
+}
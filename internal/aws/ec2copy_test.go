@@ -0,0 +1,284 @@
+package aws
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	ssmtypes "github.com/aws/aws-sdk-go-v2/service/ssm/types"
+	"github.com/blontic/awsc/internal/aws/mocks"
+	"go.uber.org/mock/gomock"
+)
+
+func TestParseCopyPath(t *testing.T) {
+	tests := []struct {
+		input string
+		want  CopyPath
+	}{
+		{"i-0123abcd:/tmp/app.log", CopyPath{InstanceId: "i-0123abcd", Path: "/tmp/app.log"}},
+		{"./local.txt", CopyPath{Path: "./local.txt"}},
+		{"C:/logs/app.log", CopyPath{Path: "C:/logs/app.log"}},
+		{"i-0123abcd:", CopyPath{Path: "i-0123abcd:"}},
+	}
+
+	for _, tt := range tests {
+		if got := ParseCopyPath(tt.input); got != tt.want {
+			t.Errorf("ParseCopyPath(%q) = %+v, want %+v", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestShellQuote(t *testing.T) {
+	if got := shellQuote("/tmp/it's here"); got != `'/tmp/it'\''s here'` {
+		t.Errorf("Unexpected quoting %s", got)
+	}
+}
+
+// newCopyTestManager returns a manager whose SSM mock answers each script with respond
+func newCopyTestManager(t *testing.T, respond func(script string) (string, error)) *EC2Manager {
+	ctrl := gomock.NewController(t)
+	t.Cleanup(ctrl.Finish)
+
+	commandPollInterval = 0
+	t.Cleanup(func() { commandPollInterval = time.Second })
+
+	mockEC2 := mocks.NewMockEC2Client(ctrl)
+	mockSSM := mocks.NewMockSSMClient(ctrl)
+
+	mockEC2.EXPECT().
+		DescribeInstances(gomock.Any(), gomock.Any()).
+		Return(&ec2.DescribeInstancesOutput{
+			Reservations: []types.Reservation{{
+				Instances: []types.Instance{{
+					InstanceId: aws.String("i-0123abcd"),
+					State:      &types.InstanceState{Name: types.InstanceStateNameRunning},
+				}},
+			}},
+		}, nil)
+	mockSSM.EXPECT().
+		DescribeInstanceInformation(gomock.Any(), gomock.Any()).
+		Return(&ssm.DescribeInstanceInformationOutput{
+			InstanceInformationList: []ssmtypes.InstanceInformation{{InstanceId: aws.String("i-0123abcd")}},
+		}, nil)
+
+	scripts := map[string]string{}
+	mockSSM.EXPECT().
+		SendCommand(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, input *ssm.SendCommandInput, _ ...func(*ssm.Options)) (*ssm.SendCommandOutput, error) {
+			commandId := strconv.Itoa(len(scripts))
+			scripts[commandId] = strings.Join(input.Parameters["commands"], "\n")
+			return &ssm.SendCommandOutput{Command: &ssmtypes.Command{CommandId: aws.String(commandId)}}, nil
+		}).
+		AnyTimes()
+	mockSSM.EXPECT().
+		GetCommandInvocation(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, input *ssm.GetCommandInvocationInput, _ ...func(*ssm.Options)) (*ssm.GetCommandInvocationOutput, error) {
+			stdout, err := respond(scripts[aws.ToString(input.CommandId)])
+			if err != nil {
+				return &ssm.GetCommandInvocationOutput{
+					Status:               ssmtypes.CommandInvocationStatusFailed,
+					ResponseCode:         1,
+					StandardErrorContent: aws.String(err.Error()),
+				}, nil
+			}
+			return &ssm.GetCommandInvocationOutput{
+				Status:                ssmtypes.CommandInvocationStatusSuccess,
+				StandardOutputContent: aws.String(stdout),
+			}, nil
+		}).
+		AnyTimes()
+
+	manager, _ := NewEC2Manager(context.Background(), EC2ManagerOptions{
+		EC2Client: mockEC2,
+		SSMClient: mockSSM,
+		Region:    "us-east-1",
+	})
+	return manager
+}
+
+func TestEC2Manager_RunCopy_Upload(t *testing.T) {
+	copyChunkSize = 4
+	copyUploadBatch = 2
+	defer func() { copyChunkSize, copyUploadBatch = 16*1024, 4 }()
+
+	content := []byte("hello, instance")
+	localPath := filepath.Join(t.TempDir(), "app.conf")
+	if err := os.WriteFile(localPath, content, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	chunkPattern := regexp.MustCompile(`^printf '%s' '([A-Za-z0-9+/=]*)' \| base64 -d (>>?) '/etc/app/app.conf.awsc-part'$`)
+	var uploaded bytes.Buffer
+	batches := 0
+	verified := false
+
+	manager := newCopyTestManager(t, func(script string) (string, error) {
+		switch {
+		case strings.HasPrefix(script, "if [ -d '/etc/app' ]"):
+			// Destination is a directory
+			return "/etc/app/app.conf", nil
+		case strings.HasPrefix(script, "set -e\n"):
+			batches++
+			lines := strings.Split(script, "\n")[1:]
+			if len(lines) > 2 {
+				t.Errorf("Expected at most 2 chunks per command, got %d", len(lines))
+			}
+			for _, line := range lines {
+				m := chunkPattern.FindStringSubmatch(line)
+				if m == nil {
+					return "", fmt.Errorf("unexpected chunk %s", line)
+				}
+				if (m[2] == ">") != (uploaded.Len() == 0) {
+					t.Errorf("Only the first chunk should truncate: %s", line)
+				}
+				data, _ := base64.StdEncoding.DecodeString(m[1])
+				uploaded.Write(data)
+			}
+			return "", nil
+		case strings.HasPrefix(script, "sum=$(sha256sum"):
+			sum := sha256.Sum256(uploaded.Bytes())
+			if !strings.Contains(script, hex.EncodeToString(sum[:])) {
+				t.Errorf("Checksum script does not contain the uploaded checksum: %s", script)
+			}
+			verified = true
+			return "", nil
+		}
+		return "", fmt.Errorf("unexpected script %s", script)
+	})
+
+	if err := manager.RunCopy(context.Background(), localPath, "i-0123abcd:/etc/app/"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if uploaded.String() != string(content) {
+		t.Errorf("Expected %q uploaded, got %q", content, uploaded.String())
+	}
+	// 15 bytes are 4 chunks of 4, sent 2 at a time
+	if batches != 2 {
+		t.Errorf("Expected 2 upload commands, got %d", batches)
+	}
+	if !verified {
+		t.Error("Expected checksum verification")
+	}
+}
+
+func TestEC2Manager_RunCopy_Download(t *testing.T) {
+	copyChunkSize = 4
+	defer func() { copyChunkSize = 16 * 1024 }()
+
+	content := []byte("remote log line\n")
+	sum := sha256.Sum256(content)
+	chunkPattern := regexp.MustCompile(`^dd if='/var/log/app.log' bs=4 skip=(\d+) count=1`)
+
+	manager := newCopyTestManager(t, func(script string) (string, error) {
+		if strings.HasPrefix(script, "test -f '/var/log/app.log'") {
+			return fmt.Sprintf("%d\n%s\n", len(content), hex.EncodeToString(sum[:])), nil
+		}
+		if m := chunkPattern.FindStringSubmatch(script); m != nil {
+			skip, _ := strconv.Atoi(m[1])
+			end := min((skip+1)*4, len(content))
+			return base64.StdEncoding.EncodeToString(content[skip*4 : end]), nil
+		}
+		return "", fmt.Errorf("unexpected script %s", script)
+	})
+
+	dir := t.TempDir()
+	if err := manager.RunCopy(context.Background(), "i-0123abcd:/var/log/app.log", dir); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	got, err := os.ReadFile(filepath.Join(dir, "app.log"))
+	if err != nil {
+		t.Fatalf("Expected downloaded file: %v", err)
+	}
+	if string(got) != string(content) {
+		t.Errorf("Expected %q, got %q", content, got)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "app.log.awsc-part")); !os.IsNotExist(err) {
+		t.Error("Expected partial file to be removed")
+	}
+}
+
+func TestEC2Manager_RunCopy_UploadToRoot(t *testing.T) {
+	localPath := filepath.Join(t.TempDir(), "app.conf")
+	if err := os.WriteFile(localPath, []byte("x"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	manager := newCopyTestManager(t, func(script string) (string, error) {
+		if strings.HasPrefix(script, "if [ -d '' ]") {
+			// "/" loses its trailing slash and resolves to nothing
+			return "", nil
+		}
+		return "", fmt.Errorf("unexpected script %s", script)
+	})
+
+	err := manager.RunCopy(context.Background(), localPath, "i-0123abcd:/")
+	if err == nil || !strings.Contains(err.Error(), "does not name a file") {
+		t.Errorf("Expected empty remote path error, got %v", err)
+	}
+}
+
+func TestEC2Manager_RunCopy_DownloadTooLarge(t *testing.T) {
+	copyMaxDownloadSize = 8
+	defer func() { copyMaxDownloadSize = 8 << 20 }()
+
+	manager := newCopyTestManager(t, func(script string) (string, error) {
+		if strings.HasPrefix(script, "test -f '/var/log/app.log'") {
+			return "16\n" + strings.Repeat("0", 64) + "\n", nil
+		}
+		return "", fmt.Errorf("unexpected script %s", script)
+	})
+
+	err := manager.RunCopy(context.Background(), "i-0123abcd:/var/log/app.log", t.TempDir())
+	if err == nil || !strings.Contains(err.Error(), "larger than the 8 B") {
+		t.Errorf("Expected size limit error, got %v", err)
+	}
+}
+
+func TestEC2Manager_RunCopy_RequiresOneRemote(t *testing.T) {
+	manager := &EC2Manager{}
+
+	if err := manager.RunCopy(context.Background(), "./a", "./b"); err == nil {
+		t.Error("Expected error copying between local paths")
+	}
+	if err := manager.RunCopy(context.Background(), "i-0123abcd:/a", "i-0456cdef:/b"); err == nil {
+		t.Error("Expected error copying between instances")
+	}
+}
+
+func TestEC2Manager_runShellScript_AuthError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSSM := mocks.NewMockSSMClient(ctrl)
+	manager, _ := NewEC2Manager(context.Background(), EC2ManagerOptions{
+		EC2Client: mocks.NewMockEC2Client(ctrl),
+		SSMClient: mockSSM,
+		Region:    "us-east-1",
+	})
+	manager.SetNonInteractive()
+
+	mockSSM.EXPECT().
+		SendCommand(gomock.Any(), gomock.Any()).
+		Return(nil, fmt.Errorf("ExpiredToken: the security token included in the request is expired")).
+		Times(1)
+
+	if _, err := manager.runShellScript(context.Background(), "i-0123abcd", "true"); err == nil || !IsAuthError(err) {
+		t.Errorf("Expected the auth error, got %v", err)
+	}
+}
@@ -0,0 +1,143 @@
+package aws
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	awscconfig "github.com/blontic/awsc/internal/config"
+)
+
+// instanceStates are the values DescribeInstances accepts for instance-state-name
+var instanceStates = map[string]bool{
+	"pending":       true,
+	"running":       true,
+	"shutting-down": true,
+	"terminated":    true,
+	"stopping":      true,
+	"stopped":       true,
+}
+
+// InstanceFilter narrows instance lists with DescribeInstances filters, so large accounts
+// aren't listed in full. Values may use * and ? wildcards.
+type InstanceFilter struct {
+	Filters          []string // Name=Value[,Value], e.g. tag:Env=prod or instance-type=t3.*
+	VpcId            string
+	AvailabilityZone string
+	States           []string
+	Name             string // Name tag
+}
+
+// InstanceListOptions controls which instances are listed and the tag columns shown for
+// them. Empty options fall back to the first matching ec2.lists config entry.
+type InstanceListOptions struct {
+	Filter     InstanceFilter
+	TagColumns []string
+}
+
+func (f InstanceFilter) isZero() bool {
+	return len(f.Filters) == 0 && f.VpcId == "" && f.AvailabilityZone == "" && len(f.States) == 0 && f.Name == ""
+}
+
+// ec2Filters translates the filter into DescribeInstances filters
+func (f InstanceFilter) ec2Filters() ([]types.Filter, error) {
+	var filters []types.Filter
+
+	for _, filter := range f.Filters {
+		name, values, ok := strings.Cut(filter, "=")
+		if !ok || name == "" || values == "" {
+			return nil, fmt.Errorf("invalid filter %q, expected Name=Value such as tag:Env=prod", filter)
+		}
+		filters = append(filters, types.Filter{Name: aws.String(name), Values: strings.Split(values, ",")})
+	}
+
+	if f.VpcId != "" {
+		filters = append(filters, types.Filter{Name: aws.String("vpc-id"), Values: []string{f.VpcId}})
+	}
+	if f.AvailabilityZone != "" {
+		filters = append(filters, types.Filter{Name: aws.String("availability-zone"), Values: []string{f.AvailabilityZone}})
+	}
+	if len(f.States) > 0 {
+		for _, state := range f.States {
+			if !instanceStates[state] {
+				return nil, fmt.Errorf("invalid state %q, expected one of pending, running, stopping, stopped, shutting-down or terminated", state)
+			}
+		}
+		filters = append(filters, types.Filter{Name: aws.String("instance-state-name"), Values: f.States})
+	}
+	if f.Name != "" {
+		filters = append(filters, types.Filter{Name: aws.String("tag:Name"), Values: []string{f.Name}})
+	}
+
+	return filters, nil
+}
+
+// SetListOptions applies filters and tag columns to every instance list from now on
+func (e *EC2Manager) SetListOptions(opts InstanceListOptions) error {
+	if opts.Filter.isZero() || len(opts.TagColumns) == 0 {
+		defaults, err := e.listDefaults()
+		if err != nil {
+			return err
+		}
+		if defaults != nil {
+			if opts.Filter.isZero() && len(defaults.Filters) > 0 {
+				opts.Filter.Filters = defaults.Filters
+				// On stderr, since ec2 ssh-config output is redirected into a config file
+				fmt.Fprintf(os.Stderr, "Filtering instances by %s from config\n", strings.Join(defaults.Filters, " "))
+			}
+			if len(opts.TagColumns) == 0 {
+				opts.TagColumns = defaults.TagColumns
+			}
+		}
+	}
+
+	filters, err := opts.Filter.ec2Filters()
+	if err != nil {
+		return err
+	}
+
+	e.filters = filters
+	e.tagColumns = opts.TagColumns
+	return nil
+}
+
+// listDefaults returns the first ec2.lists entry for the current account
+func (e *EC2Manager) listDefaults() (*awscconfig.EC2ListDefaults, error) {
+	rules, err := awscconfig.EC2ListDefaultRules()
+	if err != nil || len(rules) == 0 {
+		return nil, err
+	}
+
+	var accountId, accountName string
+	if _, session := currentSession(); session != nil {
+		accountId, accountName = session.AccountID, session.AccountName
+	}
+
+	for _, rule := range rules {
+		if rule.Matches(accountId, accountName) {
+			return &rule, nil
+		}
+	}
+	return nil, nil
+}
+
+// filterHint explains an empty instance list when filters are applied
+func (e *EC2Manager) filterHint() string {
+	if len(e.filters) > 0 {
+		return " matching the filters"
+	}
+	return ""
+}
+
+// tagColumnsLabel shows the instance's values for the configured tag columns
+func tagColumnsLabel(instance EC2Instance, columns []string) string {
+	var parts []string
+	for _, column := range columns {
+		if value, ok := instance.Tags[column]; ok && value != "" {
+			parts = append(parts, fmt.Sprintf("%s=%s", column, value))
+		}
+	}
+	return strings.Join(parts, " ")
+}
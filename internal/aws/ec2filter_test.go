@@ -0,0 +1,162 @@
+package aws
+
+import (
+	"context"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/blontic/awsc/internal/aws/mocks"
+	awscconfig "github.com/blontic/awsc/internal/config"
+	"github.com/spf13/viper"
+	"go.uber.org/mock/gomock"
+)
+
+func TestInstanceFilter_EC2Filters(t *testing.T) {
+	filter := InstanceFilter{
+		Filters:          []string{"tag:Env=prod", "instance-type=t3.*,m5.*"},
+		VpcId:            "vpc-123",
+		AvailabilityZone: "eu-west-1a",
+		States:           []string{"running", "stopped"},
+		Name:             "web-*",
+	}
+
+	filters, err := filter.ec2Filters()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	want := []types.Filter{
+		{Name: aws.String("tag:Env"), Values: []string{"prod"}},
+		{Name: aws.String("instance-type"), Values: []string{"t3.*", "m5.*"}},
+		{Name: aws.String("vpc-id"), Values: []string{"vpc-123"}},
+		{Name: aws.String("availability-zone"), Values: []string{"eu-west-1a"}},
+		{Name: aws.String("instance-state-name"), Values: []string{"running", "stopped"}},
+		{Name: aws.String("tag:Name"), Values: []string{"web-*"}},
+	}
+	if !reflect.DeepEqual(filters, want) {
+		t.Errorf("Expected %+v, got %+v", want, filters)
+	}
+
+	if filters, err := (InstanceFilter{}).ec2Filters(); err != nil || filters != nil {
+		t.Errorf("Expected no filters for the zero value, got %+v, %v", filters, err)
+	}
+
+	for _, invalid := range []InstanceFilter{
+		{Filters: []string{"tag:Env"}},
+		{Filters: []string{"=prod"}},
+		{States: []string{"sleeping"}},
+	} {
+		if _, err := invalid.ec2Filters(); err == nil {
+			t.Errorf("Expected error for %+v", invalid)
+		}
+	}
+}
+
+func TestEC2Manager_ListAllInstances_Filters(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEC2 := mocks.NewMockEC2Client(ctrl)
+	mockSSM := mocks.NewMockSSMClient(ctrl)
+
+	manager, err := NewEC2Manager(context.Background(), EC2ManagerOptions{
+		EC2Client: mockEC2,
+		SSMClient: mockSSM,
+		Region:    "us-east-1",
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error creating manager: %v", err)
+	}
+
+	if err := manager.SetListOptions(InstanceListOptions{Filter: InstanceFilter{Name: "web-*", States: []string{"stopped"}}}); err != nil {
+		t.Fatalf("SetListOptions failed: %v", err)
+	}
+
+	mockEC2.EXPECT().
+		DescribeInstances(gomock.Any(), &ec2.DescribeInstancesInput{
+			Filters: []types.Filter{
+				{Name: aws.String("instance-state-name"), Values: []string{"stopped"}},
+				{Name: aws.String("tag:Name"), Values: []string{"web-*"}},
+			},
+		}).
+		Return(&ec2.DescribeInstancesOutput{}, nil)
+
+	instances, err := manager.ListAllInstances(context.Background())
+	if err != nil {
+		t.Fatalf("ListAllInstances failed: %v", err)
+	}
+	if len(instances) != 0 {
+		t.Errorf("Expected no instances, got %d", len(instances))
+	}
+
+	if hint := manager.filterHint(); !strings.Contains(hint, "matching the filters") {
+		t.Errorf("Expected a filter hint, got %q", hint)
+	}
+}
+
+func TestEC2Manager_SetListOptions_ConfigDefaults(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("AWSC_PROFILE", "")
+	if err := awscconfig.SaveSession(os.Getppid(), "awsc-prod", "123456789012", "Production", "Admin"); err != nil {
+		t.Fatalf("SaveSession failed: %v", err)
+	}
+
+	viper.Set("ec2.lists", []map[string]interface{}{
+		{"account": "staging", "filters": []string{"tag:Team=qa"}},
+		{"account": "123456789012", "filters": []string{"tag:Team=platform"}, "tag_columns": []string{"Env", "Role"}},
+	})
+	defer viper.Reset()
+
+	manager := &EC2Manager{region: "us-east-1"}
+
+	if err := manager.SetListOptions(InstanceListOptions{}); err != nil {
+		t.Fatalf("SetListOptions failed: %v", err)
+	}
+	want := []types.Filter{{Name: aws.String("tag:Team"), Values: []string{"platform"}}}
+	if !reflect.DeepEqual(manager.filters, want) {
+		t.Errorf("Expected the account's default filters, got %+v", manager.filters)
+	}
+	if !reflect.DeepEqual(manager.tagColumns, []string{"Env", "Role"}) {
+		t.Errorf("Expected the account's tag columns, got %v", manager.tagColumns)
+	}
+
+	// Filters from flags replace the defaults, the tag columns still apply
+	if err := manager.SetListOptions(InstanceListOptions{Filter: InstanceFilter{VpcId: "vpc-123"}}); err != nil {
+		t.Fatalf("SetListOptions failed: %v", err)
+	}
+	want = []types.Filter{{Name: aws.String("vpc-id"), Values: []string{"vpc-123"}}}
+	if !reflect.DeepEqual(manager.filters, want) {
+		t.Errorf("Expected only the flag filters, got %+v", manager.filters)
+	}
+	if len(manager.tagColumns) != 2 {
+		t.Errorf("Expected the default tag columns, got %v", manager.tagColumns)
+	}
+}
+
+func TestInstanceChoices_TagColumns(t *testing.T) {
+	instances := []EC2Instance{
+		{InstanceId: "i-1", Name: "web-1", Platform: "Linux", State: "running", Tags: map[string]string{"Env": "prod", "Role": "web"}, IsSelectable: true},
+		{InstanceId: "i-2", Name: "db-1", Platform: "Linux", State: "running", Tags: map[string]string{"Env": "prod"}},
+		{InstanceId: "i-3", Name: "tmp", Platform: "Linux", State: "stopped"},
+	}
+
+	choices, selectable := instanceChoices(instances, []string{"Env", "Role"})
+
+	if choices[0] != "web-1 (i-1) - Linux - running - Env=prod Role=web" {
+		t.Errorf("Unexpected label %q", choices[0])
+	}
+	if !strings.HasSuffix(choices[1], " - Env=prod") {
+		t.Errorf("Expected only the tags the instance has, got %q", choices[1])
+	}
+	if choices[2] != "tmp (i-3) - Linux - stopped" {
+		t.Errorf("Expected no tag column without tags, got %q", choices[2])
+	}
+	if !selectable[0] || selectable[1] {
+		t.Errorf("Unexpected selectability %v", selectable)
+	}
+}
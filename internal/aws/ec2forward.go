@@ -0,0 +1,107 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	awscconfig "github.com/blontic/awsc/internal/config"
+)
+
+// ParsePortPair parses "local:remote", or a single port used on both ends, into a forward
+// to a port on the instance itself
+func ParsePortPair(s string) (ForwardSpec, error) {
+	localPart, remotePart, hasLocal := strings.Cut(s, ":")
+	if !hasLocal {
+		remotePart = localPart
+	}
+
+	remotePort, err := parsePort(remotePart)
+	if err != nil {
+		return ForwardSpec{}, fmt.Errorf("invalid port pair %q: %v", s, err)
+	}
+	localPort, err := parsePort(localPart)
+	if err != nil {
+		return ForwardSpec{}, fmt.Errorf("invalid port pair %q: %v", s, err)
+	}
+
+	return ForwardSpec{LocalPort: localPort, RemoteHost: "localhost", RemotePort: remotePort}, nil
+}
+
+// RunForward forwards local ports to ports on the instance itself, listing the instances
+// when instanceId is empty or not available
+func (e *EC2Manager) RunForward(ctx context.Context, instanceId string, specs []ForwardSpec) error {
+	if len(specs) == 0 {
+		return fmt.Errorf("no ports to forward")
+	}
+
+	instances, err := e.ListAllInstances(ctx)
+	if err != nil {
+		return fmt.Errorf("error listing EC2 instances: %v", err)
+	}
+	endpoints := e.markInstanceConnectInstances(ctx, instances)
+
+	selectable := 0
+	for _, instance := range instances {
+		if instance.IsSelectable {
+			selectable++
+		}
+	}
+	if selectable == 0 {
+		return fmt.Errorf("no running EC2 instances with the SSM agent or an EC2 Instance Connect Endpoint found in region %s", e.region)
+	}
+
+	var target *EC2Instance
+	if instanceId != "" {
+		for _, instance := range instances {
+			if instance.InstanceId == instanceId {
+				target = &instance
+				break
+			}
+		}
+
+		if target == nil {
+			fmt.Printf("Instance '%s' not found. Available instances:\n\n", instanceId)
+		} else if !target.IsSelectable {
+			fmt.Printf("Instance '%s' is not available (state: %s). Available instances:\n\n", instanceId, target.State)
+			target = nil
+		}
+	}
+
+	if target == nil {
+		target, err = e.selectInstance("Select EC2 Instance to forward to:", instances)
+		if err != nil {
+			return err
+		}
+	}
+
+	fmt.Printf("Forwarding to instance: %s (%s)\n", target.Name, target.InstanceId)
+	return e.forwardPorts(ctx, *target, endpoints, specs)
+}
+
+// forwardPorts forwards each spec's local port to its remote port on the instance, over
+// SSM or through the instance's EC2 Instance Connect Endpoint
+func (e *EC2Manager) forwardPorts(ctx context.Context, instance EC2Instance, endpoints map[string][]InstanceConnectEndpoint, specs []ForwardSpec) error {
+	cfg, err := awscconfig.LoadAWSConfigWithProfile(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load AWS config: %w", err)
+	}
+
+	if instance.Transport == TransportEICE {
+		ports := make([]int32, len(specs))
+		for i, spec := range specs {
+			ports[i] = int32(spec.RemotePort)
+		}
+		endpoint, err := e.instanceConnectEndpoint(ctx, instance, endpoints, ports...)
+		if err != nil {
+			return err
+		}
+		// The endpoint reaches the instance at its private IP, not on its loopback
+		return NewEICEForwarder(cfg, endpoint).StartMultiplePortForwarding(ctx, instance.PrivateIp, specs)
+	}
+
+	if len(specs) == 1 {
+		fmt.Printf("Forwarding %s on %s\n", specs[0], instance.InstanceId)
+	}
+	return NewExternalPluginForwarder(cfg).StartMultiplePortForwarding(ctx, instance.InstanceId, specs)
+}
@@ -0,0 +1,99 @@
+package aws
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	ssmtypes "github.com/aws/aws-sdk-go-v2/service/ssm/types"
+	"github.com/blontic/awsc/internal/aws/mocks"
+	"go.uber.org/mock/gomock"
+)
+
+func TestParsePortPair(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    ForwardSpec
+		wantErr bool
+	}{
+		{"8080", ForwardSpec{LocalPort: 8080, RemoteHost: "localhost", RemotePort: 8080}, false},
+		{"18080:8080", ForwardSpec{LocalPort: 18080, RemoteHost: "localhost", RemotePort: 8080}, false},
+		{"5985:5985", ForwardSpec{LocalPort: 5985, RemoteHost: "localhost", RemotePort: 5985}, false},
+		{"host:8080", ForwardSpec{}, true},
+		{"8080:", ForwardSpec{}, true},
+		{"0:22", ForwardSpec{}, true},
+		{"1:2:3", ForwardSpec{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParsePortPair(tt.input)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Expected error for %q, got %+v", tt.input, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Expected %+v, got %+v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestEC2Manager_RunForward_NoAvailableInstances(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEC2 := mocks.NewMockEC2Client(ctrl)
+	mockSSM := mocks.NewMockSSMClient(ctrl)
+
+	manager, err := NewEC2Manager(context.Background(), EC2ManagerOptions{
+		EC2Client: mockEC2,
+		SSMClient: mockSSM,
+		Region:    "us-east-1",
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error creating manager: %v", err)
+	}
+
+	mockEC2.EXPECT().
+		DescribeInstances(gomock.Any(), gomock.Any()).
+		Return(&ec2.DescribeInstancesOutput{
+			Reservations: []types.Reservation{
+				{
+					Instances: []types.Instance{
+						{
+							InstanceId: aws.String("i-stopped"),
+							State:      &types.InstanceState{Name: "stopped"},
+						},
+					},
+				},
+			},
+		}, nil)
+	mockEC2.EXPECT().DescribeImages(gomock.Any(), gomock.Any()).Return(&ec2.DescribeImagesOutput{}, nil).AnyTimes()
+	mockSSM.EXPECT().
+		DescribeInstanceInformation(gomock.Any(), gomock.Any()).
+		Return(&ssm.DescribeInstanceInformationOutput{InstanceInformationList: []ssmtypes.InstanceInformation{}}, nil).
+		AnyTimes()
+
+	err = manager.RunForward(context.Background(), "i-stopped", []ForwardSpec{{LocalPort: 8080, RemoteHost: "localhost", RemotePort: 8080}})
+	if err == nil || !strings.Contains(err.Error(), "no running EC2 instances") {
+		t.Errorf("Expected error about no available instances, got: %v", err)
+	}
+}
+
+func TestEC2Manager_RunForward_NoSpecs(t *testing.T) {
+	manager := &EC2Manager{region: "us-east-1"}
+
+	if err := manager.RunForward(context.Background(), "i-123", nil); err == nil {
+		t.Error("Expected error without ports to forward")
+	}
+}
@@ -0,0 +1,298 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	ssmtypes "github.com/aws/aws-sdk-go-v2/service/ssm/types"
+)
+
+// InstanceAction is a lifecycle change applied by RunInstanceAction
+type InstanceAction string
+
+const (
+	InstanceActionStart  InstanceAction = "start"
+	InstanceActionStop   InstanceAction = "stop"
+	InstanceActionReboot InstanceAction = "reboot"
+)
+
+// startInstanceKey starts the stopped instance under the cursor in the instance selector.
+// Being printable, it only acts while the filter is empty; startInstanceFilteredKey can't
+// be typed, so it also works after typing a filter.
+const (
+	startInstanceKey         = "s"
+	startInstanceFilteredKey = "ctrl+s"
+)
+
+// instanceWaitPollInterval is how often the instance is polled while waiting on it
+var instanceWaitPollInterval = 5 * time.Second
+
+// instanceWaitTimeout bounds each wait for an instance state or for the SSM agent
+var instanceWaitTimeout = 10 * time.Minute
+
+// confirmAction asks a yes/no question on stdin, defaulting to no
+var confirmAction = func(prompt string) bool {
+	fmt.Printf("%s (y/N): ", prompt)
+
+	var response string
+	fmt.Scanln(&response)
+
+	response = strings.ToLower(strings.TrimSpace(response))
+	return response == "y" || response == "yes"
+}
+
+type InstanceActionOptions struct {
+	Wait bool // Wait for the new state; start and reboot also wait for the SSM agent
+	Yes  bool // Skip the confirmation prompt
+}
+
+// fromState is the state an instance must be in for the action to apply
+func (a InstanceAction) fromState() string {
+	if a == InstanceActionStart {
+		return "stopped"
+	}
+	return "running"
+}
+
+func (a InstanceAction) verb() string {
+	switch a {
+	case InstanceActionStart:
+		return "Start"
+	case InstanceActionStop:
+		return "Stop"
+	default:
+		return "Reboot"
+	}
+}
+
+// RunInstanceAction starts, stops or reboots an instance, listing the instances the action
+// applies to when instanceId is empty or not eligible
+func (e *EC2Manager) RunInstanceAction(ctx context.Context, action InstanceAction, instanceId string, opts InstanceActionOptions) error {
+	instances, err := e.ListAllInstances(ctx)
+	if err != nil {
+		return fmt.Errorf("error listing EC2 instances: %v", err)
+	}
+
+	eligible := 0
+	for i := range instances {
+		instances[i].IsSelectable = instances[i].State == action.fromState()
+		if instances[i].IsSelectable {
+			eligible++
+		}
+	}
+	if eligible == 0 {
+		return fmt.Errorf("no %s EC2 instances to %s in region %s", action.fromState(), action, e.region)
+	}
+
+	var target *EC2Instance
+	if instanceId != "" {
+		for _, instance := range instances {
+			if instance.InstanceId == instanceId {
+				target = &instance
+				break
+			}
+		}
+
+		if target == nil {
+			fmt.Printf("Instance '%s' not found. Available instances:\n\n", instanceId)
+		} else if !target.IsSelectable {
+			fmt.Printf("Instance '%s' is %s, not %s. Available instances:\n\n", instanceId, target.State, action.fromState())
+			target = nil
+		}
+	}
+
+	if target == nil {
+		target, err = e.selectInstance(fmt.Sprintf("Select EC2 Instance to %s:", action), instances)
+		if err != nil {
+			return err
+		}
+	}
+
+	if !opts.Yes && !confirmAction(fmt.Sprintf("%s %s (%s)?", action.verb(), target.Name, target.InstanceId)) {
+		fmt.Printf("Cancelled\n")
+		return nil
+	}
+
+	return e.applyInstanceAction(ctx, action, *target, opts.Wait)
+}
+
+// applyInstanceAction requests the action and optionally waits for it to complete
+func (e *EC2Manager) applyInstanceAction(ctx context.Context, action InstanceAction, instance EC2Instance, wait bool) error {
+	requested := time.Now()
+
+	if err := e.changeInstanceState(ctx, action, instance.InstanceId); err != nil {
+		return fmt.Errorf("failed to %s %s: %w", action, instance.InstanceId, err)
+	}
+	fmt.Printf("✓ Requested %s of %s (%s)\n", action, instance.Name, instance.InstanceId)
+
+	if !wait {
+		return nil
+	}
+
+	switch action {
+	case InstanceActionStart:
+		if err := e.waitForInstanceState(ctx, instance.InstanceId, types.InstanceStateNameRunning); err != nil {
+			return err
+		}
+		return e.waitForSSMAgent(ctx, instance.InstanceId, requested)
+	case InstanceActionStop:
+		return e.waitForInstanceState(ctx, instance.InstanceId, types.InstanceStateNameStopped)
+	default:
+		// A reboot doesn't change the state, but the agent checks in again once it's back
+		return e.waitForSSMAgent(ctx, instance.InstanceId, requested)
+	}
+}
+
+func (e *EC2Manager) changeInstanceState(ctx context.Context, action InstanceAction, instanceId string) error {
+	call := func() error {
+		var err error
+		switch action {
+		case InstanceActionStart:
+			_, err = e.ec2Client.StartInstances(ctx, &ec2.StartInstancesInput{InstanceIds: []string{instanceId}})
+		case InstanceActionStop:
+			_, err = e.ec2Client.StopInstances(ctx, &ec2.StopInstancesInput{InstanceIds: []string{instanceId}})
+		case InstanceActionReboot:
+			_, err = e.ec2Client.RebootInstances(ctx, &ec2.RebootInstancesInput{InstanceIds: []string{instanceId}})
+		default:
+			err = fmt.Errorf("unknown action %q", action)
+		}
+		return err
+	}
+
+	err := call()
+	if err != nil && IsAuthError(err) {
+		if shouldReauth, reAuthErr := e.promptForReauth(ctx); shouldReauth && reAuthErr == nil {
+			if reloadErr := e.reloadClients(ctx); reloadErr != nil {
+				return reloadErr
+			}
+			err = call()
+		}
+	}
+	return err
+}
+
+// waitForInstanceState polls the instance until it reaches state
+func (e *EC2Manager) waitForInstanceState(ctx context.Context, instanceId string, state types.InstanceStateName) error {
+	fmt.Printf("Waiting for %s to be %s...\n", instanceId, state)
+	deadline := time.Now().Add(instanceWaitTimeout)
+
+	for {
+		result, err := e.ec2Client.DescribeInstances(ctx, &ec2.DescribeInstancesInput{
+			InstanceIds: []string{instanceId},
+		})
+		if err != nil {
+			return fmt.Errorf("error describing instance %s: %v", instanceId, err)
+		}
+
+		for _, reservation := range result.Reservations {
+			for _, inst := range reservation.Instances {
+				if inst.State != nil && inst.State.Name == state {
+					fmt.Printf("✓ %s is %s\n", instanceId, state)
+					return nil
+				}
+			}
+		}
+
+		if err := sleepUntil(ctx, deadline); err != nil {
+			return fmt.Errorf("timed out waiting for %s to be %s", instanceId, state)
+		}
+	}
+}
+
+// waitForSSMAgent polls until the instance's SSM agent is online and has checked in
+// after since, so an agent that was online before a reboot doesn't count
+func (e *EC2Manager) waitForSSMAgent(ctx context.Context, instanceId string, since time.Time) error {
+	fmt.Printf("Waiting for the SSM agent on %s...\n", instanceId)
+	deadline := time.Now().Add(instanceWaitTimeout)
+
+	for {
+		result, err := e.ssmClient.DescribeInstanceInformation(ctx, &ssm.DescribeInstanceInformationInput{
+			Filters: []ssmtypes.InstanceInformationStringFilter{
+				{Key: aws.String("InstanceIds"), Values: []string{instanceId}},
+			},
+		})
+		if err != nil {
+			return fmt.Errorf("error checking SSM agent on %s: %v", instanceId, err)
+		}
+
+		for _, info := range result.InstanceInformationList {
+			if info.PingStatus == ssmtypes.PingStatusOnline && info.LastPingDateTime != nil && info.LastPingDateTime.After(since) {
+				fmt.Printf("✓ SSM agent on %s is online\n", instanceId)
+				return nil
+			}
+		}
+
+		if err := sleepUntil(ctx, deadline); err != nil {
+			return fmt.Errorf("timed out waiting for the SSM agent on %s", instanceId)
+		}
+	}
+}
+
+// sleepUntil waits one poll interval, failing if that would pass deadline
+func sleepUntil(ctx context.Context, deadline time.Time) error {
+	if time.Now().Add(instanceWaitPollInterval).After(deadline) {
+		return context.DeadlineExceeded
+	}
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(instanceWaitPollInterval):
+		return nil
+	}
+}
+
+// startAndConnect starts a stopped instance, waits until it can be reached and opens a session
+func (e *EC2Manager) startAndConnect(ctx context.Context, instance EC2Instance, opts ConnectOptions) error {
+	if !confirmAction(fmt.Sprintf("Start %s (%s) and connect?", instance.Name, instance.InstanceId)) {
+		fmt.Printf("Cancelled\n")
+		return nil
+	}
+
+	started, endpoints, err := e.startForConnect(ctx, instance)
+	if err != nil {
+		return err
+	}
+	return e.connectInstance(ctx, started, endpoints, opts)
+}
+
+// startForConnect starts the instance and waits for the SSM agent when the instance is
+// registered with SSM, or otherwise only until it is running and then looks for an EC2
+// Instance Connect Endpoint to reach it through
+func (e *EC2Manager) startForConnect(ctx context.Context, instance EC2Instance) (EC2Instance, map[string][]InstanceConnectEndpoint, error) {
+	// SSM keeps listing managed instances while they are stopped
+	info, err := e.describeSSMInstance(ctx, instance.InstanceId)
+	if err != nil {
+		return instance, nil, fmt.Errorf("error checking SSM agent on %s: %v", instance.InstanceId, err)
+	}
+
+	if info != nil {
+		if err := e.applyInstanceAction(ctx, InstanceActionStart, instance, true); err != nil {
+			return instance, nil, err
+		}
+		instance.State = "running"
+		instance.Transport = ""
+		instance.IsSelectable = true
+		return instance, nil, nil
+	}
+
+	if err := e.applyInstanceAction(ctx, InstanceActionStart, instance, false); err != nil {
+		return instance, nil, err
+	}
+	if err := e.waitForInstanceState(ctx, instance.InstanceId, types.InstanceStateNameRunning); err != nil {
+		return instance, nil, err
+	}
+
+	instance.State = "running"
+	instances := []EC2Instance{instance}
+	endpoints := e.markInstanceConnectInstances(ctx, instances)
+	if instances[0].Transport != TransportEICE {
+		return instance, nil, fmt.Errorf("%s is running but has no SSM agent and no EC2 Instance Connect Endpoint in %s", instance.InstanceId, instance.VpcId)
+	}
+	return instances[0], endpoints, nil
+}
@@ -0,0 +1,243 @@
+package aws
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	ssmtypes "github.com/aws/aws-sdk-go-v2/service/ssm/types"
+	"github.com/blontic/awsc/internal/aws/mocks"
+	"go.uber.org/mock/gomock"
+)
+
+func useFastInstanceWaits(t *testing.T) {
+	t.Helper()
+	interval, timeout := instanceWaitPollInterval, instanceWaitTimeout
+	instanceWaitPollInterval, instanceWaitTimeout = time.Millisecond, time.Second
+	t.Cleanup(func() { instanceWaitPollInterval, instanceWaitTimeout = interval, timeout })
+}
+
+func stubConfirm(t *testing.T, answer bool) *[]string {
+	t.Helper()
+	original := confirmAction
+	var prompts []string
+	confirmAction = func(prompt string) bool {
+		prompts = append(prompts, prompt)
+		return answer
+	}
+	t.Cleanup(func() { confirmAction = original })
+	return &prompts
+}
+
+func instanceOutput(id string, state types.InstanceStateName) *ec2.DescribeInstancesOutput {
+	return &ec2.DescribeInstancesOutput{
+		Reservations: []types.Reservation{{
+			Instances: []types.Instance{{
+				InstanceId: aws.String(id),
+				State:      &types.InstanceState{Name: state},
+				Tags:       []types.Tag{{Key: aws.String("Name"), Value: aws.String("web")}},
+			}},
+		}},
+	}
+}
+
+func TestEC2Manager_RunInstanceAction_StopAndWait(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	useFastInstanceWaits(t)
+	stubConfirm(t, false)
+
+	mockEC2 := mocks.NewMockEC2Client(ctrl)
+	mockSSM := mocks.NewMockSSMClient(ctrl)
+	manager, _ := NewEC2Manager(context.Background(), EC2ManagerOptions{EC2Client: mockEC2, SSMClient: mockSSM, Region: "us-east-1"})
+
+	gomock.InOrder(
+		mockEC2.EXPECT().DescribeInstances(gomock.Any(), &ec2.DescribeInstancesInput{}).
+			Return(instanceOutput("i-1", types.InstanceStateNameRunning), nil),
+		mockEC2.EXPECT().StopInstances(gomock.Any(), &ec2.StopInstancesInput{InstanceIds: []string{"i-1"}}).
+			Return(&ec2.StopInstancesOutput{}, nil),
+		mockEC2.EXPECT().DescribeInstances(gomock.Any(), &ec2.DescribeInstancesInput{InstanceIds: []string{"i-1"}}).
+			Return(instanceOutput("i-1", types.InstanceStateNameStopping), nil),
+		mockEC2.EXPECT().DescribeInstances(gomock.Any(), &ec2.DescribeInstancesInput{InstanceIds: []string{"i-1"}}).
+			Return(instanceOutput("i-1", types.InstanceStateNameStopped), nil),
+	)
+	mockSSM.EXPECT().DescribeInstanceInformation(gomock.Any(), gomock.Any()).
+		Return(&ssm.DescribeInstanceInformationOutput{}, nil).AnyTimes()
+
+	// --yes skips the prompt, which would otherwise decline
+	err := manager.RunInstanceAction(context.Background(), InstanceActionStop, "i-1", InstanceActionOptions{Wait: true, Yes: true})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+}
+
+func TestEC2Manager_RunInstanceAction_Declined(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	prompts := stubConfirm(t, false)
+
+	mockEC2 := mocks.NewMockEC2Client(ctrl)
+	mockSSM := mocks.NewMockSSMClient(ctrl)
+	manager, _ := NewEC2Manager(context.Background(), EC2ManagerOptions{EC2Client: mockEC2, SSMClient: mockSSM, Region: "us-east-1"})
+
+	mockEC2.EXPECT().DescribeInstances(gomock.Any(), gomock.Any()).
+		Return(instanceOutput("i-1", types.InstanceStateNameStopped), nil)
+	// No StartInstances call is expected
+
+	err := manager.RunInstanceAction(context.Background(), InstanceActionStart, "i-1", InstanceActionOptions{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(*prompts) != 1 || (*prompts)[0] != "Start web (i-1)?" {
+		t.Errorf("Expected one confirmation prompt, got %v", *prompts)
+	}
+}
+
+func TestEC2Manager_RunInstanceAction_NoEligibleInstances(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEC2 := mocks.NewMockEC2Client(ctrl)
+	mockSSM := mocks.NewMockSSMClient(ctrl)
+	manager, _ := NewEC2Manager(context.Background(), EC2ManagerOptions{EC2Client: mockEC2, SSMClient: mockSSM, Region: "us-east-1"})
+
+	mockEC2.EXPECT().DescribeInstances(gomock.Any(), gomock.Any()).
+		Return(instanceOutput("i-1", types.InstanceStateNameStopped), nil)
+
+	err := manager.RunInstanceAction(context.Background(), InstanceActionReboot, "", InstanceActionOptions{})
+	if err == nil || !strings.Contains(err.Error(), "no running EC2 instances to reboot") {
+		t.Errorf("Expected no eligible instances error, got %v", err)
+	}
+}
+
+func TestEC2Manager_waitForSSMAgent_IgnoresPingBeforeReboot(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	useFastInstanceWaits(t)
+
+	mockSSM := mocks.NewMockSSMClient(ctrl)
+	manager, _ := NewEC2Manager(context.Background(), EC2ManagerOptions{EC2Client: mocks.NewMockEC2Client(ctrl), SSMClient: mockSSM, Region: "us-east-1"})
+
+	since := time.Now()
+	ping := func(at time.Time) *ssm.DescribeInstanceInformationOutput {
+		return &ssm.DescribeInstanceInformationOutput{
+			InstanceInformationList: []ssmtypes.InstanceInformation{{
+				InstanceId:       aws.String("i-1"),
+				PingStatus:       ssmtypes.PingStatusOnline,
+				LastPingDateTime: aws.Time(at),
+			}},
+		}
+	}
+
+	gomock.InOrder(
+		mockSSM.EXPECT().DescribeInstanceInformation(gomock.Any(), gomock.Any()).Return(ping(since.Add(-time.Minute)), nil),
+		mockSSM.EXPECT().DescribeInstanceInformation(gomock.Any(), gomock.Any()).Return(ping(since.Add(time.Second)), nil),
+	)
+
+	if err := manager.waitForSSMAgent(context.Background(), "i-1", since); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+}
+
+func TestEC2Manager_waitForInstanceState_Timeout(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	useFastInstanceWaits(t)
+	instanceWaitTimeout = 10 * time.Millisecond
+
+	mockEC2 := mocks.NewMockEC2Client(ctrl)
+	manager, _ := NewEC2Manager(context.Background(), EC2ManagerOptions{EC2Client: mockEC2, SSMClient: mocks.NewMockSSMClient(ctrl), Region: "us-east-1"})
+
+	mockEC2.EXPECT().DescribeInstances(gomock.Any(), gomock.Any()).
+		Return(instanceOutput("i-1", types.InstanceStateNamePending), nil).AnyTimes()
+
+	err := manager.waitForInstanceState(context.Background(), "i-1", types.InstanceStateNameRunning)
+	if err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Errorf("Expected timeout error, got %v", err)
+	}
+}
+
+func TestEC2Manager_startForConnect_InstanceConnectEndpoint(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	useFastInstanceWaits(t)
+
+	mockEC2 := mocks.NewMockEC2Client(ctrl)
+	mockSSM := mocks.NewMockSSMClient(ctrl)
+	manager, _ := NewEC2Manager(context.Background(), EC2ManagerOptions{EC2Client: mockEC2, SSMClient: mockSSM, Region: "us-east-1"})
+
+	// Not registered with SSM, so there's no agent to wait for
+	mockSSM.EXPECT().DescribeInstanceInformation(gomock.Any(), gomock.Any()).
+		Return(&ssm.DescribeInstanceInformationOutput{}, nil)
+	gomock.InOrder(
+		mockEC2.EXPECT().StartInstances(gomock.Any(), &ec2.StartInstancesInput{InstanceIds: []string{"i-1"}}).
+			Return(&ec2.StartInstancesOutput{}, nil),
+		mockEC2.EXPECT().DescribeInstances(gomock.Any(), &ec2.DescribeInstancesInput{InstanceIds: []string{"i-1"}}).
+			Return(instanceOutput("i-1", types.InstanceStateNameRunning), nil),
+	)
+	mockEC2.EXPECT().DescribeInstanceConnectEndpoints(gomock.Any(), gomock.Any()).
+		Return(&ec2.DescribeInstanceConnectEndpointsOutput{
+			InstanceConnectEndpoints: []types.Ec2InstanceConnectEndpoint{{
+				InstanceConnectEndpointId: aws.String("eice-1"),
+				VpcId:                     aws.String("vpc-1"),
+			}},
+		}, nil)
+
+	stopped := EC2Instance{InstanceId: "i-1", Name: "web", State: "stopped", VpcId: "vpc-1", PrivateIp: "10.0.0.5"}
+	instance, endpoints, err := manager.startForConnect(context.Background(), stopped)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if instance.Transport != TransportEICE || instance.State != "running" {
+		t.Errorf("Expected a running instance reached through EICE, got %+v", instance)
+	}
+	if len(endpoints["vpc-1"]) != 1 {
+		t.Errorf("Expected the endpoint in vpc-1, got %+v", endpoints)
+	}
+}
+
+func TestEC2Manager_startForConnect_SSMManaged(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	useFastInstanceWaits(t)
+
+	mockEC2 := mocks.NewMockEC2Client(ctrl)
+	mockSSM := mocks.NewMockSSMClient(ctrl)
+	manager, _ := NewEC2Manager(context.Background(), EC2ManagerOptions{EC2Client: mockEC2, SSMClient: mockSSM, Region: "us-east-1"})
+
+	registered := func(status ssmtypes.PingStatus, at time.Time) *ssm.DescribeInstanceInformationOutput {
+		return &ssm.DescribeInstanceInformationOutput{
+			InstanceInformationList: []ssmtypes.InstanceInformation{{
+				InstanceId:       aws.String("i-1"),
+				PingStatus:       status,
+				LastPingDateTime: aws.Time(at),
+			}},
+		}
+	}
+	gomock.InOrder(
+		mockSSM.EXPECT().DescribeInstanceInformation(gomock.Any(), gomock.Any()).
+			Return(registered(ssmtypes.PingStatusConnectionLost, time.Now().Add(-time.Hour)), nil),
+		mockSSM.EXPECT().DescribeInstanceInformation(gomock.Any(), gomock.Any()).
+			Return(registered(ssmtypes.PingStatusOnline, time.Now().Add(time.Minute)), nil),
+	)
+	gomock.InOrder(
+		mockEC2.EXPECT().StartInstances(gomock.Any(), gomock.Any()).Return(&ec2.StartInstancesOutput{}, nil),
+		mockEC2.EXPECT().DescribeInstances(gomock.Any(), gomock.Any()).
+			Return(instanceOutput("i-1", types.InstanceStateNameRunning), nil),
+	)
+	// No DescribeInstanceConnectEndpoints call is expected
+
+	stopped := EC2Instance{InstanceId: "i-1", Name: "web", State: "stopped", VpcId: "vpc-1"}
+	instance, endpoints, err := manager.startForConnect(context.Background(), stopped)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if instance.Transport != "" || endpoints != nil {
+		t.Errorf("Expected an SSM connection, got %+v with endpoints %+v", instance, endpoints)
+	}
+}
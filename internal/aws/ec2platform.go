@@ -0,0 +1,229 @@
+package aws
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	ssmtypes "github.com/aws/aws-sdk-go-v2/service/ssm/types"
+	"github.com/blontic/awsc/internal/debug"
+)
+
+// DescribeImages accepts up to 200 values per filter
+const describeImagesMaxIds = 200
+
+// osNamePattern picks a short OS name and version out of an AMI description or name
+var osNamePattern = regexp.MustCompile(`(?i)(amazon linux( \d+)?|ubuntu[ ,]+(\d+\.\d+)|windows[ _]server[ _-](\d{4}( r2)?)|red hat enterprise linux \d+(\.\d+)?|rhel-\d+(\.\d+)?|debian[ -]\d+|suse linux enterprise server \d+|sles-\d+|rocky[ -]linux[ -]\d+|rocky-\d+|almalinux[ -]\d+|centos( stream)? \d+|macos \w+)`)
+
+// imageOS is what an AMI says about the operating system of instances launched from it
+type imageOS struct {
+	windows bool
+	name    string
+}
+
+// ssmInstanceInfo returns the SSM instance information for a managed instance, or nil when
+// the instance has no SSM agent or the lookup fails
+func (e *EC2Manager) ssmInstanceInfo(ctx context.Context, instanceId string) *ssmtypes.InstanceInformation {
+	info, err := e.describeSSMInstance(ctx, instanceId)
+	if err != nil {
+		debug.Printf("Failed to describe SSM instance %s: %v\n", instanceId, err)
+		return nil
+	}
+	return info
+}
+
+// describeSSMInstance returns the SSM instance information for a managed instance, nil when
+// the instance has no SSM agent, or the error from SSM
+func (e *EC2Manager) describeSSMInstance(ctx context.Context, instanceId string) (*ssmtypes.InstanceInformation, error) {
+	input := &ssm.DescribeInstanceInformationInput{
+		Filters: []ssmtypes.InstanceInformationStringFilter{
+			{
+				Key:    aws.String("InstanceIds"),
+				Values: []string{instanceId},
+			},
+		},
+	}
+
+	result, err := e.ssmClient.DescribeInstanceInformation(ctx, input)
+	if err != nil {
+		if IsAuthError(err) {
+			if shouldReauth, reAuthErr := e.promptForReauth(ctx); shouldReauth && reAuthErr == nil {
+				// Reload all clients with fresh credentials
+				if reloadErr := e.reloadClients(ctx); reloadErr != nil {
+					return nil, reloadErr
+				}
+				// Retry after re-authentication
+				result, err = e.ssmClient.DescribeInstanceInformation(ctx, input)
+				if err != nil {
+					return nil, err
+				}
+			} else {
+				return nil, err
+			}
+		} else {
+			return nil, err
+		}
+	}
+
+	if len(result.InstanceInformationList) == 0 {
+		return nil, nil
+	}
+	return &result.InstanceInformationList[0], nil
+}
+
+// applySSMPlatform takes the platform and OS name the SSM agent reports, which is more
+// reliable than anything EC2 knows about the instance
+func applySSMPlatform(instance *EC2Instance, info *ssmtypes.InstanceInformation) {
+	switch info.PlatformType {
+	case ssmtypes.PlatformTypeWindows:
+		instance.Platform = "Windows"
+	case ssmtypes.PlatformTypeLinux:
+		instance.Platform = "Linux"
+	case ssmtypes.PlatformTypeMacos:
+		instance.Platform = "macOS"
+	}
+
+	name := strings.TrimSpace(aws.ToString(info.PlatformName))
+	if name == "" {
+		return
+	}
+	// Windows versions are build numbers, the name already says which release it is
+	if version := aws.ToString(info.PlatformVersion); version != "" && info.PlatformType != ssmtypes.PlatformTypeWindows {
+		name += " " + version
+	}
+	instance.OSName = name
+}
+
+// applyImagePlatforms fills in the platform and OS name from each instance's AMI where the
+// SSM agent didn't report them. Images are looked up once per manager.
+func (e *EC2Manager) applyImagePlatforms(ctx context.Context, instances []EC2Instance) {
+	if e.imageCache == nil {
+		e.imageCache = map[string]imageOS{}
+	}
+
+	var missing []string
+	seen := map[string]bool{}
+	for _, instance := range instances {
+		if instance.OSName != "" || instance.ImageId == "" || seen[instance.ImageId] {
+			continue
+		}
+		seen[instance.ImageId] = true
+		if _, ok := e.imageCache[instance.ImageId]; !ok {
+			missing = append(missing, instance.ImageId)
+		}
+	}
+
+	for start := 0; start < len(missing); start += describeImagesMaxIds {
+		batch := missing[start:min(start+describeImagesMaxIds, len(missing))]
+		images, err := e.describeImages(ctx, batch)
+		if err != nil {
+			// The instance data is still usable without the OS names
+			debug.Printf("Error describing images: %v\n", err)
+			break
+		}
+		// Images that are gone or not shared aren't returned, remember them as unknown
+		for _, id := range batch {
+			e.imageCache[id] = imageOS{}
+		}
+		for _, image := range images {
+			e.imageCache[aws.ToString(image.ImageId)] = imageOSFromImage(image)
+		}
+	}
+
+	for i, instance := range instances {
+		if instance.OSName != "" {
+			continue
+		}
+		image, ok := e.imageCache[instance.ImageId]
+		if !ok {
+			continue
+		}
+		if image.windows {
+			instances[i].Platform = "Windows"
+		}
+		instances[i].OSName = image.name
+	}
+}
+
+// describeImages returns the images among ids that still exist and are visible to the
+// account. Unlike ImageIds, the image-id filter skips missing images instead of failing.
+func (e *EC2Manager) describeImages(ctx context.Context, ids []string) ([]types.Image, error) {
+	var images []types.Image
+	var nextToken *string
+
+	for {
+		input := &ec2.DescribeImagesInput{
+			Filters:           []types.Filter{{Name: aws.String("image-id"), Values: ids}},
+			IncludeDeprecated: aws.Bool(true),
+			NextToken:         nextToken,
+		}
+		result, err := e.ec2Client.DescribeImages(ctx, input)
+		if err != nil {
+			if IsAuthError(err) {
+				if shouldReauth, reAuthErr := e.promptForReauth(ctx); shouldReauth && reAuthErr == nil {
+					if reloadErr := e.reloadClients(ctx); reloadErr != nil {
+						return nil, reloadErr
+					}
+					result, err = e.ec2Client.DescribeImages(ctx, input)
+					if err != nil {
+						return nil, err
+					}
+				} else {
+					return nil, err
+				}
+			} else {
+				return nil, err
+			}
+		}
+
+		images = append(images, result.Images...)
+
+		if result.NextToken == nil {
+			break
+		}
+		nextToken = result.NextToken
+	}
+
+	return images, nil
+}
+
+func imageOSFromImage(image types.Image) imageOS {
+	details := aws.ToString(image.PlatformDetails)
+	name := aws.ToString(image.Name)
+	description := aws.ToString(image.Description)
+
+	detected := imageOS{
+		windows: image.Platform == types.PlatformValuesWindows ||
+			strings.Contains(strings.ToLower(details), "windows") ||
+			strings.Contains(strings.ToLower(name), "windows"),
+	}
+
+	for _, text := range []string{description, name} {
+		if match := osNamePattern.FindString(text); match != "" {
+			detected.name = strings.NewReplacer("_", " ", "-", " ", ",", "").Replace(match)
+			break
+		}
+	}
+	// Marketplace images like "Windows with SQL Server Standard" say more than the AMI name
+	if strings.Contains(details, "SQL Server") {
+		if detected.name == "" {
+			detected.name = details
+		} else {
+			detected.name += " (" + strings.TrimPrefix(details, "Windows with ") + ")"
+		}
+	}
+
+	return detected
+}
+
+// platformLabel is the OS shown for the instance in selectors
+func (i EC2Instance) platformLabel() string {
+	if i.OSName != "" {
+		return i.OSName
+	}
+	return i.Platform
+}
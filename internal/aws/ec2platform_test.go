@@ -0,0 +1,177 @@
+package aws
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	ssmtypes "github.com/aws/aws-sdk-go-v2/service/ssm/types"
+	"github.com/blontic/awsc/internal/aws/mocks"
+	"go.uber.org/mock/gomock"
+)
+
+func TestImageOSFromImage(t *testing.T) {
+	tests := []struct {
+		name        string
+		image       types.Image
+		wantWindows bool
+		wantName    string
+	}{
+		{
+			name: "Amazon Linux",
+			image: types.Image{
+				Name:            aws.String("al2023-ami-2023.4.20240401.1-kernel-6.1-x86_64"),
+				Description:     aws.String("Amazon Linux 2023 AMI 2023.4.20240401.1 x86_64 HVM kernel-6.1"),
+				PlatformDetails: aws.String("Linux/UNIX"),
+			},
+			wantName: "Amazon Linux 2023",
+		},
+		{
+			name: "Ubuntu",
+			image: types.Image{
+				Name:        aws.String("ubuntu/images/hvm-ssd/ubuntu-jammy-22.04-amd64-server-20240301"),
+				Description: aws.String("Canonical, Ubuntu, 22.04 LTS, amd64 jammy image build on 2024-03-01"),
+			},
+			wantName: "Ubuntu 22.04",
+		},
+		{
+			name: "Windows BYOL from name only",
+			image: types.Image{
+				Name:            aws.String("Windows_Server-2019-English-Full-Base-imported"),
+				PlatformDetails: aws.String("Linux/UNIX"),
+			},
+			wantWindows: true,
+			wantName:    "Windows Server 2019",
+		},
+		{
+			name: "Windows with SQL Server",
+			image: types.Image{
+				Name:            aws.String("Windows_Server-2022-English-Full-SQL_2019_Standard-2024.03.13"),
+				Description:     aws.String("Microsoft Windows Server 2022 with SQL Server 2019 Standard"),
+				PlatformDetails: aws.String("Windows with SQL Server Standard"),
+				Platform:        types.PlatformValuesWindows,
+			},
+			wantWindows: true,
+			wantName:    "Windows Server 2022 (SQL Server Standard)",
+		},
+		{
+			name:  "unknown image",
+			image: types.Image{Name: aws.String("golden-image-42")},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := imageOSFromImage(tt.image)
+			if got.windows != tt.wantWindows {
+				t.Errorf("Expected windows=%v, got %v", tt.wantWindows, got.windows)
+			}
+			if got.name != tt.wantName {
+				t.Errorf("Expected name %q, got %q", tt.wantName, got.name)
+			}
+		})
+	}
+}
+
+func TestApplySSMPlatform(t *testing.T) {
+	linux := EC2Instance{Platform: "Linux"}
+	applySSMPlatform(&linux, &ssmtypes.InstanceInformation{
+		PlatformType:    ssmtypes.PlatformTypeLinux,
+		PlatformName:    aws.String("Ubuntu"),
+		PlatformVersion: aws.String("22.04"),
+	})
+	if linux.OSName != "Ubuntu 22.04" {
+		t.Errorf("Expected 'Ubuntu 22.04', got %q", linux.OSName)
+	}
+
+	// An instance EC2 thinks is Linux, e.g. from an imported BYOL image
+	windows := EC2Instance{Platform: "Linux"}
+	applySSMPlatform(&windows, &ssmtypes.InstanceInformation{
+		PlatformType:    ssmtypes.PlatformTypeWindows,
+		PlatformName:    aws.String("Microsoft Windows Server 2022 Datacenter"),
+		PlatformVersion: aws.String("10.0.20348"),
+	})
+	if windows.Platform != "Windows" {
+		t.Errorf("Expected platform Windows, got %q", windows.Platform)
+	}
+	if windows.OSName != "Microsoft Windows Server 2022 Datacenter" {
+		t.Errorf("Expected the Windows name without the build number, got %q", windows.OSName)
+	}
+}
+
+func TestEC2Manager_ListAllInstances_ImagePlatforms(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEC2 := mocks.NewMockEC2Client(ctrl)
+	mockSSM := mocks.NewMockSSMClient(ctrl)
+	manager, _ := NewEC2Manager(context.Background(), EC2ManagerOptions{EC2Client: mockEC2, SSMClient: mockSSM, Region: "us-east-1"})
+
+	mockEC2.EXPECT().
+		DescribeInstances(gomock.Any(), gomock.Any()).
+		Return(&ec2.DescribeInstancesOutput{
+			Reservations: []types.Reservation{{
+				Instances: []types.Instance{
+					{
+						InstanceId: aws.String("i-managed"),
+						ImageId:    aws.String("ami-linux"),
+						State:      &types.InstanceState{Name: types.InstanceStateNameRunning},
+					},
+					{
+						InstanceId: aws.String("i-stopped"),
+						ImageId:    aws.String("ami-windows"),
+						State:      &types.InstanceState{Name: types.InstanceStateNameStopped},
+					},
+				},
+			}},
+		}, nil).
+		Times(2)
+
+	mockSSM.EXPECT().
+		DescribeInstanceInformation(gomock.Any(), gomock.Any()).
+		Return(&ssm.DescribeInstanceInformationOutput{
+			InstanceInformationList: []ssmtypes.InstanceInformation{{
+				InstanceId:      aws.String("i-managed"),
+				PlatformType:    ssmtypes.PlatformTypeLinux,
+				PlatformName:    aws.String("Amazon Linux"),
+				PlatformVersion: aws.String("2023"),
+			}},
+		}, nil).
+		Times(2)
+
+	// Only the image of the instance SSM knows nothing about is described, and only once
+	mockEC2.EXPECT().
+		DescribeImages(gomock.Any(), &ec2.DescribeImagesInput{
+			Filters:           []types.Filter{{Name: aws.String("image-id"), Values: []string{"ami-windows"}}},
+			IncludeDeprecated: aws.Bool(true),
+		}).
+		Return(&ec2.DescribeImagesOutput{
+			Images: []types.Image{{
+				ImageId:         aws.String("ami-windows"),
+				Name:            aws.String("Windows_Server-2022-English-Full-Base-2024.03.13"),
+				PlatformDetails: aws.String("Windows"),
+			}},
+		}, nil).
+		Times(1)
+
+	for range 2 {
+		instances, err := manager.ListAllInstances(context.Background())
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		byId := map[string]EC2Instance{}
+		for _, instance := range instances {
+			byId[instance.InstanceId] = instance
+		}
+		if got := byId["i-managed"].platformLabel(); got != "Amazon Linux 2023" {
+			t.Errorf("Expected SSM OS name for i-managed, got %q", got)
+		}
+		if byId["i-stopped"].Platform != "Windows" || byId["i-stopped"].OSName != "Windows Server 2022" {
+			t.Errorf("Expected Windows Server 2022 from the AMI, got %+v", byId["i-stopped"])
+		}
+	}
+}
@@ -0,0 +1,208 @@
+package aws
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+)
+
+// rdpClientWait is how long to wait for the forward to listen before launching the client
+const rdpClientWait = 30 * time.Second
+
+// RDPOptions adds steps around the RDP port forward
+type RDPOptions struct {
+	Open          bool   // Launch an RDP client at the local port once it is listening
+	KeyPath       string // Decrypt the Administrator password with this key pair private key
+	PrintPassword bool   // Print the password when it can't be copied to the clipboard
+}
+
+// prepareRDP fetches the password and schedules the client launch before the forward
+// starts blocking
+func (e *EC2Manager) prepareRDP(ctx context.Context, instance EC2Instance, localPort int32, opts RDPOptions) error {
+	if opts.KeyPath != "" {
+		password, err := e.GetWindowsPassword(ctx, instance.InstanceId, opts.KeyPath)
+		if err != nil {
+			return err
+		}
+		if err := copyToClipboard(password); err != nil {
+			// Only print the password to the terminal when asked to, it may end up in logs
+			if opts.PrintPassword {
+				fmt.Printf("Administrator password: %s\n", password)
+			} else {
+				fmt.Fprintf(os.Stderr, "Failed to copy the Administrator password to the clipboard: %v (use --print-password to print it instead)\n", err)
+			}
+		} else {
+			fmt.Printf("✓ Administrator password copied to clipboard\n")
+		}
+	}
+
+	if opts.Open {
+		go func() {
+			if err := waitForPort(int(localPort), rdpClientWait, nil); err != nil {
+				fmt.Fprintf(os.Stderr, "Not launching RDP client: %v\n", err)
+				return
+			}
+			if err := openRDPClient(instance, int(localPort)); err != nil {
+				fmt.Fprintf(os.Stderr, "Failed to launch RDP client: %v\n", err)
+			}
+		}()
+	}
+
+	return nil
+}
+
+// GetWindowsPassword returns the instance's Administrator password, decrypted with the
+// private key of the key pair it was launched with
+func (e *EC2Manager) GetWindowsPassword(ctx context.Context, instanceId, keyPath string) (string, error) {
+	keyPEM, err := os.ReadFile(expandHome(keyPath))
+	if err != nil {
+		return "", fmt.Errorf("failed to read private key: %w", err)
+	}
+
+	input := &ec2.GetPasswordDataInput{InstanceId: aws.String(instanceId)}
+	result, err := e.ec2Client.GetPasswordData(ctx, input)
+	if err != nil {
+		if IsAuthError(err) {
+			if shouldReauth, reAuthErr := e.promptForReauth(ctx); shouldReauth && reAuthErr == nil {
+				if reloadErr := e.reloadClients(ctx); reloadErr != nil {
+					return "", reloadErr
+				}
+				result, err = e.ec2Client.GetPasswordData(ctx, input)
+				if err != nil {
+					return "", fmt.Errorf("failed to get password data: %w", err)
+				}
+			} else {
+				return "", fmt.Errorf("failed to get password data: %w", err)
+			}
+		} else {
+			return "", fmt.Errorf("failed to get password data: %w", err)
+		}
+	}
+
+	passwordData := strings.TrimSpace(aws.ToString(result.PasswordData))
+	if passwordData == "" {
+		return "", fmt.Errorf("no password available for %s yet; it takes a few minutes after launch and requires a key pair", instanceId)
+	}
+
+	return decryptWindowsPassword(passwordData, keyPEM)
+}
+
+// decryptWindowsPassword decrypts base64 GetPasswordData output with an RSA private key
+func decryptWindowsPassword(passwordData string, keyPEM []byte) (string, error) {
+	block, _ := pem.Decode(keyPEM)
+	if block == nil {
+		return "", fmt.Errorf("private key is not PEM encoded")
+	}
+	if block.Type == "OPENSSH PRIVATE KEY" {
+		return "", fmt.Errorf("OpenSSH format keys aren't supported, convert with: ssh-keygen -p -m PEM -f <key>")
+	}
+	if strings.Contains(block.Headers["Proc-Type"], "ENCRYPTED") {
+		return "", fmt.Errorf("encrypted private keys aren't supported")
+	}
+
+	var key *rsa.PrivateKey
+	if parsed, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		key = parsed
+	} else if parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes); err == nil {
+		rsaKey, ok := parsed.(*rsa.PrivateKey)
+		if !ok {
+			return "", fmt.Errorf("Windows passwords are encrypted with RSA key pairs only")
+		}
+		key = rsaKey
+	} else {
+		return "", fmt.Errorf("failed to parse private key: %w", err)
+	}
+
+	ciphertext, err := base64.StdEncoding.DecodeString(passwordData)
+	if err != nil {
+		return "", fmt.Errorf("failed to decode password data: %w", err)
+	}
+
+	password, err := rsa.DecryptPKCS1v15(rand.Reader, key, ciphertext)
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt password, is this the instance's key pair? %w", err)
+	}
+
+	return string(password), nil
+}
+
+// openRDPClient launches xfreerdp or Remmina on Linux, and otherwise opens a .rdp file
+// with the default RDP client
+func openRDPClient(instance EC2Instance, localPort int) error {
+	address := fmt.Sprintf("localhost:%d", localPort)
+
+	if runtime.GOOS == "linux" {
+		for _, client := range []string{"xfreerdp3", "xfreerdp"} {
+			if path, err := exec.LookPath(client); err == nil {
+				// The certificate is issued for the instance, not localhost
+				return exec.Command(path, "/v:"+address, "/u:Administrator", "/cert:ignore", "/dynamic-resolution").Start()
+			}
+		}
+		if path, err := exec.LookPath("remmina"); err == nil {
+			return exec.Command(path, "-c", "rdp://Administrator@"+address).Start()
+		}
+	}
+
+	rdpPath := filepath.Join(os.TempDir(), fmt.Sprintf("awsc-%s.rdp", instance.InstanceId))
+	if err := os.WriteFile(rdpPath, rdpFile(address), 0600); err != nil {
+		return fmt.Errorf("failed to write %s: %w", rdpPath, err)
+	}
+	fmt.Printf("Opening %s\n", rdpPath)
+
+	// cmd's start would split a path with spaces, so run the Windows client directly
+	if runtime.GOOS == "windows" {
+		return exec.Command("mstsc", rdpPath).Start()
+	}
+
+	// The OS opener handles files the same way as URLs
+	return openBrowser(rdpPath)
+}
+
+// rdpFile returns a minimal .rdp connection file for address
+func rdpFile(address string) []byte {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "full address:s:%s\r\n", address)
+	fmt.Fprintf(&b, "username:s:Administrator\r\n")
+	fmt.Fprintf(&b, "prompt for credentials:i:1\r\n")
+	fmt.Fprintf(&b, "authentication level:i:0\r\n")
+	return b.Bytes()
+}
+
+// copyToClipboard writes text to the system clipboard with whichever tool is available
+func copyToClipboard(text string) error {
+	var candidates [][]string
+	switch runtime.GOOS {
+	case "darwin":
+		candidates = [][]string{{"pbcopy"}}
+	case "windows":
+		candidates = [][]string{{"clip"}}
+	default:
+		candidates = [][]string{{"wl-copy"}, {"xclip", "-selection", "clipboard"}, {"xsel", "--clipboard", "--input"}}
+	}
+
+	for _, candidate := range candidates {
+		path, err := exec.LookPath(candidate[0])
+		if err != nil {
+			continue
+		}
+		cmd := exec.Command(path, candidate[1:]...)
+		cmd.Stdin = strings.NewReader(text)
+		return cmd.Run()
+	}
+
+	return fmt.Errorf("no clipboard tool found")
+}
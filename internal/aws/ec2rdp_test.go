@@ -0,0 +1,162 @@
+package aws
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/blontic/awsc/internal/aws/mocks"
+	"go.uber.org/mock/gomock"
+)
+
+func encryptTestPassword(t *testing.T, key *rsa.PrivateKey, password string) string {
+	t.Helper()
+	ciphertext, err := rsa.EncryptPKCS1v15(rand.Reader, &key.PublicKey, []byte(password))
+	if err != nil {
+		t.Fatalf("Failed to encrypt: %v", err)
+	}
+	return base64.StdEncoding.EncodeToString(ciphertext)
+}
+
+func TestDecryptWindowsPassword(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+	passwordData := encryptTestPassword(t, key, "Hunter2!")
+
+	pkcs8, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatalf("Failed to marshal key: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		keyPEM  []byte
+		wantErr string
+	}{
+		{"PKCS1", pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}), ""},
+		{"PKCS8", pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: pkcs8}), ""},
+		{"OpenSSH", pem.EncodeToMemory(&pem.Block{Type: "OPENSSH PRIVATE KEY", Bytes: []byte("x")}), "ssh-keygen -p -m PEM"},
+		{"not PEM", []byte("not a key"), "not PEM encoded"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			password, err := decryptWindowsPassword(passwordData, tt.keyPEM)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("Expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if password != "Hunter2!" {
+				t.Errorf("Expected 'Hunter2!', got %q", password)
+			}
+		})
+	}
+}
+
+func TestEC2Manager_GetWindowsPassword(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+	keyPath := filepath.Join(t.TempDir(), "key.pem")
+	os.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}), 0600)
+
+	mockEC2 := mocks.NewMockEC2Client(ctrl)
+	manager, _ := NewEC2Manager(context.Background(), EC2ManagerOptions{EC2Client: mockEC2, SSMClient: mocks.NewMockSSMClient(ctrl), Region: "us-east-1"})
+
+	gomock.InOrder(
+		mockEC2.EXPECT().
+			GetPasswordData(gomock.Any(), &ec2.GetPasswordDataInput{InstanceId: aws.String("i-win")}).
+			Return(&ec2.GetPasswordDataOutput{PasswordData: aws.String("")}, nil),
+		mockEC2.EXPECT().
+			GetPasswordData(gomock.Any(), &ec2.GetPasswordDataInput{InstanceId: aws.String("i-win")}).
+			Return(&ec2.GetPasswordDataOutput{PasswordData: aws.String("\n" + encryptTestPassword(t, key, "s3cret") + "\n")}, nil),
+	)
+
+	// Password data is empty until Windows has finished its first boot
+	if _, err := manager.GetWindowsPassword(context.Background(), "i-win", keyPath); err == nil || !strings.Contains(err.Error(), "no password available") {
+		t.Errorf("Expected no password error, got %v", err)
+	}
+
+	password, err := manager.GetWindowsPassword(context.Background(), "i-win", keyPath)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if password != "s3cret" {
+		t.Errorf("Expected 's3cret', got %q", password)
+	}
+}
+
+func TestEC2Manager_prepareRDP_PrintPassword(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+	keyPath := filepath.Join(t.TempDir(), "key.pem")
+	os.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}), 0600)
+
+	mockEC2 := mocks.NewMockEC2Client(ctrl)
+	manager, _ := NewEC2Manager(context.Background(), EC2ManagerOptions{EC2Client: mockEC2, SSMClient: mocks.NewMockSSMClient(ctrl), Region: "us-east-1"})
+	mockEC2.EXPECT().
+		GetPasswordData(gomock.Any(), gomock.Any()).
+		Return(&ec2.GetPasswordDataOutput{PasswordData: aws.String(encryptTestPassword(t, key, "s3cret"))}, nil).
+		Times(2)
+
+	// No clipboard tool can be found
+	t.Setenv("PATH", "")
+
+	prepare := func(printPassword bool) string {
+		t.Helper()
+		r, w, _ := os.Pipe()
+		stdout := os.Stdout
+		os.Stdout = w
+		err := manager.prepareRDP(context.Background(), EC2Instance{InstanceId: "i-win"}, 3389, RDPOptions{KeyPath: keyPath, PrintPassword: printPassword})
+		os.Stdout = stdout
+		w.Close()
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		out, _ := io.ReadAll(r)
+		return string(out)
+	}
+
+	if out := prepare(false); strings.Contains(out, "s3cret") {
+		t.Errorf("Expected the password not to be printed without --print-password, got %q", out)
+	}
+	if out := prepare(true); !strings.Contains(out, "Administrator password: s3cret") {
+		t.Errorf("Expected the password to be printed with --print-password, got %q", out)
+	}
+}
+
+func TestRDPFile(t *testing.T) {
+	content := string(rdpFile("localhost:13389"))
+
+	for _, line := range []string{"full address:s:localhost:13389\r\n", "username:s:Administrator\r\n"} {
+		if !strings.Contains(content, line) {
+			t.Errorf("Expected .rdp file to contain %q, got %q", line, content)
+		}
+	}
+}
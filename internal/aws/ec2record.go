@@ -0,0 +1,54 @@
+package aws
+
+import (
+	"fmt"
+
+	"github.com/blontic/awsc/internal/recording"
+)
+
+// startRecording creates the recording file for a session to instance
+func (e *EC2Manager) startRecording(instance EC2Instance, user string) (*recording.Recorder, error) {
+	path, err := recording.NewPath(instance.InstanceId)
+	if err != nil {
+		return nil, err
+	}
+
+	width, height := recording.TerminalSize()
+	rec, err := recording.Create(path, width, height, e.recordingMetadata(instance, user))
+	if err != nil {
+		return nil, err
+	}
+
+	fmt.Printf("Recording session to %s\n", path)
+	return rec, nil
+}
+
+// stopRecording finishes the recording, reporting rather than returning errors so the
+// session's own error isn't lost
+func (e *EC2Manager) stopRecording(rec *recording.Recorder) {
+	if err := rec.Close(); err != nil {
+		fmt.Printf("Failed to save recording %s: %v\n", rec.Path(), err)
+		return
+	}
+	fmt.Printf("Recording saved to %s\n", rec.Path())
+}
+
+// recordingMetadata describes the account, role and instance a session was recorded in
+func (e *EC2Manager) recordingMetadata(instance EC2Instance, user string) recording.Metadata {
+	metadata := recording.Metadata{
+		Region:       e.region,
+		InstanceId:   instance.InstanceId,
+		InstanceName: instance.Name,
+		User:         user,
+	}
+
+	profile, session := currentSession()
+	metadata.Profile = profile
+	if session != nil {
+		metadata.AccountId = session.AccountID
+		metadata.AccountName = session.AccountName
+		metadata.RoleName = session.RoleName
+	}
+
+	return metadata
+}
@@ -0,0 +1,34 @@
+package aws
+
+import (
+	"os"
+	"testing"
+
+	awscconfig "github.com/blontic/awsc/internal/config"
+)
+
+func TestEC2Manager_RecordingMetadata(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("AWSC_PROFILE", "")
+	if err := awscconfig.SaveSession(os.Getppid(), "awsc-prod", "123456789012", "Production", "Admin"); err != nil {
+		t.Fatalf("SaveSession failed: %v", err)
+	}
+
+	manager := &EC2Manager{region: "eu-west-1"}
+	instance := EC2Instance{InstanceId: "i-123", Name: "web-1"}
+
+	metadata := manager.recordingMetadata(instance, "ec2-user")
+	if metadata.Profile != "awsc-prod" || metadata.AccountId != "123456789012" || metadata.AccountName != "Production" || metadata.RoleName != "Admin" {
+		t.Errorf("Expected session details, got %+v", metadata)
+	}
+	if metadata.Region != "eu-west-1" || metadata.InstanceId != "i-123" || metadata.InstanceName != "web-1" || metadata.User != "ec2-user" {
+		t.Errorf("Expected instance details, got %+v", metadata)
+	}
+
+	// A different profile from the environment isn't described by the session file
+	t.Setenv("AWSC_PROFILE", "awsc-dev")
+	metadata = manager.recordingMetadata(instance, "")
+	if metadata.Profile != "awsc-dev" || metadata.AccountId != "" {
+		t.Errorf("Expected only the profile name, got %+v", metadata)
+	}
+}
@@ -0,0 +1,181 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"path"
+	"sort"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/autoscaling"
+	ssmtypes "github.com/aws/aws-sdk-go-v2/service/ssm/types"
+)
+
+// AutoScalingClient interface for mocking
+type AutoScalingClient interface {
+	DescribeAutoScalingGroups(ctx context.Context, params *autoscaling.DescribeAutoScalingGroupsInput, optFns ...func(*autoscaling.Options)) (*autoscaling.DescribeAutoScalingGroupsOutput, error)
+}
+
+// connectMatching connects to the instance picked by name or Auto Scaling group. The
+// healthiest match is used when there is one, otherwise the user chooses among the
+// equally healthy matches; group members are picked without asking.
+func (e *EC2Manager) connectMatching(ctx context.Context, instances []EC2Instance, endpoints map[string][]InstanceConnectEndpoint, opts ConnectOptions) error {
+	var matches []EC2Instance
+	var what string
+	if opts.Group != "" {
+		members, err := e.healthyGroupMembers(ctx, opts.Group)
+		if err != nil {
+			return err
+		}
+		for _, instance := range instances {
+			if members[instance.InstanceId] {
+				matches = append(matches, instance)
+			}
+		}
+		what = fmt.Sprintf("Auto Scaling group %s", opts.Group)
+	} else {
+		matches = matchInstancesByName(instances, opts.Name)
+		what = fmt.Sprintf("name %q", opts.Name)
+	}
+
+	if len(matches) == 0 {
+		return fmt.Errorf("no EC2 instances found for %s%s", what, e.filterHint())
+	}
+
+	var available []EC2Instance
+	for _, instance := range matches {
+		if instance.IsSelectable {
+			available = append(available, instance)
+		}
+	}
+	if len(available) == 0 {
+		if len(matches) == 1 && matches[0].State == "stopped" {
+			fmt.Printf("Instance %s (%s) is stopped.\n", matches[0].Name, matches[0].InstanceId)
+			return e.startAndConnect(ctx, matches[0], opts)
+		}
+		return fmt.Errorf("none of the %d EC2 instances for %s are running with the SSM agent or an EC2 Instance Connect Endpoint", len(matches), what)
+	}
+
+	healthiest := healthiestInstances(available)
+
+	var target EC2Instance
+	switch {
+	case len(healthiest) == 1:
+		target = healthiest[0]
+	case opts.Group != "" && opts.Random:
+		target = healthiest[rand.IntN(len(healthiest))]
+	case opts.Group != "":
+		sort.Slice(healthiest, func(i, j int) bool {
+			return healthiest[i].InstanceId < healthiest[j].InstanceId
+		})
+		target = healthiest[0]
+	default:
+		selected, err := e.selectInstance(fmt.Sprintf("Select EC2 Instance for %s:", what), healthiest)
+		if err != nil {
+			return err
+		}
+		target = *selected
+	}
+
+	fmt.Printf("Connecting to instance: %s (%s)\n", target.Name, target.InstanceId)
+	return e.connectInstance(ctx, target, endpoints, opts)
+}
+
+// matchInstancesByName returns the instances whose Name tag is name, or failing that, the
+// ones matching it as a glob such as "web-*". Like the tag:Name filter EC2 applies first,
+// matching is case-sensitive.
+func matchInstancesByName(instances []EC2Instance, name string) []EC2Instance {
+	var matches []EC2Instance
+	for _, instance := range instances {
+		if instance.Name == name {
+			matches = append(matches, instance)
+		}
+	}
+	if len(matches) > 0 {
+		return matches
+	}
+
+	for _, instance := range instances {
+		if ok, _ := path.Match(name, instance.Name); ok {
+			matches = append(matches, instance)
+		}
+	}
+	return matches
+}
+
+// healthRank orders instances by how likely a session to them is to work, lowest first
+func healthRank(instance EC2Instance) int {
+	switch {
+	case instance.PingStatus == string(ssmtypes.PingStatusOnline):
+		return 0
+	case instance.Transport == TransportEICE:
+		return 1
+	case instance.PingStatus == string(ssmtypes.PingStatusConnectionLost):
+		return 2
+	default:
+		return 3
+	}
+}
+
+// healthiestInstances returns the instances sharing the best health rank
+func healthiestInstances(instances []EC2Instance) []EC2Instance {
+	best := -1
+	var healthiest []EC2Instance
+	for _, instance := range instances {
+		rank := healthRank(instance)
+		switch {
+		case best == -1 || rank < best:
+			best = rank
+			healthiest = []EC2Instance{instance}
+		case rank == best:
+			healthiest = append(healthiest, instance)
+		}
+	}
+	return healthiest
+}
+
+// healthyGroupMembers returns the IDs of the group's in-service instances that Auto
+// Scaling considers healthy
+func (e *EC2Manager) healthyGroupMembers(ctx context.Context, groupName string) (map[string]bool, error) {
+	input := &autoscaling.DescribeAutoScalingGroupsInput{
+		AutoScalingGroupNames: []string{groupName},
+	}
+
+	result, err := e.autoScalingClient.DescribeAutoScalingGroups(ctx, input)
+	if err != nil {
+		if IsAuthError(err) {
+			if shouldReauth, reAuthErr := e.promptForReauth(ctx); shouldReauth && reAuthErr == nil {
+				// Reload all clients with fresh credentials
+				if reloadErr := e.reloadClients(ctx); reloadErr != nil {
+					return nil, reloadErr
+				}
+				// Retry after re-authentication
+				result, err = e.autoScalingClient.DescribeAutoScalingGroups(ctx, input)
+				if err != nil {
+					return nil, fmt.Errorf("failed to describe Auto Scaling group %s: %w", groupName, err)
+				}
+			} else {
+				return nil, fmt.Errorf("failed to describe Auto Scaling group %s: %w", groupName, err)
+			}
+		} else {
+			return nil, fmt.Errorf("failed to describe Auto Scaling group %s: %w", groupName, err)
+		}
+	}
+
+	if len(result.AutoScalingGroups) == 0 {
+		return nil, fmt.Errorf("Auto Scaling group %s not found in region %s", groupName, e.region)
+	}
+
+	members := map[string]bool{}
+	for _, instance := range result.AutoScalingGroups[0].Instances {
+		if aws.ToString(instance.HealthStatus) == "Healthy" && instance.LifecycleState == "InService" {
+			members[aws.ToString(instance.InstanceId)] = true
+		}
+	}
+	if len(members) == 0 {
+		return nil, fmt.Errorf("Auto Scaling group %s has no healthy in-service instances", groupName)
+	}
+
+	return members, nil
+}
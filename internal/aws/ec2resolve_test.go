@@ -0,0 +1,172 @@
+package aws
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/autoscaling"
+	asgtypes "github.com/aws/aws-sdk-go-v2/service/autoscaling/types"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	ssmtypes "github.com/aws/aws-sdk-go-v2/service/ssm/types"
+	"github.com/blontic/awsc/internal/aws/mocks"
+	"go.uber.org/mock/gomock"
+)
+
+func instanceIds(instances []EC2Instance) []string {
+	ids := make([]string, len(instances))
+	for i, instance := range instances {
+		ids[i] = instance.InstanceId
+	}
+	return ids
+}
+
+func TestMatchInstancesByName(t *testing.T) {
+	instances := []EC2Instance{
+		{InstanceId: "i-1", Name: "web"},
+		{InstanceId: "i-2", Name: "web-1"},
+		{InstanceId: "i-3", Name: "Web-2"},
+		{InstanceId: "i-4", Name: "db-1"},
+	}
+
+	tests := []struct {
+		name string
+		want []string
+	}{
+		{"web", []string{"i-1"}},
+		{"web-*", []string{"i-2"}},
+		{"Web-?", []string{"i-3"}},
+		{"WEB", nil},
+		{"*-1", []string{"i-2", "i-4"}},
+		{"cache", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := instanceIds(matchInstancesByName(instances, tt.name))
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("Expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestHealthiestInstances(t *testing.T) {
+	lost := EC2Instance{InstanceId: "i-lost", PingStatus: "ConnectionLost"}
+	eice := EC2Instance{InstanceId: "i-eice", Transport: TransportEICE}
+	online1 := EC2Instance{InstanceId: "i-online-1", PingStatus: "Online"}
+	online2 := EC2Instance{InstanceId: "i-online-2", PingStatus: "Online"}
+
+	if got := instanceIds(healthiestInstances([]EC2Instance{lost, online1, eice})); strings.Join(got, ",") != "i-online-1" {
+		t.Errorf("Expected the online instance, got %v", got)
+	}
+	if got := instanceIds(healthiestInstances([]EC2Instance{lost, online1, online2})); strings.Join(got, ",") != "i-online-1,i-online-2" {
+		t.Errorf("Expected both online instances, got %v", got)
+	}
+	if got := instanceIds(healthiestInstances([]EC2Instance{lost, eice})); strings.Join(got, ",") != "i-eice" {
+		t.Errorf("Expected the endpoint instance over a lost agent, got %v", got)
+	}
+}
+
+func TestEC2Manager_HealthyGroupMembers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockASG := mocks.NewMockAutoScalingClient(ctrl)
+	manager, err := NewEC2Manager(context.Background(), EC2ManagerOptions{
+		EC2Client:         mocks.NewMockEC2Client(ctrl),
+		SSMClient:         mocks.NewMockSSMClient(ctrl),
+		AutoScalingClient: mockASG,
+		Region:            "us-east-1",
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error creating manager: %v", err)
+	}
+
+	mockASG.EXPECT().
+		DescribeAutoScalingGroups(gomock.Any(), &autoscaling.DescribeAutoScalingGroupsInput{AutoScalingGroupNames: []string{"kafka"}}).
+		Return(&autoscaling.DescribeAutoScalingGroupsOutput{
+			AutoScalingGroups: []asgtypes.AutoScalingGroup{
+				{
+					Instances: []asgtypes.Instance{
+						{InstanceId: aws.String("i-1"), HealthStatus: aws.String("Healthy"), LifecycleState: asgtypes.LifecycleStateInService},
+						{InstanceId: aws.String("i-2"), HealthStatus: aws.String("Unhealthy"), LifecycleState: asgtypes.LifecycleStateInService},
+						{InstanceId: aws.String("i-3"), HealthStatus: aws.String("Healthy"), LifecycleState: asgtypes.LifecycleStateTerminating},
+					},
+				},
+			},
+		}, nil)
+
+	members, err := manager.healthyGroupMembers(context.Background(), "kafka")
+	if err != nil {
+		t.Fatalf("healthyGroupMembers failed: %v", err)
+	}
+	if len(members) != 1 || !members["i-1"] {
+		t.Errorf("Expected only i-1, got %v", members)
+	}
+
+	mockASG.EXPECT().
+		DescribeAutoScalingGroups(gomock.Any(), gomock.Any()).
+		Return(&autoscaling.DescribeAutoScalingGroupsOutput{}, nil)
+
+	if _, err := manager.healthyGroupMembers(context.Background(), "missing"); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("Expected not found error, got %v", err)
+	}
+}
+
+func TestEC2Manager_RunConnect_ByName_NoneAvailable(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEC2 := mocks.NewMockEC2Client(ctrl)
+	mockSSM := mocks.NewMockSSMClient(ctrl)
+
+	manager, err := NewEC2Manager(context.Background(), EC2ManagerOptions{
+		EC2Client: mockEC2,
+		SSMClient: mockSSM,
+		Region:    "us-east-1",
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error creating manager: %v", err)
+	}
+
+	mockEC2.EXPECT().
+		DescribeInstances(gomock.Any(), gomock.Any()).
+		Return(&ec2.DescribeInstancesOutput{
+			Reservations: []types.Reservation{
+				{
+					Instances: []types.Instance{
+						{
+							InstanceId: aws.String("i-1"),
+							State:      &types.InstanceState{Name: "running"},
+							Tags:       []types.Tag{{Key: aws.String("Name"), Value: aws.String("web-1")}},
+						},
+						{
+							InstanceId: aws.String("i-2"),
+							State:      &types.InstanceState{Name: "terminated"},
+							Tags:       []types.Tag{{Key: aws.String("Name"), Value: aws.String("web-2")}},
+						},
+					},
+				},
+			},
+		}, nil).
+		Times(2)
+	mockEC2.EXPECT().DescribeImages(gomock.Any(), gomock.Any()).Return(&ec2.DescribeImagesOutput{}, nil).AnyTimes()
+	mockSSM.EXPECT().
+		DescribeInstanceInformation(gomock.Any(), gomock.Any()).
+		Return(&ssm.DescribeInstanceInformationOutput{InstanceInformationList: []ssmtypes.InstanceInformation{}}, nil).
+		AnyTimes()
+
+	err = manager.RunConnect(context.Background(), "", ConnectOptions{Name: "web-*"})
+	if err == nil || !strings.Contains(err.Error(), "none of the 2 EC2 instances") {
+		t.Errorf("Expected error about unavailable matches, got: %v", err)
+	}
+
+	err = manager.RunConnect(context.Background(), "", ConnectOptions{Name: "db"})
+	if err == nil || !strings.Contains(err.Error(), `no EC2 instances found for name "db"`) {
+		t.Errorf("Expected error about no matches, got: %v", err)
+	}
+}
@@ -0,0 +1,321 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	ssmtypes "github.com/aws/aws-sdk-go-v2/service/ssm/types"
+)
+
+// commandPollInterval is how often GetCommandInvocation is polled while a command runs
+var commandPollInterval = time.Second
+
+// SendCommand accepts at most 50 instance IDs per call
+const sendCommandMaxTargets = 50
+
+// RunTargets selects the instances to run a command on: explicit instance IDs,
+// and/or running instances matching every Key=Value tag
+type RunTargets struct {
+	InstanceIds []string
+	Tags        []string
+}
+
+// commandInvocation tracks the output of one instance while its command runs
+type commandInvocation struct {
+	commandId string
+	instance  EC2Instance
+	stdout    lineWriter
+	stderr    lineWriter
+	done      bool
+}
+
+// RunCommand runs command on the target instances with SSM Run Command, streaming each
+// instance's output prefixed with its name. It returns an error if any target failed.
+func (e *EC2Manager) RunCommand(ctx context.Context, targets RunTargets, command string, stdout, stderr io.Writer) error {
+	instances, err := e.findRunTargets(ctx, targets)
+	if err != nil {
+		return err
+	}
+	if len(instances) == 0 {
+		return fmt.Errorf("no running instances match the given targets")
+	}
+
+	failed := 0
+	var linux, windows []EC2Instance
+	for _, instance := range instances {
+		info := e.ssmInstanceInfo(ctx, instance.InstanceId)
+		if info == nil {
+			fmt.Fprintf(stderr, "%s SSM agent not available, skipping\n", instancePrefix(instance))
+			failed++
+			continue
+		}
+		applySSMPlatform(&instance, info)
+		if strings.EqualFold(instance.Platform, "windows") {
+			windows = append(windows, instance)
+		} else {
+			linux = append(linux, instance)
+		}
+	}
+
+	var invocations []*commandInvocation
+	for _, group := range []struct {
+		document  string
+		instances []EC2Instance
+	}{
+		{"AWS-RunShellScript", linux},
+		{"AWS-RunPowerShellScript", windows},
+	} {
+		for start := 0; start < len(group.instances); start += sendCommandMaxTargets {
+			batch := group.instances[start:min(start+sendCommandMaxTargets, len(group.instances))]
+			batchInvocations, err := e.sendCommand(ctx, group.document, command, batch, stdout, stderr)
+			if err != nil {
+				return err
+			}
+			invocations = append(invocations, batchInvocations...)
+		}
+	}
+
+	failed += e.waitForInvocations(ctx, invocations, stderr)
+
+	if failed > 0 {
+		return fmt.Errorf("command failed on %d of %d instance(s)", failed, len(instances))
+	}
+	return nil
+}
+
+func (e *EC2Manager) sendCommand(ctx context.Context, document, command string, instances []EC2Instance, stdout, stderr io.Writer) ([]*commandInvocation, error) {
+	instanceIds := make([]string, len(instances))
+	for i, instance := range instances {
+		instanceIds[i] = instance.InstanceId
+	}
+
+	input := &ssm.SendCommandInput{
+		DocumentName: aws.String(document),
+		InstanceIds:  instanceIds,
+		Parameters:   map[string][]string{"commands": {command}},
+		Comment:      aws.String("awsc ec2 run"),
+	}
+
+	result, err := e.ssmClient.SendCommand(ctx, input)
+	if err != nil {
+		if IsAuthError(err) {
+			if shouldReauth, reAuthErr := e.promptForReauth(ctx); shouldReauth && reAuthErr == nil {
+				if reloadErr := e.reloadClients(ctx); reloadErr != nil {
+					return nil, reloadErr
+				}
+				result, err = e.ssmClient.SendCommand(ctx, input)
+				if err != nil {
+					return nil, fmt.Errorf("failed to send command: %w", err)
+				}
+			} else {
+				return nil, fmt.Errorf("failed to send command: %w", err)
+			}
+		} else {
+			return nil, fmt.Errorf("failed to send command: %w", err)
+		}
+	}
+
+	commandId := aws.ToString(result.Command.CommandId)
+	invocations := make([]*commandInvocation, len(instances))
+	for i, instance := range instances {
+		prefix := instancePrefix(instance) + " "
+		invocations[i] = &commandInvocation{
+			commandId: commandId,
+			instance:  instance,
+			stdout:    lineWriter{w: stdout, prefix: prefix},
+			stderr:    lineWriter{w: stderr, prefix: prefix},
+		}
+	}
+	return invocations, nil
+}
+
+// waitForInvocations polls every invocation until it finishes and returns the number that failed
+func (e *EC2Manager) waitForInvocations(ctx context.Context, invocations []*commandInvocation, stderr io.Writer) int {
+	failed := 0
+	remaining := len(invocations)
+
+	for remaining > 0 {
+		for _, inv := range invocations {
+			if inv.done {
+				continue
+			}
+
+			result, err := e.ssmClient.GetCommandInvocation(ctx, &ssm.GetCommandInvocationInput{
+				CommandId:  aws.String(inv.commandId),
+				InstanceId: aws.String(inv.instance.InstanceId),
+			})
+			if err != nil {
+				// The invocation isn't visible for a moment after SendCommand returns
+				var notYet *ssmtypes.InvocationDoesNotExist
+				if errors.As(err, &notYet) {
+					continue
+				}
+				fmt.Fprintf(stderr, "%s error getting command status: %v\n", instancePrefix(inv.instance), err)
+				inv.done = true
+				failed++
+				remaining--
+				continue
+			}
+
+			inv.stdout.update(aws.ToString(result.StandardOutputContent))
+			inv.stderr.update(aws.ToString(result.StandardErrorContent))
+
+			if !isTerminalInvocationStatus(result.Status) {
+				continue
+			}
+
+			inv.stdout.flush()
+			inv.stderr.flush()
+			inv.done = true
+			remaining--
+
+			if result.Status != ssmtypes.CommandInvocationStatusSuccess {
+				failed++
+				fmt.Fprintf(stderr, "%s %s (exit code %d)\n", instancePrefix(inv.instance), result.Status, result.ResponseCode)
+			}
+		}
+
+		if remaining == 0 {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			return failed + remaining
+		case <-time.After(commandPollInterval):
+		}
+	}
+
+	return failed
+}
+
+func isTerminalInvocationStatus(status ssmtypes.CommandInvocationStatus) bool {
+	switch status {
+	case ssmtypes.CommandInvocationStatusSuccess,
+		ssmtypes.CommandInvocationStatusFailed,
+		ssmtypes.CommandInvocationStatusCancelled,
+		ssmtypes.CommandInvocationStatusTimedOut:
+		return true
+	}
+	return false
+}
+
+// findRunTargets returns the running instances matching targets, sorted by name
+func (e *EC2Manager) findRunTargets(ctx context.Context, targets RunTargets) ([]EC2Instance, error) {
+	if len(targets.InstanceIds) == 0 && len(targets.Tags) == 0 {
+		return nil, fmt.Errorf("specify at least one --instance-id or --tag")
+	}
+
+	filters := []types.Filter{
+		{Name: aws.String("instance-state-name"), Values: []string{"running"}},
+	}
+	for _, tag := range targets.Tags {
+		key, value, ok := strings.Cut(tag, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid tag %q, expected Key=Value", tag)
+		}
+		filters = append(filters, types.Filter{Name: aws.String("tag:" + key), Values: []string{value}})
+	}
+
+	var instances []EC2Instance
+	var nextToken *string
+
+	for {
+		input := &ec2.DescribeInstancesInput{
+			InstanceIds: targets.InstanceIds,
+			Filters:     filters,
+			NextToken:   nextToken,
+		}
+		result, err := e.ec2Client.DescribeInstances(ctx, input)
+		if err != nil {
+			if IsAuthError(err) {
+				if shouldReauth, reAuthErr := e.promptForReauth(ctx); shouldReauth && reAuthErr == nil {
+					if reloadErr := e.reloadClients(ctx); reloadErr != nil {
+						return nil, reloadErr
+					}
+					result, err = e.ec2Client.DescribeInstances(ctx, input)
+					if err != nil {
+						return nil, err
+					}
+				} else {
+					return nil, err
+				}
+			} else {
+				return nil, err
+			}
+		}
+
+		for _, reservation := range result.Reservations {
+			for _, inst := range reservation.Instances {
+				instances = append(instances, EC2Instance{
+					InstanceId:       aws.ToString(inst.InstanceId),
+					Name:             e.getInstanceName(inst.Tags),
+					InstanceType:     string(inst.InstanceType),
+					State:            string(inst.State.Name),
+					Platform:         e.getPlatform(inst),
+					PrivateIp:        aws.ToString(inst.PrivateIpAddress),
+					VpcId:            aws.ToString(inst.VpcId),
+					SecurityGroupIds: groupIds(inst.SecurityGroups),
+					IsSelectable:     true,
+				})
+			}
+		}
+
+		if result.NextToken == nil {
+			break
+		}
+		nextToken = result.NextToken
+	}
+
+	sort.Slice(instances, func(i, j int) bool {
+		return instances[i].Name < instances[j].Name
+	})
+
+	return instances, nil
+}
+
+func instancePrefix(instance EC2Instance) string {
+	return fmt.Sprintf("[%s %s]", instance.Name, instance.InstanceId)
+}
+
+// lineWriter prints newly seen output one prefixed line at a time. SSM returns the
+// output collected so far on every poll, so only the part past written is new.
+type lineWriter struct {
+	w       io.Writer
+	prefix  string
+	written int
+	partial string
+}
+
+func (l *lineWriter) update(content string) {
+	if len(content) <= l.written {
+		return
+	}
+	l.partial += content[l.written:]
+	l.written = len(content)
+
+	for {
+		line, rest, ok := strings.Cut(l.partial, "\n")
+		if !ok {
+			break
+		}
+		fmt.Fprintf(l.w, "%s%s\n", l.prefix, line)
+		l.partial = rest
+	}
+}
+
+func (l *lineWriter) flush() {
+	if l.partial != "" {
+		fmt.Fprintf(l.w, "%s%s\n", l.prefix, l.partial)
+		l.partial = ""
+	}
+}
@@ -0,0 +1,137 @@
+package aws
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	ssmtypes "github.com/aws/aws-sdk-go-v2/service/ssm/types"
+	"github.com/blontic/awsc/internal/aws/mocks"
+	"go.uber.org/mock/gomock"
+)
+
+func TestEC2Manager_RunCommand(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	commandPollInterval = 0
+	defer func() { commandPollInterval = time.Second }()
+
+	mockEC2 := mocks.NewMockEC2Client(ctrl)
+	mockSSM := mocks.NewMockSSMClient(ctrl)
+	manager, _ := NewEC2Manager(context.Background(), EC2ManagerOptions{
+		EC2Client: mockEC2,
+		SSMClient: mockSSM,
+		Region:    "us-east-1",
+	})
+
+	mockEC2.EXPECT().
+		DescribeInstances(gomock.Any(), &ec2.DescribeInstancesInput{
+			Filters: []types.Filter{
+				{Name: aws.String("instance-state-name"), Values: []string{"running"}},
+				{Name: aws.String("tag:Role"), Values: []string{"web"}},
+			},
+		}).
+		Return(&ec2.DescribeInstancesOutput{
+			Reservations: []types.Reservation{{
+				Instances: []types.Instance{
+					{
+						InstanceId: aws.String("i-linux"),
+						State:      &types.InstanceState{Name: types.InstanceStateNameRunning},
+						Tags:       []types.Tag{{Key: aws.String("Name"), Value: aws.String("web-1")}},
+					},
+					{
+						InstanceId: aws.String("i-windows"),
+						State:      &types.InstanceState{Name: types.InstanceStateNameRunning},
+						Platform:   types.PlatformValuesWindows,
+						Tags:       []types.Tag{{Key: aws.String("Name"), Value: aws.String("web-2")}},
+					},
+				},
+			}},
+		}, nil)
+
+	mockSSM.EXPECT().
+		DescribeInstanceInformation(gomock.Any(), gomock.Any()).
+		Return(&ssm.DescribeInstanceInformationOutput{
+			InstanceInformationList: []ssmtypes.InstanceInformation{{InstanceId: aws.String("i-any")}},
+		}, nil).
+		Times(2)
+
+	mockSSM.EXPECT().
+		SendCommand(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, input *ssm.SendCommandInput, _ ...func(*ssm.Options)) (*ssm.SendCommandOutput, error) {
+			if len(input.InstanceIds) != 1 {
+				t.Errorf("Expected one instance per document, got %v", input.InstanceIds)
+			}
+			commandId := "cmd-" + input.InstanceIds[0]
+			wantDocument := "AWS-RunShellScript"
+			if input.InstanceIds[0] == "i-windows" {
+				wantDocument = "AWS-RunPowerShellScript"
+			}
+			if aws.ToString(input.DocumentName) != wantDocument {
+				t.Errorf("Expected %s for %s, got %s", wantDocument, input.InstanceIds[0], aws.ToString(input.DocumentName))
+			}
+			if input.Parameters["commands"][0] != "hostname" {
+				t.Errorf("Unexpected commands parameter %v", input.Parameters["commands"])
+			}
+			return &ssm.SendCommandOutput{Command: &ssmtypes.Command{CommandId: aws.String(commandId)}}, nil
+		}).
+		Times(2)
+
+	gomock.InOrder(
+		mockSSM.EXPECT().
+			GetCommandInvocation(gomock.Any(), &ssm.GetCommandInvocationInput{CommandId: aws.String("cmd-i-linux"), InstanceId: aws.String("i-linux")}).
+			Return(nil, &ssmtypes.InvocationDoesNotExist{}),
+		mockSSM.EXPECT().
+			GetCommandInvocation(gomock.Any(), &ssm.GetCommandInvocationInput{CommandId: aws.String("cmd-i-linux"), InstanceId: aws.String("i-linux")}).
+			Return(&ssm.GetCommandInvocationOutput{
+				Status:                ssmtypes.CommandInvocationStatusInProgress,
+				StandardOutputContent: aws.String("line one\nline "),
+			}, nil),
+		mockSSM.EXPECT().
+			GetCommandInvocation(gomock.Any(), &ssm.GetCommandInvocationInput{CommandId: aws.String("cmd-i-linux"), InstanceId: aws.String("i-linux")}).
+			Return(&ssm.GetCommandInvocationOutput{
+				Status:                ssmtypes.CommandInvocationStatusSuccess,
+				StandardOutputContent: aws.String("line one\nline two\n"),
+			}, nil),
+	)
+
+	mockSSM.EXPECT().
+		GetCommandInvocation(gomock.Any(), &ssm.GetCommandInvocationInput{CommandId: aws.String("cmd-i-windows"), InstanceId: aws.String("i-windows")}).
+		Return(&ssm.GetCommandInvocationOutput{
+			Status:               ssmtypes.CommandInvocationStatusFailed,
+			ResponseCode:         1,
+			StandardErrorContent: aws.String("access denied"),
+		}, nil)
+
+	var stdout, stderr bytes.Buffer
+	err := manager.RunCommand(context.Background(), RunTargets{Tags: []string{"Role=web"}}, "hostname", &stdout, &stderr)
+	if err == nil || !strings.Contains(err.Error(), "1 of 2") {
+		t.Errorf("Expected failure on 1 of 2 instances, got %v", err)
+	}
+
+	if stdout.String() != "[web-1 i-linux] line one\n[web-1 i-linux] line two\n" {
+		t.Errorf("Unexpected stdout %q", stdout.String())
+	}
+	if !strings.Contains(stderr.String(), "[web-2 i-windows] access denied\n") || !strings.Contains(stderr.String(), "Failed (exit code 1)") {
+		t.Errorf("Unexpected stderr %q", stderr.String())
+	}
+}
+
+func TestEC2Manager_RunCommand_InvalidTargets(t *testing.T) {
+	manager := &EC2Manager{}
+
+	if err := manager.RunCommand(context.Background(), RunTargets{}, "uptime", &bytes.Buffer{}, &bytes.Buffer{}); err == nil {
+		t.Error("Expected error without targets")
+	}
+
+	if err := manager.RunCommand(context.Background(), RunTargets{Tags: []string{"Role"}}, "uptime", &bytes.Buffer{}, &bytes.Buffer{}); err == nil {
+		t.Error("Expected error for tag without value")
+	}
+}
@@ -0,0 +1,190 @@
+package aws
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	awscconfig "github.com/blontic/awsc/internal/config"
+)
+
+// interactiveCommandDocument runs a single command as the session instead of a shell
+const interactiveCommandDocument = "AWS-StartInteractiveCommand"
+
+// runAsUserParameter is the parameter custom Session documents receive the run-as user in
+const runAsUserParameter = "runAsUser"
+
+// runAsUserPattern matches POSIX user names, which keeps them safe to use in a command
+var runAsUserPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.-]*\$?$`)
+
+// SessionDocument is the Session document an interactive session runs. The zero value is
+// the account's default shell document.
+type SessionDocument struct {
+	Name       string
+	Parameters map[string][]string
+}
+
+// ParseSessionParameters parses key=value pairs into document parameters; repeating a key
+// adds another value
+func ParseSessionParameters(pairs []string) (map[string][]string, error) {
+	if len(pairs) == 0 {
+		return nil, nil
+	}
+
+	parameters := map[string][]string{}
+	for _, pair := range pairs {
+		key, value, ok := strings.Cut(pair, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid parameter %q, expected key=value", pair)
+		}
+		parameters[key] = append(parameters[key], value)
+	}
+	return parameters, nil
+}
+
+// currentSession returns the profile in use and its session details, or a nil session when
+// they're unknown or AWSC_PROFILE points at a different profile
+func currentSession() (string, *awscconfig.SessionInfo) {
+	profile, err := awscconfig.CurrentProfileName()
+	if err != nil {
+		return "", nil
+	}
+
+	session, err := awscconfig.GetCurrentSession()
+	if err != nil || session.ProfileName != profile {
+		return profile, nil
+	}
+	return profile, session
+}
+
+// sessionDocument combines the connect options with the first matching ec2.sessions rule,
+// returning the document to start and the user the session runs as. The rule's user only
+// applies with the rule's document or the default shell, and is skipped with a notice
+// where it can't be used.
+func (e *EC2Manager) sessionDocument(instance EC2Instance, opts ConnectOptions) (SessionDocument, string, error) {
+	document := SessionDocument{Name: opts.Document, Parameters: opts.Parameters}
+	user := opts.User
+	userFromRule := false
+
+	rule, err := e.sessionRule(instance)
+	if err != nil {
+		return SessionDocument{}, "", err
+	}
+	if rule != nil {
+		if document.Name == "" && rule.Document != "" {
+			// Parameters from the command line add to the rule's
+			parameters, err := ParseSessionParameters(rule.Parameters)
+			if err != nil {
+				return SessionDocument{}, "", fmt.Errorf("invalid ec2.sessions config: %w", err)
+			}
+			for key, values := range opts.Parameters {
+				if parameters == nil {
+					parameters = map[string][]string{}
+				}
+				parameters[key] = values
+			}
+			document = SessionDocument{Name: rule.Document, Parameters: parameters}
+		}
+		if user == "" && opts.Document == "" && rule.User != "" {
+			user = rule.User
+			userFromRule = true
+		}
+	}
+
+	if instance.Transport == TransportEICE {
+		if opts.Document != "" {
+			return SessionDocument{}, "", fmt.Errorf("--document needs the SSM agent, %s is reached through an EC2 Instance Connect Endpoint", instance.InstanceId)
+		}
+		if user == "" {
+			user = "ec2-user"
+		}
+		return SessionDocument{}, user, nil
+	}
+
+	if document.Name == "" && len(document.Parameters) > 0 {
+		return SessionDocument{}, "", fmt.Errorf("parameters need a --document")
+	}
+	if user == "" {
+		return document, "", nil
+	}
+
+	if !runAsUserPattern.MatchString(user) {
+		if userFromRule {
+			return SessionDocument{}, "", fmt.Errorf("invalid ec2.sessions config: invalid user name %q", user)
+		}
+		return SessionDocument{}, "", fmt.Errorf("invalid user name %q", user)
+	}
+
+	// skipRuleUser starts the document as the default user when the rule's user can't apply
+	skipRuleUser := func(reason string) (SessionDocument, string, error) {
+		fmt.Printf("Not running as %s from the ec2.sessions config: %s\n", user, reason)
+		return document, "", nil
+	}
+
+	switch {
+	case document.Name == "":
+		// Without a custom document, switch user once the default shell is up
+		if strings.EqualFold(instance.Platform, "windows") {
+			if userFromRule {
+				return skipRuleUser("run-as on Windows needs a custom document")
+			}
+			return SessionDocument{}, "", fmt.Errorf("run-as on Windows needs a custom --document that accepts a %s parameter", runAsUserParameter)
+		}
+		document = SessionDocument{
+			Name:       interactiveCommandDocument,
+			Parameters: map[string][]string{"command": {"sudo -i -u " + user}},
+		}
+	case strings.HasPrefix(document.Name, "AWS-"):
+		if userFromRule {
+			return skipRuleUser(fmt.Sprintf("%s doesn't take a run-as user", document.Name))
+		}
+		return SessionDocument{}, "", fmt.Errorf("%s doesn't take a run-as user, use a custom document with a %s parameter", document.Name, runAsUserParameter)
+	default:
+		parameters := map[string][]string{runAsUserParameter: {user}}
+		for key, values := range document.Parameters {
+			parameters[key] = values
+		}
+		document.Parameters = parameters
+	}
+
+	return document, user, nil
+}
+
+// sessionRule returns the first ec2.sessions rule that applies to the instance
+func (e *EC2Manager) sessionRule(instance EC2Instance) (*awscconfig.EC2SessionRule, error) {
+	rules, err := awscconfig.EC2SessionRules()
+	if err != nil || len(rules) == 0 {
+		return nil, err
+	}
+
+	var accountId, accountName string
+	if _, session := currentSession(); session != nil {
+		accountId, accountName = session.AccountID, session.AccountName
+	}
+
+	for _, rule := range rules {
+		if rule.Matches(accountId, accountName, instance.Tags) {
+			return &rule, nil
+		}
+	}
+	return nil, nil
+}
+
+// describeDocument is how a session document is shown before connecting
+func describeDocument(document SessionDocument) string {
+	keys := make([]string, 0, len(document.Parameters))
+	for key := range document.Parameters {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var parts []string
+	for _, key := range keys {
+		parts = append(parts, fmt.Sprintf("%s=%s", key, strings.Join(document.Parameters[key], ",")))
+	}
+	if len(parts) == 0 {
+		return document.Name
+	}
+	return fmt.Sprintf("%s (%s)", document.Name, strings.Join(parts, " "))
+}
@@ -0,0 +1,238 @@
+package aws
+
+import (
+	"encoding/json"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	awscconfig "github.com/blontic/awsc/internal/config"
+	"github.com/spf13/viper"
+)
+
+func TestParseSessionParameters(t *testing.T) {
+	parameters, err := ParseSessionParameters([]string{"command=bash -l", "command=echo a=b", "portNumber=8080"})
+	if err != nil {
+		t.Fatalf("ParseSessionParameters failed: %v", err)
+	}
+	want := map[string][]string{
+		"command":    {"bash -l", "echo a=b"},
+		"portNumber": {"8080"},
+	}
+	if !reflect.DeepEqual(parameters, want) {
+		t.Errorf("Expected %v, got %v", want, parameters)
+	}
+
+	for _, invalid := range []string{"command", "=value"} {
+		if _, err := ParseSessionParameters([]string{invalid}); err == nil {
+			t.Errorf("Expected error for %q", invalid)
+		}
+	}
+}
+
+func TestEC2Manager_SessionDocument(t *testing.T) {
+	linux := EC2Instance{InstanceId: "i-linux", Platform: "Linux", Tags: map[string]string{"Role": "db"}}
+	windows := EC2Instance{InstanceId: "i-windows", Platform: "Windows"}
+	eice := EC2Instance{InstanceId: "i-eice", Platform: "Linux", Transport: TransportEICE}
+
+	tests := []struct {
+		name     string
+		instance EC2Instance
+		opts     ConnectOptions
+		want     SessionDocument
+		wantUser string
+		wantErr  string
+	}{
+		{
+			name:     "default shell",
+			instance: linux,
+		},
+		{
+			name:     "document with parameters",
+			instance: linux,
+			opts:     ConnectOptions{Document: "AWS-StartInteractiveCommand", Parameters: map[string][]string{"command": {"top"}}},
+			want:     SessionDocument{Name: "AWS-StartInteractiveCommand", Parameters: map[string][]string{"command": {"top"}}},
+		},
+		{
+			name:     "run-as with the default shell",
+			instance: linux,
+			opts:     ConnectOptions{User: "deploy"},
+			want:     SessionDocument{Name: "AWS-StartInteractiveCommand", Parameters: map[string][]string{"command": {"sudo -i -u deploy"}}},
+			wantUser: "deploy",
+		},
+		{
+			name:     "run-as with a custom document",
+			instance: linux,
+			opts:     ConnectOptions{Document: "Custom-BashLogin", User: "deploy"},
+			want:     SessionDocument{Name: "Custom-BashLogin", Parameters: map[string][]string{"runAsUser": {"deploy"}}},
+			wantUser: "deploy",
+		},
+		{
+			name:     "run-as with an AWS document",
+			instance: linux,
+			opts:     ConnectOptions{Document: "AWS-StartInteractiveCommand", User: "deploy"},
+			wantErr:  "doesn't take a run-as user",
+		},
+		{
+			name:     "run-as on Windows",
+			instance: windows,
+			opts:     ConnectOptions{User: "admin"},
+			wantErr:  "run-as on Windows",
+		},
+		{
+			name:     "run-as on Windows as EC2 reports it",
+			instance: EC2Instance{InstanceId: "i-windows", Platform: "windows"},
+			opts:     ConnectOptions{User: "admin"},
+			wantErr:  "run-as on Windows",
+		},
+		{
+			name:     "unsafe user name",
+			instance: linux,
+			opts:     ConnectOptions{User: "root; rm -rf /"},
+			wantErr:  "invalid user name",
+		},
+		{
+			name:     "parameters without a document",
+			instance: linux,
+			opts:     ConnectOptions{Parameters: map[string][]string{"command": {"top"}}},
+			wantErr:  "parameters need a --document",
+		},
+		{
+			name:     "instance connect endpoint user",
+			instance: eice,
+			wantUser: "ec2-user",
+		},
+		{
+			name:     "document through an instance connect endpoint",
+			instance: eice,
+			opts:     ConnectOptions{Document: "Custom-BashLogin"},
+			wantErr:  "needs the SSM agent",
+		},
+	}
+
+	manager := &EC2Manager{region: "us-east-1"}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			document, user, err := manager.sessionDocument(tt.instance, tt.opts)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("Expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(document, tt.want) {
+				t.Errorf("Expected document %+v, got %+v", tt.want, document)
+			}
+			if user != tt.wantUser {
+				t.Errorf("Expected user %q, got %q", tt.wantUser, user)
+			}
+		})
+	}
+}
+
+func TestEC2Manager_SessionDocument_ConfigRules(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("AWSC_PROFILE", "")
+	if err := awscconfig.SaveSession(os.Getppid(), "awsc-prod", "123456789012", "Production", "Admin"); err != nil {
+		t.Fatalf("SaveSession failed: %v", err)
+	}
+
+	viper.Set("ec2.sessions", []map[string]interface{}{
+		{"account": "staging", "document": "Staging-Shell"},
+		{"account": "production", "tag": "Role=db", "document": "Custom-BashLogin", "user": "dba", "parameters": []string{"shell=bash"}},
+		{"user": "ops"},
+	})
+	defer viper.Reset()
+
+	manager := &EC2Manager{region: "us-east-1"}
+
+	db := EC2Instance{InstanceId: "i-db", Platform: "Linux", Tags: map[string]string{"Role": "db"}}
+	document, user, err := manager.sessionDocument(db, ConnectOptions{Parameters: map[string][]string{"shell": {"zsh"}}})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	want := SessionDocument{Name: "Custom-BashLogin", Parameters: map[string][]string{"runAsUser": {"dba"}, "shell": {"zsh"}}}
+	if !reflect.DeepEqual(document, want) || user != "dba" {
+		t.Errorf("Expected %+v as dba, got %+v as %s", want, document, user)
+	}
+
+	// Flags win over the rule
+	document, user, err = manager.sessionDocument(db, ConnectOptions{Document: "Other-Doc", User: "me"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if document.Name != "Other-Doc" || user != "me" || len(document.Parameters) != 1 {
+		t.Errorf("Expected flags to override the rule, got %+v as %s", document, user)
+	}
+
+	// Other instances fall through to the catch-all rule
+	web := EC2Instance{InstanceId: "i-web", Platform: "Linux", Tags: map[string]string{"Role": "web"}}
+	document, user, err = manager.sessionDocument(web, ConnectOptions{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if document.Name != interactiveCommandDocument || user != "ops" {
+		t.Errorf("Expected run-as ops through %s, got %+v as %s", interactiveCommandDocument, document, user)
+	}
+
+	// An explicit --document doesn't pick up the rule's user
+	document, user, err = manager.sessionDocument(web, ConnectOptions{Document: interactiveCommandDocument, Parameters: map[string][]string{"command": {"top"}}})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if document.Name != interactiveCommandDocument || user != "" {
+		t.Errorf("Expected %s without a run-as user, got %+v as %s", interactiveCommandDocument, document, user)
+	}
+
+	// The rule's user is skipped on Windows instead of failing the connect
+	windows := EC2Instance{InstanceId: "i-win", Platform: "Windows"}
+	document, user, err = manager.sessionDocument(windows, ConnectOptions{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if document.Name != "" || user != "" {
+		t.Errorf("Expected the default shell without a run-as user, got %+v as %s", document, user)
+	}
+
+	// An explicit --user on Windows still fails
+	if _, _, err := manager.sessionDocument(windows, ConnectOptions{User: "admin"}); err == nil {
+		t.Error("Expected error for --user on Windows with the default shell")
+	}
+}
+
+func TestSessionParameters(t *testing.T) {
+	input := &ssm.StartSessionInput{
+		Target:       aws.String("i-123"),
+		DocumentName: aws.String("AWS-StartInteractiveCommand"),
+		Parameters:   map[string][]string{"command": {`echo "quoted" \ path`}},
+	}
+
+	encoded, err := json.Marshal(sessionParameters(input))
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+
+	var decoded struct {
+		Target       string
+		DocumentName string
+		Parameters   map[string][]string
+	}
+	if err := json.Unmarshal(encoded, &decoded); err != nil {
+		t.Fatalf("Plugin parameters aren't valid JSON: %v\n%s", err, encoded)
+	}
+	if decoded.Target != "i-123" || decoded.DocumentName != "AWS-StartInteractiveCommand" || decoded.Parameters["command"][0] != `echo "quoted" \ path` {
+		t.Errorf("Unexpected parameters %s", encoded)
+	}
+
+	// The default shell sends only the target
+	encoded, _ = json.Marshal(sessionParameters(&ssm.StartSessionInput{Target: aws.String("i-123")}))
+	if string(encoded) != `{"Target":"i-123"}` {
+		t.Errorf("Expected only the target, got %s", encoded)
+	}
+}
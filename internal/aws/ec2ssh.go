@@ -0,0 +1,248 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2instanceconnect"
+	awscconfig "github.com/blontic/awsc/internal/config"
+)
+
+// EC2InstanceConnectClient interface for mocking
+type EC2InstanceConnectClient interface {
+	SendSSHPublicKey(ctx context.Context, params *ec2instanceconnect.SendSSHPublicKeyInput, optFns ...func(*ec2instanceconnect.Options)) (*ec2instanceconnect.SendSSHPublicKeyOutput, error)
+}
+
+// SSHKeyOptions pushes a public key with EC2 Instance Connect before connecting, so the
+// instance accepts it for 60 seconds without managing authorized_keys
+type SSHKeyOptions struct {
+	PublicKeyPath string
+	OSUser        string
+}
+
+// SSHConfigOptions controls the Host blocks written by WriteSSHConfig
+type SSHConfigOptions struct {
+	Prefix        string // Prepended to every Host alias
+	User          string
+	PublicKeyPath string // Adds --push-key to the ProxyCommand
+}
+
+var sshAliasUnsafe = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// RunSSHProxy connects stdin/stdout to port on the instance through an AWS-StartSSHSession
+// session, for use as an OpenSSH ProxyCommand. host is an instance ID or Name tag. Instances
+// without the SSM agent are reached through an EC2 Instance Connect Endpoint instead.
+// Nothing but the session stream may be written to stdout.
+func (e *EC2Manager) RunSSHProxy(ctx context.Context, host string, port int, key SSHKeyOptions) error {
+	instanceId, err := e.resolveSSHHost(ctx, host)
+	if err != nil {
+		return err
+	}
+
+	if key.PublicKeyPath != "" {
+		if err := e.pushSSHPublicKey(ctx, instanceId, key); err != nil {
+			return err
+		}
+	}
+
+	cfg, err := awscconfig.LoadAWSConfigWithProfile(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load AWS config: %w", err)
+	}
+
+	info, err := e.describeSSMInstance(ctx, instanceId)
+	if err != nil {
+		return fmt.Errorf("error checking SSM agent on %s: %v", instanceId, err)
+	}
+	if info == nil {
+		return e.runInstanceConnectProxy(ctx, cfg, instanceId, port)
+	}
+
+	pf := NewExternalPluginForwarder(cfg)
+	return pf.StartSessionToTarget(ctx, instanceId, "AWS-StartSSHSession", map[string][]string{
+		"portNumber": {strconv.Itoa(port)},
+	})
+}
+
+// resolveSSHHost returns host if it is an instance ID, otherwise the running instance
+// with that Name tag, preferring instances with the SSM agent when names are shared
+func (e *EC2Manager) resolveSSHHost(ctx context.Context, host string) (string, error) {
+	if instanceIdPattern.MatchString(host) {
+		return host, nil
+	}
+
+	instances, err := e.findRunTargets(ctx, RunTargets{Tags: []string{"Name=" + host}})
+	if err != nil {
+		return "", fmt.Errorf("error looking up instance %s: %v", host, err)
+	}
+
+	var managed []string
+	for _, instance := range instances {
+		info, err := e.describeSSMInstance(ctx, instance.InstanceId)
+		if err != nil {
+			return "", fmt.Errorf("error checking SSM agent on %s: %v", instance.InstanceId, err)
+		}
+		if info != nil {
+			managed = append(managed, instance.InstanceId)
+		}
+	}
+
+	switch len(managed) {
+	case 0:
+		// A single instance without the agent may still be reachable through EICE
+		if len(instances) == 1 {
+			return instances[0].InstanceId, nil
+		}
+		return "", fmt.Errorf("no running instance named %s with the SSM agent found", host)
+	case 1:
+		return managed[0], nil
+	default:
+		return "", fmt.Errorf("%d instances are named %s (%s), use the instance ID instead", len(managed), host, strings.Join(managed, ", "))
+	}
+}
+
+// runInstanceConnectProxy connects stdin/stdout to port on the instance through the
+// EC2 Instance Connect Endpoint in its VPC
+func (e *EC2Manager) runInstanceConnectProxy(ctx context.Context, cfg aws.Config, instanceId string, port int) error {
+	instances, err := e.findRunTargets(ctx, RunTargets{InstanceIds: []string{instanceId}})
+	if err != nil {
+		return fmt.Errorf("error describing instance %s: %v", instanceId, err)
+	}
+	if len(instances) == 0 {
+		return fmt.Errorf("instance %s is not running", instanceId)
+	}
+	instance := instances[0]
+
+	endpoints, err := e.bastionFinder().ListInstanceConnectEndpoints(ctx)
+	if err != nil {
+		return fmt.Errorf("error listing EC2 Instance Connect Endpoints: %v", err)
+	}
+	endpoint, err := e.instanceConnectEndpoint(ctx, instance, endpoints, int32(port))
+	if err != nil {
+		return fmt.Errorf("instance %s has no SSM agent and %v", instanceId, err)
+	}
+
+	conn, err := NewEICEForwarder(cfg, endpoint).Dial(ctx, instance.PrivateIp, port)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	// The websocket can't half-close, so the end of ssh's input ends the tunnel
+	go func() {
+		io.Copy(conn, os.Stdin)
+		conn.Close()
+	}()
+	_, err = io.Copy(os.Stdout, conn)
+	return err
+}
+
+func (e *EC2Manager) pushSSHPublicKey(ctx context.Context, instanceId string, key SSHKeyOptions) error {
+	if key.OSUser == "" {
+		return fmt.Errorf("an OS user is required to push an SSH key")
+	}
+
+	publicKey, err := os.ReadFile(expandHome(key.PublicKeyPath))
+	if err != nil {
+		return fmt.Errorf("failed to read public key: %w", err)
+	}
+
+	result, err := e.instanceConnectClient.SendSSHPublicKey(ctx, &ec2instanceconnect.SendSSHPublicKeyInput{
+		InstanceId:     aws.String(instanceId),
+		InstanceOSUser: aws.String(key.OSUser),
+		SSHPublicKey:   aws.String(strings.TrimSpace(string(publicKey))),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to push SSH key with EC2 Instance Connect: %w", err)
+	}
+	if !result.Success {
+		return fmt.Errorf("EC2 Instance Connect did not accept the SSH key")
+	}
+
+	return nil
+}
+
+// WriteSSHConfig writes an OpenSSH Host block for every running SSM-managed instance.
+// Each ProxyCommand runs 'awsc ec2 ssh-proxy' pinned to the current profile and region.
+func (e *EC2Manager) WriteSSHConfig(ctx context.Context, w io.Writer, opts SSHConfigOptions) error {
+	instances, err := e.ListAllInstances(ctx)
+	if err != nil {
+		return fmt.Errorf("error listing EC2 instances: %v", err)
+	}
+
+	executable, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to locate awsc executable: %w", err)
+	}
+
+	profileName, err := awscconfig.CurrentProfileName()
+	if err != nil {
+		return err
+	}
+
+	proxyCommand := fmt.Sprintf("env AWSC_PROFILE=%s %s ec2 ssh-proxy %%h %%p --region %s", shellQuoteIfNeeded(profileName), shellQuoteIfNeeded(executable), e.region)
+	if opts.PublicKeyPath != "" {
+		proxyCommand += fmt.Sprintf(" --push-key %s --os-user %%r", shellQuoteIfNeeded(expandHome(opts.PublicKeyPath)))
+	}
+
+	// Instances sharing a Name get the instance ID appended
+	nameCounts := map[string]int{}
+	for _, instance := range instances {
+		nameCounts[instance.Name]++
+	}
+
+	fmt.Fprintf(w, "# Generated by awsc ec2 ssh-config for profile %s in %s\n", profileName, e.region)
+
+	written := 0
+	for _, instance := range instances {
+		if !instance.IsSelectable {
+			continue
+		}
+
+		alias := sshAliasUnsafe.ReplaceAllString(instance.Name, "-")
+		if instance.Name == "Unnamed" || alias == "" {
+			alias = instance.InstanceId
+		} else if nameCounts[instance.Name] > 1 {
+			alias = fmt.Sprintf("%s-%s", alias, instance.InstanceId)
+		}
+
+		fmt.Fprintf(w, "\nHost %s%s\n", opts.Prefix, alias)
+		fmt.Fprintf(w, "    HostName %s\n", instance.InstanceId)
+		if opts.User != "" {
+			fmt.Fprintf(w, "    User %s\n", opts.User)
+		}
+		fmt.Fprintf(w, "    ProxyCommand %s\n", proxyCommand)
+		written++
+	}
+
+	if written == 0 {
+		return fmt.Errorf("no running EC2 instances with SSM agent found in region %s", e.region)
+	}
+
+	return nil
+}
+
+// shellQuoteIfNeeded quotes s only when it contains characters the shell would interpret
+func shellQuoteIfNeeded(s string) string {
+	if s != "" && !strings.ContainsAny(s, " \t\"'$`\\|&;<>()*?[]#~") {
+		return s
+	}
+	return shellQuote(s)
+}
+
+// expandHome replaces a leading ~/ with the home directory
+func expandHome(path string) string {
+	if rest, ok := strings.CutPrefix(path, "~/"); ok {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, rest)
+		}
+	}
+	return path
+}
@@ -0,0 +1,205 @@
+package aws
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/aws-sdk-go-v2/service/ec2instanceconnect"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	ssmtypes "github.com/aws/aws-sdk-go-v2/service/ssm/types"
+	"github.com/blontic/awsc/internal/aws/mocks"
+	"go.uber.org/mock/gomock"
+)
+
+func runningInstance(id, name string) types.Instance {
+	return types.Instance{
+		InstanceId: aws.String(id),
+		State:      &types.InstanceState{Name: types.InstanceStateNameRunning},
+		Tags:       []types.Tag{{Key: aws.String("Name"), Value: aws.String(name)}},
+	}
+}
+
+func TestEC2Manager_ResolveSSHHost(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEC2 := mocks.NewMockEC2Client(ctrl)
+	mockSSM := mocks.NewMockSSMClient(ctrl)
+	manager, _ := NewEC2Manager(context.Background(), EC2ManagerOptions{
+		EC2Client: mockEC2,
+		SSMClient: mockSSM,
+		Region:    "us-east-1",
+	})
+
+	// Instance IDs are used as-is
+	if id, err := manager.resolveSSHHost(context.Background(), "i-0123abcd"); err != nil || id != "i-0123abcd" {
+		t.Errorf("Expected i-0123abcd, got %s (%v)", id, err)
+	}
+
+	mockEC2.EXPECT().
+		DescribeInstances(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, input *ec2.DescribeInstancesInput, _ ...func(*ec2.Options)) (*ec2.DescribeInstancesOutput, error) {
+			if aws.ToString(input.Filters[1].Name) != "tag:Name" || input.Filters[1].Values[0] != "i-web" {
+				t.Errorf("Expected Name tag filter, got %+v", input.Filters)
+			}
+			return &ec2.DescribeInstancesOutput{
+				Reservations: []types.Reservation{{
+					Instances: []types.Instance{runningInstance("i-nossm", "i-web"), runningInstance("i-ssm", "i-web")},
+				}},
+			}, nil
+		})
+
+	mockSSM.EXPECT().
+		DescribeInstanceInformation(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, input *ssm.DescribeInstanceInformationInput, _ ...func(*ssm.Options)) (*ssm.DescribeInstanceInformationOutput, error) {
+			if input.Filters[0].Values[0] == "i-ssm" {
+				return &ssm.DescribeInstanceInformationOutput{
+					InstanceInformationList: []ssmtypes.InstanceInformation{{InstanceId: aws.String("i-ssm")}},
+				}, nil
+			}
+			return &ssm.DescribeInstanceInformationOutput{}, nil
+		}).
+		Times(2)
+
+	// Names that merely start with i- are looked up like any other name
+	id, err := manager.resolveSSHHost(context.Background(), "i-web")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if id != "i-ssm" {
+		t.Errorf("Expected the SSM-managed instance, got %s", id)
+	}
+}
+
+func TestEC2Manager_ResolveSSHHost_NonInteractive(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEC2 := mocks.NewMockEC2Client(ctrl)
+	mockSSM := mocks.NewMockSSMClient(ctrl)
+	manager, _ := NewEC2Manager(context.Background(), EC2ManagerOptions{
+		EC2Client: mockEC2,
+		SSMClient: mockSSM,
+		Region:    "us-east-1",
+	})
+	manager.SetNonInteractive()
+
+	// Expired credentials are returned without prompting to log in
+	mockEC2.EXPECT().
+		DescribeInstances(gomock.Any(), gomock.Any()).
+		Return(nil, fmt.Errorf("ExpiredToken: the security token included in the request is expired"))
+
+	if _, err := manager.resolveSSHHost(context.Background(), "web"); err == nil || !IsAuthError(err) {
+		t.Errorf("Expected auth error, got %v", err)
+	}
+
+	// SSM failures are reported instead of being treated as a missing agent
+	mockEC2.EXPECT().
+		DescribeInstances(gomock.Any(), gomock.Any()).
+		Return(&ec2.DescribeInstancesOutput{
+			Reservations: []types.Reservation{{Instances: []types.Instance{runningInstance("i-web", "web")}}},
+		}, nil)
+	mockSSM.EXPECT().
+		DescribeInstanceInformation(gomock.Any(), gomock.Any()).
+		Return(nil, fmt.Errorf("AccessDeniedException: not authorized"))
+
+	if _, err := manager.resolveSSHHost(context.Background(), "web"); err == nil || !strings.Contains(err.Error(), "AccessDeniedException") {
+		t.Errorf("Expected SSM error, got %v", err)
+	}
+}
+
+func TestEC2Manager_PushSSHPublicKey(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockConnect := mocks.NewMockEC2InstanceConnectClient(ctrl)
+	manager, _ := NewEC2Manager(context.Background(), EC2ManagerOptions{
+		EC2Client:             mocks.NewMockEC2Client(ctrl),
+		InstanceConnectClient: mockConnect,
+	})
+
+	keyPath := filepath.Join(t.TempDir(), "id_ed25519.pub")
+	os.WriteFile(keyPath, []byte("ssh-ed25519 AAAAC3Nza user@host\n"), 0644)
+
+	mockConnect.EXPECT().
+		SendSSHPublicKey(gomock.Any(), &ec2instanceconnect.SendSSHPublicKeyInput{
+			InstanceId:     aws.String("i-0123abcd"),
+			InstanceOSUser: aws.String("ec2-user"),
+			SSHPublicKey:   aws.String("ssh-ed25519 AAAAC3Nza user@host"),
+		}).
+		Return(&ec2instanceconnect.SendSSHPublicKeyOutput{Success: true}, nil)
+
+	if err := manager.pushSSHPublicKey(context.Background(), "i-0123abcd", SSHKeyOptions{PublicKeyPath: keyPath, OSUser: "ec2-user"}); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	if err := manager.pushSSHPublicKey(context.Background(), "i-0123abcd", SSHKeyOptions{PublicKeyPath: keyPath}); err == nil {
+		t.Error("Expected error without OS user")
+	}
+}
+
+func TestEC2Manager_WriteSSHConfig(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Setenv("AWSC_PROFILE", "awsc-prod")
+	t.Setenv("HOME", "/home/dev")
+
+	mockEC2 := mocks.NewMockEC2Client(ctrl)
+	mockSSM := mocks.NewMockSSMClient(ctrl)
+	manager, _ := NewEC2Manager(context.Background(), EC2ManagerOptions{
+		EC2Client: mockEC2,
+		SSMClient: mockSSM,
+		Region:    "eu-west-1",
+	})
+
+	mockEC2.EXPECT().
+		DescribeInstances(gomock.Any(), gomock.Any()).
+		Return(&ec2.DescribeInstancesOutput{
+			Reservations: []types.Reservation{{
+				Instances: []types.Instance{
+					runningInstance("i-1", "web server"),
+					runningInstance("i-2", "api"),
+					runningInstance("i-3", "api"),
+					{InstanceId: aws.String("i-4"), State: &types.InstanceState{Name: types.InstanceStateNameStopped}},
+				},
+			}},
+		}, nil)
+
+	mockSSM.EXPECT().
+		DescribeInstanceInformation(gomock.Any(), gomock.Any()).
+		Return(&ssm.DescribeInstanceInformationOutput{
+			InstanceInformationList: []ssmtypes.InstanceInformation{{InstanceId: aws.String("i-any")}},
+		}, nil).
+		Times(3)
+
+	var out bytes.Buffer
+	err := manager.WriteSSHConfig(context.Background(), &out, SSHConfigOptions{Prefix: "prod-", User: "ec2-user", PublicKeyPath: "~/.ssh/id_ed25519.pub"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	config := out.String()
+	for _, want := range []string{
+		"Host prod-api-i-2\n    HostName i-2\n    User ec2-user\n",
+		"Host prod-api-i-3\n",
+		"Host prod-web-server\n    HostName i-1\n",
+		"ProxyCommand env AWSC_PROFILE=awsc-prod ",
+		" ec2 ssh-proxy %h %p --region eu-west-1 --push-key /home/dev/.ssh/id_ed25519.pub --os-user %r\n",
+	} {
+		if !strings.Contains(config, want) {
+			t.Errorf("Expected config to contain %q, got:\n%s", want, config)
+		}
+	}
+	if strings.Contains(config, "i-4") {
+		t.Error("Stopped instances should not get a Host block")
+	}
+}
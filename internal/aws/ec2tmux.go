@@ -0,0 +1,194 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	awscconfig "github.com/blontic/awsc/internal/config"
+)
+
+// TmuxOptions selects the instances to open side by side sessions to. Every Key=Value tag
+// must match; Connect is passed on to each pane's 'awsc ec2 connect'.
+type TmuxOptions struct {
+	Tags    []string
+	Sync    bool
+	Connect ConnectOptions
+}
+
+// tmux runs a tmux command and returns its trimmed output, replaced in tests
+var tmux = func(args ...string) (string, error) {
+	output, err := exec.Command("tmux", args...).Output()
+	if err != nil {
+		return "", fmt.Errorf("tmux %s failed: %w", args[0], err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// attachTmux attaches the terminal to a tmux session, replaced in tests
+var attachTmux = func(session string) error {
+	cmd := exec.Command("tmux", "attach-session", "-t", session)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+// RunTmux opens a tmux window with one pane per running SSM-managed instance matching the
+// tags, each running 'awsc ec2 connect --instance-id' pinned to the current profile and
+// region. Without tmux the commands are printed to run by hand.
+func (e *EC2Manager) RunTmux(ctx context.Context, opts TmuxOptions) error {
+	if len(opts.Tags) == 0 {
+		return fmt.Errorf("specify at least one --tag")
+	}
+	for _, tag := range opts.Tags {
+		key, value, ok := strings.Cut(tag, "=")
+		if !ok || key == "" {
+			return fmt.Errorf("invalid tag %q, expected Key=Value", tag)
+		}
+		e.filters = append(e.filters, types.Filter{Name: aws.String("tag:" + key), Values: []string{value}})
+	}
+
+	instances, err := e.ListAllInstances(ctx)
+	if err != nil {
+		return fmt.Errorf("error listing EC2 instances: %v", err)
+	}
+
+	var targets []EC2Instance
+	for _, instance := range instances {
+		if instance.IsSelectable {
+			targets = append(targets, instance)
+		} else if instance.State == "running" {
+			fmt.Printf("Skipping %s (%s), SSM agent not available\n", instance.Name, instance.InstanceId)
+		}
+	}
+	if len(targets) == 0 {
+		return fmt.Errorf("no running EC2 instances with SSM agent found for %s in region %s", strings.Join(opts.Tags, " "), e.region)
+	}
+
+	executable, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to locate awsc executable: %w", err)
+	}
+
+	profileName, err := awscconfig.CurrentProfileName()
+	if err != nil {
+		return err
+	}
+
+	commands := make([]string, len(targets))
+	for i, instance := range targets {
+		commands[i] = connectCommand(executable, profileName, e.region, instance.InstanceId, opts.Connect)
+	}
+
+	if _, err := exec.LookPath("tmux"); err != nil {
+		fmt.Printf("tmux not found, run these commands in separate terminals:\n\n")
+		for i, command := range commands {
+			fmt.Printf("# %s (%s)\n%s\n", targets[i].Name, targets[i].InstanceId, command)
+		}
+		return nil
+	}
+
+	name := tmuxWindowName(opts.Tags)
+	fmt.Printf("Opening %d sessions in tmux window %s\n", len(commands), name)
+	return openTmuxWindow(name, commands, opts.Sync, os.Getenv("TMUX") != "")
+}
+
+// connectCommand is the shell command that opens a session to one instance
+func connectCommand(executable, profileName, region, instanceId string, opts ConnectOptions) string {
+	args := []string{
+		"env", "AWSC_PROFILE=" + shellQuoteIfNeeded(profileName),
+		shellQuoteIfNeeded(executable), "ec2", "connect",
+		"--instance-id", instanceId,
+		// Keeps each pane from listing every instance in the region, which many panes at
+		// once would do in parallel
+		"--filter", "instance-id=" + instanceId,
+		"--region", region,
+	}
+	if opts.User != "" {
+		args = append(args, "--user", shellQuoteIfNeeded(opts.User))
+	}
+	if opts.Document != "" {
+		args = append(args, "--document", shellQuoteIfNeeded(opts.Document))
+	}
+	keys := make([]string, 0, len(opts.Parameters))
+	for key := range opts.Parameters {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		for _, value := range opts.Parameters[key] {
+			args = append(args, "--parameter", shellQuoteIfNeeded(key+"="+value))
+		}
+	}
+	if opts.Record {
+		args = append(args, "--record")
+	}
+	return strings.Join(args, " ")
+}
+
+// tmuxWindowName names the window after the tags, without the characters tmux treats as
+// target separators
+func tmuxWindowName(tags []string) string {
+	name := "awsc-" + strings.Join(tags, "-")
+	return strings.NewReplacer(".", "_", ":", "_", " ", "_").Replace(name)
+}
+
+// openTmuxWindow runs each command in its own pane of a new tiled window. Inside tmux the
+// window is added to the current session, otherwise a new session is created and attached.
+// Panes stay open after their command exits so connection errors can still be read.
+func openTmuxWindow(name string, commands []string, sync, insideTmux bool) error {
+	// The window starts on the default shell and the first command is respawned into it once
+	// remain-on-exit is set, so even a command that fails immediately keeps its pane
+	var window, session string
+	if insideTmux {
+		output, err := tmux("new-window", "-P", "-F", "#{window_id}", "-n", name)
+		if err != nil {
+			return err
+		}
+		window = output
+	} else {
+		// Sessions are left unnamed so a second run doesn't fail with a duplicate session
+		output, err := tmux("new-session", "-d", "-P", "-F", "#{session_id} #{window_id}", "-n", name)
+		if err != nil {
+			return err
+		}
+		var ok bool
+		if session, window, ok = strings.Cut(output, " "); !ok {
+			return fmt.Errorf("unexpected tmux new-session output %q", output)
+		}
+	}
+
+	if _, err := tmux("set-window-option", "-t", window, "remain-on-exit", "on"); err != nil {
+		return err
+	}
+	if _, err := tmux("respawn-pane", "-k", "-t", window, commands[0]); err != nil {
+		return err
+	}
+
+	for _, command := range commands[1:] {
+		if _, err := tmux("split-window", "-t", window, command); err != nil {
+			return err
+		}
+		// Re-tile after every split so later panes still have room
+		if _, err := tmux("select-layout", "-t", window, "tiled"); err != nil {
+			return err
+		}
+	}
+
+	if sync {
+		if _, err := tmux("set-window-option", "-t", window, "synchronize-panes", "on"); err != nil {
+			return err
+		}
+	}
+
+	if insideTmux {
+		return nil
+	}
+	return attachTmux(session)
+}
@@ -0,0 +1,102 @@
+package aws
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestConnectCommand(t *testing.T) {
+	opts := ConnectOptions{
+		User:       "deploy",
+		Document:   "Custom-Shell",
+		Parameters: map[string][]string{"shell": {"bash -l"}, "dir": {"/srv"}},
+		Record:     true,
+	}
+
+	got := connectCommand("/usr/local/bin/awsc", "awsc-prod", "eu-west-1", "i-123", opts)
+	want := "env AWSC_PROFILE=awsc-prod /usr/local/bin/awsc ec2 connect --instance-id i-123 --filter instance-id=i-123 --region eu-west-1" +
+		" --user deploy --document Custom-Shell --parameter dir=/srv --parameter 'shell=bash -l' --record"
+	if got != want {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+
+	if !strings.Contains(got, " --filter instance-id=i-123 ") {
+		t.Errorf("Expected the pane to list only its own instance, got %q", got)
+	}
+
+	got = connectCommand("/opt/my tools/awsc", "awsc-dev", "us-east-1", "i-456", ConnectOptions{})
+	want = "env AWSC_PROFILE=awsc-dev '/opt/my tools/awsc' ec2 connect --instance-id i-456 --filter instance-id=i-456 --region us-east-1"
+	if got != want {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+}
+
+func TestTmuxWindowName(t *testing.T) {
+	if got := tmuxWindowName([]string{"Role=kafka", "Env=prod.eu"}); got != "awsc-Role=kafka-Env=prod_eu" {
+		t.Errorf("Unexpected window name %q", got)
+	}
+}
+
+func TestOpenTmuxWindow(t *testing.T) {
+	var calls []string
+	var attached string
+	origTmux, origAttach := tmux, attachTmux
+	defer func() { tmux, attachTmux = origTmux, origAttach }()
+	tmux = func(args ...string) (string, error) {
+		calls = append(calls, strings.Join(args, " "))
+		if args[0] == "new-session" {
+			return "$3 @7", nil
+		}
+		return "@7", nil
+	}
+	attachTmux = func(session string) error {
+		attached = session
+		return nil
+	}
+
+	if err := openTmuxWindow("awsc-Role=kafka", []string{"cmd1", "cmd2"}, true, true); err != nil {
+		t.Fatalf("openTmuxWindow failed: %v", err)
+	}
+	want := []string{
+		"new-window -P -F #{window_id} -n awsc-Role=kafka",
+		"set-window-option -t @7 remain-on-exit on",
+		"respawn-pane -k -t @7 cmd1",
+		"split-window -t @7 cmd2",
+		"select-layout -t @7 tiled",
+		"set-window-option -t @7 synchronize-panes on",
+	}
+	if strings.Join(calls, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Unexpected tmux calls inside tmux:\n%s", strings.Join(calls, "\n"))
+	}
+	if attached != "" {
+		t.Errorf("Should not attach when already inside tmux")
+	}
+
+	calls = nil
+	if err := openTmuxWindow("awsc-Role=kafka", []string{"cmd1"}, false, false); err != nil {
+		t.Fatalf("openTmuxWindow failed: %v", err)
+	}
+	want = []string{
+		"new-session -d -P -F #{session_id} #{window_id} -n awsc-Role=kafka",
+		"set-window-option -t @7 remain-on-exit on",
+		"respawn-pane -k -t @7 cmd1",
+	}
+	if strings.Join(calls, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Unexpected tmux calls outside tmux:\n%s", strings.Join(calls, "\n"))
+	}
+	if attached != "$3" {
+		t.Errorf("Expected to attach to the new session, got %q", attached)
+	}
+}
+
+func TestEC2Manager_RunTmux_InvalidTags(t *testing.T) {
+	manager := &EC2Manager{region: "us-east-1"}
+
+	if err := manager.RunTmux(context.Background(), TmuxOptions{}); err == nil {
+		t.Error("Expected error without tags")
+	}
+	if err := manager.RunTmux(context.Background(), TmuxOptions{Tags: []string{"Role"}}); err == nil || !strings.Contains(err.Error(), "invalid tag") {
+		t.Errorf("Expected invalid tag error, got %v", err)
+	}
+}
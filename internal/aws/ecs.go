@@ -0,0 +1,579 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ecs"
+	ecstypes "github.com/aws/aws-sdk-go-v2/service/ecs/types"
+	awscconfig "github.com/blontic/awsc/internal/config"
+	"github.com/blontic/awsc/internal/ui"
+)
+
+// ECSClient interface for mocking
+type ECSClient interface {
+	ListClusters(ctx context.Context, params *ecs.ListClustersInput, optFns ...func(*ecs.Options)) (*ecs.ListClustersOutput, error)
+	ListServices(ctx context.Context, params *ecs.ListServicesInput, optFns ...func(*ecs.Options)) (*ecs.ListServicesOutput, error)
+	DescribeServices(ctx context.Context, params *ecs.DescribeServicesInput, optFns ...func(*ecs.Options)) (*ecs.DescribeServicesOutput, error)
+	ListTasks(ctx context.Context, params *ecs.ListTasksInput, optFns ...func(*ecs.Options)) (*ecs.ListTasksOutput, error)
+	DescribeTasks(ctx context.Context, params *ecs.DescribeTasksInput, optFns ...func(*ecs.Options)) (*ecs.DescribeTasksOutput, error)
+	ExecuteCommand(ctx context.Context, params *ecs.ExecuteCommandInput, optFns ...func(*ecs.Options)) (*ecs.ExecuteCommandOutput, error)
+}
+
+type ECSManager struct {
+	ecsClient ECSClient
+	region    string
+}
+
+type ECSManagerOptions struct {
+	ECSClient ECSClient
+	Region    string
+}
+
+// ECSTarget identifies a container to exec into or forward to
+type ECSTarget struct {
+	Cluster   string // Cluster name
+	Service   string // Service name
+	TaskId    string // Last segment of the task ARN
+	Container string
+	RuntimeId string
+}
+
+// SSMTarget returns the Session Manager target for the container
+func (t ECSTarget) SSMTarget() string {
+	return fmt.Sprintf("ecs:%s_%s_%s", t.Cluster, t.TaskId, t.RuntimeId)
+}
+
+// ECSTask is a running task with the containers that can accept ECS Exec sessions
+type ECSTask struct {
+	TaskId               string
+	TaskDefinition       string
+	LastStatus           string
+	EnableExecuteCommand bool
+	Containers           []ECSContainer
+}
+
+type ECSContainer struct {
+	Name       string
+	RuntimeId  string
+	AgentReady bool // ExecuteCommandAgent is running in the container
+}
+
+func NewECSManager(ctx context.Context, opts ...ECSManagerOptions) (*ECSManager, error) {
+	if len(opts) > 0 && opts[0].ECSClient != nil {
+		// Use provided clients (for testing)
+		return &ECSManager{
+			ecsClient: opts[0].ECSClient,
+			region:    opts[0].Region,
+		}, nil
+	}
+
+	// Production path
+	cfg, err := awscconfig.LoadAWSConfigWithProfile(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ECSManager{
+		ecsClient: ecs.NewFromConfig(cfg),
+		region:    cfg.Region,
+	}, nil
+}
+
+// RunExec selects a container and starts an interactive ECS Exec session running command
+func (m *ECSManager) RunExec(ctx context.Context, selector ECSTarget, command string) error {
+	target, err := m.SelectTarget(ctx, selector)
+	if err != nil {
+		return err
+	}
+
+	if command == "" {
+		command = "/bin/sh"
+	}
+
+	input := &ecs.ExecuteCommandInput{
+		Cluster:     aws.String(target.Cluster),
+		Task:        aws.String(target.TaskId),
+		Container:   aws.String(target.Container),
+		Command:     aws.String(command),
+		Interactive: true,
+	}
+	result, err := m.ecsClient.ExecuteCommand(ctx, input)
+	if err != nil {
+		if IsAuthError(err) {
+			if shouldReauth, reAuthErr := PromptForReauth(ctx); shouldReauth && reAuthErr == nil {
+				if reloadErr := m.reloadClients(ctx); reloadErr != nil {
+					return reloadErr
+				}
+				result, err = m.ecsClient.ExecuteCommand(ctx, input)
+				if err != nil {
+					return fmt.Errorf("failed to execute command: %w", err)
+				}
+			} else {
+				return fmt.Errorf("failed to execute command: %w", err)
+			}
+		} else {
+			return fmt.Errorf("failed to execute command: %w", err)
+		}
+	}
+	if result.Session == nil {
+		return fmt.Errorf("ECS did not return a session")
+	}
+
+	cfg, err := awscconfig.LoadAWSConfigWithProfile(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load AWS config: %w", err)
+	}
+
+	pf := NewExternalPluginForwarder(cfg)
+
+	fmt.Printf("Starting ECS Exec session in %s (%s)...\n", target.Container, target.TaskId)
+	return pf.AttachSession(ctx, aws.ToString(result.Session.SessionId), aws.ToString(result.Session.StreamUrl), aws.ToString(result.Session.TokenValue), target.SSMTarget())
+}
+
+// RunForward selects a container and forwards localPort to remotePort inside the task
+func (m *ECSManager) RunForward(ctx context.Context, selector ECSTarget, remotePort, localPort int) error {
+	target, err := m.SelectTarget(ctx, selector)
+	if err != nil {
+		return err
+	}
+
+	if localPort == 0 {
+		localPort = remotePort
+	}
+
+	cfg, err := awscconfig.LoadAWSConfigWithProfile(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load AWS config: %w", err)
+	}
+
+	pf := NewExternalPluginForwarder(cfg)
+	if err := pf.checkPortAvailable(localPort); err != nil {
+		return err
+	}
+
+	fmt.Printf("Starting port forwarding to %s:%d on localhost:%d...\n", target.Container, remotePort, localPort)
+	return pf.StartSessionToTarget(ctx, target.SSMTarget(), "AWS-StartPortForwardingSession", map[string][]string{
+		"portNumber":      {strconv.Itoa(remotePort)},
+		"localPortNumber": {strconv.Itoa(localPort)},
+	})
+}
+
+// SelectTarget walks cluster → service → task → container, using the values in selector
+// where given and prompting for the rest. A task given without a service skips the service step.
+func (m *ECSManager) SelectTarget(ctx context.Context, selector ECSTarget) (ECSTarget, error) {
+	target := selector
+
+	clusters, err := m.ListClusters(ctx)
+	if err != nil {
+		return ECSTarget{}, fmt.Errorf("error listing ECS clusters: %v", err)
+	}
+	if len(clusters) == 0 {
+		return ECSTarget{}, fmt.Errorf("no ECS clusters found")
+	}
+
+	clusterSelectable := make([]bool, len(clusters))
+	for i := range clusters {
+		clusterSelectable[i] = true
+	}
+	index, err := selectECSItem("cluster", "Select ECS Cluster:", target.Cluster, clusters, clusters, clusterSelectable)
+	if err != nil {
+		return ECSTarget{}, err
+	}
+	target.Cluster = clusters[index]
+
+	// A task given without a service is looked up directly, which also reaches standalone
+	// tasks started with RunTask
+	var tasks []ECSTask
+	if target.TaskId != "" && target.Service == "" {
+		tasks, err = m.describeTasks(ctx, target.Cluster, []string{target.TaskId})
+		if err != nil {
+			return ECSTarget{}, fmt.Errorf("error describing ECS task: %v", err)
+		}
+		if len(tasks) == 0 {
+			return ECSTarget{}, fmt.Errorf("task %s not found in cluster %s", target.TaskId, target.Cluster)
+		}
+	} else {
+		tasks, err = m.selectServiceTasks(ctx, &target)
+		if err != nil {
+			return ECSTarget{}, err
+		}
+	}
+
+	taskIds := make([]string, len(tasks))
+	taskOptions := make([]string, len(tasks))
+	taskSelectable := make([]bool, len(tasks))
+	for i, task := range tasks {
+		taskIds[i] = task.TaskId
+		taskOptions[i] = fmt.Sprintf("%s (%s)", task.TaskId, task.TaskDefinition)
+		if !task.EnableExecuteCommand {
+			taskOptions[i] += " - execute command disabled"
+		} else if task.LastStatus != "RUNNING" {
+			taskOptions[i] += fmt.Sprintf(" - %s", strings.ToLower(task.LastStatus))
+		}
+		taskSelectable[i] = task.EnableExecuteCommand && task.LastStatus == "RUNNING"
+	}
+	index, err = selectECSItem("task", "Select ECS Task:", target.TaskId, taskIds, taskOptions, taskSelectable)
+	if err != nil {
+		return ECSTarget{}, err
+	}
+	task := tasks[index]
+	target.TaskId = task.TaskId
+
+	containerNames := make([]string, len(task.Containers))
+	containerOptions := make([]string, len(task.Containers))
+	containerSelectable := make([]bool, len(task.Containers))
+	for i, container := range task.Containers {
+		containerNames[i] = container.Name
+		containerOptions[i] = container.Name
+		if !container.AgentReady {
+			containerOptions[i] += " - execute command agent not running"
+		}
+		containerSelectable[i] = container.AgentReady
+	}
+	index, err = selectECSItem("container", "Select Container:", target.Container, containerNames, containerOptions, containerSelectable)
+	if err != nil {
+		return ECSTarget{}, err
+	}
+	target.Container = task.Containers[index].Name
+	target.RuntimeId = task.Containers[index].RuntimeId
+
+	return target, nil
+}
+
+// selectServiceTasks selects the target's service, prompting when it isn't given, and
+// returns its running tasks
+func (m *ECSManager) selectServiceTasks(ctx context.Context, target *ECSTarget) ([]ECSTask, error) {
+	services, err := m.ListServices(ctx, target.Cluster)
+	if err != nil {
+		return nil, fmt.Errorf("error listing ECS services: %v", err)
+	}
+	if len(services) == 0 {
+		return nil, fmt.Errorf("no services found in cluster %s", target.Cluster)
+	}
+
+	serviceNames := make([]string, len(services))
+	serviceOptions := make([]string, len(services))
+	serviceSelectable := make([]bool, len(services))
+	for i, service := range services {
+		serviceNames[i] = aws.ToString(service.ServiceName)
+		serviceOptions[i] = fmt.Sprintf("%s (%d/%d running)", serviceNames[i], service.RunningCount, service.DesiredCount)
+		if !service.EnableExecuteCommand {
+			serviceOptions[i] += " - execute command disabled"
+		}
+		serviceSelectable[i] = service.EnableExecuteCommand && service.RunningCount > 0
+	}
+	index, err := selectECSItem("service", "Select ECS Service:", target.Service, serviceNames, serviceOptions, serviceSelectable)
+	if err != nil {
+		if !slices.Contains(serviceSelectable, true) {
+			fmt.Printf("\nTurn on execute command for the service and redeploy it so new tasks accept ECS Exec sessions.\n")
+		}
+		return nil, err
+	}
+	target.Service = serviceNames[index]
+
+	tasks, err := m.ListTasks(ctx, target.Cluster, target.Service)
+	if err != nil {
+		return nil, fmt.Errorf("error listing ECS tasks: %v", err)
+	}
+	if len(tasks) == 0 {
+		return nil, fmt.Errorf("no running tasks found for service %s", target.Service)
+	}
+
+	return tasks, nil
+}
+
+// selectECSItem returns the index of name in names when given and selectable, selects
+// automatically when only one item is selectable, and otherwise shows the selector
+func selectECSItem(kind, title, name string, names, options []string, selectable []bool) (int, error) {
+	if name != "" {
+		for i, n := range names {
+			if n != name {
+				continue
+			}
+			if selectable[i] {
+				fmt.Printf("✓ Selected %s: %s\n", kind, n)
+				return i, nil
+			}
+			fmt.Printf("ECS %s '%s' is not available. Available %ss:\n\n", kind, name, kind)
+			break
+		}
+		if !slices.Contains(names, name) {
+			fmt.Printf("ECS %s '%s' not found. Available %ss:\n\n", kind, name, kind)
+		}
+	}
+
+	onlyIndex := -1
+	selectableCount := 0
+	for i, ok := range selectable {
+		if ok {
+			selectableCount++
+			onlyIndex = i
+		}
+	}
+
+	if selectableCount == 0 {
+		for _, option := range options {
+			fmt.Printf("- %s\n", option)
+		}
+		return -1, fmt.Errorf("no available %ss for ECS Exec", kind)
+	}
+
+	if selectableCount == 1 && name == "" {
+		fmt.Printf("✓ Selected %s: %s\n", kind, names[onlyIndex])
+		return onlyIndex, nil
+	}
+
+	selectedIndex, err := ui.RunSelectorWithSelectability(title, options, selectable)
+	if err != nil {
+		return -1, fmt.Errorf("error selecting %s: %v", kind, err)
+	}
+	if selectedIndex == -1 {
+		return -1, fmt.Errorf("no %s selected", kind)
+	}
+
+	fmt.Printf("✓ Selected %s: %s\n", kind, names[selectedIndex])
+	return selectedIndex, nil
+}
+
+// ListClusters returns cluster names sorted alphabetically
+func (m *ECSManager) ListClusters(ctx context.Context) ([]string, error) {
+	var names []string
+	var nextToken *string
+
+	for {
+		result, err := m.ecsClient.ListClusters(ctx, &ecs.ListClustersInput{
+			NextToken: nextToken,
+		})
+		if err != nil {
+			if IsAuthError(err) {
+				if shouldReauth, reAuthErr := PromptForReauth(ctx); shouldReauth && reAuthErr == nil {
+					if reloadErr := m.reloadClients(ctx); reloadErr != nil {
+						return nil, reloadErr
+					}
+					result, err = m.ecsClient.ListClusters(ctx, &ecs.ListClustersInput{
+						NextToken: nextToken,
+					})
+					if err != nil {
+						return nil, err
+					}
+				} else {
+					return nil, err
+				}
+			} else {
+				return nil, err
+			}
+		}
+
+		for _, arn := range result.ClusterArns {
+			names = append(names, arnResourceName(arn))
+		}
+
+		if result.NextToken == nil {
+			break
+		}
+		nextToken = result.NextToken
+	}
+
+	sort.Strings(names)
+	return names, nil
+}
+
+// ListServices returns the cluster's services sorted by name
+func (m *ECSManager) ListServices(ctx context.Context, cluster string) ([]ecstypes.Service, error) {
+	var arns []string
+	var nextToken *string
+
+	for {
+		input := &ecs.ListServicesInput{
+			Cluster:   aws.String(cluster),
+			NextToken: nextToken,
+		}
+		result, err := m.ecsClient.ListServices(ctx, input)
+		if err != nil {
+			if IsAuthError(err) {
+				if shouldReauth, reAuthErr := PromptForReauth(ctx); shouldReauth && reAuthErr == nil {
+					if reloadErr := m.reloadClients(ctx); reloadErr != nil {
+						return nil, reloadErr
+					}
+					result, err = m.ecsClient.ListServices(ctx, input)
+					if err != nil {
+						return nil, err
+					}
+				} else {
+					return nil, err
+				}
+			} else {
+				return nil, err
+			}
+		}
+
+		arns = append(arns, result.ServiceArns...)
+
+		if result.NextToken == nil {
+			break
+		}
+		nextToken = result.NextToken
+	}
+
+	var services []ecstypes.Service
+	// DescribeServices accepts at most 10 services per call
+	for start := 0; start < len(arns); start += 10 {
+		end := min(start+10, len(arns))
+		input := &ecs.DescribeServicesInput{
+			Cluster:  aws.String(cluster),
+			Services: arns[start:end],
+		}
+		result, err := m.ecsClient.DescribeServices(ctx, input)
+		if err != nil {
+			if IsAuthError(err) {
+				if shouldReauth, reAuthErr := PromptForReauth(ctx); shouldReauth && reAuthErr == nil {
+					if reloadErr := m.reloadClients(ctx); reloadErr != nil {
+						return nil, reloadErr
+					}
+					result, err = m.ecsClient.DescribeServices(ctx, input)
+					if err != nil {
+						return nil, err
+					}
+				} else {
+					return nil, err
+				}
+			} else {
+				return nil, err
+			}
+		}
+		services = append(services, result.Services...)
+	}
+
+	sort.Slice(services, func(i, j int) bool {
+		return aws.ToString(services[i].ServiceName) < aws.ToString(services[j].ServiceName)
+	})
+
+	return services, nil
+}
+
+// ListTasks returns the service's running tasks
+func (m *ECSManager) ListTasks(ctx context.Context, cluster, service string) ([]ECSTask, error) {
+	var arns []string
+	var nextToken *string
+
+	for {
+		input := &ecs.ListTasksInput{
+			Cluster:       aws.String(cluster),
+			ServiceName:   aws.String(service),
+			DesiredStatus: ecstypes.DesiredStatusRunning,
+			NextToken:     nextToken,
+		}
+		result, err := m.ecsClient.ListTasks(ctx, input)
+		if err != nil {
+			if IsAuthError(err) {
+				if shouldReauth, reAuthErr := PromptForReauth(ctx); shouldReauth && reAuthErr == nil {
+					if reloadErr := m.reloadClients(ctx); reloadErr != nil {
+						return nil, reloadErr
+					}
+					result, err = m.ecsClient.ListTasks(ctx, input)
+					if err != nil {
+						return nil, err
+					}
+				} else {
+					return nil, err
+				}
+			} else {
+				return nil, err
+			}
+		}
+
+		arns = append(arns, result.TaskArns...)
+
+		if result.NextToken == nil {
+			break
+		}
+		nextToken = result.NextToken
+	}
+
+	return m.describeTasks(ctx, cluster, arns)
+}
+
+// describeTasks returns the tasks with the given ARNs or IDs, skipping any that aren't found
+func (m *ECSManager) describeTasks(ctx context.Context, cluster string, arns []string) ([]ECSTask, error) {
+	var tasks []ECSTask
+	// DescribeTasks accepts at most 100 tasks per call
+	for start := 0; start < len(arns); start += 100 {
+		end := min(start+100, len(arns))
+		input := &ecs.DescribeTasksInput{
+			Cluster: aws.String(cluster),
+			Tasks:   arns[start:end],
+		}
+		result, err := m.ecsClient.DescribeTasks(ctx, input)
+		if err != nil {
+			if IsAuthError(err) {
+				if shouldReauth, reAuthErr := PromptForReauth(ctx); shouldReauth && reAuthErr == nil {
+					if reloadErr := m.reloadClients(ctx); reloadErr != nil {
+						return nil, reloadErr
+					}
+					result, err = m.ecsClient.DescribeTasks(ctx, input)
+					if err != nil {
+						return nil, err
+					}
+				} else {
+					return nil, err
+				}
+			} else {
+				return nil, err
+			}
+		}
+
+		for _, task := range result.Tasks {
+			ecsTask := ECSTask{
+				TaskId:               arnResourceName(aws.ToString(task.TaskArn)),
+				TaskDefinition:       arnResourceName(aws.ToString(task.TaskDefinitionArn)),
+				LastStatus:           aws.ToString(task.LastStatus),
+				EnableExecuteCommand: task.EnableExecuteCommand,
+			}
+
+			for _, container := range task.Containers {
+				ecsContainer := ECSContainer{
+					Name:      aws.ToString(container.Name),
+					RuntimeId: aws.ToString(container.RuntimeId),
+				}
+				for _, agent := range container.ManagedAgents {
+					if agent.Name == ecstypes.ManagedAgentNameExecuteCommandAgent && aws.ToString(agent.LastStatus) == "RUNNING" {
+						ecsContainer.AgentReady = true
+					}
+				}
+				ecsTask.Containers = append(ecsTask.Containers, ecsContainer)
+			}
+
+			tasks = append(tasks, ecsTask)
+		}
+	}
+
+	sort.Slice(tasks, func(i, j int) bool {
+		return tasks[i].TaskId < tasks[j].TaskId
+	})
+
+	return tasks, nil
+}
+
+// arnResourceName returns the last path segment of an ARN, e.g. the task ID of
+// arn:aws:ecs:us-east-1:123456789012:task/prod/0123abcd
+func arnResourceName(arn string) string {
+	return arn[strings.LastIndex(arn, "/")+1:]
+}
+
+func (m *ECSManager) reloadClients(ctx context.Context) error {
+	cfg, err := awscconfig.LoadAWSConfigWithProfile(ctx)
+	if err != nil {
+		return err
+	}
+
+	m.ecsClient = ecs.NewFromConfig(cfg)
+	m.region = cfg.Region
+
+	return nil
+}
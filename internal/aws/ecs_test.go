@@ -0,0 +1,274 @@
+package aws
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ecs"
+	ecstypes "github.com/aws/aws-sdk-go-v2/service/ecs/types"
+	"github.com/blontic/awsc/internal/aws/mocks"
+	"go.uber.org/mock/gomock"
+)
+
+const testTaskArn = "arn:aws:ecs:us-east-1:123456789012:task/prod/0123abcd"
+
+func TestNewECSManager(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	manager, err := NewECSManager(context.Background(), ECSManagerOptions{
+		ECSClient: mocks.NewMockECSClient(ctrl),
+		Region:    "us-east-1",
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if manager.region != "us-east-1" {
+		t.Errorf("Expected region us-east-1, got %s", manager.region)
+	}
+}
+
+func TestECSManager_ListClusters(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockECS := mocks.NewMockECSClient(ctrl)
+	manager, _ := NewECSManager(context.Background(), ECSManagerOptions{ECSClient: mockECS})
+
+	gomock.InOrder(
+		mockECS.EXPECT().
+			ListClusters(gomock.Any(), &ecs.ListClustersInput{}).
+			Return(&ecs.ListClustersOutput{
+				ClusterArns: []string{"arn:aws:ecs:us-east-1:123456789012:cluster/staging"},
+				NextToken:   aws.String("page2"),
+			}, nil),
+		mockECS.EXPECT().
+			ListClusters(gomock.Any(), &ecs.ListClustersInput{NextToken: aws.String("page2")}).
+			Return(&ecs.ListClustersOutput{
+				ClusterArns: []string{"arn:aws:ecs:us-east-1:123456789012:cluster/prod"},
+			}, nil),
+	)
+
+	clusters, err := manager.ListClusters(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(clusters) != 2 || clusters[0] != "prod" || clusters[1] != "staging" {
+		t.Errorf("Expected [prod staging], got %v", clusters)
+	}
+}
+
+func TestECSManager_ListTasks(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockECS := mocks.NewMockECSClient(ctrl)
+	manager, _ := NewECSManager(context.Background(), ECSManagerOptions{ECSClient: mockECS})
+
+	mockECS.EXPECT().
+		ListTasks(gomock.Any(), &ecs.ListTasksInput{
+			Cluster:       aws.String("prod"),
+			ServiceName:   aws.String("api"),
+			DesiredStatus: ecstypes.DesiredStatusRunning,
+		}).
+		Return(&ecs.ListTasksOutput{TaskArns: []string{testTaskArn}}, nil)
+
+	mockECS.EXPECT().
+		DescribeTasks(gomock.Any(), &ecs.DescribeTasksInput{
+			Cluster: aws.String("prod"),
+			Tasks:   []string{testTaskArn},
+		}).
+		Return(&ecs.DescribeTasksOutput{
+			Tasks: []ecstypes.Task{{
+				TaskArn:              aws.String(testTaskArn),
+				TaskDefinitionArn:    aws.String("arn:aws:ecs:us-east-1:123456789012:task-definition/api:42"),
+				LastStatus:           aws.String("RUNNING"),
+				EnableExecuteCommand: true,
+				Containers: []ecstypes.Container{
+					{
+						Name:      aws.String("app"),
+						RuntimeId: aws.String("0123abcd-1111"),
+						ManagedAgents: []ecstypes.ManagedAgent{{
+							Name:       ecstypes.ManagedAgentNameExecuteCommandAgent,
+							LastStatus: aws.String("RUNNING"),
+						}},
+					},
+					{
+						Name:      aws.String("log-router"),
+						RuntimeId: aws.String("0123abcd-2222"),
+					},
+				},
+			}},
+		}, nil)
+
+	tasks, err := manager.ListTasks(context.Background(), "prod", "api")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(tasks) != 1 {
+		t.Fatalf("Expected 1 task, got %d", len(tasks))
+	}
+
+	task := tasks[0]
+	if task.TaskId != "0123abcd" || task.TaskDefinition != "api:42" {
+		t.Errorf("Unexpected task %+v", task)
+	}
+	if len(task.Containers) != 2 || !task.Containers[0].AgentReady || task.Containers[1].AgentReady {
+		t.Errorf("Expected only app container to have a ready agent, got %+v", task.Containers)
+	}
+}
+
+func TestECSManager_SelectTarget(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockECS := mocks.NewMockECSClient(ctrl)
+	manager, _ := NewECSManager(context.Background(), ECSManagerOptions{ECSClient: mockECS})
+
+	mockECS.EXPECT().
+		ListClusters(gomock.Any(), gomock.Any()).
+		Return(&ecs.ListClustersOutput{ClusterArns: []string{"arn:aws:ecs:us-east-1:123456789012:cluster/prod"}}, nil)
+	mockECS.EXPECT().
+		ListServices(gomock.Any(), gomock.Any()).
+		Return(&ecs.ListServicesOutput{ServiceArns: []string{"arn:aws:ecs:us-east-1:123456789012:service/prod/api"}}, nil)
+	mockECS.EXPECT().
+		DescribeServices(gomock.Any(), gomock.Any()).
+		Return(&ecs.DescribeServicesOutput{
+			Services: []ecstypes.Service{{
+				ServiceName:          aws.String("api"),
+				RunningCount:         1,
+				DesiredCount:         1,
+				EnableExecuteCommand: true,
+			}},
+		}, nil)
+	mockECS.EXPECT().
+		ListTasks(gomock.Any(), gomock.Any()).
+		Return(&ecs.ListTasksOutput{TaskArns: []string{testTaskArn}}, nil)
+	mockECS.EXPECT().
+		DescribeTasks(gomock.Any(), gomock.Any()).
+		Return(&ecs.DescribeTasksOutput{
+			Tasks: []ecstypes.Task{{
+				TaskArn:              aws.String(testTaskArn),
+				LastStatus:           aws.String("RUNNING"),
+				EnableExecuteCommand: true,
+				Containers: []ecstypes.Container{{
+					Name:      aws.String("app"),
+					RuntimeId: aws.String("0123abcd-1111"),
+					ManagedAgents: []ecstypes.ManagedAgent{{
+						Name:       ecstypes.ManagedAgentNameExecuteCommandAgent,
+						LastStatus: aws.String("RUNNING"),
+					}},
+				}},
+			}},
+		}, nil)
+
+	// Single selectable items are picked without prompting
+	target, err := manager.SelectTarget(context.Background(), ECSTarget{Cluster: "prod"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if target.SSMTarget() != "ecs:prod_0123abcd_0123abcd-1111" {
+		t.Errorf("Unexpected SSM target %s", target.SSMTarget())
+	}
+	if target.Service != "api" || target.Container != "app" {
+		t.Errorf("Unexpected target %+v", target)
+	}
+}
+
+func TestECSManager_SelectTarget_ExecDisabled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockECS := mocks.NewMockECSClient(ctrl)
+	manager, _ := NewECSManager(context.Background(), ECSManagerOptions{ECSClient: mockECS})
+
+	mockECS.EXPECT().
+		ListClusters(gomock.Any(), gomock.Any()).
+		Return(&ecs.ListClustersOutput{ClusterArns: []string{"arn:aws:ecs:us-east-1:123456789012:cluster/prod"}}, nil)
+	mockECS.EXPECT().
+		ListServices(gomock.Any(), gomock.Any()).
+		Return(&ecs.ListServicesOutput{ServiceArns: []string{"arn:aws:ecs:us-east-1:123456789012:service/prod/api"}}, nil)
+	mockECS.EXPECT().
+		DescribeServices(gomock.Any(), gomock.Any()).
+		Return(&ecs.DescribeServicesOutput{
+			Services: []ecstypes.Service{{ServiceName: aws.String("api"), RunningCount: 2, DesiredCount: 2}},
+		}, nil)
+
+	_, err := manager.SelectTarget(context.Background(), ECSTarget{Service: "api"})
+	if err == nil {
+		t.Fatal("Expected error when execute command is disabled")
+	}
+}
+
+func TestECSManager_SelectTarget_StandaloneTask(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockECS := mocks.NewMockECSClient(ctrl)
+	manager, _ := NewECSManager(context.Background(), ECSManagerOptions{ECSClient: mockECS})
+
+	mockECS.EXPECT().
+		ListClusters(gomock.Any(), gomock.Any()).
+		Return(&ecs.ListClustersOutput{ClusterArns: []string{"arn:aws:ecs:us-east-1:123456789012:cluster/prod"}}, nil)
+	// The task is described directly, without listing services or their tasks
+	mockECS.EXPECT().
+		DescribeTasks(gomock.Any(), &ecs.DescribeTasksInput{
+			Cluster: aws.String("prod"),
+			Tasks:   []string{"0123abcd"},
+		}).
+		Return(&ecs.DescribeTasksOutput{
+			Tasks: []ecstypes.Task{{
+				TaskArn:              aws.String(testTaskArn),
+				Group:                aws.String("family:migrate"),
+				LastStatus:           aws.String("RUNNING"),
+				EnableExecuteCommand: true,
+				Containers: []ecstypes.Container{{
+					Name:      aws.String("app"),
+					RuntimeId: aws.String("0123abcd-1111"),
+					ManagedAgents: []ecstypes.ManagedAgent{{
+						Name:       ecstypes.ManagedAgentNameExecuteCommandAgent,
+						LastStatus: aws.String("RUNNING"),
+					}},
+				}},
+			}},
+		}, nil)
+
+	target, err := manager.SelectTarget(context.Background(), ECSTarget{Cluster: "prod", TaskId: "0123abcd"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if target.SSMTarget() != "ecs:prod_0123abcd_0123abcd-1111" {
+		t.Errorf("Unexpected SSM target %s", target.SSMTarget())
+	}
+}
+
+func TestECSManager_SelectTarget_TaskNotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockECS := mocks.NewMockECSClient(ctrl)
+	manager, _ := NewECSManager(context.Background(), ECSManagerOptions{ECSClient: mockECS})
+
+	mockECS.EXPECT().
+		ListClusters(gomock.Any(), gomock.Any()).
+		Return(&ecs.ListClustersOutput{ClusterArns: []string{"arn:aws:ecs:us-east-1:123456789012:cluster/prod"}}, nil)
+	mockECS.EXPECT().
+		DescribeTasks(gomock.Any(), gomock.Any()).
+		Return(&ecs.DescribeTasksOutput{
+			Failures: []ecstypes.Failure{{Arn: aws.String("0123abcd"), Reason: aws.String("MISSING")}},
+		}, nil)
+
+	_, err := manager.SelectTarget(context.Background(), ECSTarget{TaskId: "0123abcd"})
+	if err == nil || !strings.Contains(err.Error(), "task 0123abcd not found in cluster prod") {
+		t.Errorf("Expected task not found error, got %v", err)
+	}
+}
+
+func TestArnResourceName(t *testing.T) {
+	if got := arnResourceName(testTaskArn); got != "0123abcd" {
+		t.Errorf("Expected 0123abcd, got %s", got)
+	}
+}
@@ -0,0 +1,487 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	awscconfig "github.com/blontic/awsc/internal/config"
+	"github.com/blontic/awsc/internal/debug"
+	"github.com/blontic/awsc/internal/proxy"
+	"github.com/blontic/awsc/internal/recording"
+	"github.com/gorilla/websocket"
+)
+
+// TransportEICE marks instances reached through an EC2 Instance Connect Endpoint
+// rather than Session Manager
+const TransportEICE = "eice"
+
+const (
+	eiceSigningService     = "ec2-instance-connect"
+	eiceMaxTunnelDuration  = 3600 // Seconds, the longest EICE allows
+	eicePresignExpiry      = 60   // Seconds the signed URL is valid for opening the tunnel
+	emptyPayloadSHA256Hash = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+)
+
+// InstanceConnectEndpoint is an EC2 Instance Connect Endpoint. It opens TCP tunnels to
+// private IPs in its VPC over a SigV4-signed websocket, with no agent on the target.
+type InstanceConnectEndpoint struct {
+	Id               string
+	DnsName          string
+	VpcId            string
+	SecurityGroupIds []string
+}
+
+// ListInstanceConnectEndpoints returns the ready endpoints of each VPC, keyed by VPC ID
+func (b *BastionFinder) ListInstanceConnectEndpoints(ctx context.Context) (map[string][]InstanceConnectEndpoint, error) {
+	all, err := b.listInstanceConnectEndpoints(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	endpoints := map[string][]InstanceConnectEndpoint{}
+	for _, endpoint := range all {
+		endpoints[endpoint.VpcId] = append(endpoints[endpoint.VpcId], endpoint)
+	}
+	return endpoints, nil
+}
+
+// listInstanceConnectEndpoints returns every ready endpoint in the region
+func (b *BastionFinder) listInstanceConnectEndpoints(ctx context.Context) ([]InstanceConnectEndpoint, error) {
+	var endpoints []InstanceConnectEndpoint
+	var nextToken *string
+
+	for {
+		input := &ec2.DescribeInstanceConnectEndpointsInput{
+			Filters: []types.Filter{
+				{Name: aws.String("state"), Values: []string{string(types.Ec2InstanceConnectEndpointStateCreateComplete)}},
+			},
+			NextToken: nextToken,
+		}
+		result, err := b.ec2Client.DescribeInstanceConnectEndpoints(ctx, input)
+		if err != nil {
+			if IsAuthError(err) {
+				if shouldReauth, reAuthErr := b.promptForReauth(ctx); shouldReauth && reAuthErr == nil {
+					if reloadErr := b.reloadClients(ctx); reloadErr != nil {
+						return nil, reloadErr
+					}
+					result, err = b.ec2Client.DescribeInstanceConnectEndpoints(ctx, input)
+					if err != nil {
+						return nil, err
+					}
+				} else {
+					return nil, err
+				}
+			} else {
+				return nil, err
+			}
+		}
+
+		for _, endpoint := range result.InstanceConnectEndpoints {
+			endpoints = append(endpoints, InstanceConnectEndpoint{
+				Id:               aws.ToString(endpoint.InstanceConnectEndpointId),
+				DnsName:          aws.ToString(endpoint.DnsName),
+				VpcId:            aws.ToString(endpoint.VpcId),
+				SecurityGroupIds: endpoint.SecurityGroupIds,
+			})
+		}
+
+		if result.NextToken == nil {
+			break
+		}
+		nextToken = result.NextToken
+	}
+
+	return endpoints, nil
+}
+
+// FindInstanceConnectEndpoint returns an endpoint in the target's VPC whose security groups
+// the target allows on its port, or nil when there is none
+func (b *BastionFinder) FindInstanceConnectEndpoint(ctx context.Context, target BastionTarget) (*InstanceConnectEndpoint, error) {
+	if len(target.SecurityGroupIds) == 0 {
+		return nil, nil
+	}
+
+	input := &ec2.DescribeSecurityGroupsInput{GroupIds: target.SecurityGroupIds}
+	groups, err := b.ec2Client.DescribeSecurityGroups(ctx, input)
+	if err != nil {
+		if IsAuthError(err) {
+			if shouldReauth, reAuthErr := b.promptForReauth(ctx); shouldReauth && reAuthErr == nil {
+				if reloadErr := b.reloadClients(ctx); reloadErr != nil {
+					return nil, reloadErr
+				}
+				groups, err = b.ec2Client.DescribeSecurityGroups(ctx, input)
+				if err != nil {
+					return nil, err
+				}
+			} else {
+				return nil, err
+			}
+		} else {
+			return nil, err
+		}
+	}
+	if len(groups.SecurityGroups) == 0 {
+		return nil, nil
+	}
+	vpcId := aws.ToString(groups.SecurityGroups[0].VpcId)
+
+	endpoints, err := b.listInstanceConnectEndpoints(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var candidates []InstanceConnectEndpoint
+	for _, endpoint := range endpoints {
+		if endpoint.VpcId == vpcId {
+			candidates = append(candidates, endpoint)
+		}
+	}
+	if len(candidates) == 0 {
+		debug.Printf("No EC2 Instance Connect Endpoint in %s\n", vpcId)
+		return nil, nil
+	}
+
+	return b.connectableEndpoint(ctx, candidates, target.SecurityGroupIds, target.Service, target.Port), nil
+}
+
+// connectableEndpoint returns the first of endpoints whose security groups the target's
+// security groups allow on every port, or nil when there is none. A VPC can have several
+// endpoints with different security groups, so each is tried in turn.
+func (b *BastionFinder) connectableEndpoint(ctx context.Context, endpoints []InstanceConnectEndpoint, targetSecurityGroups []string, targetName string, ports ...int32) *InstanceConnectEndpoint {
+	for _, endpoint := range endpoints {
+		endpointGroups := make([]types.GroupIdentifier, len(endpoint.SecurityGroupIds))
+		for i, id := range endpoint.SecurityGroupIds {
+			endpointGroups[i] = types.GroupIdentifier{GroupId: aws.String(id)}
+		}
+
+		allowed := true
+		for _, port := range ports {
+			if !b.canConnect(ctx, endpointGroups, targetSecurityGroups, port) {
+				allowed = false
+				break
+			}
+		}
+		if allowed {
+			return &endpoint
+		}
+		debug.Printf("✗ EC2 Instance Connect Endpoint %s cannot connect to %s\n", endpoint.Id, targetName)
+	}
+	return nil
+}
+
+// findInstanceConnectFallback looks for an EC2 Instance Connect Endpoint that can reach the
+// target after no bastion was found, returning nil if there is none
+func findInstanceConnectFallback(ctx context.Context, finder *BastionFinder, target BastionTarget) *InstanceConnectEndpoint {
+	endpoint, err := finder.FindInstanceConnectEndpoint(ctx, target)
+	if err != nil {
+		debug.Printf("Error looking for EC2 Instance Connect Endpoints: %v\n", err)
+		return nil
+	}
+	if endpoint != nil {
+		fmt.Printf("Using EC2 Instance Connect Endpoint %s instead\n", endpoint.Id)
+	}
+	return endpoint
+}
+
+// EICEForwarder opens tunnels through an EC2 Instance Connect Endpoint
+type EICEForwarder struct {
+	endpoint    InstanceConnectEndpoint
+	credentials aws.CredentialsProvider
+	region      string
+	lookupIP    func(ctx context.Context, network, host string) ([]net.IP, error)
+}
+
+func NewEICEForwarder(cfg aws.Config, endpoint InstanceConnectEndpoint) *EICEForwarder {
+	return &EICEForwarder{
+		endpoint:    endpoint,
+		credentials: cfg.Credentials,
+		region:      cfg.Region,
+		lookupIP:    net.DefaultResolver.LookupIP,
+	}
+}
+
+// Dial opens a tunnel to host:port. The endpoint only accepts private IP addresses, so
+// host names are resolved locally first.
+func (f *EICEForwarder) Dial(ctx context.Context, host string, port int) (net.Conn, error) {
+	ip, err := f.resolvePrivateIP(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+
+	tunnelURL, err := f.presignTunnelURL(ctx, ip, port)
+	if err != nil {
+		return nil, err
+	}
+
+	ws, resp, err := websocket.DefaultDialer.DialContext(ctx, tunnelURL, nil)
+	if err != nil {
+		if resp != nil {
+			return nil, fmt.Errorf("EC2 Instance Connect Endpoint refused the tunnel to %s:%d: %s", ip, port, resp.Status)
+		}
+		return nil, fmt.Errorf("failed to open EC2 Instance Connect Endpoint tunnel: %w", err)
+	}
+
+	return proxy.NewWebSocketConn(ws), nil
+}
+
+func (f *EICEForwarder) presignTunnelURL(ctx context.Context, privateIP string, port int) (string, error) {
+	query := url.Values{}
+	query.Set("instanceConnectEndpointId", f.endpoint.Id)
+	query.Set("maxTunnelDuration", strconv.Itoa(eiceMaxTunnelDuration))
+	query.Set("privateIpAddress", privateIP)
+	query.Set("remotePort", strconv.Itoa(port))
+	query.Set("X-Amz-Expires", strconv.Itoa(eicePresignExpiry))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("wss://%s/openTunnel?%s", f.endpoint.DnsName, query.Encode()), nil)
+	if err != nil {
+		return "", err
+	}
+
+	creds, err := f.credentials.Retrieve(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to retrieve credentials: %w", err)
+	}
+
+	signedURL, _, err := v4.NewSigner().PresignHTTP(ctx, creds, req, emptyPayloadSHA256Hash, eiceSigningService, f.region, time.Now())
+	if err != nil {
+		return "", fmt.Errorf("failed to sign tunnel request: %w", err)
+	}
+
+	return signedURL, nil
+}
+
+// sharedAddressSpace is the RFC 6598 carrier-grade NAT range, 100.64.0.0/10
+var sharedAddressSpace = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+func (f *EICEForwarder) resolvePrivateIP(ctx context.Context, host string) (string, error) {
+	ip := net.ParseIP(host)
+	if ip == nil {
+		ips, err := f.lookupIP(ctx, "ip4", host)
+		if err != nil {
+			return "", fmt.Errorf("failed to resolve %s: %w", host, err)
+		}
+		if len(ips) == 0 {
+			return "", fmt.Errorf("%s has no IPv4 address", host)
+		}
+		ip = ips[0]
+	}
+
+	// VPCs can also use the RFC 6598 shared address space
+	if !ip.IsPrivate() && !sharedAddressSpace.Contains(ip) {
+		return "", fmt.Errorf("%s resolves to %s, but EC2 Instance Connect Endpoints only reach private addresses", host, ip)
+	}
+	return ip.String(), nil
+}
+
+// StartPortForwarding forwards localhost:localPort to host:port until interrupted
+func (f *EICEForwarder) StartPortForwarding(ctx context.Context, host string, port, localPort int) error {
+	listener, err := net.Listen("tcp", fmt.Sprintf("127.0.0.1:%d", localPort))
+	if err != nil {
+		return fmt.Errorf("port %d is already in use; try a different port with --local-port", localPort)
+	}
+
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	fmt.Printf("Forwarding localhost:%d -> %s:%d via %s\n", localPort, host, port, f.endpoint.Id)
+	fmt.Printf("Press Ctrl+C to stop.\n")
+
+	return proxy.ServeTunnel(ctx, listener, func(ctx context.Context) (net.Conn, error) {
+		return f.Dial(ctx, host, port)
+	})
+}
+
+// StartMultiplePortForwarding forwards the local port of each spec to its port on host
+// until interrupted; the specs' remote hosts are ignored
+func (f *EICEForwarder) StartMultiplePortForwarding(ctx context.Context, host string, specs []ForwardSpec) error {
+	// Listen on every local port up front so nothing is forwarded if one is taken
+	listeners := make([]net.Listener, 0, len(specs))
+	defer func() {
+		for _, listener := range listeners {
+			listener.Close()
+		}
+	}()
+	for _, spec := range specs {
+		listener, err := net.Listen("tcp", fmt.Sprintf("127.0.0.1:%d", spec.LocalPort))
+		if err != nil {
+			return fmt.Errorf("port %d is already in use; try a different local port", spec.LocalPort)
+		}
+		listeners = append(listeners, listener)
+	}
+
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	errs := make(chan error, len(specs))
+	for i, spec := range specs {
+		fmt.Printf("Forwarding localhost:%d -> %s:%d via %s\n", spec.LocalPort, host, spec.RemotePort, f.endpoint.Id)
+		go func(listener net.Listener, port int) {
+			errs <- proxy.ServeTunnel(ctx, listener, func(ctx context.Context) (net.Conn, error) {
+				return f.Dial(ctx, host, port)
+			})
+		}(listeners[i], spec.RemotePort)
+	}
+	fmt.Printf("Press Ctrl+C to stop.\n")
+
+	// The first forward to stop ends them all
+	err := <-errs
+	stop()
+	return err
+}
+
+// RunWithPortForwarding forwards localhost:localPort to host:port while fn runs
+func (f *EICEForwarder) RunWithPortForwarding(ctx context.Context, host string, port, localPort int, fn func() error) error {
+	listener, err := net.Listen("tcp", fmt.Sprintf("127.0.0.1:%d", localPort))
+	if err != nil {
+		return fmt.Errorf("port %d is already in use", localPort)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	served := make(chan struct{})
+	go func() {
+		defer close(served)
+		proxy.ServeTunnel(ctx, listener, func(ctx context.Context) (net.Conn, error) {
+			return f.Dial(ctx, host, port)
+		})
+	}()
+	defer func() {
+		cancel()
+		<-served
+	}()
+
+	return fn()
+}
+
+// startInstanceConnectForwarding forwards localPort to host:port through endpoint
+func startInstanceConnectForwarding(ctx context.Context, endpoint InstanceConnectEndpoint, host string, port, localPort int) error {
+	cfg, err := awscconfig.LoadAWSConfigWithProfile(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load AWS config: %w", err)
+	}
+
+	return NewEICEForwarder(cfg, endpoint).StartPortForwarding(ctx, host, port, localPort)
+}
+
+// markInstanceConnectInstances makes running instances without the SSM agent selectable
+// when their VPC has an EC2 Instance Connect Endpoint, and returns the endpoints by VPC.
+// Security groups are only checked once an instance is picked, see instanceConnectEndpoint.
+func (e *EC2Manager) markInstanceConnectInstances(ctx context.Context, instances []EC2Instance) map[string][]InstanceConnectEndpoint {
+	needsEndpoint := false
+	for _, instance := range instances {
+		if instance.State == "running" && !instance.IsSelectable && instance.PrivateIp != "" {
+			needsEndpoint = true
+			break
+		}
+	}
+	if !needsEndpoint {
+		return nil
+	}
+
+	endpoints, err := e.bastionFinder().ListInstanceConnectEndpoints(ctx)
+	if err != nil {
+		// Missing permissions shouldn't stop SSM connections from working
+		debug.Printf("Error listing EC2 Instance Connect Endpoints: %v\n", err)
+		return nil
+	}
+
+	applyInstanceConnect(instances, endpoints)
+	return endpoints
+}
+
+// applyInstanceConnect marks the running instances without the SSM agent whose VPC has one
+// of endpoints as reachable through it
+func applyInstanceConnect(instances []EC2Instance, endpoints map[string][]InstanceConnectEndpoint) {
+	for i, instance := range instances {
+		if instance.State != "running" || instance.IsSelectable || instance.PrivateIp == "" {
+			continue
+		}
+		if len(endpoints[instance.VpcId]) > 0 {
+			instances[i].Transport = TransportEICE
+			instances[i].IsSelectable = true
+		}
+	}
+}
+
+// instanceConnectEndpoint returns an endpoint in the instance's VPC whose security groups
+// the instance's security groups allow on every port
+func (e *EC2Manager) instanceConnectEndpoint(ctx context.Context, instance EC2Instance, endpoints map[string][]InstanceConnectEndpoint, ports ...int32) (InstanceConnectEndpoint, error) {
+	candidates := endpoints[instance.VpcId]
+	if len(candidates) == 0 {
+		return InstanceConnectEndpoint{}, fmt.Errorf("%s has no EC2 Instance Connect Endpoint", instance.VpcId)
+	}
+
+	endpoint := e.bastionFinder().connectableEndpoint(ctx, candidates, instance.SecurityGroupIds, instance.InstanceId, ports...)
+	if endpoint == nil {
+		return InstanceConnectEndpoint{}, fmt.Errorf("the security groups of %s don't allow any EC2 Instance Connect Endpoint in %s on port %s", instance.InstanceId, instance.VpcId, joinPorts(ports))
+	}
+	return *endpoint, nil
+}
+
+func joinPorts(ports []int32) string {
+	parts := make([]string, len(ports))
+	for i, port := range ports {
+		parts[i] = strconv.Itoa(int(port))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// startInstanceConnectSSH runs ssh to the instance through a local tunnel to its port 22,
+// recording the session to rec when it isn't nil
+func (e *EC2Manager) startInstanceConnectSSH(ctx context.Context, instance EC2Instance, endpoint InstanceConnectEndpoint, key SSHKeyOptions, rec *recording.Recorder) error {
+	sshPath, err := exec.LookPath("ssh")
+	if err != nil {
+		return fmt.Errorf("ssh is required to connect through an EC2 Instance Connect Endpoint")
+	}
+
+	if key.OSUser == "" {
+		key.OSUser = "ec2-user"
+	}
+	if key.PublicKeyPath != "" {
+		if err := e.pushSSHPublicKey(ctx, instance.InstanceId, key); err != nil {
+			return err
+		}
+	}
+
+	cfg, err := awscconfig.LoadAWSConfigWithProfile(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load AWS config: %w", err)
+	}
+
+	forwarder := NewEICEForwarder(cfg, endpoint)
+
+	tunnelPort, err := freeLocalPort()
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Connecting via EC2 Instance Connect Endpoint %s as %s...\n", endpoint.Id, key.OSUser)
+
+	return forwarder.RunWithPortForwarding(ctx, instance.PrivateIp, 22, tunnelPort, func() error {
+		// Key the host entry by instance ID, since the tunnel port changes every time
+		cmd := exec.CommandContext(ctx, sshPath,
+			"-p", strconv.Itoa(tunnelPort),
+			"-o", "HostKeyAlias="+instance.InstanceId,
+			fmt.Sprintf("%s@127.0.0.1", key.OSUser))
+		if rec != nil {
+			return recording.Run(cmd, rec)
+		}
+		cmd.Stdin = os.Stdin
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+		return cmd.Run()
+	})
+}
@@ -0,0 +1,329 @@
+package aws
+
+import (
+	"context"
+	"net"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/blontic/awsc/internal/aws/mocks"
+	"go.uber.org/mock/gomock"
+)
+
+func TestBastionFinder_FindInstanceConnectEndpoint(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEC2 := mocks.NewMockEC2Client(ctrl)
+	finder := NewBastionFinder(mockEC2, "us-east-1")
+
+	// Target security group in vpc-1 allows the endpoint security group on the port
+	mockEC2.EXPECT().
+		DescribeSecurityGroups(gomock.Any(), &ec2.DescribeSecurityGroupsInput{
+			GroupIds: []string{"sg-target"},
+		}).
+		Return(&ec2.DescribeSecurityGroupsOutput{
+			SecurityGroups: []types.SecurityGroup{
+				{
+					VpcId: aws.String("vpc-1"),
+					IpPermissions: []types.IpPermission{
+						{
+							FromPort:         aws.Int32(5432),
+							ToPort:           aws.Int32(5432),
+							UserIdGroupPairs: []types.UserIdGroupPair{{GroupId: aws.String("sg-eice")}},
+						},
+					},
+				},
+			},
+		}, nil).
+		Times(2)
+
+	mockEC2.EXPECT().
+		DescribeInstanceConnectEndpoints(gomock.Any(), gomock.Any()).
+		Return(&ec2.DescribeInstanceConnectEndpointsOutput{
+			InstanceConnectEndpoints: []types.Ec2InstanceConnectEndpoint{
+				{
+					InstanceConnectEndpointId: aws.String("eice-other"),
+					DnsName:                   aws.String("eice-other.example.com"),
+					VpcId:                     aws.String("vpc-2"),
+				},
+				{
+					InstanceConnectEndpointId: aws.String("eice-1"),
+					DnsName:                   aws.String("eice-1.example.com"),
+					VpcId:                     aws.String("vpc-1"),
+					SecurityGroupIds:          []string{"sg-eice"},
+				},
+			},
+		}, nil).
+		Times(1)
+
+	endpoint, err := finder.FindInstanceConnectEndpoint(context.Background(), BastionTarget{
+		Name:             "db",
+		Service:          "RDS",
+		SecurityGroupIds: []string{"sg-target"},
+		Port:             5432,
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if endpoint == nil || endpoint.Id != "eice-1" {
+		t.Fatalf("Expected endpoint eice-1, got %+v", endpoint)
+	}
+}
+
+func TestBastionFinder_FindInstanceConnectEndpoint_SecondEndpointInVpc(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEC2 := mocks.NewMockEC2Client(ctrl)
+	finder := NewBastionFinder(mockEC2, "us-east-1")
+
+	// Only the second endpoint's security group is allowed by the target
+	mockEC2.EXPECT().
+		DescribeSecurityGroups(gomock.Any(), gomock.Any()).
+		Return(&ec2.DescribeSecurityGroupsOutput{
+			SecurityGroups: []types.SecurityGroup{
+				{
+					VpcId: aws.String("vpc-1"),
+					IpPermissions: []types.IpPermission{
+						{
+							FromPort:         aws.Int32(5432),
+							ToPort:           aws.Int32(5432),
+							UserIdGroupPairs: []types.UserIdGroupPair{{GroupId: aws.String("sg-eice-db")}},
+						},
+					},
+				},
+			},
+		}, nil).
+		AnyTimes()
+
+	mockEC2.EXPECT().
+		DescribeInstanceConnectEndpoints(gomock.Any(), gomock.Any()).
+		Return(&ec2.DescribeInstanceConnectEndpointsOutput{
+			InstanceConnectEndpoints: []types.Ec2InstanceConnectEndpoint{
+				{
+					InstanceConnectEndpointId: aws.String("eice-web"),
+					VpcId:                     aws.String("vpc-1"),
+					SecurityGroupIds:          []string{"sg-eice-web"},
+				},
+				{
+					InstanceConnectEndpointId: aws.String("eice-db"),
+					VpcId:                     aws.String("vpc-1"),
+					SecurityGroupIds:          []string{"sg-eice-db"},
+				},
+			},
+		}, nil).
+		Times(1)
+
+	endpoint, err := finder.FindInstanceConnectEndpoint(context.Background(), BastionTarget{
+		Name:             "db",
+		Service:          "RDS",
+		SecurityGroupIds: []string{"sg-target"},
+		Port:             5432,
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if endpoint == nil || endpoint.Id != "eice-db" {
+		t.Fatalf("Expected endpoint eice-db, got %+v", endpoint)
+	}
+}
+
+func TestBastionFinder_FindInstanceConnectEndpoint_NoEndpointInVpc(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEC2 := mocks.NewMockEC2Client(ctrl)
+	finder := NewBastionFinder(mockEC2, "us-east-1")
+
+	mockEC2.EXPECT().
+		DescribeSecurityGroups(gomock.Any(), gomock.Any()).
+		Return(&ec2.DescribeSecurityGroupsOutput{
+			SecurityGroups: []types.SecurityGroup{{VpcId: aws.String("vpc-1")}},
+		}, nil).
+		Times(1)
+
+	mockEC2.EXPECT().
+		DescribeInstanceConnectEndpoints(gomock.Any(), gomock.Any()).
+		Return(&ec2.DescribeInstanceConnectEndpointsOutput{}, nil).
+		Times(1)
+
+	endpoint, err := finder.FindInstanceConnectEndpoint(context.Background(), BastionTarget{
+		SecurityGroupIds: []string{"sg-target"},
+		Port:             5432,
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if endpoint != nil {
+		t.Errorf("Expected no endpoint, got %+v", endpoint)
+	}
+}
+
+func TestApplyInstanceConnect(t *testing.T) {
+	instances := []EC2Instance{
+		{InstanceId: "i-ssm", State: "running", VpcId: "vpc-1", PrivateIp: "10.0.0.1", IsSelectable: true},
+		{InstanceId: "i-no-agent", State: "running", VpcId: "vpc-1", PrivateIp: "10.0.0.2"},
+		{InstanceId: "i-other-vpc", State: "running", VpcId: "vpc-2", PrivateIp: "10.1.0.1"},
+		{InstanceId: "i-stopped", State: "stopped", VpcId: "vpc-1", PrivateIp: "10.0.0.3"},
+	}
+
+	applyInstanceConnect(instances, map[string][]InstanceConnectEndpoint{
+		"vpc-1": {{Id: "eice-1", VpcId: "vpc-1"}},
+	})
+
+	expected := map[string]string{
+		"i-ssm":       "",
+		"i-no-agent":  TransportEICE,
+		"i-other-vpc": "",
+		"i-stopped":   "",
+	}
+	for _, instance := range instances {
+		if instance.Transport != expected[instance.InstanceId] {
+			t.Errorf("%s: expected transport %q, got %q", instance.InstanceId, expected[instance.InstanceId], instance.Transport)
+		}
+	}
+	if !instances[1].IsSelectable {
+		t.Error("Instance reachable through EICE should be selectable")
+	}
+	if instances[2].IsSelectable || instances[3].IsSelectable {
+		t.Error("Instances without an endpoint or not running should stay unselectable")
+	}
+}
+
+func TestEC2Manager_instanceConnectEndpoint(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEC2 := mocks.NewMockEC2Client(ctrl)
+	manager, _ := NewEC2Manager(context.Background(), EC2ManagerOptions{
+		EC2Client: mockEC2,
+		Region:    "us-east-1",
+	})
+
+	// The instance allows SSH from the second endpoint only, and nothing on 3389
+	mockEC2.EXPECT().
+		DescribeSecurityGroups(gomock.Any(), &ec2.DescribeSecurityGroupsInput{GroupIds: []string{"sg-instance"}}).
+		Return(&ec2.DescribeSecurityGroupsOutput{
+			SecurityGroups: []types.SecurityGroup{
+				{
+					IpPermissions: []types.IpPermission{
+						{
+							FromPort:         aws.Int32(22),
+							ToPort:           aws.Int32(22),
+							UserIdGroupPairs: []types.UserIdGroupPair{{GroupId: aws.String("sg-eice-ssh")}},
+						},
+					},
+				},
+			},
+		}, nil).
+		AnyTimes()
+
+	instance := EC2Instance{InstanceId: "i-web", VpcId: "vpc-1", SecurityGroupIds: []string{"sg-instance"}}
+	endpoints := map[string][]InstanceConnectEndpoint{
+		"vpc-1": {
+			{Id: "eice-db", VpcId: "vpc-1", SecurityGroupIds: []string{"sg-eice-db"}},
+			{Id: "eice-ssh", VpcId: "vpc-1", SecurityGroupIds: []string{"sg-eice-ssh"}},
+		},
+	}
+
+	endpoint, err := manager.instanceConnectEndpoint(context.Background(), instance, endpoints, 22)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if endpoint.Id != "eice-ssh" {
+		t.Errorf("Expected eice-ssh, got %s", endpoint.Id)
+	}
+
+	if _, err := manager.instanceConnectEndpoint(context.Background(), instance, endpoints, 22, 3389); err == nil || !strings.Contains(err.Error(), "22, 3389") {
+		t.Errorf("Expected an error when no endpoint is allowed on every port, got %v", err)
+	}
+
+	instance.VpcId = "vpc-2"
+	if _, err := manager.instanceConnectEndpoint(context.Background(), instance, endpoints, 22); err == nil {
+		t.Error("Expected an error for a VPC without endpoints")
+	}
+}
+
+func TestEICEForwarder_resolvePrivateIP(t *testing.T) {
+	forwarder := &EICEForwarder{
+		lookupIP: func(ctx context.Context, network, host string) ([]net.IP, error) {
+			switch host {
+			case "db.internal":
+				return []net.IP{net.ParseIP("10.0.1.5")}, nil
+			default:
+				return []net.IP{net.ParseIP("52.1.2.3")}, nil
+			}
+		},
+	}
+
+	tests := []struct {
+		host    string
+		want    string
+		wantErr bool
+	}{
+		{"10.0.0.7", "10.0.0.7", false},
+		{"db.internal", "10.0.1.5", false},
+		{"100.64.3.9", "100.64.3.9", false},
+		{"100.128.0.1", "", true},
+		{"public.example.com", "", true},
+		{"8.8.8.8", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.host, func(t *testing.T) {
+			got, err := forwarder.resolvePrivateIP(context.Background(), tt.host)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Expected error %v, got %v", tt.wantErr, err)
+			}
+			if got != tt.want {
+				t.Errorf("Expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestEICEForwarder_presignTunnelURL(t *testing.T) {
+	forwarder := NewEICEForwarder(aws.Config{
+		Region: "us-east-1",
+		Credentials: aws.CredentialsProviderFunc(func(ctx context.Context) (aws.Credentials, error) {
+			return aws.Credentials{AccessKeyID: "AKID", SecretAccessKey: "SECRET"}, nil
+		}),
+	}, InstanceConnectEndpoint{Id: "eice-1", DnsName: "eice-1.example.com"})
+
+	signed, err := forwarder.presignTunnelURL(context.Background(), "10.0.0.7", 22)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	parsed, err := url.Parse(signed)
+	if err != nil {
+		t.Fatalf("Invalid URL %q: %v", signed, err)
+	}
+	if parsed.Scheme != "wss" || parsed.Host != "eice-1.example.com" || parsed.Path != "/openTunnel" {
+		t.Errorf("Unexpected tunnel URL %q", signed)
+	}
+
+	query := parsed.Query()
+	for key, want := range map[string]string{
+		"instanceConnectEndpointId": "eice-1",
+		"privateIpAddress":          "10.0.0.7",
+		"remotePort":                "22",
+		"maxTunnelDuration":         "3600",
+		"X-Amz-Expires":             "60",
+	} {
+		if got := query.Get(key); got != want {
+			t.Errorf("Expected %s=%s, got %q", key, want, got)
+		}
+	}
+	if query.Get("X-Amz-Signature") == "" {
+		t.Error("Expected a presigned X-Amz-Signature")
+	}
+	if !strings.Contains(query.Get("X-Amz-Credential"), "/us-east-1/ec2-instance-connect/") {
+		t.Errorf("Expected credential scope for ec2-instance-connect, got %q", query.Get("X-Amz-Credential"))
+	}
+}
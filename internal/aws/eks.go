@@ -0,0 +1,402 @@
+package aws
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/eks"
+	ekstypes "github.com/aws/aws-sdk-go-v2/service/eks/types"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+	awscconfig "github.com/blontic/awsc/internal/config"
+	"github.com/blontic/awsc/internal/debug"
+	"github.com/blontic/awsc/internal/ui"
+)
+
+// EKSClient interface for mocking
+type EKSClient interface {
+	ListClusters(ctx context.Context, params *eks.ListClustersInput, optFns ...func(*eks.Options)) (*eks.ListClustersOutput, error)
+	DescribeCluster(ctx context.Context, params *eks.DescribeClusterInput, optFns ...func(*eks.Options)) (*eks.DescribeClusterOutput, error)
+}
+
+// eksTokenExpiry is how long kubectl may cache a token; presigned URLs are valid for 15 minutes
+const eksTokenExpiry = 14 * time.Minute
+
+type EKSManager struct {
+	eksClient     EKSClient
+	bastionFinder *BastionFinder
+	region        string
+}
+
+type EKSCluster struct {
+	Name                     string
+	Endpoint                 string // API server hostname without scheme
+	Version                  string
+	Status                   string
+	PrivateAccess            bool
+	CertificateAuthorityData string
+	SecurityGroupIds         []string
+}
+
+// IsAvailable reports whether the cluster's API server can be reached through a bastion
+func (c EKSCluster) IsAvailable() bool {
+	return c.Status == string(ekstypes.ClusterStatusActive) && c.PrivateAccess
+}
+
+type EKSManagerOptions struct {
+	EKSClient EKSClient
+	EC2Client EC2Client
+	Region    string
+}
+
+func NewEKSManager(ctx context.Context, opts ...EKSManagerOptions) (*EKSManager, error) {
+	if len(opts) > 0 && opts[0].EKSClient != nil {
+		// Use provided clients (for testing)
+		return &EKSManager{
+			eksClient:     opts[0].EKSClient,
+			bastionFinder: NewBastionFinder(opts[0].EC2Client, opts[0].Region),
+			region:        opts[0].Region,
+		}, nil
+	}
+
+	// Production path
+	cfg, err := awscconfig.LoadAWSConfigWithProfile(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &EKSManager{
+		eksClient:     eks.NewFromConfig(cfg),
+		bastionFinder: NewBastionFinder(ec2.NewFromConfig(cfg), cfg.Region),
+		region:        cfg.Region,
+	}, nil
+}
+
+// RunConnect tunnels to a cluster's private API endpoint and points a kubeconfig context at
+// the tunnel, authenticating through `awsc eks token`
+func (e *EKSManager) RunConnect(ctx context.Context, name string, localPort int32, kubeconfigPath string) error {
+	clusters, err := e.ListClusters(ctx)
+	if err != nil {
+		return fmt.Errorf("error listing EKS clusters: %v", err)
+	}
+
+	if len(clusters) == 0 {
+		return fmt.Errorf("no EKS clusters found")
+	}
+
+	selectedCluster, err := e.selectCluster(clusters, name)
+	if err != nil {
+		return err
+	}
+
+	// Find bastion hosts
+	bastions, err := e.bastionFinder.FindBastionHosts(ctx, BastionTarget{
+		Name:             selectedCluster.Name,
+		Service:          "EKS",
+		SecurityGroupIds: selectedCluster.SecurityGroupIds,
+		Port:             443,
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(bastions) == 0 {
+		return fmt.Errorf("no bastion hosts available for %s", selectedCluster.Name)
+	}
+
+	// Use first available bastion
+	bastion := bastions[0]
+	fmt.Printf("Using bastion: %s\n", bastion.Name)
+
+	if localPort == 0 {
+		localPort = 8443
+	}
+
+	if kubeconfigPath == "" {
+		if kubeconfigPath, err = awscconfig.DefaultKubeconfigPath(); err != nil {
+			return err
+		}
+	}
+
+	contextName, err := e.writeKubeconfig(selectedCluster, localPort, kubeconfigPath)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Wrote kubeconfig context %s to %s (now the current context)\n", contextName, kubeconfigPath)
+
+	return e.StartPortForwarding(ctx, bastion.InstanceId, selectedCluster.Endpoint, 443, localPort)
+}
+
+func (e *EKSManager) writeKubeconfig(cluster EKSCluster, localPort int32, kubeconfigPath string) (string, error) {
+	executable, err := os.Executable()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate awsc executable: %w", err)
+	}
+
+	// Pin the profile so kubectl gets tokens for this account from any terminal
+	profileName, err := awscconfig.CurrentProfileName()
+	if err != nil {
+		return "", err
+	}
+
+	contextName := fmt.Sprintf("awsc-%s", cluster.Name)
+	err = awscconfig.WriteKubeconfigEntry(kubeconfigPath, awscconfig.KubeconfigEntry{
+		Name:                     contextName,
+		Server:                   fmt.Sprintf("https://localhost:%d", localPort),
+		TLSServerName:            cluster.Endpoint,
+		CertificateAuthorityData: cluster.CertificateAuthorityData,
+		ExecCommand:              executable,
+		ExecArgs:                 []string{"eks", "token", "--cluster-name", cluster.Name, "--region", e.region},
+		ExecEnv:                  map[string]string{"AWSC_PROFILE": profileName},
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return contextName, nil
+}
+
+func (e *EKSManager) selectCluster(clusters []EKSCluster, name string) (EKSCluster, error) {
+	// If name provided, try to connect directly
+	if name != "" {
+		var target *EKSCluster
+		for _, cluster := range clusters {
+			if cluster.Name == name {
+				target = &cluster
+				break
+			}
+		}
+
+		if target == nil {
+			fmt.Printf("EKS cluster '%s' not found. Available clusters:\n\n", name)
+		} else if !target.IsAvailable() {
+			fmt.Printf("EKS cluster '%s' is not available (%s). Available clusters:\n\n", name, clusterUnavailableReason(*target))
+		} else {
+			fmt.Printf("Connecting to EKS cluster: %s\n", target.Name)
+			fmt.Printf("✓ Selected: %s\n", target.Name)
+			return *target, nil
+		}
+	}
+
+	options := make([]string, len(clusters))
+	selectable := make([]bool, len(clusters))
+	hasSelectable := false
+	for i, cluster := range clusters {
+		options[i] = fmt.Sprintf("%s (%s)", cluster.Name, cluster.Version)
+		if !cluster.IsAvailable() {
+			options[i] += fmt.Sprintf(" - %s", clusterUnavailableReason(cluster))
+		}
+		selectable[i] = cluster.IsAvailable()
+		hasSelectable = hasSelectable || selectable[i]
+	}
+
+	if !hasSelectable {
+		return EKSCluster{}, fmt.Errorf("no available EKS clusters with private endpoint access found")
+	}
+
+	selectedIndex, err := ui.RunSelectorWithSelectability("Select EKS Cluster:", options, selectable)
+	if err != nil {
+		return EKSCluster{}, fmt.Errorf("error selecting cluster: %v", err)
+	}
+	if selectedIndex == -1 {
+		return EKSCluster{}, fmt.Errorf("no cluster selected")
+	}
+
+	selected := clusters[selectedIndex]
+	fmt.Printf("✓ Selected: %s\n", selected.Name)
+	return selected, nil
+}
+
+func clusterUnavailableReason(cluster EKSCluster) string {
+	if cluster.Status != string(ekstypes.ClusterStatusActive) {
+		return strings.ToLower(cluster.Status)
+	}
+	return "private endpoint access disabled"
+}
+
+// ListClusters returns every cluster in the region with the details needed to connect
+func (e *EKSManager) ListClusters(ctx context.Context) ([]EKSCluster, error) {
+	names, err := e.listClusterNames(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var clusters []EKSCluster
+	for _, name := range names {
+		input := &eks.DescribeClusterInput{Name: aws.String(name)}
+		result, err := e.eksClient.DescribeCluster(ctx, input)
+		if err != nil {
+			if IsAuthError(err) {
+				if shouldReauth, reAuthErr := PromptForReauth(ctx); shouldReauth && reAuthErr == nil {
+					if reloadErr := e.reloadClients(ctx); reloadErr != nil {
+						return nil, reloadErr
+					}
+					result, err = e.eksClient.DescribeCluster(ctx, input)
+					if err != nil {
+						return nil, err
+					}
+				} else {
+					return nil, err
+				}
+			} else {
+				// A cluster that can't be described is left out, the rest can still be listed
+				debug.Printf("Error describing cluster %s: %v\n", name, err)
+				continue
+			}
+		}
+		if result.Cluster == nil {
+			continue
+		}
+
+		cluster := EKSCluster{
+			Name:     name,
+			Endpoint: strings.TrimPrefix(aws.ToString(result.Cluster.Endpoint), "https://"),
+			Version:  aws.ToString(result.Cluster.Version),
+			Status:   string(result.Cluster.Status),
+		}
+		if result.Cluster.CertificateAuthority != nil {
+			cluster.CertificateAuthorityData = aws.ToString(result.Cluster.CertificateAuthority.Data)
+		}
+		if vpcConfig := result.Cluster.ResourcesVpcConfig; vpcConfig != nil {
+			cluster.PrivateAccess = vpcConfig.EndpointPrivateAccess
+			// The cluster security group governs access to the API server ENIs
+			if vpcConfig.ClusterSecurityGroupId != nil {
+				cluster.SecurityGroupIds = append(cluster.SecurityGroupIds, *vpcConfig.ClusterSecurityGroupId)
+			}
+			cluster.SecurityGroupIds = append(cluster.SecurityGroupIds, vpcConfig.SecurityGroupIds...)
+		}
+
+		clusters = append(clusters, cluster)
+	}
+
+	return clusters, nil
+}
+
+func (e *EKSManager) listClusterNames(ctx context.Context) ([]string, error) {
+	var allNames []string
+	var nextToken *string
+
+	for {
+		result, err := e.eksClient.ListClusters(ctx, &eks.ListClustersInput{
+			NextToken: nextToken,
+		})
+		if err != nil {
+			if IsAuthError(err) {
+				if shouldReauth, reAuthErr := PromptForReauth(ctx); shouldReauth && reAuthErr == nil {
+					if reloadErr := e.reloadClients(ctx); reloadErr != nil {
+						return nil, reloadErr
+					}
+					result, err = e.eksClient.ListClusters(ctx, &eks.ListClustersInput{
+						NextToken: nextToken,
+					})
+					if err != nil {
+						return nil, err
+					}
+				} else {
+					return nil, err
+				}
+			} else {
+				return nil, err
+			}
+		}
+
+		allNames = append(allNames, result.Clusters...)
+
+		if result.NextToken == nil {
+			break
+		}
+		nextToken = result.NextToken
+	}
+
+	return allNames, nil
+}
+
+func (e *EKSManager) StartPortForwarding(ctx context.Context, bastionId, apiEndpoint string, remotePort, localPort int32) error {
+	// Create port forwarder
+	cfg, err := awscconfig.LoadAWSConfigWithProfile(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load AWS config: %w", err)
+	}
+
+	pf := NewExternalPluginForwarder(cfg)
+
+	fmt.Printf("Starting port forwarding via %s...\n", bastionId)
+
+	// Start port forwarding to remote host through bastion
+	return pf.StartPortForwardingToRemoteHost(ctx, bastionId, apiEndpoint, int(remotePort), int(localPort))
+}
+
+// ExecCredential is the client.authentication.k8s.io/v1beta1 response kubectl expects
+type ExecCredential struct {
+	APIVersion string               `json:"apiVersion"`
+	Kind       string               `json:"kind"`
+	Spec       struct{}             `json:"spec"`
+	Status     ExecCredentialStatus `json:"status"`
+}
+
+type ExecCredentialStatus struct {
+	ExpirationTimestamp string `json:"expirationTimestamp"`
+	Token               string `json:"token"`
+}
+
+// WriteToken writes an ExecCredential with a token for clusterName, the same
+// presigned STS GetCallerIdentity token `aws eks get-token` produces
+func WriteToken(ctx context.Context, w io.Writer, clusterName string) error {
+	cfg, err := awscconfig.LoadAWSConfigWithProfile(ctx)
+	if err != nil {
+		return err
+	}
+
+	token, err := generateEKSToken(ctx, sts.NewPresignClient(sts.NewFromConfig(cfg)), clusterName)
+	if err != nil {
+		return err
+	}
+
+	credential := ExecCredential{
+		APIVersion: "client.authentication.k8s.io/v1beta1",
+		Kind:       "ExecCredential",
+		Status: ExecCredentialStatus{
+			ExpirationTimestamp: time.Now().UTC().Add(eksTokenExpiry).Format(time.RFC3339),
+			Token:               token,
+		},
+	}
+
+	return json.NewEncoder(w).Encode(credential)
+}
+
+func generateEKSToken(ctx context.Context, presignClient *sts.PresignClient, clusterName string) (string, error) {
+	request, err := presignClient.PresignGetCallerIdentity(ctx, &sts.GetCallerIdentityInput{}, func(po *sts.PresignOptions) {
+		po.ClientOptions = append(po.ClientOptions, func(o *sts.Options) {
+			o.APIOptions = append(o.APIOptions,
+				smithyhttp.SetHeaderValue("x-k8s-aws-id", clusterName),
+				smithyhttp.SetHeaderValue("X-Amz-Expires", "60"),
+			)
+		})
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to presign token request: %w", err)
+	}
+
+	return "k8s-aws-v1." + base64.RawURLEncoding.EncodeToString([]byte(request.URL)), nil
+}
+
+func (e *EKSManager) reloadClients(ctx context.Context) error {
+	cfg, err := awscconfig.LoadAWSConfigWithProfile(ctx)
+	if err != nil {
+		return err
+	}
+
+	e.eksClient = eks.NewFromConfig(cfg)
+	e.bastionFinder = NewBastionFinder(ec2.NewFromConfig(cfg), cfg.Region)
+	e.region = cfg.Region
+
+	return nil
+}
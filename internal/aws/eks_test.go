@@ -0,0 +1,145 @@
+package aws
+
+import (
+	"context"
+	"encoding/base64"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/eks"
+	ekstypes "github.com/aws/aws-sdk-go-v2/service/eks/types"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	"github.com/blontic/awsc/internal/aws/mocks"
+	"go.uber.org/mock/gomock"
+)
+
+func TestNewEKSManager(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	manager, err := NewEKSManager(context.Background(), EKSManagerOptions{
+		EKSClient: mocks.NewMockEKSClient(ctrl),
+		EC2Client: mocks.NewMockEC2Client(ctrl),
+		Region:    "us-east-1",
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if manager.region != "us-east-1" {
+		t.Errorf("Expected region us-east-1, got %s", manager.region)
+	}
+}
+
+func TestEKSManager_ListClusters(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEKS := mocks.NewMockEKSClient(ctrl)
+
+	manager, _ := NewEKSManager(context.Background(), EKSManagerOptions{
+		EKSClient: mockEKS,
+		Region:    "us-east-1",
+	})
+
+	mockEKS.EXPECT().
+		ListClusters(gomock.Any(), gomock.Any()).
+		Return(&eks.ListClustersOutput{Clusters: []string{"prod", "public-only"}}, nil)
+
+	mockEKS.EXPECT().
+		DescribeCluster(gomock.Any(), &eks.DescribeClusterInput{Name: aws.String("prod")}).
+		Return(&eks.DescribeClusterOutput{
+			Cluster: &ekstypes.Cluster{
+				Name:                 aws.String("prod"),
+				Endpoint:             aws.String("https://ABC123.gr7.us-east-1.eks.amazonaws.com"),
+				Version:              aws.String("1.30"),
+				Status:               ekstypes.ClusterStatusActive,
+				CertificateAuthority: &ekstypes.Certificate{Data: aws.String("Y2EtZGF0YQ==")},
+				ResourcesVpcConfig: &ekstypes.VpcConfigResponse{
+					EndpointPrivateAccess:  true,
+					ClusterSecurityGroupId: aws.String("sg-cluster"),
+					SecurityGroupIds:       []string{"sg-extra"},
+				},
+			},
+		}, nil)
+
+	mockEKS.EXPECT().
+		DescribeCluster(gomock.Any(), &eks.DescribeClusterInput{Name: aws.String("public-only")}).
+		Return(&eks.DescribeClusterOutput{
+			Cluster: &ekstypes.Cluster{
+				Name:               aws.String("public-only"),
+				Endpoint:           aws.String("https://DEF456.gr7.us-east-1.eks.amazonaws.com"),
+				Status:             ekstypes.ClusterStatusActive,
+				ResourcesVpcConfig: &ekstypes.VpcConfigResponse{EndpointPublicAccess: true},
+			},
+		}, nil)
+
+	clusters, err := manager.ListClusters(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(clusters) != 2 {
+		t.Fatalf("Expected 2 clusters, got %d", len(clusters))
+	}
+
+	prod := clusters[0]
+	if prod.Endpoint != "ABC123.gr7.us-east-1.eks.amazonaws.com" {
+		t.Errorf("Expected endpoint without scheme, got %s", prod.Endpoint)
+	}
+	if !prod.IsAvailable() {
+		t.Error("Expected prod to be available")
+	}
+	if len(prod.SecurityGroupIds) != 2 || prod.SecurityGroupIds[0] != "sg-cluster" {
+		t.Errorf("Expected cluster security group first, got %v", prod.SecurityGroupIds)
+	}
+	if prod.CertificateAuthorityData != "Y2EtZGF0YQ==" {
+		t.Errorf("Unexpected CA data %s", prod.CertificateAuthorityData)
+	}
+
+	if clusters[1].IsAvailable() {
+		t.Error("Expected cluster without private access to be unavailable")
+	}
+	if reason := clusterUnavailableReason(clusters[1]); reason != "private endpoint access disabled" {
+		t.Errorf("Unexpected reason %q", reason)
+	}
+}
+
+func TestGenerateEKSToken(t *testing.T) {
+	presignClient := sts.NewPresignClient(sts.New(sts.Options{
+		Region:      "us-east-1",
+		Credentials: credentials.NewStaticCredentialsProvider("AKIDEXAMPLE", "secret", ""),
+	}))
+
+	token, err := generateEKSToken(context.Background(), presignClient, "prod")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if !strings.HasPrefix(token, "k8s-aws-v1.") {
+		t.Fatalf("Expected k8s-aws-v1 prefix, got %s", token)
+	}
+
+	decoded, err := base64.RawURLEncoding.DecodeString(strings.TrimPrefix(token, "k8s-aws-v1."))
+	if err != nil {
+		t.Fatalf("Token payload is not unpadded base64url: %v", err)
+	}
+
+	presigned, err := url.Parse(string(decoded))
+	if err != nil {
+		t.Fatalf("Token payload is not a URL: %v", err)
+	}
+
+	query := presigned.Query()
+	if query.Get("Action") != "GetCallerIdentity" {
+		t.Errorf("Expected GetCallerIdentity, got %s", query.Get("Action"))
+	}
+	if !strings.Contains(query.Get("X-Amz-SignedHeaders"), "x-k8s-aws-id") {
+		t.Errorf("Expected x-k8s-aws-id to be signed, got %s", query.Get("X-Amz-SignedHeaders"))
+	}
+	if query.Get("X-Amz-Expires") != "60" {
+		t.Errorf("Expected X-Amz-Expires 60, got %s", query.Get("X-Amz-Expires"))
+	}
+}
@@ -8,11 +8,18 @@ import (
 	"os"
 	"os/exec"
 	"strconv"
+	"sync"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	"github.com/blontic/awsc/internal/debug"
+	"github.com/blontic/awsc/internal/recording"
 )
 
+// portReadyTimeout is how long to wait for a background tunnel to accept connections
+const portReadyTimeout = 30 * time.Second
+
 // ExternalPluginForwarder uses the external session-manager-plugin binary
 type ExternalPluginForwarder struct {
 	ssmClient *ssm.Client
@@ -27,14 +34,235 @@ func NewExternalPluginForwarder(cfg aws.Config) *ExternalPluginForwarder {
 }
 
 func (pf *ExternalPluginForwarder) StartPortForwardingToRemoteHost(ctx context.Context, bastionId, remoteHost string, remotePort, localPort int) error {
+	cmd, _, err := pf.newPortForwardingCommand(ctx, bastionId, remoteHost, remotePort, localPort)
+	if err != nil {
+		return err
+	}
+
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.Stdin = os.Stdin
+
+	// Start the plugin and wait for it to complete
+	return cmd.Run()
+}
+
+// ForwardSpec is a local port forwarded to a host and port reachable from the instance
+type ForwardSpec struct {
+	LocalPort  int
+	RemoteHost string
+	RemotePort int
+}
+
+func (s ForwardSpec) String() string {
+	return fmt.Sprintf("localhost:%d -> %s", s.LocalPort, net.JoinHostPort(s.RemoteHost, strconv.Itoa(s.RemotePort)))
+}
+
+// StartMultiplePortForwarding forwards several targets through one instance, using a
+// session per target, until any session ends or the user interrupts
+func (pf *ExternalPluginForwarder) StartMultiplePortForwarding(ctx context.Context, bastionId string, specs []ForwardSpec) error {
+	if len(specs) == 1 {
+		return pf.StartPortForwardingToRemoteHost(ctx, bastionId, specs[0].RemoteHost, specs[0].RemotePort, specs[0].LocalPort)
+	}
+
+	// Check every local port up front so no session is left running if one is taken
+	for _, spec := range specs {
+		if err := pf.checkPortAvailable(spec.LocalPort); err != nil {
+			return err
+		}
+	}
+
+	var cmds []*exec.Cmd
+	var sessionIds []*string
+	defer func() {
+		for _, cmd := range cmds {
+			if cmd.ProcessState == nil {
+				cmd.Process.Kill()
+			}
+		}
+		for _, sessionId := range sessionIds {
+			if _, err := pf.ssmClient.TerminateSession(context.Background(), &ssm.TerminateSessionInput{SessionId: sessionId}); err != nil {
+				debug.Printf("Error terminating session %s: %v\n", *sessionId, err)
+			}
+		}
+	}()
+
+	for _, spec := range specs {
+		cmd, sessionId, err := pf.newPortForwardingCommand(ctx, bastionId, spec.RemoteHost, spec.RemotePort, spec.LocalPort)
+		if err != nil {
+			return err
+		}
+		sessionIds = append(sessionIds, sessionId)
+
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+		if err := cmd.Start(); err != nil {
+			return fmt.Errorf("failed to start session-manager-plugin: %w", err)
+		}
+		cmds = append(cmds, cmd)
+
+		fmt.Printf("Forwarding %s\n", spec)
+	}
+
+	// The first forward to stop ends them all, like the EICE transport
+	index, err := waitFirstExit(cmds)
+	cmds = nil // Already waited on, the deferred cleanup only ends their sessions
+	if err != nil {
+		return fmt.Errorf("forwarding %s ended: %w", specs[index], err)
+	}
+	return nil
+}
+
+// waitFirstExit waits until any of cmds exits, kills the others and waits for them too.
+// It returns the index and exit error of the first one to exit.
+func waitFirstExit(cmds []*exec.Cmd) (int, error) {
+	type exit struct {
+		index int
+		err   error
+	}
+	exits := make(chan exit, len(cmds))
+	for i, cmd := range cmds {
+		go func() {
+			exits <- exit{i, cmd.Wait()}
+		}()
+	}
+
+	first := <-exits
+	for _, cmd := range cmds {
+		cmd.Process.Kill()
+	}
+	for range len(cmds) - 1 {
+		<-exits
+	}
+	return first.index, first.err
+}
+
+// DialRemoteHost opens a dedicated port forwarding session to remoteHost:remotePort on a
+// free local port and returns a connection through it; closing the connection ends the session
+func (pf *ExternalPluginForwarder) DialRemoteHost(ctx context.Context, bastionId, remoteHost string, remotePort int) (net.Conn, error) {
+	localPort, err := freeLocalPort()
+	if err != nil {
+		return nil, err
+	}
+
+	cmd, sessionId, err := pf.newPortForwardingCommand(ctx, bastionId, remoteHost, remotePort, localPort)
+	if err != nil {
+		return nil, err
+	}
+
+	if debug.IsVerbose() {
+		cmd.Stdout = os.Stderr
+		cmd.Stderr = os.Stderr
+	}
+
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start session-manager-plugin: %w", err)
+	}
+
+	exited := make(chan struct{})
+	go func() {
+		cmd.Wait()
+		close(exited)
+	}()
+
+	teardown := func() {
+		select {
+		case <-exited:
+		default:
+			cmd.Process.Kill()
+			<-exited
+		}
+		if _, err := pf.ssmClient.TerminateSession(context.Background(), &ssm.TerminateSessionInput{SessionId: sessionId}); err != nil {
+			debug.Printf("Error terminating session %s: %v\n", *sessionId, err)
+		}
+	}
+
+	conn, err := dialWhenReady(localPort, portReadyTimeout, exited)
+	if err != nil {
+		teardown()
+		return nil, err
+	}
+
+	return &sessionConn{Conn: conn, teardown: teardown}, nil
+}
+
+// sessionConn ends its SSM session when the connection is closed
+type sessionConn struct {
+	net.Conn
+	teardown func()
+	once     sync.Once
+}
+
+func (c *sessionConn) Close() error {
+	err := c.Conn.Close()
+	c.once.Do(c.teardown)
+	return err
+}
+
+func freeLocalPort() (int, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return 0, fmt.Errorf("failed to find a free local port: %w", err)
+	}
+	defer listener.Close()
+	return listener.Addr().(*net.TCPAddr).Port, nil
+}
+
+// RunWithPortForwarding starts port forwarding in the background, waits until the
+// local port accepts connections and then runs fn. The session is terminated once
+// fn returns.
+func (pf *ExternalPluginForwarder) RunWithPortForwarding(ctx context.Context, bastionId, remoteHost string, remotePort, localPort int, fn func() error) error {
+	cmd, sessionId, err := pf.newPortForwardingCommand(ctx, bastionId, remoteHost, remotePort, localPort)
+	if err != nil {
+		return err
+	}
+
+	// Plugin output would interleave with whatever fn runs, so only show it in verbose mode
+	if debug.IsVerbose() {
+		cmd.Stdout = os.Stderr
+		cmd.Stderr = os.Stderr
+	}
+
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to start session-manager-plugin: %w", err)
+	}
+
+	exited := make(chan struct{})
+	go func() {
+		cmd.Wait()
+		close(exited)
+	}()
+
+	defer func() {
+		select {
+		case <-exited:
+		default:
+			cmd.Process.Kill()
+			<-exited
+		}
+		if _, err := pf.ssmClient.TerminateSession(context.Background(), &ssm.TerminateSessionInput{SessionId: sessionId}); err != nil {
+			debug.Printf("Error terminating session %s: %v\n", *sessionId, err)
+		}
+	}()
+
+	if err := waitForPort(localPort, portReadyTimeout, exited); err != nil {
+		return err
+	}
+
+	return fn()
+}
+
+// newPortForwardingCommand starts a port forwarding session to a remote host and
+// returns the (not yet started) plugin command together with the session ID
+func (pf *ExternalPluginForwarder) newPortForwardingCommand(ctx context.Context, bastionId, remoteHost string, remotePort, localPort int) (*exec.Cmd, *string, error) {
 	// Check if session-manager-plugin is available
 	if _, err := exec.LookPath("session-manager-plugin"); err != nil {
-		return pf.handleMissingPlugin()
+		return nil, nil, pf.handleMissingPlugin()
 	}
 
 	// Check if local port is available
 	if err := pf.checkPortAvailable(localPort); err != nil {
-		return err
+		return nil, nil, err
 	}
 
 	// Start SSM session
@@ -50,28 +278,24 @@ func (pf *ExternalPluginForwarder) StartPortForwardingToRemoteHost(ctx context.C
 
 	result, err := pf.ssmClient.StartSession(ctx, sessionInput)
 	if err != nil {
-		return fmt.Errorf("failed to start SSM session: %w", err)
+		return nil, nil, fmt.Errorf("failed to start SSM session: %w", err)
 	}
 
-	// Prepare session response for plugin
-	responseJson, _ := json.Marshal(map[string]interface{}{
-		"SessionId":  *result.SessionId,
-		"StreamUrl":  *result.StreamUrl,
-		"TokenValue": *result.TokenValue,
-	})
+	cmd, err := pf.pluginCommand(ctx, aws.ToString(result.SessionId), aws.ToString(result.StreamUrl), aws.ToString(result.TokenValue), sessionParameters(sessionInput))
+	if err != nil {
+		return nil, nil, err
+	}
 
-	// Prepare parameters for plugin
-	parametersJson := fmt.Sprintf(`{"Target":"%s","DocumentName":"AWS-StartPortForwardingSessionToRemoteHost","Parameters":{"host":["%s"],"portNumber":["%s"],"localPortNumber":["%s"]}}`,
-		bastionId, remoteHost, strconv.Itoa(remotePort), strconv.Itoa(localPort))
+	return cmd, result.SessionId, nil
+}
 
-	// Call session-manager-plugin with exact same arguments as AWS CLI
-	cmd := exec.CommandContext(ctx, "session-manager-plugin",
-		string(responseJson), // Session response
-		pf.region,            // Region
-		"StartSession",       // Operation
-		"",                   // Profile (empty)
-		parametersJson,       // Parameters
-		"")                   // Endpoint (empty)
+// StartInteractiveSession opens a session with the given Session document on the instance
+// and attaches the terminal; the zero SessionDocument is the default shell
+func (pf *ExternalPluginForwarder) StartInteractiveSession(ctx context.Context, instanceId string, document SessionDocument) error {
+	cmd, err := pf.newInteractiveSessionCommand(ctx, instanceId, document)
+	if err != nil {
+		return err
+	}
 
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
@@ -81,40 +305,84 @@ func (pf *ExternalPluginForwarder) StartPortForwardingToRemoteHost(ctx context.C
 	return cmd.Run()
 }
 
-func (pf *ExternalPluginForwarder) StartInteractiveSession(ctx context.Context, instanceId string) error {
+// StartRecordedSession is StartInteractiveSession with the plugin running in a
+// pseudo-terminal, so everything typed and printed is also written to rec
+func (pf *ExternalPluginForwarder) StartRecordedSession(ctx context.Context, instanceId string, document SessionDocument, rec *recording.Recorder) error {
+	cmd, err := pf.newInteractiveSessionCommand(ctx, instanceId, document)
+	if err != nil {
+		return err
+	}
+
+	return recording.Run(cmd, rec)
+}
+
+// newInteractiveSessionCommand starts a session on the instance and returns the plugin
+// command that attaches to it
+func (pf *ExternalPluginForwarder) newInteractiveSessionCommand(ctx context.Context, instanceId string, document SessionDocument) (*exec.Cmd, error) {
 	// Check if session-manager-plugin is available
 	if _, err := exec.LookPath("session-manager-plugin"); err != nil {
-		return pf.handleMissingPlugin()
+		return nil, pf.handleMissingPlugin()
 	}
 
 	// Start SSM session
 	sessionInput := &ssm.StartSessionInput{
 		Target: aws.String(instanceId),
 	}
+	if document.Name != "" {
+		sessionInput.DocumentName = aws.String(document.Name)
+		sessionInput.Parameters = document.Parameters
+	}
 
 	result, err := pf.ssmClient.StartSession(ctx, sessionInput)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start SSM session: %w", err)
+	}
+
+	return pf.pluginCommand(ctx, aws.ToString(result.SessionId), aws.ToString(result.StreamUrl), aws.ToString(result.TokenValue), sessionParameters(sessionInput))
+}
+
+// StartSessionToTarget starts a session with the given document on any SSM target (for
+// example an ECS container, "ecs:<cluster>_<task>_<runtimeId>") and attaches the terminal
+func (pf *ExternalPluginForwarder) StartSessionToTarget(ctx context.Context, target, documentName string, parameters map[string][]string) error {
+	// Check if session-manager-plugin is available
+	if _, err := exec.LookPath("session-manager-plugin"); err != nil {
+		return pf.handleMissingPlugin()
+	}
+
+	result, err := pf.ssmClient.StartSession(ctx, &ssm.StartSessionInput{
+		Target:       aws.String(target),
+		DocumentName: aws.String(documentName),
+		Parameters:   parameters,
+	})
 	if err != nil {
 		return fmt.Errorf("failed to start SSM session: %w", err)
 	}
 
-	// Prepare session response for plugin
-	responseJson, _ := json.Marshal(map[string]interface{}{
-		"SessionId":  *result.SessionId,
-		"StreamUrl":  *result.StreamUrl,
-		"TokenValue": *result.TokenValue,
+	return pf.runPlugin(ctx, aws.ToString(result.SessionId), aws.ToString(result.StreamUrl), aws.ToString(result.TokenValue), map[string]interface{}{
+		"Target":       target,
+		"DocumentName": documentName,
+		"Parameters":   parameters,
 	})
+}
+
+// AttachSession hands a session started by another service (such as ECS ExecuteCommand)
+// to the plugin and attaches the terminal
+func (pf *ExternalPluginForwarder) AttachSession(ctx context.Context, sessionId, streamUrl, tokenValue, target string) error {
+	// Check if session-manager-plugin is available
+	if _, err := exec.LookPath("session-manager-plugin"); err != nil {
+		return pf.handleMissingPlugin()
+	}
 
-	// Prepare parameters for plugin
-	parametersJson := fmt.Sprintf(`{"Target":"%s"}`, instanceId)
+	return pf.runPlugin(ctx, sessionId, streamUrl, tokenValue, map[string]interface{}{
+		"Target": target,
+	})
+}
 
-	// Call session-manager-plugin with exact same arguments as AWS CLI
-	cmd := exec.CommandContext(ctx, "session-manager-plugin",
-		string(responseJson), // Session response
-		pf.region,            // Region
-		"StartSession",       // Operation
-		"",                   // Profile (empty)
-		parametersJson,       // Parameters
-		"")                   // Endpoint (empty)
+func (pf *ExternalPluginForwarder) runPlugin(ctx context.Context, sessionId, streamUrl, tokenValue string, parameters map[string]interface{}) error {
+	cmd, err := pf.pluginCommand(ctx, sessionId, streamUrl, tokenValue, parameters)
+	if err != nil {
+		return err
+	}
 
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
@@ -124,17 +392,89 @@ func (pf *ExternalPluginForwarder) StartInteractiveSession(ctx context.Context,
 	return cmd.Run()
 }
 
+// pluginCommand returns the session-manager-plugin command that attaches to a started
+// session, with parameters being the StartSession request it came from
+func (pf *ExternalPluginForwarder) pluginCommand(ctx context.Context, sessionId, streamUrl, tokenValue string, parameters map[string]interface{}) (*exec.Cmd, error) {
+	// Prepare session response for plugin
+	responseJson, _ := json.Marshal(map[string]interface{}{
+		"SessionId":  sessionId,
+		"StreamUrl":  streamUrl,
+		"TokenValue": tokenValue,
+	})
+
+	parametersJson, err := json.Marshal(parameters)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode session parameters: %w", err)
+	}
+
+	// Call session-manager-plugin with exact same arguments as AWS CLI
+	cmd := exec.CommandContext(ctx, "session-manager-plugin",
+		string(responseJson),   // Session response
+		pf.region,              // Region
+		"StartSession",         // Operation
+		"",                     // Profile (empty)
+		string(parametersJson), // Parameters
+		"")                     // Endpoint (empty)
+
+	return cmd, nil
+}
+
+// sessionParameters is the StartSession request in the form the plugin expects
+func sessionParameters(input *ssm.StartSessionInput) map[string]interface{} {
+	parameters := map[string]interface{}{
+		"Target": aws.ToString(input.Target),
+	}
+	if input.DocumentName != nil {
+		parameters["DocumentName"] = aws.ToString(input.DocumentName)
+	}
+	if len(input.Parameters) > 0 {
+		parameters["Parameters"] = input.Parameters
+	}
+	return parameters
+}
+
 func (pf *ExternalPluginForwarder) checkPortAvailable(port int) error {
 	listener, err := net.Listen("tcp", fmt.Sprintf("127.0.0.1:%d", port))
 	if err != nil {
-		fmt.Printf("\nError: Port %d is already in use.\n", port)
-		fmt.Printf("Try using a different port with --local-port <port>\n")
-		os.Exit(1)
+		return fmt.Errorf("port %d is already in use", port)
 	}
 	listener.Close()
 	return nil
 }
 
+// waitForPort polls the local port until it accepts connections, the plugin exits
+// or the timeout expires
+func waitForPort(port int, timeout time.Duration, exited <-chan struct{}) error {
+	conn, err := dialWhenReady(port, timeout, exited)
+	if err != nil {
+		return err
+	}
+	conn.Close()
+	return nil
+}
+
+// dialWhenReady connects to a local tunnel port, retrying until the plugin is listening
+func dialWhenReady(port int, timeout time.Duration, exited <-chan struct{}) (net.Conn, error) {
+	address := fmt.Sprintf("127.0.0.1:%d", port)
+	deadline := time.Now().Add(timeout)
+
+	for time.Now().Before(deadline) {
+		select {
+		case <-exited:
+			return nil, fmt.Errorf("session-manager-plugin exited before port %d was ready", port)
+		default:
+		}
+
+		conn, err := net.DialTimeout("tcp", address, time.Second)
+		if err == nil {
+			return conn, nil
+		}
+		time.Sleep(200 * time.Millisecond)
+	}
+
+	return nil, fmt.Errorf("timed out waiting for port forwarding on localhost:%d", port)
+}
+
 func (pf *ExternalPluginForwarder) handleMissingPlugin() error {
 	fmt.Printf("\n❌ Session Manager Plugin not found\n\n")
 	fmt.Printf("The AWS Session Manager Plugin is required for SSM sessions.\n")
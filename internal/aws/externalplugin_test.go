@@ -1,7 +1,13 @@
 package aws
 
 import (
+	"errors"
+	"fmt"
+	"net"
+	"os/exec"
+	"runtime"
 	"testing"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 )
@@ -46,3 +52,85 @@ func TestExternalPluginForwarder_StartInteractiveSession(t *testing.T) {
 	// and valid AWS credentials
 	t.Skip("Skipping StartInteractiveSession test - requires session-manager-plugin and AWS credentials")
 }
+
+func TestWaitForPort(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to open listener: %v", err)
+	}
+	defer listener.Close()
+
+	port := listener.Addr().(*net.TCPAddr).Port
+	if err := waitForPort(port, time.Second, make(chan struct{})); err != nil {
+		t.Errorf("Expected port to be ready, got: %v", err)
+	}
+}
+
+func TestWaitForPort_PluginExited(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to open listener: %v", err)
+	}
+	port := listener.Addr().(*net.TCPAddr).Port
+	listener.Close()
+
+	exited := make(chan struct{})
+	close(exited)
+
+	if err := waitForPort(port, time.Second, exited); err == nil {
+		t.Error("Expected error when plugin exited before port was ready")
+	}
+}
+
+func TestExternalPluginForwarder_checkPortAvailable(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to open listener: %v", err)
+	}
+	defer listener.Close()
+
+	forwarder := &ExternalPluginForwarder{region: "us-east-1"}
+	port := listener.Addr().(*net.TCPAddr).Port
+	err = forwarder.checkPortAvailable(port)
+	if err == nil {
+		t.Fatal("Expected error when port is in use")
+	}
+	if err.Error() != fmt.Sprintf("port %d is already in use", port) {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+func TestWaitFirstExit(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Skipping waitFirstExit test - uses sh and sleep")
+	}
+
+	cmds := []*exec.Cmd{
+		exec.Command("sleep", "30"),
+		exec.Command("sh", "-c", "exit 3"),
+		exec.Command("sleep", "30"),
+	}
+	for _, cmd := range cmds {
+		if err := cmd.Start(); err != nil {
+			t.Fatalf("Failed to start %v: %v", cmd.Args, err)
+		}
+	}
+
+	start := time.Now()
+	index, err := waitFirstExit(cmds)
+	if index != 1 {
+		t.Errorf("Expected the second command to exit first, got %d", index)
+	}
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) || exitErr.ExitCode() != 3 {
+		t.Errorf("Expected exit status 3, got %v", err)
+	}
+	if time.Since(start) > 10*time.Second {
+		t.Error("Expected the other commands to be killed")
+	}
+	for i, cmd := range cmds {
+		if cmd.ProcessState == nil {
+			t.Errorf("Expected command %d to have been waited on", i)
+		}
+	}
+}
@@ -0,0 +1,465 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"os/signal"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	awscconfig "github.com/blontic/awsc/internal/config"
+	"github.com/blontic/awsc/internal/debug"
+	"github.com/blontic/awsc/internal/proxy"
+)
+
+// ViaAuto selects an SSM instance in the VPC that contains the target's private IP
+const ViaAuto = "auto"
+
+// instanceIdPattern matches EC2 instance IDs, in both the short and the long format
+var instanceIdPattern = regexp.MustCompile(`^i-[0-9a-f]{8,17}$`)
+
+// ForwardManager forwards local ports to arbitrary hosts reachable from an SSM-managed instance
+type ForwardManager struct {
+	ec2Client EC2Client
+	ssmClient SSMClient
+	region    string
+	lookupIP  func(host string) ([]net.IP, error)
+}
+
+type ForwardManagerOptions struct {
+	EC2Client EC2Client
+	SSMClient SSMClient
+	Region    string
+	LookupIP  func(host string) ([]net.IP, error)
+}
+
+func NewForwardManager(ctx context.Context, opts ...ForwardManagerOptions) (*ForwardManager, error) {
+	if len(opts) > 0 && opts[0].EC2Client != nil {
+		// Use provided clients (for testing)
+		lookupIP := opts[0].LookupIP
+		if lookupIP == nil {
+			lookupIP = net.LookupIP
+		}
+		return &ForwardManager{
+			ec2Client: opts[0].EC2Client,
+			ssmClient: opts[0].SSMClient,
+			region:    opts[0].Region,
+			lookupIP:  lookupIP,
+		}, nil
+	}
+
+	// Production path
+	cfg, err := awscconfig.LoadAWSConfigWithProfile(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ForwardManager{
+		ec2Client: ec2.NewFromConfig(cfg),
+		ssmClient: ssm.NewFromConfig(cfg),
+		region:    cfg.Region,
+		lookupIP:  net.LookupIP,
+	}, nil
+}
+
+// ParseForwardSpec parses "local:host:port" or "host:port"; without a local port the
+// remote port is used. IPv6 hosts must be bracketed, e.g. "8443:[fd00::1]:443".
+func ParseForwardSpec(s string) (ForwardSpec, error) {
+	localPart := ""
+	target := s
+	// A leading numeric field followed by a colon is the local port
+	if i := strings.Index(s, ":"); i > 0 {
+		if _, err := strconv.Atoi(s[:i]); err == nil && strings.Count(s, ":") >= 2 {
+			localPart = s[:i]
+			target = s[i+1:]
+		}
+	}
+
+	host, portStr, err := net.SplitHostPort(target)
+	if err != nil || host == "" {
+		return ForwardSpec{}, fmt.Errorf("invalid forward %q: expected [local-port:]host:port", s)
+	}
+
+	remotePort, err := parsePort(portStr)
+	if err != nil {
+		return ForwardSpec{}, fmt.Errorf("invalid forward %q: %v", s, err)
+	}
+
+	localPort := remotePort
+	if localPart != "" {
+		if localPort, err = parsePort(localPart); err != nil {
+			return ForwardSpec{}, fmt.Errorf("invalid forward %q: %v", s, err)
+		}
+	}
+
+	return ForwardSpec{LocalPort: localPort, RemoteHost: host, RemotePort: remotePort}, nil
+}
+
+func parsePort(s string) (int, error) {
+	port, err := strconv.Atoi(s)
+	if err != nil || port < 1 || port > 65535 {
+		return 0, fmt.Errorf("invalid port %q", s)
+	}
+	return port, nil
+}
+
+// RunForward resolves the instance to forward through and starts a session per spec. vpcId
+// picks the VPC for --via auto when the host's IP is in more than one.
+func (f *ForwardManager) RunForward(ctx context.Context, via, vpcId string, specs []ForwardSpec) error {
+	if len(specs) == 0 {
+		return fmt.Errorf("no forwards specified")
+	}
+
+	seen := make(map[int]bool)
+	for _, spec := range specs {
+		if seen[spec.LocalPort] {
+			return fmt.Errorf("local port %d is used by more than one forward", spec.LocalPort)
+		}
+		seen[spec.LocalPort] = true
+	}
+
+	var hosts []string
+	for _, spec := range specs {
+		hosts = append(hosts, spec.RemoteHost)
+	}
+
+	instanceId, err := f.resolveVia(ctx, via, hosts, vpcId)
+	if err != nil {
+		return err
+	}
+
+	cfg, err := awscconfig.LoadAWSConfigWithProfile(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load AWS config: %w", err)
+	}
+
+	pf := NewExternalPluginForwarder(cfg)
+
+	fmt.Printf("Starting port forwarding via %s...\n", instanceId)
+	return pf.StartMultiplePortForwarding(ctx, instanceId, specs)
+}
+
+// RunProxy serves a local SOCKS5 proxy (and HTTP CONNECT when allowHTTP is set) on
+// listenPort, opening a port forwarding session through the instance for each connection
+func (f *ForwardManager) RunProxy(ctx context.Context, via string, listenPort int, allowHTTP bool) error {
+	if via == ViaAuto {
+		return fmt.Errorf("--via auto needs a target host; pass an instance ID or Name tag")
+	}
+
+	instanceId, err := f.resolveVia(ctx, via, nil, "")
+	if err != nil {
+		return err
+	}
+
+	cfg, err := awscconfig.LoadAWSConfigWithProfile(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load AWS config: %w", err)
+	}
+
+	pf := NewExternalPluginForwarder(cfg)
+
+	// Check up front rather than failing on the first proxied connection
+	if _, err := exec.LookPath("session-manager-plugin"); err != nil {
+		return pf.handleMissingPlugin()
+	}
+
+	listener, err := net.Listen("tcp", fmt.Sprintf("127.0.0.1:%d", listenPort))
+	if err != nil {
+		return fmt.Errorf("port %d is already in use; try a different port with --port", listenPort)
+	}
+
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	protocols := "SOCKS5"
+	if allowHTTP {
+		protocols = "SOCKS5 and HTTP CONNECT"
+	}
+	fmt.Printf("%s proxy listening on 127.0.0.1:%d via %s\n", protocols, listenPort, instanceId)
+	fmt.Printf("Each connection opens its own SSM session, so the first request to a host takes a few seconds.\n")
+	fmt.Printf("Press Ctrl+C to stop.\n")
+
+	server := &proxy.Server{
+		AllowHTTP: allowHTTP,
+		Dial: func(ctx context.Context, host string, port int) (net.Conn, error) {
+			debug.Printf("proxy: opening %s\n", net.JoinHostPort(host, strconv.Itoa(port)))
+			return pf.DialRemoteHost(ctx, instanceId, host, port)
+		},
+	}
+
+	return server.Serve(ctx, listener)
+}
+
+// resolveVia turns the --via value into an instance ID: an instance ID is used as is,
+// "auto" looks up the VPC of the hosts, anything else is matched against instance names,
+// and an empty value shows the instance selector
+func (f *ForwardManager) resolveVia(ctx context.Context, via string, hosts []string, vpcId string) (string, error) {
+	if instanceIdPattern.MatchString(via) {
+		return via, nil
+	}
+
+	if via == ViaAuto {
+		return f.findInstanceForHosts(ctx, hosts, vpcId)
+	}
+
+	ec2Manager, err := f.ec2Manager(ctx)
+	if err != nil {
+		return "", err
+	}
+	instances, err := ec2Manager.ListAllInstances(ctx)
+	if err != nil {
+		return "", fmt.Errorf("error listing EC2 instances: %v", err)
+	}
+
+	var candidates []EC2Instance
+	for _, instance := range instances {
+		if instance.IsSelectable {
+			candidates = append(candidates, instance)
+		}
+	}
+	if len(candidates) == 0 {
+		return "", fmt.Errorf("no running EC2 instances with SSM agent found")
+	}
+
+	if via != "" {
+		var matches []EC2Instance
+		for _, instance := range candidates {
+			if instance.Name == via {
+				matches = append(matches, instance)
+			}
+		}
+		if len(matches) == 1 {
+			fmt.Printf("✓ Selected: %s (%s)\n", matches[0].Name, matches[0].InstanceId)
+			return matches[0].InstanceId, nil
+		}
+		if len(matches) == 0 {
+			fmt.Printf("Instance '%s' not found or not available. Available instances:\n\n", via)
+		} else {
+			candidates = matches
+		}
+	}
+
+	selected, err := ec2Manager.selectInstance("Select instance to forward through:", candidates)
+	if err != nil {
+		return "", err
+	}
+	return selected.InstanceId, nil
+}
+
+// ec2Manager returns an EC2Manager sharing the forward manager's clients
+func (f *ForwardManager) ec2Manager(ctx context.Context) (*EC2Manager, error) {
+	return NewEC2Manager(ctx, EC2ManagerOptions{
+		EC2Client: f.ec2Client,
+		SSMClient: f.ssmClient,
+		Region:    f.region,
+	})
+}
+
+// findInstanceForHosts returns the first running SSM-managed instance in the VPC that
+// contains every host. Hosts in different VPCs can't be reached through one instance.
+func (f *ForwardManager) findInstanceForHosts(ctx context.Context, hosts []string, vpcId string) (string, error) {
+	var hostsVpc, firstHost string
+	seen := make(map[string]bool)
+	for _, host := range hosts {
+		if seen[host] {
+			continue
+		}
+		seen[host] = true
+		hostVpc, err := f.findVpcForHost(ctx, host, vpcId)
+		if err != nil {
+			return "", err
+		}
+		if hostsVpc == "" {
+			hostsVpc, firstHost = hostVpc, host
+		} else if hostVpc != hostsVpc {
+			return "", fmt.Errorf("%s is in %s but %s is in %s; forward them separately or use --via <instance> instead of auto", firstHost, hostsVpc, host, hostVpc)
+		}
+	}
+
+	return f.findInstanceInVpc(ctx, hostsVpc)
+}
+
+// findVpcForHost resolves host to a private IPv4 address and returns the VPC whose CIDR
+// contains it. When the address is in several VPCs, vpcId must name one of them.
+func (f *ForwardManager) findVpcForHost(ctx context.Context, host, vpcId string) (string, error) {
+	ips, err := f.lookupIP(host)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve %s: %v", host, err)
+	}
+
+	var ip net.IP
+	for _, candidate := range ips {
+		if candidate.To4() != nil && candidate.IsPrivate() {
+			ip = candidate
+			break
+		}
+	}
+	if ip == nil {
+		return "", fmt.Errorf("%s does not resolve to a private IPv4 address from here; use --via <instance> instead of auto", host)
+	}
+	debug.Printf("%s resolved to %s\n", host, ip)
+
+	vpcIds, err := f.findVpcsForIP(ctx, ip)
+	if err != nil {
+		return "", err
+	}
+	switch {
+	case vpcId != "":
+		if !slices.Contains(vpcIds, vpcId) {
+			return "", fmt.Errorf("%s (%s) is not in %s", host, ip, vpcId)
+		}
+	case len(vpcIds) == 0:
+		return "", fmt.Errorf("no VPC in region %s contains %s; use --via <instance> instead of auto", f.region, ip)
+	case len(vpcIds) > 1:
+		// Overlapping CIDRs are common across environments, so don't guess
+		return "", fmt.Errorf("%s is in more than one VPC (%s); pick one with --vpc or use --via <instance> instead of auto", ip, strings.Join(vpcIds, ", "))
+	default:
+		vpcId = vpcIds[0]
+	}
+	fmt.Printf("%s (%s) is in %s\n", host, ip, vpcId)
+
+	return vpcId, nil
+}
+
+// findInstanceInVpc returns the first running SSM-managed instance in the VPC
+func (f *ForwardManager) findInstanceInVpc(ctx context.Context, vpcId string) (string, error) {
+	reservations, err := f.describeRunningInstances(ctx, vpcId)
+	if err != nil {
+		return "", err
+	}
+
+	ec2Manager, err := f.ec2Manager(ctx)
+	if err != nil {
+		return "", err
+	}
+	for _, reservation := range reservations {
+		for _, instance := range reservation.Instances {
+			if instance.InstanceId == nil || !ec2Manager.hasSSMAgent(ctx, *instance.InstanceId) {
+				continue
+			}
+			fmt.Printf("✓ Selected: %s (%s)\n", ec2Manager.getInstanceName(instance.Tags), *instance.InstanceId)
+			return *instance.InstanceId, nil
+		}
+	}
+
+	return "", fmt.Errorf("no running EC2 instances with SSM agent found in %s", vpcId)
+}
+
+// findVpcsForIP returns the VPCs with a CIDR block containing ip
+func (f *ForwardManager) findVpcsForIP(ctx context.Context, ip net.IP) ([]string, error) {
+	var vpcIds []string
+	var nextToken *string
+
+	for {
+		result, err := f.ec2Client.DescribeVpcs(ctx, &ec2.DescribeVpcsInput{
+			NextToken: nextToken,
+		})
+		if err != nil {
+			if IsAuthError(err) {
+				if shouldReauth, reAuthErr := PromptForReauth(ctx); shouldReauth && reAuthErr == nil {
+					if reloadErr := f.reloadClients(ctx); reloadErr != nil {
+						return nil, reloadErr
+					}
+					result, err = f.ec2Client.DescribeVpcs(ctx, &ec2.DescribeVpcsInput{
+						NextToken: nextToken,
+					})
+					if err != nil {
+						return nil, err
+					}
+				} else {
+					return nil, err
+				}
+			} else {
+				return nil, err
+			}
+		}
+
+		for _, vpc := range result.Vpcs {
+			for _, assoc := range vpc.CidrBlockAssociationSet {
+				if assoc.CidrBlock == nil {
+					continue
+				}
+				_, cidr, err := net.ParseCIDR(*assoc.CidrBlock)
+				if err == nil && cidr.Contains(ip) {
+					vpcIds = append(vpcIds, aws.ToString(vpc.VpcId))
+					break
+				}
+			}
+		}
+
+		if result.NextToken == nil {
+			break
+		}
+		nextToken = result.NextToken
+	}
+
+	return vpcIds, nil
+}
+
+func (f *ForwardManager) describeRunningInstances(ctx context.Context, vpcId string) ([]types.Reservation, error) {
+	var allReservations []types.Reservation
+	var nextToken *string
+
+	input := func() *ec2.DescribeInstancesInput {
+		return &ec2.DescribeInstancesInput{
+			Filters: []types.Filter{
+				{Name: aws.String("vpc-id"), Values: []string{vpcId}},
+				{Name: aws.String("instance-state-name"), Values: []string{"running"}},
+			},
+			NextToken: nextToken,
+		}
+	}
+
+	for {
+		result, err := f.ec2Client.DescribeInstances(ctx, input())
+		if err != nil {
+			if IsAuthError(err) {
+				if shouldReauth, reAuthErr := PromptForReauth(ctx); shouldReauth && reAuthErr == nil {
+					if reloadErr := f.reloadClients(ctx); reloadErr != nil {
+						return nil, reloadErr
+					}
+					result, err = f.ec2Client.DescribeInstances(ctx, input())
+					if err != nil {
+						return nil, err
+					}
+				} else {
+					return nil, err
+				}
+			} else {
+				return nil, err
+			}
+		}
+
+		allReservations = append(allReservations, result.Reservations...)
+
+		if result.NextToken == nil {
+			break
+		}
+		nextToken = result.NextToken
+	}
+
+	return allReservations, nil
+}
+
+func (f *ForwardManager) reloadClients(ctx context.Context) error {
+	cfg, err := awscconfig.LoadAWSConfigWithProfile(ctx)
+	if err != nil {
+		return err
+	}
+
+	f.ec2Client = ec2.NewFromConfig(cfg)
+	f.ssmClient = ssm.NewFromConfig(cfg)
+	f.region = cfg.Region
+
+	return nil
+}
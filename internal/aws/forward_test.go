@@ -0,0 +1,275 @@
+package aws
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	ssmtypes "github.com/aws/aws-sdk-go-v2/service/ssm/types"
+	"github.com/blontic/awsc/internal/aws/mocks"
+	"go.uber.org/mock/gomock"
+)
+
+func TestParseForwardSpec(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    ForwardSpec
+		wantErr bool
+	}{
+		{"internal.example:8443", ForwardSpec{LocalPort: 8443, RemoteHost: "internal.example", RemotePort: 8443}, false},
+		{"9000:internal.example:8443", ForwardSpec{LocalPort: 9000, RemoteHost: "internal.example", RemotePort: 8443}, false},
+		{"10.0.1.5:9092", ForwardSpec{LocalPort: 9092, RemoteHost: "10.0.1.5", RemotePort: 9092}, false},
+		{"19092:10.0.1.5:9092", ForwardSpec{LocalPort: 19092, RemoteHost: "10.0.1.5", RemotePort: 9092}, false},
+		{"8443:[fd00::1]:443", ForwardSpec{LocalPort: 8443, RemoteHost: "fd00::1", RemotePort: 443}, false},
+		{"internal.example", ForwardSpec{}, true},
+		{"internal.example:http", ForwardSpec{}, true},
+		{"70000:internal.example:443", ForwardSpec{}, true},
+		{":443", ForwardSpec{}, true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseForwardSpec(tt.input)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("ParseForwardSpec(%q) expected error, got %+v", tt.input, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseForwardSpec(%q) unexpected error: %v", tt.input, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseForwardSpec(%q) = %+v, want %+v", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestForwardManager_RunForward_DuplicateLocalPort(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	manager, _ := NewForwardManager(context.Background(), ForwardManagerOptions{
+		EC2Client: mocks.NewMockEC2Client(ctrl),
+		SSMClient: mocks.NewMockSSMClient(ctrl),
+		Region:    "us-east-1",
+	})
+
+	err := manager.RunForward(context.Background(), "i-123", "", []ForwardSpec{
+		{LocalPort: 8443, RemoteHost: "a.internal", RemotePort: 443},
+		{LocalPort: 8443, RemoteHost: "b.internal", RemotePort: 443},
+	})
+	if err == nil || !strings.Contains(err.Error(), "8443") {
+		t.Errorf("Expected duplicate local port error, got %v", err)
+	}
+}
+
+func TestForwardManager_ResolveVia_Auto(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEC2 := mocks.NewMockEC2Client(ctrl)
+	mockSSM := mocks.NewMockSSMClient(ctrl)
+
+	manager, _ := NewForwardManager(context.Background(), ForwardManagerOptions{
+		EC2Client: mockEC2,
+		SSMClient: mockSSM,
+		Region:    "us-east-1",
+		LookupIP: func(host string) ([]net.IP, error) {
+			return []net.IP{net.ParseIP("10.20.3.4")}, nil
+		},
+	})
+
+	mockEC2.EXPECT().
+		DescribeVpcs(gomock.Any(), gomock.Any()).
+		Return(&ec2.DescribeVpcsOutput{
+			Vpcs: []types.Vpc{
+				{
+					VpcId: aws.String("vpc-other"),
+					CidrBlockAssociationSet: []types.VpcCidrBlockAssociation{
+						{CidrBlock: aws.String("10.10.0.0/16")},
+					},
+				},
+				{
+					VpcId: aws.String("vpc-target"),
+					CidrBlockAssociationSet: []types.VpcCidrBlockAssociation{
+						{CidrBlock: aws.String("10.0.0.0/16")},
+						{CidrBlock: aws.String("10.20.0.0/16")},
+					},
+				},
+			},
+		}, nil)
+
+	mockEC2.EXPECT().
+		DescribeInstances(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, input *ec2.DescribeInstancesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeInstancesOutput, error) {
+			if len(input.Filters) == 0 || input.Filters[0].Values[0] != "vpc-target" {
+				t.Errorf("Expected DescribeInstances to filter on vpc-target, got %+v", input.Filters)
+			}
+			return &ec2.DescribeInstancesOutput{
+				Reservations: []types.Reservation{
+					{
+						Instances: []types.Instance{
+							{InstanceId: aws.String("i-unmanaged")},
+							{InstanceId: aws.String("i-managed"), Tags: []types.Tag{{Key: aws.String("Name"), Value: aws.String("bastion")}}},
+						},
+					},
+				},
+			}, nil
+		})
+
+	mockSSM.EXPECT().
+		DescribeInstanceInformation(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, input *ssm.DescribeInstanceInformationInput, optFns ...func(*ssm.Options)) (*ssm.DescribeInstanceInformationOutput, error) {
+			if input.Filters[0].Values[0] == "i-managed" {
+				return &ssm.DescribeInstanceInformationOutput{
+					InstanceInformationList: []ssmtypes.InstanceInformation{{InstanceId: aws.String("i-managed")}},
+				}, nil
+			}
+			return &ssm.DescribeInstanceInformationOutput{}, nil
+		}).
+		Times(2)
+
+	instanceId, err := manager.resolveVia(context.Background(), ViaAuto, []string{"broker.internal"}, "")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if instanceId != "i-managed" {
+		t.Errorf("Expected i-managed, got %s", instanceId)
+	}
+}
+
+func TestForwardManager_ResolveVia_AutoPublicIP(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	manager, _ := NewForwardManager(context.Background(), ForwardManagerOptions{
+		EC2Client: mocks.NewMockEC2Client(ctrl),
+		SSMClient: mocks.NewMockSSMClient(ctrl),
+		Region:    "us-east-1",
+		LookupIP: func(host string) ([]net.IP, error) {
+			return []net.IP{net.ParseIP("52.1.2.3")}, nil
+		},
+	})
+
+	_, err := manager.resolveVia(context.Background(), ViaAuto, []string{"public.example"}, "")
+	if err == nil || !strings.Contains(err.Error(), "--via") {
+		t.Errorf("Expected error suggesting --via, got %v", err)
+	}
+}
+
+func TestForwardManager_ResolveVia_InstanceId(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	manager, _ := NewForwardManager(context.Background(), ForwardManagerOptions{
+		EC2Client: mocks.NewMockEC2Client(ctrl),
+		SSMClient: mocks.NewMockSSMClient(ctrl),
+		Region:    "us-east-1",
+	})
+
+	instanceId, err := manager.resolveVia(context.Background(), "i-0abc1234def567890", []string{"host"}, "")
+	if err != nil || instanceId != "i-0abc1234def567890" {
+		t.Errorf("Expected i-0abc1234def567890, got %s (%v)", instanceId, err)
+	}
+}
+
+func TestInstanceIdPattern(t *testing.T) {
+	for _, id := range []string{"i-0abc1234", "i-1234567890abcdef0"} {
+		if !instanceIdPattern.MatchString(id) {
+			t.Errorf("Expected %q to be an instance ID", id)
+		}
+	}
+	for _, name := range []string{"i-proxy", "i-0abc", "i-1234567890abcdef01", "bastion"} {
+		if instanceIdPattern.MatchString(name) {
+			t.Errorf("Expected %q not to be an instance ID", name)
+		}
+	}
+}
+
+func TestForwardManager_ResolveVia_AutoOverlappingVpcs(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEC2 := mocks.NewMockEC2Client(ctrl)
+
+	manager, _ := NewForwardManager(context.Background(), ForwardManagerOptions{
+		EC2Client: mockEC2,
+		SSMClient: mocks.NewMockSSMClient(ctrl),
+		Region:    "us-east-1",
+		LookupIP: func(host string) ([]net.IP, error) {
+			return []net.IP{net.ParseIP("10.0.3.4")}, nil
+		},
+	})
+
+	mockEC2.EXPECT().
+		DescribeVpcs(gomock.Any(), gomock.Any()).
+		Return(&ec2.DescribeVpcsOutput{
+			Vpcs: []types.Vpc{
+				{
+					VpcId:                   aws.String("vpc-staging"),
+					CidrBlockAssociationSet: []types.VpcCidrBlockAssociation{{CidrBlock: aws.String("10.0.0.0/16")}},
+				},
+				{
+					VpcId:                   aws.String("vpc-prod"),
+					CidrBlockAssociationSet: []types.VpcCidrBlockAssociation{{CidrBlock: aws.String("10.0.0.0/16")}},
+				},
+			},
+		}, nil).
+		Times(2)
+
+	_, err := manager.resolveVia(context.Background(), ViaAuto, []string{"broker.internal"}, "")
+	if err == nil || !strings.Contains(err.Error(), "more than one VPC") || !strings.Contains(err.Error(), "--vpc") {
+		t.Errorf("Expected an ambiguous VPC error suggesting --vpc, got %v", err)
+	}
+
+	_, err = manager.resolveVia(context.Background(), ViaAuto, []string{"broker.internal"}, "vpc-other")
+	if err == nil || !strings.Contains(err.Error(), "is not in vpc-other") {
+		t.Errorf("Expected an error for a VPC not containing the host, got %v", err)
+	}
+}
+
+func TestForwardManager_ResolveVia_AutoHostsInDifferentVpcs(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockEC2 := mocks.NewMockEC2Client(ctrl)
+
+	manager, _ := NewForwardManager(context.Background(), ForwardManagerOptions{
+		EC2Client: mockEC2,
+		SSMClient: mocks.NewMockSSMClient(ctrl),
+		Region:    "us-east-1",
+		LookupIP: func(host string) ([]net.IP, error) {
+			if host == "cache.internal" {
+				return []net.IP{net.ParseIP("10.1.3.4")}, nil
+			}
+			return []net.IP{net.ParseIP("10.0.3.4")}, nil
+		},
+	})
+
+	mockEC2.EXPECT().
+		DescribeVpcs(gomock.Any(), gomock.Any()).
+		Return(&ec2.DescribeVpcsOutput{
+			Vpcs: []types.Vpc{
+				{
+					VpcId:                   aws.String("vpc-app"),
+					CidrBlockAssociationSet: []types.VpcCidrBlockAssociation{{CidrBlock: aws.String("10.0.0.0/16")}},
+				},
+				{
+					VpcId:                   aws.String("vpc-data"),
+					CidrBlockAssociationSet: []types.VpcCidrBlockAssociation{{CidrBlock: aws.String("10.1.0.0/16")}},
+				},
+			},
+		}, nil).
+		Times(2)
+
+	_, err := manager.resolveVia(context.Background(), ViaAuto, []string{"broker.internal", "broker.internal", "cache.internal"}, "")
+	if err == nil || !strings.Contains(err.Error(), "cache.internal is in vpc-data") {
+		t.Errorf("Expected an error for hosts in different VPCs, got %v", err)
+	}
+}
@@ -1,9 +1,9 @@
 // Code generated by MockGen. DO NOT EDIT.
-// Source: github.com/blontic/awsc/internal/aws (interfaces: RDSClient,EC2Client,SSMClient,SecretsManagerClient,OpenSearchClient)
+// Source: github.com/blontic/awsc/internal/aws (interfaces: RDSClient,RDSDataClient,EC2Client,SSMClient,SecretsManagerClient,OpenSearchClient,ElastiCacheClient,RedshiftClient,RedshiftServerlessClient,OpenSearchServerlessClient,EKSClient,ECSClient,EC2InstanceConnectClient,AutoScalingClient)
 //
 // Generated by this command:
 //
-//	mockgen -destination=mocks/aws_mocks.go -package=mocks . RDSClient,EC2Client,SSMClient,SecretsManagerClient,OpenSearchClient
+//	mockgen -destination=mocks/aws_mocks.go -package=mocks . RDSClient,RDSDataClient,EC2Client,SSMClient,SecretsManagerClient,OpenSearchClient,ElastiCacheClient,RedshiftClient,RedshiftServerlessClient,OpenSearchServerlessClient,EKSClient,ECSClient,EC2InstanceConnectClient,AutoScalingClient
 //
 
 // Package mocks is a generated GoMock package.
@@ -13,9 +13,18 @@ import (
 	context "context"
 	reflect "reflect"
 
+	autoscaling "github.com/aws/aws-sdk-go-v2/service/autoscaling"
 	ec2 "github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2instanceconnect "github.com/aws/aws-sdk-go-v2/service/ec2instanceconnect"
+	ecs "github.com/aws/aws-sdk-go-v2/service/ecs"
+	eks "github.com/aws/aws-sdk-go-v2/service/eks"
+	elasticache "github.com/aws/aws-sdk-go-v2/service/elasticache"
 	opensearch "github.com/aws/aws-sdk-go-v2/service/opensearch"
+	opensearchserverless "github.com/aws/aws-sdk-go-v2/service/opensearchserverless"
 	rds "github.com/aws/aws-sdk-go-v2/service/rds"
+	rdsdata "github.com/aws/aws-sdk-go-v2/service/rdsdata"
+	redshift "github.com/aws/aws-sdk-go-v2/service/redshift"
+	redshiftserverless "github.com/aws/aws-sdk-go-v2/service/redshiftserverless"
 	secretsmanager "github.com/aws/aws-sdk-go-v2/service/secretsmanager"
 	ssm "github.com/aws/aws-sdk-go-v2/service/ssm"
 	gomock "go.uber.org/mock/gomock"
@@ -45,6 +54,26 @@ func (m *MockRDSClient) EXPECT() *MockRDSClientMockRecorder {
 	return m.recorder
 }
 
+// DescribeDBClusterEndpoints mocks base method.
+func (m *MockRDSClient) DescribeDBClusterEndpoints(ctx context.Context, params *rds.DescribeDBClusterEndpointsInput, optFns ...func(*rds.Options)) (*rds.DescribeDBClusterEndpointsOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DescribeDBClusterEndpoints", varargs...)
+	ret0, _ := ret[0].(*rds.DescribeDBClusterEndpointsOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeDBClusterEndpoints indicates an expected call of DescribeDBClusterEndpoints.
+func (mr *MockRDSClientMockRecorder) DescribeDBClusterEndpoints(ctx, params any, optFns ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeDBClusterEndpoints", reflect.TypeOf((*MockRDSClient)(nil).DescribeDBClusterEndpoints), varargs...)
+}
+
 // DescribeDBClusters mocks base method.
 func (m *MockRDSClient) DescribeDBClusters(ctx context.Context, params *rds.DescribeDBClustersInput, optFns ...func(*rds.Options)) (*rds.DescribeDBClustersOutput, error) {
 	m.ctrl.T.Helper()
@@ -85,6 +114,90 @@ func (mr *MockRDSClientMockRecorder) DescribeDBInstances(ctx, params any, optFns
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeDBInstances", reflect.TypeOf((*MockRDSClient)(nil).DescribeDBInstances), varargs...)
 }
 
+// DescribeDBProxies mocks base method.
+func (m *MockRDSClient) DescribeDBProxies(ctx context.Context, params *rds.DescribeDBProxiesInput, optFns ...func(*rds.Options)) (*rds.DescribeDBProxiesOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DescribeDBProxies", varargs...)
+	ret0, _ := ret[0].(*rds.DescribeDBProxiesOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeDBProxies indicates an expected call of DescribeDBProxies.
+func (mr *MockRDSClientMockRecorder) DescribeDBProxies(ctx, params any, optFns ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeDBProxies", reflect.TypeOf((*MockRDSClient)(nil).DescribeDBProxies), varargs...)
+}
+
+// DescribeDBProxyEndpoints mocks base method.
+func (m *MockRDSClient) DescribeDBProxyEndpoints(ctx context.Context, params *rds.DescribeDBProxyEndpointsInput, optFns ...func(*rds.Options)) (*rds.DescribeDBProxyEndpointsOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DescribeDBProxyEndpoints", varargs...)
+	ret0, _ := ret[0].(*rds.DescribeDBProxyEndpointsOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeDBProxyEndpoints indicates an expected call of DescribeDBProxyEndpoints.
+func (mr *MockRDSClientMockRecorder) DescribeDBProxyEndpoints(ctx, params any, optFns ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeDBProxyEndpoints", reflect.TypeOf((*MockRDSClient)(nil).DescribeDBProxyEndpoints), varargs...)
+}
+
+// MockRDSDataClient is a mock of RDSDataClient interface.
+type MockRDSDataClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockRDSDataClientMockRecorder
+	isgomock struct{}
+}
+
+// MockRDSDataClientMockRecorder is the mock recorder for MockRDSDataClient.
+type MockRDSDataClientMockRecorder struct {
+	mock *MockRDSDataClient
+}
+
+// NewMockRDSDataClient creates a new mock instance.
+func NewMockRDSDataClient(ctrl *gomock.Controller) *MockRDSDataClient {
+	mock := &MockRDSDataClient{ctrl: ctrl}
+	mock.recorder = &MockRDSDataClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRDSDataClient) EXPECT() *MockRDSDataClientMockRecorder {
+	return m.recorder
+}
+
+// ExecuteStatement mocks base method.
+func (m *MockRDSDataClient) ExecuteStatement(ctx context.Context, params *rdsdata.ExecuteStatementInput, optFns ...func(*rdsdata.Options)) (*rdsdata.ExecuteStatementOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ExecuteStatement", varargs...)
+	ret0, _ := ret[0].(*rdsdata.ExecuteStatementOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExecuteStatement indicates an expected call of ExecuteStatement.
+func (mr *MockRDSDataClientMockRecorder) ExecuteStatement(ctx, params any, optFns ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExecuteStatement", reflect.TypeOf((*MockRDSDataClient)(nil).ExecuteStatement), varargs...)
+}
+
 // MockEC2Client is a mock of EC2Client interface.
 type MockEC2Client struct {
 	ctrl     *gomock.Controller
@@ -109,6 +222,46 @@ func (m *MockEC2Client) EXPECT() *MockEC2ClientMockRecorder {
 	return m.recorder
 }
 
+// DescribeImages mocks base method.
+func (m *MockEC2Client) DescribeImages(ctx context.Context, params *ec2.DescribeImagesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeImagesOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DescribeImages", varargs...)
+	ret0, _ := ret[0].(*ec2.DescribeImagesOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeImages indicates an expected call of DescribeImages.
+func (mr *MockEC2ClientMockRecorder) DescribeImages(ctx, params any, optFns ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeImages", reflect.TypeOf((*MockEC2Client)(nil).DescribeImages), varargs...)
+}
+
+// DescribeInstanceConnectEndpoints mocks base method.
+func (m *MockEC2Client) DescribeInstanceConnectEndpoints(ctx context.Context, params *ec2.DescribeInstanceConnectEndpointsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeInstanceConnectEndpointsOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DescribeInstanceConnectEndpoints", varargs...)
+	ret0, _ := ret[0].(*ec2.DescribeInstanceConnectEndpointsOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeInstanceConnectEndpoints indicates an expected call of DescribeInstanceConnectEndpoints.
+func (mr *MockEC2ClientMockRecorder) DescribeInstanceConnectEndpoints(ctx, params any, optFns ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeInstanceConnectEndpoints", reflect.TypeOf((*MockEC2Client)(nil).DescribeInstanceConnectEndpoints), varargs...)
+}
+
 // DescribeInstances mocks base method.
 func (m *MockEC2Client) DescribeInstances(ctx context.Context, params *ec2.DescribeInstancesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeInstancesOutput, error) {
 	m.ctrl.T.Helper()
@@ -149,6 +302,106 @@ func (mr *MockEC2ClientMockRecorder) DescribeSecurityGroups(ctx, params any, opt
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeSecurityGroups", reflect.TypeOf((*MockEC2Client)(nil).DescribeSecurityGroups), varargs...)
 }
 
+// DescribeVpcs mocks base method.
+func (m *MockEC2Client) DescribeVpcs(ctx context.Context, params *ec2.DescribeVpcsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeVpcsOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DescribeVpcs", varargs...)
+	ret0, _ := ret[0].(*ec2.DescribeVpcsOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeVpcs indicates an expected call of DescribeVpcs.
+func (mr *MockEC2ClientMockRecorder) DescribeVpcs(ctx, params any, optFns ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeVpcs", reflect.TypeOf((*MockEC2Client)(nil).DescribeVpcs), varargs...)
+}
+
+// GetPasswordData mocks base method.
+func (m *MockEC2Client) GetPasswordData(ctx context.Context, params *ec2.GetPasswordDataInput, optFns ...func(*ec2.Options)) (*ec2.GetPasswordDataOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetPasswordData", varargs...)
+	ret0, _ := ret[0].(*ec2.GetPasswordDataOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPasswordData indicates an expected call of GetPasswordData.
+func (mr *MockEC2ClientMockRecorder) GetPasswordData(ctx, params any, optFns ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPasswordData", reflect.TypeOf((*MockEC2Client)(nil).GetPasswordData), varargs...)
+}
+
+// RebootInstances mocks base method.
+func (m *MockEC2Client) RebootInstances(ctx context.Context, params *ec2.RebootInstancesInput, optFns ...func(*ec2.Options)) (*ec2.RebootInstancesOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "RebootInstances", varargs...)
+	ret0, _ := ret[0].(*ec2.RebootInstancesOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RebootInstances indicates an expected call of RebootInstances.
+func (mr *MockEC2ClientMockRecorder) RebootInstances(ctx, params any, optFns ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RebootInstances", reflect.TypeOf((*MockEC2Client)(nil).RebootInstances), varargs...)
+}
+
+// StartInstances mocks base method.
+func (m *MockEC2Client) StartInstances(ctx context.Context, params *ec2.StartInstancesInput, optFns ...func(*ec2.Options)) (*ec2.StartInstancesOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "StartInstances", varargs...)
+	ret0, _ := ret[0].(*ec2.StartInstancesOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StartInstances indicates an expected call of StartInstances.
+func (mr *MockEC2ClientMockRecorder) StartInstances(ctx, params any, optFns ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartInstances", reflect.TypeOf((*MockEC2Client)(nil).StartInstances), varargs...)
+}
+
+// StopInstances mocks base method.
+func (m *MockEC2Client) StopInstances(ctx context.Context, params *ec2.StopInstancesInput, optFns ...func(*ec2.Options)) (*ec2.StopInstancesOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "StopInstances", varargs...)
+	ret0, _ := ret[0].(*ec2.StopInstancesOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StopInstances indicates an expected call of StopInstances.
+func (mr *MockEC2ClientMockRecorder) StopInstances(ctx, params any, optFns ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StopInstances", reflect.TypeOf((*MockEC2Client)(nil).StopInstances), varargs...)
+}
+
 // MockSSMClient is a mock of SSMClient interface.
 type MockSSMClient struct {
 	ctrl     *gomock.Controller
@@ -193,6 +446,46 @@ func (mr *MockSSMClientMockRecorder) DescribeInstanceInformation(ctx, params any
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeInstanceInformation", reflect.TypeOf((*MockSSMClient)(nil).DescribeInstanceInformation), varargs...)
 }
 
+// GetCommandInvocation mocks base method.
+func (m *MockSSMClient) GetCommandInvocation(ctx context.Context, params *ssm.GetCommandInvocationInput, optFns ...func(*ssm.Options)) (*ssm.GetCommandInvocationOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetCommandInvocation", varargs...)
+	ret0, _ := ret[0].(*ssm.GetCommandInvocationOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCommandInvocation indicates an expected call of GetCommandInvocation.
+func (mr *MockSSMClientMockRecorder) GetCommandInvocation(ctx, params any, optFns ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCommandInvocation", reflect.TypeOf((*MockSSMClient)(nil).GetCommandInvocation), varargs...)
+}
+
+// SendCommand mocks base method.
+func (m *MockSSMClient) SendCommand(ctx context.Context, params *ssm.SendCommandInput, optFns ...func(*ssm.Options)) (*ssm.SendCommandOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "SendCommand", varargs...)
+	ret0, _ := ret[0].(*ssm.SendCommandOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SendCommand indicates an expected call of SendCommand.
+func (mr *MockSSMClientMockRecorder) SendCommand(ctx, params any, optFns ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendCommand", reflect.TypeOf((*MockSSMClient)(nil).SendCommand), varargs...)
+}
+
 // MockSecretsManagerClient is a mock of SecretsManagerClient interface.
 type MockSecretsManagerClient struct {
 	ctrl     *gomock.Controller
@@ -320,3 +613,575 @@ func (mr *MockOpenSearchClientMockRecorder) ListDomainNames(ctx, params any, opt
 	varargs := append([]any{ctx, params}, optFns...)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDomainNames", reflect.TypeOf((*MockOpenSearchClient)(nil).ListDomainNames), varargs...)
 }
+
+// MockElastiCacheClient is a mock of ElastiCacheClient interface.
+type MockElastiCacheClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockElastiCacheClientMockRecorder
+	isgomock struct{}
+}
+
+// MockElastiCacheClientMockRecorder is the mock recorder for MockElastiCacheClient.
+type MockElastiCacheClientMockRecorder struct {
+	mock *MockElastiCacheClient
+}
+
+// NewMockElastiCacheClient creates a new mock instance.
+func NewMockElastiCacheClient(ctrl *gomock.Controller) *MockElastiCacheClient {
+	mock := &MockElastiCacheClient{ctrl: ctrl}
+	mock.recorder = &MockElastiCacheClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockElastiCacheClient) EXPECT() *MockElastiCacheClientMockRecorder {
+	return m.recorder
+}
+
+// DescribeCacheClusters mocks base method.
+func (m *MockElastiCacheClient) DescribeCacheClusters(ctx context.Context, params *elasticache.DescribeCacheClustersInput, optFns ...func(*elasticache.Options)) (*elasticache.DescribeCacheClustersOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DescribeCacheClusters", varargs...)
+	ret0, _ := ret[0].(*elasticache.DescribeCacheClustersOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeCacheClusters indicates an expected call of DescribeCacheClusters.
+func (mr *MockElastiCacheClientMockRecorder) DescribeCacheClusters(ctx, params any, optFns ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeCacheClusters", reflect.TypeOf((*MockElastiCacheClient)(nil).DescribeCacheClusters), varargs...)
+}
+
+// DescribeReplicationGroups mocks base method.
+func (m *MockElastiCacheClient) DescribeReplicationGroups(ctx context.Context, params *elasticache.DescribeReplicationGroupsInput, optFns ...func(*elasticache.Options)) (*elasticache.DescribeReplicationGroupsOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DescribeReplicationGroups", varargs...)
+	ret0, _ := ret[0].(*elasticache.DescribeReplicationGroupsOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeReplicationGroups indicates an expected call of DescribeReplicationGroups.
+func (mr *MockElastiCacheClientMockRecorder) DescribeReplicationGroups(ctx, params any, optFns ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeReplicationGroups", reflect.TypeOf((*MockElastiCacheClient)(nil).DescribeReplicationGroups), varargs...)
+}
+
+// MockRedshiftClient is a mock of RedshiftClient interface.
+type MockRedshiftClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockRedshiftClientMockRecorder
+	isgomock struct{}
+}
+
+// MockRedshiftClientMockRecorder is the mock recorder for MockRedshiftClient.
+type MockRedshiftClientMockRecorder struct {
+	mock *MockRedshiftClient
+}
+
+// NewMockRedshiftClient creates a new mock instance.
+func NewMockRedshiftClient(ctrl *gomock.Controller) *MockRedshiftClient {
+	mock := &MockRedshiftClient{ctrl: ctrl}
+	mock.recorder = &MockRedshiftClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRedshiftClient) EXPECT() *MockRedshiftClientMockRecorder {
+	return m.recorder
+}
+
+// DescribeClusters mocks base method.
+func (m *MockRedshiftClient) DescribeClusters(ctx context.Context, params *redshift.DescribeClustersInput, optFns ...func(*redshift.Options)) (*redshift.DescribeClustersOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DescribeClusters", varargs...)
+	ret0, _ := ret[0].(*redshift.DescribeClustersOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeClusters indicates an expected call of DescribeClusters.
+func (mr *MockRedshiftClientMockRecorder) DescribeClusters(ctx, params any, optFns ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeClusters", reflect.TypeOf((*MockRedshiftClient)(nil).DescribeClusters), varargs...)
+}
+
+// MockRedshiftServerlessClient is a mock of RedshiftServerlessClient interface.
+type MockRedshiftServerlessClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockRedshiftServerlessClientMockRecorder
+	isgomock struct{}
+}
+
+// MockRedshiftServerlessClientMockRecorder is the mock recorder for MockRedshiftServerlessClient.
+type MockRedshiftServerlessClientMockRecorder struct {
+	mock *MockRedshiftServerlessClient
+}
+
+// NewMockRedshiftServerlessClient creates a new mock instance.
+func NewMockRedshiftServerlessClient(ctrl *gomock.Controller) *MockRedshiftServerlessClient {
+	mock := &MockRedshiftServerlessClient{ctrl: ctrl}
+	mock.recorder = &MockRedshiftServerlessClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRedshiftServerlessClient) EXPECT() *MockRedshiftServerlessClientMockRecorder {
+	return m.recorder
+}
+
+// ListWorkgroups mocks base method.
+func (m *MockRedshiftServerlessClient) ListWorkgroups(ctx context.Context, params *redshiftserverless.ListWorkgroupsInput, optFns ...func(*redshiftserverless.Options)) (*redshiftserverless.ListWorkgroupsOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ListWorkgroups", varargs...)
+	ret0, _ := ret[0].(*redshiftserverless.ListWorkgroupsOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListWorkgroups indicates an expected call of ListWorkgroups.
+func (mr *MockRedshiftServerlessClientMockRecorder) ListWorkgroups(ctx, params any, optFns ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWorkgroups", reflect.TypeOf((*MockRedshiftServerlessClient)(nil).ListWorkgroups), varargs...)
+}
+
+// MockOpenSearchServerlessClient is a mock of OpenSearchServerlessClient interface.
+type MockOpenSearchServerlessClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockOpenSearchServerlessClientMockRecorder
+	isgomock struct{}
+}
+
+// MockOpenSearchServerlessClientMockRecorder is the mock recorder for MockOpenSearchServerlessClient.
+type MockOpenSearchServerlessClientMockRecorder struct {
+	mock *MockOpenSearchServerlessClient
+}
+
+// NewMockOpenSearchServerlessClient creates a new mock instance.
+func NewMockOpenSearchServerlessClient(ctrl *gomock.Controller) *MockOpenSearchServerlessClient {
+	mock := &MockOpenSearchServerlessClient{ctrl: ctrl}
+	mock.recorder = &MockOpenSearchServerlessClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOpenSearchServerlessClient) EXPECT() *MockOpenSearchServerlessClientMockRecorder {
+	return m.recorder
+}
+
+// BatchGetCollection mocks base method.
+func (m *MockOpenSearchServerlessClient) BatchGetCollection(ctx context.Context, params *opensearchserverless.BatchGetCollectionInput, optFns ...func(*opensearchserverless.Options)) (*opensearchserverless.BatchGetCollectionOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "BatchGetCollection", varargs...)
+	ret0, _ := ret[0].(*opensearchserverless.BatchGetCollectionOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BatchGetCollection indicates an expected call of BatchGetCollection.
+func (mr *MockOpenSearchServerlessClientMockRecorder) BatchGetCollection(ctx, params any, optFns ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BatchGetCollection", reflect.TypeOf((*MockOpenSearchServerlessClient)(nil).BatchGetCollection), varargs...)
+}
+
+// BatchGetVpcEndpoint mocks base method.
+func (m *MockOpenSearchServerlessClient) BatchGetVpcEndpoint(ctx context.Context, params *opensearchserverless.BatchGetVpcEndpointInput, optFns ...func(*opensearchserverless.Options)) (*opensearchserverless.BatchGetVpcEndpointOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "BatchGetVpcEndpoint", varargs...)
+	ret0, _ := ret[0].(*opensearchserverless.BatchGetVpcEndpointOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BatchGetVpcEndpoint indicates an expected call of BatchGetVpcEndpoint.
+func (mr *MockOpenSearchServerlessClientMockRecorder) BatchGetVpcEndpoint(ctx, params any, optFns ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BatchGetVpcEndpoint", reflect.TypeOf((*MockOpenSearchServerlessClient)(nil).BatchGetVpcEndpoint), varargs...)
+}
+
+// GetSecurityPolicy mocks base method.
+func (m *MockOpenSearchServerlessClient) GetSecurityPolicy(ctx context.Context, params *opensearchserverless.GetSecurityPolicyInput, optFns ...func(*opensearchserverless.Options)) (*opensearchserverless.GetSecurityPolicyOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetSecurityPolicy", varargs...)
+	ret0, _ := ret[0].(*opensearchserverless.GetSecurityPolicyOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSecurityPolicy indicates an expected call of GetSecurityPolicy.
+func (mr *MockOpenSearchServerlessClientMockRecorder) GetSecurityPolicy(ctx, params any, optFns ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSecurityPolicy", reflect.TypeOf((*MockOpenSearchServerlessClient)(nil).GetSecurityPolicy), varargs...)
+}
+
+// ListCollections mocks base method.
+func (m *MockOpenSearchServerlessClient) ListCollections(ctx context.Context, params *opensearchserverless.ListCollectionsInput, optFns ...func(*opensearchserverless.Options)) (*opensearchserverless.ListCollectionsOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ListCollections", varargs...)
+	ret0, _ := ret[0].(*opensearchserverless.ListCollectionsOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListCollections indicates an expected call of ListCollections.
+func (mr *MockOpenSearchServerlessClientMockRecorder) ListCollections(ctx, params any, optFns ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCollections", reflect.TypeOf((*MockOpenSearchServerlessClient)(nil).ListCollections), varargs...)
+}
+
+// ListSecurityPolicies mocks base method.
+func (m *MockOpenSearchServerlessClient) ListSecurityPolicies(ctx context.Context, params *opensearchserverless.ListSecurityPoliciesInput, optFns ...func(*opensearchserverless.Options)) (*opensearchserverless.ListSecurityPoliciesOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ListSecurityPolicies", varargs...)
+	ret0, _ := ret[0].(*opensearchserverless.ListSecurityPoliciesOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSecurityPolicies indicates an expected call of ListSecurityPolicies.
+func (mr *MockOpenSearchServerlessClientMockRecorder) ListSecurityPolicies(ctx, params any, optFns ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSecurityPolicies", reflect.TypeOf((*MockOpenSearchServerlessClient)(nil).ListSecurityPolicies), varargs...)
+}
+
+// MockEKSClient is a mock of EKSClient interface.
+type MockEKSClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockEKSClientMockRecorder
+	isgomock struct{}
+}
+
+// MockEKSClientMockRecorder is the mock recorder for MockEKSClient.
+type MockEKSClientMockRecorder struct {
+	mock *MockEKSClient
+}
+
+// NewMockEKSClient creates a new mock instance.
+func NewMockEKSClient(ctrl *gomock.Controller) *MockEKSClient {
+	mock := &MockEKSClient{ctrl: ctrl}
+	mock.recorder = &MockEKSClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockEKSClient) EXPECT() *MockEKSClientMockRecorder {
+	return m.recorder
+}
+
+// DescribeCluster mocks base method.
+func (m *MockEKSClient) DescribeCluster(ctx context.Context, params *eks.DescribeClusterInput, optFns ...func(*eks.Options)) (*eks.DescribeClusterOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DescribeCluster", varargs...)
+	ret0, _ := ret[0].(*eks.DescribeClusterOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeCluster indicates an expected call of DescribeCluster.
+func (mr *MockEKSClientMockRecorder) DescribeCluster(ctx, params any, optFns ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeCluster", reflect.TypeOf((*MockEKSClient)(nil).DescribeCluster), varargs...)
+}
+
+// ListClusters mocks base method.
+func (m *MockEKSClient) ListClusters(ctx context.Context, params *eks.ListClustersInput, optFns ...func(*eks.Options)) (*eks.ListClustersOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ListClusters", varargs...)
+	ret0, _ := ret[0].(*eks.ListClustersOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListClusters indicates an expected call of ListClusters.
+func (mr *MockEKSClientMockRecorder) ListClusters(ctx, params any, optFns ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListClusters", reflect.TypeOf((*MockEKSClient)(nil).ListClusters), varargs...)
+}
+
+// MockECSClient is a mock of ECSClient interface.
+type MockECSClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockECSClientMockRecorder
+	isgomock struct{}
+}
+
+// MockECSClientMockRecorder is the mock recorder for MockECSClient.
+type MockECSClientMockRecorder struct {
+	mock *MockECSClient
+}
+
+// NewMockECSClient creates a new mock instance.
+func NewMockECSClient(ctrl *gomock.Controller) *MockECSClient {
+	mock := &MockECSClient{ctrl: ctrl}
+	mock.recorder = &MockECSClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockECSClient) EXPECT() *MockECSClientMockRecorder {
+	return m.recorder
+}
+
+// DescribeServices mocks base method.
+func (m *MockECSClient) DescribeServices(ctx context.Context, params *ecs.DescribeServicesInput, optFns ...func(*ecs.Options)) (*ecs.DescribeServicesOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DescribeServices", varargs...)
+	ret0, _ := ret[0].(*ecs.DescribeServicesOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeServices indicates an expected call of DescribeServices.
+func (mr *MockECSClientMockRecorder) DescribeServices(ctx, params any, optFns ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeServices", reflect.TypeOf((*MockECSClient)(nil).DescribeServices), varargs...)
+}
+
+// DescribeTasks mocks base method.
+func (m *MockECSClient) DescribeTasks(ctx context.Context, params *ecs.DescribeTasksInput, optFns ...func(*ecs.Options)) (*ecs.DescribeTasksOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DescribeTasks", varargs...)
+	ret0, _ := ret[0].(*ecs.DescribeTasksOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeTasks indicates an expected call of DescribeTasks.
+func (mr *MockECSClientMockRecorder) DescribeTasks(ctx, params any, optFns ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeTasks", reflect.TypeOf((*MockECSClient)(nil).DescribeTasks), varargs...)
+}
+
+// ExecuteCommand mocks base method.
+func (m *MockECSClient) ExecuteCommand(ctx context.Context, params *ecs.ExecuteCommandInput, optFns ...func(*ecs.Options)) (*ecs.ExecuteCommandOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ExecuteCommand", varargs...)
+	ret0, _ := ret[0].(*ecs.ExecuteCommandOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExecuteCommand indicates an expected call of ExecuteCommand.
+func (mr *MockECSClientMockRecorder) ExecuteCommand(ctx, params any, optFns ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExecuteCommand", reflect.TypeOf((*MockECSClient)(nil).ExecuteCommand), varargs...)
+}
+
+// ListClusters mocks base method.
+func (m *MockECSClient) ListClusters(ctx context.Context, params *ecs.ListClustersInput, optFns ...func(*ecs.Options)) (*ecs.ListClustersOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ListClusters", varargs...)
+	ret0, _ := ret[0].(*ecs.ListClustersOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListClusters indicates an expected call of ListClusters.
+func (mr *MockECSClientMockRecorder) ListClusters(ctx, params any, optFns ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListClusters", reflect.TypeOf((*MockECSClient)(nil).ListClusters), varargs...)
+}
+
+// ListServices mocks base method.
+func (m *MockECSClient) ListServices(ctx context.Context, params *ecs.ListServicesInput, optFns ...func(*ecs.Options)) (*ecs.ListServicesOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ListServices", varargs...)
+	ret0, _ := ret[0].(*ecs.ListServicesOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListServices indicates an expected call of ListServices.
+func (mr *MockECSClientMockRecorder) ListServices(ctx, params any, optFns ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListServices", reflect.TypeOf((*MockECSClient)(nil).ListServices), varargs...)
+}
+
+// ListTasks mocks base method.
+func (m *MockECSClient) ListTasks(ctx context.Context, params *ecs.ListTasksInput, optFns ...func(*ecs.Options)) (*ecs.ListTasksOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ListTasks", varargs...)
+	ret0, _ := ret[0].(*ecs.ListTasksOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTasks indicates an expected call of ListTasks.
+func (mr *MockECSClientMockRecorder) ListTasks(ctx, params any, optFns ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTasks", reflect.TypeOf((*MockECSClient)(nil).ListTasks), varargs...)
+}
+
+// MockEC2InstanceConnectClient is a mock of EC2InstanceConnectClient interface.
+type MockEC2InstanceConnectClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockEC2InstanceConnectClientMockRecorder
+	isgomock struct{}
+}
+
+// MockEC2InstanceConnectClientMockRecorder is the mock recorder for MockEC2InstanceConnectClient.
+type MockEC2InstanceConnectClientMockRecorder struct {
+	mock *MockEC2InstanceConnectClient
+}
+
+// NewMockEC2InstanceConnectClient creates a new mock instance.
+func NewMockEC2InstanceConnectClient(ctrl *gomock.Controller) *MockEC2InstanceConnectClient {
+	mock := &MockEC2InstanceConnectClient{ctrl: ctrl}
+	mock.recorder = &MockEC2InstanceConnectClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockEC2InstanceConnectClient) EXPECT() *MockEC2InstanceConnectClientMockRecorder {
+	return m.recorder
+}
+
+// SendSSHPublicKey mocks base method.
+func (m *MockEC2InstanceConnectClient) SendSSHPublicKey(ctx context.Context, params *ec2instanceconnect.SendSSHPublicKeyInput, optFns ...func(*ec2instanceconnect.Options)) (*ec2instanceconnect.SendSSHPublicKeyOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "SendSSHPublicKey", varargs...)
+	ret0, _ := ret[0].(*ec2instanceconnect.SendSSHPublicKeyOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SendSSHPublicKey indicates an expected call of SendSSHPublicKey.
+func (mr *MockEC2InstanceConnectClientMockRecorder) SendSSHPublicKey(ctx, params any, optFns ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendSSHPublicKey", reflect.TypeOf((*MockEC2InstanceConnectClient)(nil).SendSSHPublicKey), varargs...)
+}
+
+// MockAutoScalingClient is a mock of AutoScalingClient interface.
+type MockAutoScalingClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockAutoScalingClientMockRecorder
+	isgomock struct{}
+}
+
+// MockAutoScalingClientMockRecorder is the mock recorder for MockAutoScalingClient.
+type MockAutoScalingClientMockRecorder struct {
+	mock *MockAutoScalingClient
+}
+
+// NewMockAutoScalingClient creates a new mock instance.
+func NewMockAutoScalingClient(ctrl *gomock.Controller) *MockAutoScalingClient {
+	mock := &MockAutoScalingClient{ctrl: ctrl}
+	mock.recorder = &MockAutoScalingClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAutoScalingClient) EXPECT() *MockAutoScalingClientMockRecorder {
+	return m.recorder
+}
+
+// DescribeAutoScalingGroups mocks base method.
+func (m *MockAutoScalingClient) DescribeAutoScalingGroups(ctx context.Context, params *autoscaling.DescribeAutoScalingGroupsInput, optFns ...func(*autoscaling.Options)) (*autoscaling.DescribeAutoScalingGroupsOutput, error) {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, params}
+	for _, a := range optFns {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DescribeAutoScalingGroups", varargs...)
+	ret0, _ := ret[0].(*autoscaling.DescribeAutoScalingGroupsOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeAutoScalingGroups indicates an expected call of DescribeAutoScalingGroups.
+func (mr *MockAutoScalingClientMockRecorder) DescribeAutoScalingGroups(ctx, params any, optFns ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, params}, optFns...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeAutoScalingGroups", reflect.TypeOf((*MockAutoScalingClient)(nil).DescribeAutoScalingGroups), varargs...)
+}
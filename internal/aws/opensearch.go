@@ -2,16 +2,26 @@ package aws
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"path"
 	"strings"
+	"syscall"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/ec2"
-	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
 	"github.com/aws/aws-sdk-go-v2/service/opensearch"
+	"github.com/aws/aws-sdk-go-v2/service/opensearchserverless"
+	serverlesstypes "github.com/aws/aws-sdk-go-v2/service/opensearchserverless/types"
 	ssmservice "github.com/aws/aws-sdk-go-v2/service/ssm"
 	awscconfig "github.com/blontic/awsc/internal/config"
 	"github.com/blontic/awsc/internal/debug"
+	"github.com/blontic/awsc/internal/proxy"
 	"github.com/blontic/awsc/internal/ui"
 )
 
@@ -21,25 +31,49 @@ type OpenSearchClient interface {
 	DescribeDomain(ctx context.Context, params *opensearch.DescribeDomainInput, optFns ...func(*opensearch.Options)) (*opensearch.DescribeDomainOutput, error)
 }
 
+// OpenSearchServerlessClient interface for mocking
+type OpenSearchServerlessClient interface {
+	ListCollections(ctx context.Context, params *opensearchserverless.ListCollectionsInput, optFns ...func(*opensearchserverless.Options)) (*opensearchserverless.ListCollectionsOutput, error)
+	BatchGetCollection(ctx context.Context, params *opensearchserverless.BatchGetCollectionInput, optFns ...func(*opensearchserverless.Options)) (*opensearchserverless.BatchGetCollectionOutput, error)
+	ListSecurityPolicies(ctx context.Context, params *opensearchserverless.ListSecurityPoliciesInput, optFns ...func(*opensearchserverless.Options)) (*opensearchserverless.ListSecurityPoliciesOutput, error)
+	GetSecurityPolicy(ctx context.Context, params *opensearchserverless.GetSecurityPolicyInput, optFns ...func(*opensearchserverless.Options)) (*opensearchserverless.GetSecurityPolicyOutput, error)
+	BatchGetVpcEndpoint(ctx context.Context, params *opensearchserverless.BatchGetVpcEndpointInput, optFns ...func(*opensearchserverless.Options)) (*opensearchserverless.BatchGetVpcEndpointOutput, error)
+}
+
 type OpenSearchManager struct {
 	opensearchClient OpenSearchClient
+	serverlessClient OpenSearchServerlessClient
 	ec2Client        EC2Client
 	ssmClient        *ssmservice.Client
+	bastionFinder    *BastionFinder
 	region           string
 }
 
 type OpenSearchDomain struct {
-	Name     string
-	Endpoint string
-	Port     int32
-	Version  string
+	Name             string
+	Endpoint         string
+	Port             int32
+	Version          string
+	Type             string   // "domain" or "serverless"
+	Public           bool     // Reachable without a tunnel; connect serves a signing proxy directly
+	SecurityGroupIds []string // VPC endpoint security groups for serverless collections
+	ExcludedReason   string   // Why the domain can't be connected to, shown in the selector
+}
+
+// signingService returns the SigV4 service name for the domain type
+func (d OpenSearchDomain) signingService() string {
+	if d.Type == "serverless" {
+		return "aoss"
+	}
+	return "es"
 }
 
 type OpenSearchManagerOptions struct {
-	OpenSearchClient OpenSearchClient
-	EC2Client        EC2Client
-	SSMClient        *ssmservice.Client
-	Region           string
+	OpenSearchClient           OpenSearchClient
+	OpenSearchServerlessClient OpenSearchServerlessClient
+	EC2Client                  EC2Client
+	SSMClient                  *ssmservice.Client
+	Region                     string
 }
 
 func NewOpenSearchManager(ctx context.Context, opts ...OpenSearchManagerOptions) (*OpenSearchManager, error) {
@@ -47,8 +81,10 @@ func NewOpenSearchManager(ctx context.Context, opts ...OpenSearchManagerOptions)
 		// Use provided clients (for testing)
 		return &OpenSearchManager{
 			opensearchClient: opts[0].OpenSearchClient,
+			serverlessClient: opts[0].OpenSearchServerlessClient,
 			ec2Client:        opts[0].EC2Client,
 			ssmClient:        opts[0].SSMClient,
+			bastionFinder:    NewBastionFinder(opts[0].EC2Client, opts[0].Region),
 			region:           opts[0].Region,
 		}, nil
 	}
@@ -61,25 +97,152 @@ func NewOpenSearchManager(ctx context.Context, opts ...OpenSearchManagerOptions)
 
 	return &OpenSearchManager{
 		opensearchClient: opensearch.NewFromConfig(cfg),
+		serverlessClient: opensearchserverless.NewFromConfig(cfg),
 		ec2Client:        ec2.NewFromConfig(cfg),
 		ssmClient:        ssmservice.NewFromConfig(cfg),
+		bastionFinder:    NewBastionFinder(ec2.NewFromConfig(cfg), cfg.Region),
 		region:           cfg.Region,
 	}, nil
 }
 
-func (o *OpenSearchManager) RunConnect(ctx context.Context, domainName string, localPort int32) error {
-	// List OpenSearch domains
+// RunConnect tunnels to the selected domain or collection; with sign set, localPort serves a
+// plain HTTP proxy that signs requests with SigV4 instead of exposing the raw TLS endpoint.
+// Public endpoints need no tunnel and always get the signing proxy.
+func (o *OpenSearchManager) RunConnect(ctx context.Context, domainName string, localPort int32, sign bool) error {
+	// List OpenSearch domains and serverless collections
 	domains, err := o.ListOpenSearchDomains(ctx)
 	if err != nil {
 		return fmt.Errorf("error listing OpenSearch domains: %v", err)
 	}
 
+	// Serverless isn't offered in every region or allowed for every role, so domains are
+	// still listed when collections can't be
+	collections, err := o.ListServerlessCollections(ctx)
+	if err != nil {
+		debug.Printf("Error listing OpenSearch Serverless collections: %v\n", err)
+	}
+	domains = append(domains, collections...)
+
 	if len(domains) == 0 {
-		return fmt.Errorf("no OpenSearch domains found")
+		return fmt.Errorf("no OpenSearch domains or collections found")
+	}
+
+	selectedDomain, err := o.selectOpenSearchDomain(domains, domainName)
+	if err != nil {
+		return err
+	}
+
+	if selectedDomain.Public {
+		if localPort == 0 {
+			localPort = 9200
+		}
+		cfg, err := awscconfig.LoadAWSConfigWithProfile(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to load AWS config: %w", err)
+		}
+		fmt.Printf("%s has a public endpoint; serving a local signing proxy instead of a tunnel\n", selectedDomain.Name)
+		return serveSigningProxy(ctx, &proxy.SigningProxy{
+			Host:        selectedDomain.Endpoint,
+			Region:      cfg.Region,
+			Service:     selectedDomain.signingService(),
+			Credentials: cfg.Credentials,
+		}, selectedDomain, localPort)
+	}
+
+	// Find bastion hosts
+	var bastions []BastionHost
+	if selectedDomain.Type == "serverless" {
+		bastions, err = o.bastionFinder.FindBastionHosts(ctx, BastionTarget{
+			Name:             selectedDomain.Name,
+			Service:          "OpenSearch Serverless",
+			SecurityGroupIds: selectedDomain.SecurityGroupIds,
+			Port:             selectedDomain.Port,
+		})
+	} else {
+		bastions, err = o.FindBastionHosts(ctx, selectedDomain)
+	}
+	if err != nil || len(bastions) == 0 {
+		// Fall back to an EC2 Instance Connect Endpoint in the domain's VPC
+		if endpoint := o.findInstanceConnectEndpoint(ctx, selectedDomain); endpoint != nil {
+			return o.connectInstanceConnect(ctx, *endpoint, selectedDomain, localPort, sign)
+		}
+		if err != nil {
+			return err
+		}
+		return fmt.Errorf("no bastion hosts available for %s", selectedDomain.Name)
+	}
+
+	// Use first available bastion
+	bastion := bastions[0]
+	fmt.Printf("Using bastion: %s\n", bastion.Name)
+
+	if sign {
+		if localPort == 0 {
+			localPort = 9200
+		}
+		return o.StartSigningProxy(ctx, bastion.InstanceId, selectedDomain, localPort)
+	}
+
+	if localPort == 0 {
+		localPort = selectedDomain.Port
+	}
+
+	// Start port forwarding
+	return o.StartPortForwarding(ctx, bastion.InstanceId, selectedDomain.Endpoint, selectedDomain.Port, localPort)
+}
+
+func (o *OpenSearchManager) findInstanceConnectEndpoint(ctx context.Context, domain OpenSearchDomain) *InstanceConnectEndpoint {
+	securityGroups := domain.SecurityGroupIds
+	if domain.Type != "serverless" {
+		var err error
+		if securityGroups, err = o.getOpenSearchSecurityGroups(ctx, domain); err != nil {
+			return nil
+		}
+	}
+
+	return findInstanceConnectFallback(ctx, o.bastionFinder, BastionTarget{
+		Name:             domain.Name,
+		Service:          "OpenSearch",
+		SecurityGroupIds: securityGroups,
+		Port:             domain.Port,
+	})
+}
+
+// connectInstanceConnect is the tail of RunConnect through an EC2 Instance Connect Endpoint
+func (o *OpenSearchManager) connectInstanceConnect(ctx context.Context, endpoint InstanceConnectEndpoint, domain OpenSearchDomain, localPort int32, sign bool) error {
+	if !sign {
+		if localPort == 0 {
+			localPort = domain.Port
+		}
+		return startInstanceConnectForwarding(ctx, endpoint, domain.Endpoint, int(domain.Port), int(localPort))
+	}
+
+	if localPort == 0 {
+		localPort = 9200
+	}
+
+	cfg, err := awscconfig.LoadAWSConfigWithProfile(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load AWS config: %w", err)
 	}
 
-	var selectedDomain OpenSearchDomain
+	tunnelPort, err := freeLocalPort()
+	if err != nil {
+		return err
+	}
+
+	return NewEICEForwarder(cfg, endpoint).RunWithPortForwarding(ctx, domain.Endpoint, int(domain.Port), tunnelPort, func() error {
+		return serveSigningProxy(ctx, &proxy.SigningProxy{
+			Host:        domain.Endpoint,
+			Upstream:    fmt.Sprintf("127.0.0.1:%d", tunnelPort),
+			Region:      cfg.Region,
+			Service:     domain.signingService(),
+			Credentials: cfg.Credentials,
+		}, domain, localPort)
+	})
+}
 
+func (o *OpenSearchManager) selectOpenSearchDomain(domains []OpenSearchDomain, domainName string) (OpenSearchDomain, error) {
 	// If domain name provided, try to connect directly
 	if domainName != "" {
 		var targetDomain *OpenSearchDomain
@@ -90,54 +253,55 @@ func (o *OpenSearchManager) RunConnect(ctx context.Context, domainName string, l
 			}
 		}
 
-		if targetDomain != nil {
-			fmt.Printf("Connecting to OpenSearch domain: %s\n", targetDomain.Name)
-			selectedDomain = *targetDomain
-		} else {
+		if targetDomain == nil {
 			fmt.Printf("OpenSearch domain '%s' not found. Available domains:\n\n", domainName)
-			// Fall through to show list of available domains
+		} else if targetDomain.ExcludedReason != "" {
+			fmt.Printf("OpenSearch domain '%s' can't be connected to (%s). Available domains:\n\n", domainName, targetDomain.ExcludedReason)
+		} else {
+			fmt.Printf("Connecting to OpenSearch domain: %s\n", targetDomain.Name)
+			fmt.Printf("✓ Selected: %s\n", targetDomain.Name)
+			return *targetDomain, nil
 		}
 	}
 
-	// If no domain name provided or domain not found, show interactive selection
-	if domainName == "" || selectedDomain.Name == "" {
-		// Create domain options for selection
-		domainOptions := make([]string, len(domains))
-		for i, domain := range domains {
-			domainOptions[i] = fmt.Sprintf("%s (%s)", domain.Name, domain.Version)
+	// Excluded domains are listed with the reason but can't be selected
+	domainOptions := make([]string, len(domains))
+	selectable := make([]bool, len(domains))
+	hasSelectable := false
+	for i, domain := range domains {
+		domainOptions[i] = fmt.Sprintf("%s (%s)", domain.Name, domain.Version)
+		if domain.Type == "serverless" {
+			domainOptions[i] += " [Serverless]"
 		}
-
-		// Interactive domain selection
-		selectedIndex, err := ui.RunSelector("Select OpenSearch Domain:", domainOptions)
-		if err != nil {
-			return fmt.Errorf("error selecting domain: %v", err)
+		if domain.Public {
+			domainOptions[i] += " [Public]"
 		}
-		if selectedIndex == -1 {
-			return fmt.Errorf("no domain selected")
+		if domain.ExcludedReason != "" {
+			domainOptions[i] += fmt.Sprintf(" - %s", domain.ExcludedReason)
 		}
+		selectable[i] = domain.ExcludedReason == ""
+		hasSelectable = hasSelectable || selectable[i]
+	}
 
-		selectedDomain = domains[selectedIndex]
-		fmt.Printf("✓ Selected: %s\n", selectedDomain.Name)
-	} else {
-		fmt.Printf("✓ Selected: %s\n", selectedDomain.Name)
+	if !hasSelectable {
+		for _, option := range domainOptions {
+			fmt.Printf("- %s\n", option)
+		}
+		return OpenSearchDomain{}, fmt.Errorf("no OpenSearch domains or collections can be connected to")
 	}
 
-	// Find bastion hosts
-	bastions, err := o.FindBastionHosts(ctx, selectedDomain)
+	// Interactive domain selection
+	selectedIndex, err := ui.RunSelectorWithSelectability("Select OpenSearch Domain:", domainOptions, selectable)
 	if err != nil {
-		return err
+		return OpenSearchDomain{}, fmt.Errorf("error selecting domain: %v", err)
 	}
-
-	if len(bastions) == 0 {
-		return fmt.Errorf("no bastion hosts available for %s", selectedDomain.Name)
+	if selectedIndex == -1 {
+		return OpenSearchDomain{}, fmt.Errorf("no domain selected")
 	}
 
-	// Use first available bastion
-	bastion := bastions[0]
-	fmt.Printf("Using bastion: %s\n", bastion.Name)
-
-	// Start port forwarding
-	return o.StartPortForwarding(ctx, bastion.InstanceId, selectedDomain.Endpoint, selectedDomain.Port, localPort)
+	selected := domains[selectedIndex]
+	fmt.Printf("✓ Selected: %s\n", selected.Name)
+	return selected, nil
 }
 
 func (o *OpenSearchManager) ListOpenSearchDomains(ctx context.Context) ([]OpenSearchDomain, error) {
@@ -194,70 +358,162 @@ func (o *OpenSearchManager) ListOpenSearchDomains(ctx context.Context) ([]OpenSe
 		}
 
 		domain := domainDetail.DomainStatus
-		if domain == nil || domain.DomainEndpointOptions == nil || domain.DomainEndpointOptions.EnforceHTTPS == nil || !*domain.DomainEndpointOptions.EnforceHTTPS {
-			continue // Skip domains without HTTPS enforcement
-		}
-
-		// Only include domains that are active and have VPC endpoints
-		if domain.Processing != nil && *domain.Processing {
-			continue // Skip domains that are being processed
+		if domain == nil {
+			continue
 		}
 
-		if domain.VPCOptions == nil || len(domain.VPCOptions.SecurityGroupIds) == 0 {
-			continue // Skip domains without VPC configuration
+		entry := OpenSearchDomain{
+			Name:    *domainInfo.DomainName,
+			Port:    443, // Default HTTPS port
+			Version: aws.ToString(domain.EngineVersion),
+			Type:    "domain",
 		}
 
-		var endpoint string
-		var port int32 = 443 // Default HTTPS port
-
 		if domain.Endpoints != nil {
 			if vpcEndpoint, exists := domain.Endpoints["vpc"]; exists {
-				endpoint = vpcEndpoint
+				entry.Endpoint = vpcEndpoint
 			}
 		}
 
-		if endpoint == "" && domain.DomainEndpointOptions != nil && domain.DomainEndpointOptions.CustomEndpoint != nil {
-			endpoint = *domain.DomainEndpointOptions.CustomEndpoint
+		if entry.Endpoint == "" && domain.DomainEndpointOptions != nil && domain.DomainEndpointOptions.CustomEndpoint != nil {
+			entry.Endpoint = *domain.DomainEndpointOptions.CustomEndpoint
 		}
 
-		if endpoint == "" {
-			continue // Skip domains without accessible endpoints
+		// Domains outside a VPC are reached directly through the signing proxy
+		if domain.VPCOptions == nil || len(domain.VPCOptions.SecurityGroupIds) == 0 {
+			entry.Public = true
+			if entry.Endpoint == "" {
+				entry.Endpoint = aws.ToString(domain.Endpoint)
+			}
 		}
 
 		// Remove https:// prefix if present
-		endpoint = strings.TrimPrefix(endpoint, "https://")
-
-		var version string
-		if domain.EngineVersion != nil {
-			version = *domain.EngineVersion
+		entry.Endpoint = strings.TrimPrefix(entry.Endpoint, "https://")
+
+		switch {
+		case domain.Processing != nil && *domain.Processing:
+			entry.ExcludedReason = "configuration change in progress"
+		case domain.DomainEndpointOptions == nil || domain.DomainEndpointOptions.EnforceHTTPS == nil || !*domain.DomainEndpointOptions.EnforceHTTPS:
+			entry.ExcludedReason = "HTTPS not enforced"
+		case entry.Endpoint == "":
+			entry.ExcludedReason = "no endpoint"
 		}
 
-		domains = append(domains, OpenSearchDomain{
-			Name:     *domainInfo.DomainName,
-			Endpoint: endpoint,
-			Port:     port,
-			Version:  version,
-		})
+		domains = append(domains, entry)
 	}
 
 	return domains, nil
 }
 
-func (o *OpenSearchManager) FindBastionHosts(ctx context.Context, domain OpenSearchDomain) ([]BastionHost, error) {
-	// Get OpenSearch security groups
-	opensearchSecurityGroups, err := o.getOpenSearchSecurityGroups(ctx, domain)
+// networkPolicyStatement is one entry of an OpenSearch Serverless network policy document
+type networkPolicyStatement struct {
+	Rules []struct {
+		ResourceType string
+		Resource     []string
+	}
+	AllowFromPublic bool
+	SourceVPCEs     []string
+}
+
+// ListServerlessCollections returns OpenSearch Serverless collections, using network
+// policies to decide whether each is public or reached through a VPC endpoint
+func (o *OpenSearchManager) ListServerlessCollections(ctx context.Context) ([]OpenSearchDomain, error) {
+	summaries, err := o.listCollectionSummaries(ctx)
 	if err != nil {
 		return nil, err
 	}
+	if len(summaries) == 0 {
+		return nil, nil
+	}
 
-	debug.Printf("OpenSearch %s security groups: %v\n", domain.Name, opensearchSecurityGroups)
+	var ids []string
+	for _, summary := range summaries {
+		if summary.Id != nil {
+			ids = append(ids, *summary.Id)
+		}
+	}
 
-	// Find all EC2 instances (running and stopped) that can connect to OpenSearch
-	var allReservations []types.Reservation
+	var details []serverlesstypes.CollectionDetail
+	// BatchGetCollection accepts at most 100 IDs per call
+	for start := 0; start < len(ids); start += 100 {
+		end := min(start+100, len(ids))
+		input := &opensearchserverless.BatchGetCollectionInput{
+			Ids: ids[start:end],
+		}
+		result, err := o.serverlessClient.BatchGetCollection(ctx, input)
+		if err != nil {
+			if IsAuthError(err) {
+				if shouldReauth, reAuthErr := PromptForReauth(ctx); shouldReauth && reAuthErr == nil {
+					if reloadErr := o.reloadClients(ctx); reloadErr != nil {
+						return nil, reloadErr
+					}
+					result, err = o.serverlessClient.BatchGetCollection(ctx, input)
+					if err != nil {
+						return nil, err
+					}
+				} else {
+					return nil, err
+				}
+			} else {
+				return nil, err
+			}
+		}
+		details = append(details, result.CollectionDetails...)
+	}
+
+	policies, err := o.getNetworkPolicies(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	vpcEndpoints, err := o.getVpcEndpoints(ctx, policies)
+	if err != nil {
+		return nil, err
+	}
+
+	var collections []OpenSearchDomain
+	for _, detail := range details {
+		if detail.Name == nil {
+			continue
+		}
+
+		collection := OpenSearchDomain{
+			Name:     *detail.Name,
+			Endpoint: strings.TrimPrefix(aws.ToString(detail.CollectionEndpoint), "https://"),
+			Port:     443,
+			Version:  strings.ToLower(string(detail.Type)),
+			Type:     "serverless",
+		}
+
+		public, vpceIds := collectionNetworkAccess(collection.Name, policies)
+		for _, id := range vpceIds {
+			if vpce, ok := vpcEndpoints[id]; ok && vpce.Status == serverlesstypes.VpcEndpointStatusActive {
+				collection.SecurityGroupIds = append(collection.SecurityGroupIds, vpce.SecurityGroupIds...)
+			}
+		}
+		collection.Public = public
+
+		switch {
+		case detail.Status != serverlesstypes.CollectionStatusActive:
+			collection.ExcludedReason = strings.ToLower(string(detail.Status))
+		case !public && len(vpceIds) == 0:
+			collection.ExcludedReason = "no network policy allows access"
+		case !public && len(collection.SecurityGroupIds) == 0:
+			collection.ExcludedReason = "no active VPC endpoint"
+		}
+
+		collections = append(collections, collection)
+	}
+
+	return collections, nil
+}
+
+func (o *OpenSearchManager) listCollectionSummaries(ctx context.Context) ([]serverlesstypes.CollectionSummary, error) {
+	var allSummaries []serverlesstypes.CollectionSummary
 	var nextToken *string
 
 	for {
-		result, err := o.ec2Client.DescribeInstances(ctx, &ec2.DescribeInstancesInput{
+		result, err := o.serverlessClient.ListCollections(ctx, &opensearchserverless.ListCollectionsInput{
 			NextToken: nextToken,
 		})
 		if err != nil {
@@ -266,7 +522,7 @@ func (o *OpenSearchManager) FindBastionHosts(ctx context.Context, domain OpenSea
 					if reloadErr := o.reloadClients(ctx); reloadErr != nil {
 						return nil, reloadErr
 					}
-					result, err = o.ec2Client.DescribeInstances(ctx, &ec2.DescribeInstancesInput{
+					result, err = o.serverlessClient.ListCollections(ctx, &opensearchserverless.ListCollectionsInput{
 						NextToken: nextToken,
 					})
 					if err != nil {
@@ -280,7 +536,7 @@ func (o *OpenSearchManager) FindBastionHosts(ctx context.Context, domain OpenSea
 			}
 		}
 
-		allReservations = append(allReservations, result.Reservations...)
+		allSummaries = append(allSummaries, result.CollectionSummaries...)
 
 		if result.NextToken == nil {
 			break
@@ -288,81 +544,181 @@ func (o *OpenSearchManager) FindBastionHosts(ctx context.Context, domain OpenSea
 		nextToken = result.NextToken
 	}
 
-	// Count and categorize instances
-	totalInstances := 0
-	runningInstances := 0
-	stoppedInstances := 0
-	var stoppedInstanceNames []string
-
-	for _, reservation := range allReservations {
-		totalInstances += len(reservation.Instances)
-		for _, instance := range reservation.Instances {
-			if instance.State != nil {
-				if instance.State.Name == "running" {
-					runningInstances++
-				} else if instance.State.Name == "stopped" {
-					stoppedInstances++
-					stoppedInstanceNames = append(stoppedInstanceNames, o.getInstanceName(instance.Tags))
+	return allSummaries, nil
+}
+
+func (o *OpenSearchManager) getNetworkPolicies(ctx context.Context) ([]networkPolicyStatement, error) {
+	var statements []networkPolicyStatement
+	var nextToken *string
+
+	for {
+		input := &opensearchserverless.ListSecurityPoliciesInput{
+			Type:      serverlesstypes.SecurityPolicyTypeNetwork,
+			NextToken: nextToken,
+		}
+		result, err := o.serverlessClient.ListSecurityPolicies(ctx, input)
+		if err != nil {
+			if IsAuthError(err) {
+				if shouldReauth, reAuthErr := PromptForReauth(ctx); shouldReauth && reAuthErr == nil {
+					if reloadErr := o.reloadClients(ctx); reloadErr != nil {
+						return nil, reloadErr
+					}
+					result, err = o.serverlessClient.ListSecurityPolicies(ctx, input)
+					if err != nil {
+						return nil, err
+					}
+				} else {
+					return nil, err
+				}
+			} else {
+				return nil, err
+			}
+		}
+
+		for _, summary := range result.SecurityPolicySummaries {
+			policyInput := &opensearchserverless.GetSecurityPolicyInput{
+				Name: summary.Name,
+				Type: serverlesstypes.SecurityPolicyTypeNetwork,
+			}
+			policy, err := o.serverlessClient.GetSecurityPolicy(ctx, policyInput)
+			if err != nil {
+				if IsAuthError(err) {
+					if shouldReauth, reAuthErr := PromptForReauth(ctx); shouldReauth && reAuthErr == nil {
+						if reloadErr := o.reloadClients(ctx); reloadErr != nil {
+							return nil, reloadErr
+						}
+						policy, err = o.serverlessClient.GetSecurityPolicy(ctx, policyInput)
+						if err != nil {
+							return nil, err
+						}
+					} else {
+						return nil, err
+					}
+				} else {
+					return nil, err
 				}
 			}
+			if policy.SecurityPolicyDetail == nil || policy.SecurityPolicyDetail.Policy == nil {
+				continue
+			}
+
+			// Round-trip through JSON; the policy document is a JSON array of statements
+			var policyStatements []networkPolicyStatement
+			policyJson, err := policy.SecurityPolicyDetail.Policy.MarshalSmithyDocument()
+			if err == nil {
+				err = json.Unmarshal(policyJson, &policyStatements)
+			}
+			if err != nil {
+				debug.Printf("Error parsing network policy %s: %v\n", aws.ToString(summary.Name), err)
+				continue
+			}
+			statements = append(statements, policyStatements...)
+		}
+
+		if result.NextToken == nil {
+			break
 		}
+		nextToken = result.NextToken
 	}
-	debug.Printf("Found %d total EC2 instances (%d running, %d stopped)\n", totalInstances, runningInstances, stoppedInstances)
 
-	var bastions []BastionHost
-	for _, reservation := range allReservations {
-		for _, instance := range reservation.Instances {
-			// Only check running instances for bastion capability
-			if instance.State == nil || instance.State.Name != "running" {
-				continue
+	return statements, nil
+}
+
+// getVpcEndpoints describes every VPC endpoint referenced by the network policies
+func (o *OpenSearchManager) getVpcEndpoints(ctx context.Context, policies []networkPolicyStatement) (map[string]serverlesstypes.VpcEndpointDetail, error) {
+	seen := make(map[string]bool)
+	var ids []string
+	for _, policy := range policies {
+		for _, id := range policy.SourceVPCEs {
+			if !seen[id] {
+				seen[id] = true
+				ids = append(ids, id)
 			}
+		}
+	}
 
-			name := o.getInstanceName(instance.Tags)
-			ec2SgIds := o.getSecurityGroupIds(instance.SecurityGroups)
-			debug.Printf("Checking instance %s (%s) with security groups: %v\n", name, *instance.InstanceId, ec2SgIds)
+	endpoints := make(map[string]serverlesstypes.VpcEndpointDetail)
+	if len(ids) == 0 {
+		return endpoints, nil
+	}
 
-			if o.canConnectToOpenSearch(ctx, instance.SecurityGroups, opensearchSecurityGroups, domain.Port) {
-				debug.Printf("✓ Instance %s can connect to OpenSearch\n", name)
-				bastions = append(bastions, BastionHost{
-					InstanceId:       *instance.InstanceId,
-					Name:             name,
-					SecurityGroupIds: ec2SgIds,
-				})
+	// BatchGetVpcEndpoint accepts at most 100 IDs per call
+	for start := 0; start < len(ids); start += 100 {
+		end := min(start+100, len(ids))
+		input := &opensearchserverless.BatchGetVpcEndpointInput{
+			Ids: ids[start:end],
+		}
+		result, err := o.serverlessClient.BatchGetVpcEndpoint(ctx, input)
+		if err != nil {
+			if IsAuthError(err) {
+				if shouldReauth, reAuthErr := PromptForReauth(ctx); shouldReauth && reAuthErr == nil {
+					if reloadErr := o.reloadClients(ctx); reloadErr != nil {
+						return nil, reloadErr
+					}
+					result, err = o.serverlessClient.BatchGetVpcEndpoint(ctx, input)
+					if err != nil {
+						return nil, err
+					}
+				} else {
+					return nil, err
+				}
 			} else {
-				debug.Printf("✗ Instance %s cannot connect to OpenSearch\n", name)
+				return nil, err
 			}
 		}
-	}
 
-	if len(bastions) == 0 {
-		// Show stopped instances if any exist
-		if stoppedInstances > 0 {
-			fmt.Printf("\nFound %d stopped EC2 instance(s):\n", stoppedInstances)
-			for _, name := range stoppedInstanceNames {
-				fmt.Printf("- %s (stopped)\n", name)
+		for _, detail := range result.VpcEndpointDetails {
+			if detail.Id != nil {
+				endpoints[*detail.Id] = detail
 			}
-			fmt.Printf("\n")
 		}
+	}
+
+	return endpoints, nil
+}
 
-		if runningInstances == 0 {
-			fmt.Printf("No running EC2 instances found in region %s.\n", o.region)
-			fmt.Printf("To use OpenSearch port forwarding, you need a running EC2 instance with:\n")
-			fmt.Printf("- SSM agent installed and configured\n")
-			fmt.Printf("- Network access to the OpenSearch domain\n")
-			if stoppedInstances > 0 {
-				fmt.Printf("\nYou can start one of the stopped instances above and try again.\n")
-				return nil, fmt.Errorf("no running bastion hosts found - %d stopped instances available", stoppedInstances)
+// collectionNetworkAccess reports whether any network policy allows public access to the
+// collection and which VPC endpoints are allowed to reach it
+func collectionNetworkAccess(name string, policies []networkPolicyStatement) (bool, []string) {
+	public := false
+	var vpceIds []string
+
+	for _, policy := range policies {
+		matched := false
+		for _, rule := range policy.Rules {
+			if rule.ResourceType != "collection" {
+				continue
+			}
+			for _, resource := range rule.Resource {
+				if ok, _ := path.Match(strings.TrimPrefix(resource, "collection/"), name); ok {
+					matched = true
+				}
 			}
-			fmt.Printf("\nAlternatively, you can connect directly if your OpenSearch domain is publicly accessible.\n")
-			return nil, fmt.Errorf("no running EC2 instances found in region %s", o.region)
-		} else {
-			fmt.Printf("Found %d running EC2 instances but none can connect to OpenSearch %s.\n", runningInstances, domain.Name)
-			fmt.Printf("This usually means the security groups don't allow the connection.\n")
-			return nil, fmt.Errorf("no suitable bastion hosts found - security groups may not allow connection")
 		}
+		if !matched {
+			continue
+		}
+
+		public = public || policy.AllowFromPublic
+		vpceIds = append(vpceIds, policy.SourceVPCEs...)
 	}
 
-	return bastions, nil
+	return public, vpceIds
+}
+
+func (o *OpenSearchManager) FindBastionHosts(ctx context.Context, domain OpenSearchDomain) ([]BastionHost, error) {
+	// Get OpenSearch security groups
+	opensearchSecurityGroups, err := o.getOpenSearchSecurityGroups(ctx, domain)
+	if err != nil {
+		return nil, err
+	}
+
+	return o.bastionFinder.FindBastionHosts(ctx, BastionTarget{
+		Name:             domain.Name,
+		Service:          "OpenSearch",
+		SecurityGroupIds: opensearchSecurityGroups,
+		Port:             domain.Port,
+	})
 }
 
 func (o *OpenSearchManager) StartPortForwarding(ctx context.Context, bastionId, opensearchEndpoint string, opensearchPort, localPort int32) error {
@@ -380,144 +736,99 @@ func (o *OpenSearchManager) StartPortForwarding(ctx context.Context, bastionId,
 	return pf.StartPortForwardingToRemoteHost(ctx, bastionId, opensearchEndpoint, int(opensearchPort), int(localPort))
 }
 
-func (o *OpenSearchManager) getOpenSearchSecurityGroups(ctx context.Context, domain OpenSearchDomain) ([]string, error) {
-	result, err := o.opensearchClient.DescribeDomain(ctx, &opensearch.DescribeDomainInput{
-		DomainName: aws.String(domain.Name),
-	})
+// StartSigningProxy tunnels to the domain on a free local port and serves a SigV4 signing
+// HTTP proxy on localPort that forwards through the tunnel with the domain's SNI and Host
+func (o *OpenSearchManager) StartSigningProxy(ctx context.Context, bastionId string, domain OpenSearchDomain, localPort int32) error {
+	cfg, err := awscconfig.LoadAWSConfigWithProfile(ctx)
 	if err != nil {
-		if IsAuthError(err) {
-			if shouldReauth, reAuthErr := PromptForReauth(ctx); shouldReauth && reAuthErr == nil {
-				if reloadErr := o.reloadClients(ctx); reloadErr != nil {
-					return nil, reloadErr
-				}
-				result, err = o.opensearchClient.DescribeDomain(ctx, &opensearch.DescribeDomainInput{
-					DomainName: aws.String(domain.Name),
-				})
-				if err != nil {
-					return nil, err
-				}
-			} else {
-				return nil, err
-			}
-		} else {
-			return nil, err
-		}
+		return fmt.Errorf("failed to load AWS config: %w", err)
 	}
 
-	if result.DomainStatus == nil || result.DomainStatus.VPCOptions == nil {
-		return nil, fmt.Errorf("OpenSearch domain not found or not in VPC")
+	pf := NewExternalPluginForwarder(cfg)
+
+	tunnelPort, err := freeLocalPort()
+	if err != nil {
+		return err
 	}
 
-	return result.DomainStatus.VPCOptions.SecurityGroupIds, nil
+	fmt.Printf("Starting port forwarding via %s...\n", bastionId)
+
+	return pf.RunWithPortForwarding(ctx, bastionId, domain.Endpoint, int(domain.Port), tunnelPort, func() error {
+		return serveSigningProxy(ctx, &proxy.SigningProxy{
+			Host:        domain.Endpoint,
+			Upstream:    fmt.Sprintf("127.0.0.1:%d", tunnelPort),
+			Region:      cfg.Region,
+			Service:     domain.signingService(),
+			Credentials: cfg.Credentials,
+		}, domain, localPort)
+	})
 }
 
-func (o *OpenSearchManager) canConnectToOpenSearch(ctx context.Context, ec2SecurityGroups []types.GroupIdentifier, opensearchSecurityGroups []string, opensearchPort int32) bool {
-	ec2SgIds := make(map[string]bool)
-	for _, sg := range ec2SecurityGroups {
-		ec2SgIds[*sg.GroupId] = true
+// serveSigningProxy serves the signing proxy on localhost until interrupted
+func serveSigningProxy(ctx context.Context, signingProxy *proxy.SigningProxy, domain OpenSearchDomain, localPort int32) error {
+	listener, err := net.Listen("tcp", fmt.Sprintf("127.0.0.1:%d", localPort))
+	if err != nil {
+		return fmt.Errorf("port %d is already in use; try a different port with --local-port", localPort)
 	}
 
-	// Check if any OpenSearch security group allows inbound from EC2 security groups
-	for _, opensearchSgId := range opensearchSecurityGroups {
-		if o.checkSecurityGroupRules(ctx, opensearchSgId, ec2SgIds, opensearchPort) {
-			return true
-		}
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	server := &http.Server{Handler: signingProxy.Handler()}
+
+	fmt.Printf("Signing proxy for %s listening on http://localhost:%d\n", domain.Name, localPort)
+	if domain.Type == "serverless" {
+		// Serverless Dashboards live on a separate host that this proxy doesn't serve
+		fmt.Printf("Dashboards for serverless collections are not available through the proxy\n")
+	} else {
+		fmt.Printf("Dashboards: http://localhost:%d/_dashboards/\n", localPort)
 	}
+	fmt.Printf("Press Ctrl+C to stop.\n")
+
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- server.Serve(listener)
+	}()
 
-	return false
+	select {
+	case err := <-serveErr:
+		return err
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		return server.Shutdown(shutdownCtx)
+	}
 }
 
-func (o *OpenSearchManager) checkSecurityGroupRules(ctx context.Context, opensearchSgId string, ec2SgIds map[string]bool, port int32) bool {
-	result, err := o.ec2Client.DescribeSecurityGroups(ctx, &ec2.DescribeSecurityGroupsInput{
-		GroupIds: []string{opensearchSgId},
+func (o *OpenSearchManager) getOpenSearchSecurityGroups(ctx context.Context, domain OpenSearchDomain) ([]string, error) {
+	result, err := o.opensearchClient.DescribeDomain(ctx, &opensearch.DescribeDomainInput{
+		DomainName: aws.String(domain.Name),
 	})
 	if err != nil {
 		if IsAuthError(err) {
 			if shouldReauth, reAuthErr := PromptForReauth(ctx); shouldReauth && reAuthErr == nil {
 				if reloadErr := o.reloadClients(ctx); reloadErr != nil {
-					debug.Printf("  Error reloading clients: %v\n", reloadErr)
-					return false
+					return nil, reloadErr
 				}
-				result, err = o.ec2Client.DescribeSecurityGroups(ctx, &ec2.DescribeSecurityGroupsInput{
-					GroupIds: []string{opensearchSgId},
+				result, err = o.opensearchClient.DescribeDomain(ctx, &opensearch.DescribeDomainInput{
+					DomainName: aws.String(domain.Name),
 				})
 				if err != nil {
-					debug.Printf("  Error describing security group %s after retry: %v\n", opensearchSgId, err)
-					return false
+					return nil, err
 				}
 			} else {
-				return false
-			}
-		} else {
-			debug.Printf("  Error describing security group %s: %v\n", opensearchSgId, err)
-			return false
-		}
-	}
-
-	if len(result.SecurityGroups) == 0 {
-		debug.Printf("  No security group found for %s\n", opensearchSgId)
-		return false
-	}
-
-	debug.Printf("  Checking security group %s rules for port %d\n", opensearchSgId, port)
-
-	for _, rule := range result.SecurityGroups[0].IpPermissions {
-		if o.ruleMatchesPort(rule, port) {
-			debug.Printf("    Rule matches port %d\n", port)
-			// Check if rule allows access from EC2 security groups
-			for _, userIdGroupPair := range rule.UserIdGroupPairs {
-				if userIdGroupPair.GroupId != nil {
-					var ec2SgList []string
-					for sgId := range ec2SgIds {
-						ec2SgList = append(ec2SgList, sgId)
-					}
-					debug.Printf("      Checking if EC2 SG %s is allowed (EC2 has: %s)\n", *userIdGroupPair.GroupId, strings.Join(ec2SgList, ", "))
-					if ec2SgIds[*userIdGroupPair.GroupId] {
-						debug.Printf("      ✓ Match found!\n")
-						return true
-					}
-				}
-			}
-			// Check for open access (0.0.0.0/0)
-			for _, ipRange := range rule.IpRanges {
-				if ipRange.CidrIp != nil {
-					debug.Printf("      Checking IP range: %s\n", *ipRange.CidrIp)
-					if *ipRange.CidrIp == "0.0.0.0/0" {
-						debug.Printf("      ✓ Open access found!\n")
-						return true
-					}
-				}
+				return nil, err
 			}
 		} else {
-			debug.Printf("    Rule does not match port %d (from:%v to:%v)\n", port, rule.FromPort, rule.ToPort)
+			return nil, err
 		}
 	}
 
-	return false
-}
-
-func (o *OpenSearchManager) ruleMatchesPort(rule types.IpPermission, port int32) bool {
-	if rule.FromPort == nil || rule.ToPort == nil {
-		return false
-	}
-	return *rule.FromPort <= port && port <= *rule.ToPort
-}
-
-func (o *OpenSearchManager) getInstanceName(tags []types.Tag) string {
-	for _, tag := range tags {
-		if tag.Key != nil && *tag.Key == "Name" && tag.Value != nil {
-			return *tag.Value
-		}
+	if result.DomainStatus == nil || result.DomainStatus.VPCOptions == nil {
+		return nil, fmt.Errorf("OpenSearch domain not found or not in VPC")
 	}
-	return "Unnamed"
-}
 
-func (o *OpenSearchManager) getSecurityGroupIds(sgs []types.GroupIdentifier) []string {
-	var ids []string
-	for _, sg := range sgs {
-		ids = append(ids, *sg.GroupId)
-	}
-	return ids
+	return result.DomainStatus.VPCOptions.SecurityGroupIds, nil
 }
 
 func (o *OpenSearchManager) reloadClients(ctx context.Context) error {
@@ -527,8 +838,10 @@ func (o *OpenSearchManager) reloadClients(ctx context.Context) error {
 	}
 
 	o.opensearchClient = opensearch.NewFromConfig(cfg)
+	o.serverlessClient = opensearchserverless.NewFromConfig(cfg)
 	o.ec2Client = ec2.NewFromConfig(cfg)
 	o.ssmClient = ssmservice.NewFromConfig(cfg)
+	o.bastionFinder = NewBastionFinder(o.ec2Client, cfg.Region)
 	o.region = cfg.Region
 
 	return nil
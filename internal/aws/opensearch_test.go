@@ -2,10 +2,15 @@ package aws
 
 import (
 	"context"
+	"fmt"
 	"testing"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/opensearch"
 	opensearchtypes "github.com/aws/aws-sdk-go-v2/service/opensearch/types"
+	"github.com/aws/aws-sdk-go-v2/service/opensearchserverless"
+	serverlessdocument "github.com/aws/aws-sdk-go-v2/service/opensearchserverless/document"
+	serverlesstypes "github.com/aws/aws-sdk-go-v2/service/opensearchserverless/types"
 	"github.com/blontic/awsc/internal/aws/mocks"
 	"go.uber.org/mock/gomock"
 )
@@ -113,3 +118,229 @@ func TestListOpenSearchDomains(t *testing.T) {
 		t.Errorf("Expected version to be OpenSearch_2.3, got %s", domain.Version)
 	}
 }
+
+func TestListOpenSearchDomains_PublicAndExcluded(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockOpenSearchClient := mocks.NewMockOpenSearchClient(ctrl)
+
+	manager, _ := NewOpenSearchManager(ctx, OpenSearchManagerOptions{
+		OpenSearchClient: mockOpenSearchClient,
+		EC2Client:        mocks.NewMockEC2Client(ctrl),
+		Region:           "us-east-1",
+	})
+
+	mockOpenSearchClient.EXPECT().
+		ListDomainNames(gomock.Any(), gomock.Any()).
+		Return(&opensearch.ListDomainNamesOutput{
+			DomainNames: []opensearchtypes.DomainInfo{
+				{DomainName: aws.String("public-domain")},
+				{DomainName: aws.String("plain-http")},
+			},
+		}, nil)
+
+	mockOpenSearchClient.EXPECT().
+		DescribeDomain(gomock.Any(), &opensearch.DescribeDomainInput{DomainName: aws.String("public-domain")}).
+		Return(&opensearch.DescribeDomainOutput{
+			DomainStatus: &opensearchtypes.DomainStatus{
+				DomainName:    aws.String("public-domain"),
+				EngineVersion: aws.String("OpenSearch_2.11"),
+				Endpoint:      aws.String("search-public-domain-abc.us-east-1.es.amazonaws.com"),
+				DomainEndpointOptions: &opensearchtypes.DomainEndpointOptions{
+					EnforceHTTPS: aws.Bool(true),
+				},
+			},
+		}, nil)
+
+	mockOpenSearchClient.EXPECT().
+		DescribeDomain(gomock.Any(), &opensearch.DescribeDomainInput{DomainName: aws.String("plain-http")}).
+		Return(&opensearch.DescribeDomainOutput{
+			DomainStatus: &opensearchtypes.DomainStatus{
+				DomainName: aws.String("plain-http"),
+				Endpoints:  map[string]string{"vpc": "vpc-plain-http.us-east-1.es.amazonaws.com"},
+				DomainEndpointOptions: &opensearchtypes.DomainEndpointOptions{
+					EnforceHTTPS: aws.Bool(false),
+				},
+				VPCOptions: &opensearchtypes.VPCDerivedInfo{
+					SecurityGroupIds: []string{"sg-123456"},
+				},
+			},
+		}, nil)
+
+	domains, err := manager.ListOpenSearchDomains(ctx)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(domains) != 2 {
+		t.Fatalf("Expected excluded domains to be listed too, got %d", len(domains))
+	}
+
+	if !domains[0].Public || domains[0].Endpoint != "search-public-domain-abc.us-east-1.es.amazonaws.com" || domains[0].ExcludedReason != "" {
+		t.Errorf("Expected a selectable public domain, got %+v", domains[0])
+	}
+
+	if domains[1].ExcludedReason != "HTTPS not enforced" {
+		t.Errorf("Expected plain-http to be excluded for HTTPS, got %q", domains[1].ExcludedReason)
+	}
+}
+
+func TestListServerlessCollections(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockServerless := mocks.NewMockOpenSearchServerlessClient(ctrl)
+
+	manager, _ := NewOpenSearchManager(ctx, OpenSearchManagerOptions{
+		OpenSearchClient:           mocks.NewMockOpenSearchClient(ctrl),
+		OpenSearchServerlessClient: mockServerless,
+		EC2Client:                  mocks.NewMockEC2Client(ctrl),
+		Region:                     "us-east-1",
+	})
+
+	mockServerless.EXPECT().
+		ListCollections(gomock.Any(), gomock.Any()).
+		Return(&opensearchserverless.ListCollectionsOutput{
+			CollectionSummaries: []serverlesstypes.CollectionSummary{
+				{Id: aws.String("id-logs"), Name: aws.String("logs-prod")},
+				{Id: aws.String("id-vectors"), Name: aws.String("vectors")},
+				{Id: aws.String("id-metrics"), Name: aws.String("metrics")},
+				{Id: aws.String("id-new"), Name: aws.String("logs-new")},
+			},
+		}, nil)
+
+	mockServerless.EXPECT().
+		BatchGetCollection(gomock.Any(), gomock.Any()).
+		Return(&opensearchserverless.BatchGetCollectionOutput{
+			CollectionDetails: []serverlesstypes.CollectionDetail{
+				{Id: aws.String("id-logs"), Name: aws.String("logs-prod"), Status: serverlesstypes.CollectionStatusActive, Type: serverlesstypes.CollectionTypeTimeseries, CollectionEndpoint: aws.String("https://id-logs.us-east-1.aoss.amazonaws.com")},
+				{Id: aws.String("id-vectors"), Name: aws.String("vectors"), Status: serverlesstypes.CollectionStatusActive, Type: serverlesstypes.CollectionTypeVectorsearch, CollectionEndpoint: aws.String("https://id-vectors.us-east-1.aoss.amazonaws.com")},
+				{Id: aws.String("id-metrics"), Name: aws.String("metrics"), Status: serverlesstypes.CollectionStatusActive, Type: serverlesstypes.CollectionTypeSearch, CollectionEndpoint: aws.String("https://id-metrics.us-east-1.aoss.amazonaws.com")},
+				{Id: aws.String("id-new"), Name: aws.String("logs-new"), Status: serverlesstypes.CollectionStatusCreating, Type: serverlesstypes.CollectionTypeTimeseries},
+			},
+		}, nil)
+
+	mockServerless.EXPECT().
+		ListSecurityPolicies(gomock.Any(), gomock.Any()).
+		Return(&opensearchserverless.ListSecurityPoliciesOutput{
+			SecurityPolicySummaries: []serverlesstypes.SecurityPolicySummary{
+				{Name: aws.String("private-logs")},
+				{Name: aws.String("public-vectors")},
+			},
+		}, nil)
+
+	mockServerless.EXPECT().
+		GetSecurityPolicy(gomock.Any(), &opensearchserverless.GetSecurityPolicyInput{Name: aws.String("private-logs"), Type: serverlesstypes.SecurityPolicyTypeNetwork}).
+		Return(&opensearchserverless.GetSecurityPolicyOutput{
+			SecurityPolicyDetail: &serverlesstypes.SecurityPolicyDetail{
+				Policy: serverlessdocument.NewLazyDocument([]map[string]interface{}{
+					{
+						"Rules":           []map[string]interface{}{{"ResourceType": "collection", "Resource": []string{"collection/logs-*"}}},
+						"AllowFromPublic": false,
+						"SourceVPCEs":     []string{"vpce-logs"},
+					},
+				}),
+			},
+		}, nil)
+
+	mockServerless.EXPECT().
+		GetSecurityPolicy(gomock.Any(), &opensearchserverless.GetSecurityPolicyInput{Name: aws.String("public-vectors"), Type: serverlesstypes.SecurityPolicyTypeNetwork}).
+		Return(&opensearchserverless.GetSecurityPolicyOutput{
+			SecurityPolicyDetail: &serverlesstypes.SecurityPolicyDetail{
+				Policy: serverlessdocument.NewLazyDocument([]map[string]interface{}{
+					{
+						"Rules":           []map[string]interface{}{{"ResourceType": "collection", "Resource": []string{"collection/vectors"}}},
+						"AllowFromPublic": true,
+					},
+				}),
+			},
+		}, nil)
+
+	mockServerless.EXPECT().
+		BatchGetVpcEndpoint(gomock.Any(), &opensearchserverless.BatchGetVpcEndpointInput{Ids: []string{"vpce-logs"}}).
+		Return(&opensearchserverless.BatchGetVpcEndpointOutput{
+			VpcEndpointDetails: []serverlesstypes.VpcEndpointDetail{
+				{Id: aws.String("vpce-logs"), Status: serverlesstypes.VpcEndpointStatusActive, SecurityGroupIds: []string{"sg-vpce"}},
+			},
+		}, nil)
+
+	collections, err := manager.ListServerlessCollections(ctx)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(collections) != 4 {
+		t.Fatalf("Expected 4 collections, got %d", len(collections))
+	}
+
+	logs := collections[0]
+	if logs.Type != "serverless" || logs.Public || logs.ExcludedReason != "" || logs.Endpoint != "id-logs.us-east-1.aoss.amazonaws.com" {
+		t.Errorf("Unexpected VPC collection: %+v", logs)
+	}
+	if len(logs.SecurityGroupIds) != 1 || logs.SecurityGroupIds[0] != "sg-vpce" {
+		t.Errorf("Expected VPC endpoint security groups, got %v", logs.SecurityGroupIds)
+	}
+
+	if !collections[1].Public || collections[1].ExcludedReason != "" {
+		t.Errorf("Expected vectors to be public, got %+v", collections[1])
+	}
+
+	if collections[2].ExcludedReason != "no network policy allows access" {
+		t.Errorf("Expected metrics to be excluded for network policy, got %q", collections[2].ExcludedReason)
+	}
+
+	if collections[3].ExcludedReason != "creating" {
+		t.Errorf("Expected logs-new to be excluded while creating, got %q", collections[3].ExcludedReason)
+	}
+
+	if collections[1].signingService() != "aoss" {
+		t.Errorf("Expected serverless collections to sign for aoss, got %s", collections[1].signingService())
+	}
+}
+
+func TestGetVpcEndpoints_Batches(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockServerless := mocks.NewMockOpenSearchServerlessClient(ctrl)
+
+	manager, _ := NewOpenSearchManager(ctx, OpenSearchManagerOptions{
+		OpenSearchClient:           mocks.NewMockOpenSearchClient(ctrl),
+		OpenSearchServerlessClient: mockServerless,
+		EC2Client:                  mocks.NewMockEC2Client(ctrl),
+		Region:                     "us-east-1",
+	})
+
+	var policy networkPolicyStatement
+	for i := 0; i < 150; i++ {
+		policy.SourceVPCEs = append(policy.SourceVPCEs, fmt.Sprintf("vpce-%03d", i))
+	}
+
+	var batchSizes []int
+	mockServerless.EXPECT().
+		BatchGetVpcEndpoint(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, input *opensearchserverless.BatchGetVpcEndpointInput, optFns ...func(*opensearchserverless.Options)) (*opensearchserverless.BatchGetVpcEndpointOutput, error) {
+			batchSizes = append(batchSizes, len(input.Ids))
+			var details []serverlesstypes.VpcEndpointDetail
+			for _, id := range input.Ids {
+				details = append(details, serverlesstypes.VpcEndpointDetail{Id: aws.String(id)})
+			}
+			return &opensearchserverless.BatchGetVpcEndpointOutput{VpcEndpointDetails: details}, nil
+		}).
+		Times(2)
+
+	endpoints, err := manager.getVpcEndpoints(ctx, []networkPolicyStatement{policy})
+	if err != nil {
+		t.Fatalf("getVpcEndpoints failed: %v", err)
+	}
+	if len(batchSizes) != 2 || batchSizes[0] != 100 || batchSizes[1] != 50 {
+		t.Errorf("Expected batches of 100 and 50, got %v", batchSizes)
+	}
+	if len(endpoints) != 150 {
+		t.Errorf("Expected 150 endpoints, got %d", len(endpoints))
+	}
+}
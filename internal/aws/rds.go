@@ -2,14 +2,25 @@ package aws
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
 	"strings"
+	"text/tabwriter"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/rds/auth"
 	"github.com/aws/aws-sdk-go-v2/service/ec2"
-	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
 	"github.com/aws/aws-sdk-go-v2/service/rds"
 	rdstypes "github.com/aws/aws-sdk-go-v2/service/rds/types"
+	"github.com/aws/aws-sdk-go-v2/service/rdsdata"
+	rdsdatatypes "github.com/aws/aws-sdk-go-v2/service/rdsdata/types"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	secretstypes "github.com/aws/aws-sdk-go-v2/service/secretsmanager/types"
 	ssmservice "github.com/aws/aws-sdk-go-v2/service/ssm"
 	awscconfig "github.com/blontic/awsc/internal/config"
 	"github.com/blontic/awsc/internal/debug"
@@ -20,28 +31,66 @@ import (
 type RDSClient interface {
 	DescribeDBInstances(ctx context.Context, params *rds.DescribeDBInstancesInput, optFns ...func(*rds.Options)) (*rds.DescribeDBInstancesOutput, error)
 	DescribeDBClusters(ctx context.Context, params *rds.DescribeDBClustersInput, optFns ...func(*rds.Options)) (*rds.DescribeDBClustersOutput, error)
+	DescribeDBClusterEndpoints(ctx context.Context, params *rds.DescribeDBClusterEndpointsInput, optFns ...func(*rds.Options)) (*rds.DescribeDBClusterEndpointsOutput, error)
+	DescribeDBProxies(ctx context.Context, params *rds.DescribeDBProxiesInput, optFns ...func(*rds.Options)) (*rds.DescribeDBProxiesOutput, error)
+	DescribeDBProxyEndpoints(ctx context.Context, params *rds.DescribeDBProxyEndpointsInput, optFns ...func(*rds.Options)) (*rds.DescribeDBProxyEndpointsOutput, error)
+}
+
+// RDSDataClient interface for mocking
+type RDSDataClient interface {
+	ExecuteStatement(ctx context.Context, params *rdsdata.ExecuteStatementInput, optFns ...func(*rdsdata.Options)) (*rdsdata.ExecuteStatementOutput, error)
 }
 
 // EC2Client interface for mocking
 type EC2Client interface {
 	DescribeInstances(ctx context.Context, params *ec2.DescribeInstancesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeInstancesOutput, error)
 	DescribeSecurityGroups(ctx context.Context, params *ec2.DescribeSecurityGroupsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeSecurityGroupsOutput, error)
+	DescribeVpcs(ctx context.Context, params *ec2.DescribeVpcsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeVpcsOutput, error)
+	DescribeInstanceConnectEndpoints(ctx context.Context, params *ec2.DescribeInstanceConnectEndpointsInput, optFns ...func(*ec2.Options)) (*ec2.DescribeInstanceConnectEndpointsOutput, error)
+	StartInstances(ctx context.Context, params *ec2.StartInstancesInput, optFns ...func(*ec2.Options)) (*ec2.StartInstancesOutput, error)
+	StopInstances(ctx context.Context, params *ec2.StopInstancesInput, optFns ...func(*ec2.Options)) (*ec2.StopInstancesOutput, error)
+	RebootInstances(ctx context.Context, params *ec2.RebootInstancesInput, optFns ...func(*ec2.Options)) (*ec2.RebootInstancesOutput, error)
+	DescribeImages(ctx context.Context, params *ec2.DescribeImagesInput, optFns ...func(*ec2.Options)) (*ec2.DescribeImagesOutput, error)
+	GetPasswordData(ctx context.Context, params *ec2.GetPasswordDataInput, optFns ...func(*ec2.Options)) (*ec2.GetPasswordDataOutput, error)
 }
 
 type RDSManager struct {
-	rdsClient RDSClient
-	ec2Client EC2Client
-	ssmClient *ssmservice.Client
-	region    string
+	rdsClient      RDSClient
+	rdsDataClient  RDSDataClient
+	ec2Client      EC2Client
+	ssmClient      *ssmservice.Client
+	secretsManager *SecretsManager
+	bastionFinder  *BastionFinder
+	credentials    aws.CredentialsProvider // Signs IAM auth tokens
+	region         string
 }
 
 type RDSInstance struct {
-	Identifier   string
-	Endpoint     string
-	Port         int32
-	Engine       string
-	EndpointType string // "instance", "cluster-writer", "cluster-reader"
-	ClusterName  string // For cluster endpoints
+	Identifier     string
+	Endpoint       string
+	Port           int32
+	Engine         string
+	EndpointType   string // "instance", "cluster-writer", "cluster-reader", "cluster-custom", "cluster-instance", "proxy", "proxy-endpoint"
+	ClusterName    string // For cluster endpoints and cluster member instances
+	ProxyName      string // For RDS Proxy default and additional endpoints
+	Status         string // Instance, cluster, proxy or endpoint status
+	IAMAuthEnabled bool
+	MasterSecret   string // ARN of the RDS-managed master user secret, or the proxy's secret
+	ClusterArn     string // Set on cluster writer endpoints, used as the Data API resource
+	DataAPIEnabled bool   // Cluster has the RDS Data API (HTTP endpoint) enabled
+}
+
+// IsAvailable reports whether the endpoint can currently accept connections
+func (i RDSInstance) IsAvailable() bool {
+	return i.Status == "available"
+}
+
+// RDSConnectOptions controls optional behaviour of RunConnect
+type RDSConnectOptions struct {
+	IAMAuth      bool   // Generate an IAM auth token for DBUser
+	DBUser       string // Database user for IAM auth and launched clients
+	LaunchClient bool   // Launch psql/mysql through the tunnel instead of waiting
+	Credentials  bool   // Fetch credentials from Secrets Manager
 }
 
 type BastionHost struct {
@@ -51,20 +100,30 @@ type BastionHost struct {
 }
 
 type RDSManagerOptions struct {
-	RDSClient RDSClient
-	EC2Client EC2Client
-	SSMClient *ssmservice.Client
-	Region    string
+	RDSClient     RDSClient
+	RDSDataClient RDSDataClient
+	EC2Client     EC2Client
+	SSMClient     *ssmservice.Client
+	SecretsClient SecretsManagerClient
+	Credentials   aws.CredentialsProvider
+	Region        string
 }
 
 func NewRDSManager(ctx context.Context, opts ...RDSManagerOptions) (*RDSManager, error) {
 	if len(opts) > 0 && opts[0].RDSClient != nil {
 		// Use provided clients (for testing)
 		return &RDSManager{
-			rdsClient: opts[0].RDSClient,
-			ec2Client: opts[0].EC2Client,
-			ssmClient: opts[0].SSMClient,
-			region:    opts[0].Region,
+			rdsClient:     opts[0].RDSClient,
+			rdsDataClient: opts[0].RDSDataClient,
+			ec2Client:     opts[0].EC2Client,
+			ssmClient:     opts[0].SSMClient,
+			secretsManager: &SecretsManager{
+				client: opts[0].SecretsClient,
+				region: opts[0].Region,
+			},
+			bastionFinder: NewBastionFinder(opts[0].EC2Client, opts[0].Region),
+			credentials:   opts[0].Credentials,
+			region:        opts[0].Region,
 		}, nil
 	}
 
@@ -75,14 +134,34 @@ func NewRDSManager(ctx context.Context, opts ...RDSManagerOptions) (*RDSManager,
 	}
 
 	return &RDSManager{
-		rdsClient: rds.NewFromConfig(cfg),
-		ec2Client: ec2.NewFromConfig(cfg),
-		ssmClient: ssmservice.NewFromConfig(cfg),
-		region:    cfg.Region,
+		rdsClient:     rds.NewFromConfig(cfg),
+		rdsDataClient: rdsdata.NewFromConfig(cfg),
+		ec2Client:     ec2.NewFromConfig(cfg),
+		ssmClient:     ssmservice.NewFromConfig(cfg),
+		secretsManager: &SecretsManager{
+			client: secretsmanager.NewFromConfig(cfg),
+			region: cfg.Region,
+		},
+		bastionFinder: NewBastionFinder(ec2.NewFromConfig(cfg), cfg.Region),
+		credentials:   cfg.Credentials,
+		region:        cfg.Region,
 	}, nil
 }
 
-func (r *RDSManager) RunConnect(ctx context.Context, instanceName string, localPort int32) error {
+func (r *RDSManager) RunConnect(ctx context.Context, instanceName string, localPort int32, opts ...RDSConnectOptions) error {
+	var options RDSConnectOptions
+	if len(opts) > 0 {
+		options = opts[0]
+	}
+
+	if options.IAMAuth && options.DBUser == "" {
+		return fmt.Errorf("--db-user is required for IAM authentication")
+	}
+
+	if options.IAMAuth && options.Credentials {
+		return fmt.Errorf("--iam-auth and --with-credentials cannot be used together")
+	}
+
 	// List RDS instances
 	instances, err := r.ListRDSInstances(ctx)
 	if err != nil {
@@ -93,80 +172,485 @@ func (r *RDSManager) RunConnect(ctx context.Context, instanceName string, localP
 		return fmt.Errorf("no RDS instances found")
 	}
 
-	var selectedInstance RDSInstance
+	selectedInstance, err := r.selectRDSInstance(instances, instanceName, options.IAMAuth)
+	if err != nil {
+		return err
+	}
+
+	// Find bastion hosts, falling back to an EC2 Instance Connect Endpoint
+	bastion, endpoint, err := r.findTunnel(ctx, selectedInstance)
+	if err != nil {
+		return err
+	}
 
-	// If instance name provided, try to connect directly
-	if instanceName != "" {
-		var targetInstance *RDSInstance
-		for _, instance := range instances {
-			if instance.Identifier == instanceName {
-				targetInstance = &instance
-				break
-			}
+	// Use default local port if not specified
+	if localPort == 0 {
+		localPort = selectedInstance.Port
+	}
+
+	creds := dbCredentials{Username: options.DBUser}
+	if options.IAMAuth {
+		// Token is signed for the real endpoint, not the local end of the tunnel
+		token, err := r.GenerateAuthToken(ctx, selectedInstance, options.DBUser)
+		if err != nil {
+			return err
 		}
+		creds.Password = token
+		creds.IAM = true
 
-		if targetInstance != nil {
-			fmt.Printf("Connecting to RDS instance: %s\n", targetInstance.Identifier)
-			selectedInstance = *targetInstance
-		} else {
-			fmt.Printf("RDS instance '%s' not found. Available instances:\n\n", instanceName)
-			// Fall through to show list of available instances
-		}
-	}
-
-	// If no instance name provided or instance not found, show interactive selection
-	if instanceName == "" || selectedInstance.Identifier == "" {
-		// Create instance options for selection
-		instanceOptions := make([]string, len(instances))
-		for i, instance := range instances {
-			switch instance.EndpointType {
-			case "cluster-writer":
-				instanceOptions[i] = fmt.Sprintf("%s (%s:%d) [Writer]", instance.Identifier, instance.Engine, instance.Port)
-			case "cluster-reader":
-				instanceOptions[i] = fmt.Sprintf("%s (%s:%d) [Reader]", instance.Identifier, instance.Engine, instance.Port)
-			default:
-				instanceOptions[i] = fmt.Sprintf("%s (%s:%d)", instance.Identifier, instance.Engine, instance.Port)
-			}
+		if !options.LaunchClient {
+			fmt.Printf("\nIAM auth token for %s (valid for 15 minutes):\n%s\n\n", options.DBUser, token)
 		}
+	}
 
-		// Interactive instance selection
-		selectedIndex, err := ui.RunSelector("Select RDS Instance:", instanceOptions)
+	if options.Credentials {
+		creds, err = r.GetCredentials(ctx, selectedInstance)
 		if err != nil {
-			return fmt.Errorf("error selecting instance: %v", err)
+			return err
 		}
-		if selectedIndex == -1 {
-			return fmt.Errorf("no instance selected")
+
+		if !options.LaunchClient {
+			fmt.Printf("\nUsername: %s\nPassword: %s\n\n", creds.Username, creds.Password)
 		}
+	}
 
-		selectedInstance = instances[selectedIndex]
-		fmt.Printf("✓ Selected: %s\n", selectedInstance.Identifier)
-	} else {
-		fmt.Printf("✓ Selected: %s\n", selectedInstance.Identifier)
+	if endpoint != nil {
+		if options.LaunchClient {
+			return r.RunClientWithInstanceConnect(ctx, *endpoint, selectedInstance, localPort, creds)
+		}
+		return startInstanceConnectForwarding(ctx, *endpoint, selectedInstance.Endpoint, int(selectedInstance.Port), int(localPort))
 	}
 
-	// Find bastion hosts
-	bastions, err := r.FindBastionHosts(ctx, selectedInstance)
-	if err != nil {
-		return err
+	if options.LaunchClient {
+		return r.RunClientWithPortForwarding(ctx, bastion.InstanceId, selectedInstance, localPort, creds)
 	}
 
-	if len(bastions) == 0 {
-		return fmt.Errorf("no bastion hosts available for %s", selectedInstance.Identifier)
+	// Start port forwarding
+	return r.StartPortForwarding(ctx, bastion.InstanceId, selectedInstance.Endpoint, selectedInstance.Port, localPort)
+}
+
+// findTunnel returns the first bastion that can reach the instance or, when there is
+// none, an EC2 Instance Connect Endpoint in its VPC that can
+func (r *RDSManager) findTunnel(ctx context.Context, rdsInstance RDSInstance) (BastionHost, *InstanceConnectEndpoint, error) {
+	bastions, err := r.FindBastionHosts(ctx, rdsInstance)
+	if err != nil || len(bastions) == 0 {
+		if endpoint := r.findInstanceConnectEndpoint(ctx, rdsInstance); endpoint != nil {
+			return BastionHost{}, endpoint, nil
+		}
+		if err != nil {
+			return BastionHost{}, nil, err
+		}
+		return BastionHost{}, nil, fmt.Errorf("no bastion hosts available for %s", rdsInstance.Identifier)
 	}
 
 	// Use first available bastion
 	bastion := bastions[0]
 	fmt.Printf("Using bastion: %s\n", bastion.Name)
+	return bastion, nil, nil
+}
+
+func (r *RDSManager) findInstanceConnectEndpoint(ctx context.Context, rdsInstance RDSInstance) *InstanceConnectEndpoint {
+	securityGroups, err := r.getRDSSecurityGroups(ctx, rdsInstance)
+	if err != nil {
+		return nil
+	}
+
+	return findInstanceConnectFallback(ctx, r.bastionFinder, BastionTarget{
+		Name:             rdsInstance.Identifier,
+		Service:          "RDS",
+		SecurityGroupIds: securityGroups,
+		Port:             rdsInstance.Port,
+	})
+}
+
+// DBEngine is a database engine managed through the RDS API that has its own command
+type DBEngine struct {
+	Name        string // Display name, e.g. "DocumentDB"
+	Engine      string // RDS engine identifier, e.g. "docdb"
+	DefaultPort int32
+	ClientHint  string // Example client command, formatted with the local port
+}
+
+var (
+	DocumentDBEngine = DBEngine{
+		Name:        "DocumentDB",
+		Engine:      "docdb",
+		DefaultPort: 27017,
+		ClientHint:  "mongosh --tls --tlsAllowInvalidHostnames --tlsCAFile global-bundle.pem --host localhost:%d",
+	}
+	NeptuneEngine = DBEngine{
+		Name:        "Neptune",
+		Engine:      "neptune",
+		DefaultPort: 8182,
+		ClientHint:  "curl -k https://localhost:%d/status",
+	}
+)
+
+// RunConnectEngine connects to a cluster or instance of a single engine, such as
+// DocumentDB or Neptune, using the same flow as RunConnect
+func (r *RDSManager) RunConnectEngine(ctx context.Context, engine DBEngine, name string, localPort int32) error {
+	allInstances, err := r.ListRDSInstances(ctx)
+	if err != nil {
+		return fmt.Errorf("error listing %s clusters: %v", engine.Name, err)
+	}
+
+	var instances []RDSInstance
+	for _, instance := range allInstances {
+		if instance.Engine == engine.Engine {
+			instances = append(instances, instance)
+		}
+	}
+
+	if len(instances) == 0 {
+		return fmt.Errorf("no %s clusters found", engine.Name)
+	}
+
+	selectedInstance, err := r.selectRDSInstance(instances, name, false)
+	if err != nil {
+		return err
+	}
+
+	if selectedInstance.Port == 0 {
+		selectedInstance.Port = engine.DefaultPort
+	}
+
+	// Find bastion hosts, falling back to an EC2 Instance Connect Endpoint
+	bastion, endpoint, err := r.findTunnel(ctx, selectedInstance)
+	if err != nil {
+		return err
+	}
 
 	// Use default local port if not specified
 	if localPort == 0 {
 		localPort = selectedInstance.Port
 	}
 
-	// Start port forwarding
+	// The certificate is issued for the cluster endpoint, not localhost
+	fmt.Printf("\n%s uses TLS, connect with e.g.:\n  %s\n\n", engine.Name, fmt.Sprintf(engine.ClientHint, localPort))
+
+	if endpoint != nil {
+		return startInstanceConnectForwarding(ctx, *endpoint, selectedInstance.Endpoint, int(selectedInstance.Port), int(localPort))
+	}
+
 	return r.StartPortForwarding(ctx, bastion.InstanceId, selectedInstance.Endpoint, selectedInstance.Port, localPort)
 }
 
+// RunToken generates an IAM authentication token for an RDS instance and prints it to stdout
+func (r *RDSManager) RunToken(ctx context.Context, instanceName, dbUser string) error {
+	if dbUser == "" {
+		return fmt.Errorf("--db-user is required for IAM authentication")
+	}
+
+	instances, err := r.ListRDSInstances(ctx)
+	if err != nil {
+		return fmt.Errorf("error listing RDS instances: %v", err)
+	}
+
+	if len(instances) == 0 {
+		return fmt.Errorf("no RDS instances found")
+	}
+
+	selectedInstance, err := r.selectRDSInstance(instances, instanceName, true)
+	if err != nil {
+		return err
+	}
+
+	token, err := r.GenerateAuthToken(ctx, selectedInstance, dbUser)
+	if err != nil {
+		return err
+	}
+
+	// Token only on stdout so it can be captured, e.g. PGPASSWORD=$(awsc rds token ...)
+	fmt.Println(token)
+	return nil
+}
+
+// GenerateAuthToken builds a signed IAM database authentication token for the instance endpoint
+func (r *RDSManager) GenerateAuthToken(ctx context.Context, rdsInstance RDSInstance, dbUser string) (string, error) {
+	endpoint := fmt.Sprintf("%s:%d", rdsInstance.Endpoint, rdsInstance.Port)
+	token, err := auth.BuildAuthToken(ctx, endpoint, r.region, dbUser, r.credentials)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate IAM auth token: %w", err)
+	}
+
+	return token, nil
+}
+
+// GetCredentials looks up the database credentials in Secrets Manager, using the
+// RDS-managed master user secret or else a secret tagged with the DB identifier
+func (r *RDSManager) GetCredentials(ctx context.Context, rdsInstance RDSInstance) (dbCredentials, error) {
+	secretId, err := r.findCredentialsSecret(ctx, rdsInstance)
+	if err != nil {
+		return dbCredentials{}, err
+	}
+
+	fmt.Printf("Fetching credentials from secret: %s\n", secretId)
+
+	secretValue, err := r.secretsManager.GetSecretValue(ctx, secretId)
+	if err != nil {
+		return dbCredentials{}, fmt.Errorf("error getting secret value: %v", err)
+	}
+
+	return parseDBCredentials(secretValue)
+}
+
+// credentialsSecretTag is the tag whose value names the DB instance or cluster a
+// credentials secret belongs to
+const credentialsSecretTag = "awsc:db-identifier"
+
+// findCredentialsSecret returns the ARN of the secret holding the database credentials
+func (r *RDSManager) findCredentialsSecret(ctx context.Context, rdsInstance RDSInstance) (string, error) {
+	if rdsInstance.MasterSecret != "" {
+		return rdsInstance.MasterSecret, nil
+	}
+
+	identifier := rdsInstance.Identifier
+	if rdsInstance.ClusterName != "" {
+		identifier = rdsInstance.ClusterName
+	}
+
+	// Secrets Manager filters match prefixes, so app-db would also find app-db-staging
+	secrets, err := r.secretsManager.ListSecrets(ctx,
+		secretstypes.Filter{Key: secretstypes.FilterNameStringTypeTagKey, Values: []string{credentialsSecretTag}},
+		secretstypes.Filter{Key: secretstypes.FilterNameStringTypeTagValue, Values: []string{identifier}},
+	)
+	if err != nil {
+		return "", fmt.Errorf("error listing secrets: %v", err)
+	}
+
+	var matches []Secret
+	for _, secret := range secrets {
+		if value, ok := secret.Tags[credentialsSecretTag]; ok && value == identifier {
+			matches = append(matches, secret)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return "", fmt.Errorf("no credentials secret tagged %s=%s found", credentialsSecretTag, identifier)
+	case 1:
+		return matches[0].ARN, nil
+	default:
+		names := make([]string, len(matches))
+		for i, secret := range matches {
+			names[i] = secret.Name
+		}
+		return "", fmt.Errorf("%d secrets are tagged %s=%s (%s), tag only one", len(matches), credentialsSecretTag, identifier, strings.Join(names, ", "))
+	}
+}
+
+// RunQuery executes a SQL statement against an Aurora cluster through the RDS Data API.
+// No tunnel is needed; results are printed to stdout as a table.
+func (r *RDSManager) RunQuery(ctx context.Context, clusterName, sql, database string) error {
+	if strings.TrimSpace(sql) == "" {
+		return fmt.Errorf("a SQL statement is required")
+	}
+
+	instances, err := r.ListRDSInstances(ctx)
+	if err != nil {
+		return fmt.Errorf("error listing RDS instances: %v", err)
+	}
+
+	// The Data API targets whole clusters, so offer one entry per cluster
+	var clusters []RDSInstance
+	for _, instance := range instances {
+		if instance.EndpointType == "cluster-writer" && instance.DataAPIEnabled {
+			instance.Identifier = instance.ClusterName
+			clusters = append(clusters, instance)
+		}
+	}
+
+	if len(clusters) == 0 {
+		return fmt.Errorf("no clusters with the RDS Data API enabled found")
+	}
+
+	selectedCluster, err := r.selectRDSInstance(clusters, clusterName, false)
+	if err != nil {
+		return err
+	}
+
+	secretArn, err := r.findCredentialsSecret(ctx, selectedCluster)
+	if err != nil {
+		return err
+	}
+	debug.Printf("Using credentials secret: %s\n", secretArn)
+
+	input := &rdsdata.ExecuteStatementInput{
+		ResourceArn:           aws.String(selectedCluster.ClusterArn),
+		SecretArn:             aws.String(secretArn),
+		Sql:                   aws.String(sql),
+		IncludeResultMetadata: true,
+	}
+	if database != "" {
+		input.Database = aws.String(database)
+	}
+
+	result, err := r.rdsDataClient.ExecuteStatement(ctx, input)
+	if err != nil {
+		if IsAuthError(err) {
+			if shouldReauth, reAuthErr := PromptForReauth(ctx); shouldReauth && reAuthErr == nil {
+				if reloadErr := r.reloadClients(ctx); reloadErr != nil {
+					return reloadErr
+				}
+				result, err = r.rdsDataClient.ExecuteStatement(ctx, input)
+				if err != nil {
+					return fmt.Errorf("error executing statement: %v", err)
+				}
+			} else {
+				return fmt.Errorf("error executing statement: %v", err)
+			}
+		} else {
+			return fmt.Errorf("error executing statement: %v", err)
+		}
+	}
+
+	printQueryResult(os.Stdout, result)
+	return nil
+}
+
+// printQueryResult writes the records of a Data API result as an aligned table,
+// or the number of affected rows for statements that return no result set
+func printQueryResult(w io.Writer, result *rdsdata.ExecuteStatementOutput) {
+	if len(result.ColumnMetadata) == 0 {
+		fmt.Fprintf(w, "%d row(s) affected\n", result.NumberOfRecordsUpdated)
+		return
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	columns := make([]string, len(result.ColumnMetadata))
+	for i, column := range result.ColumnMetadata {
+		columns[i] = aws.ToString(column.Label)
+		if columns[i] == "" {
+			columns[i] = aws.ToString(column.Name)
+		}
+	}
+	fmt.Fprintln(tw, strings.Join(columns, "\t"))
+
+	for _, record := range result.Records {
+		values := make([]string, len(record))
+		for i, field := range record {
+			values[i] = formatField(field)
+		}
+		fmt.Fprintln(tw, strings.Join(values, "\t"))
+	}
+	tw.Flush()
+
+	fmt.Fprintf(w, "(%d row(s))\n", len(result.Records))
+}
+
+func formatField(field rdsdatatypes.Field) string {
+	switch v := field.(type) {
+	case *rdsdatatypes.FieldMemberStringValue:
+		return v.Value
+	case *rdsdatatypes.FieldMemberLongValue:
+		return strconv.FormatInt(v.Value, 10)
+	case *rdsdatatypes.FieldMemberDoubleValue:
+		return strconv.FormatFloat(v.Value, 'f', -1, 64)
+	case *rdsdatatypes.FieldMemberBooleanValue:
+		return strconv.FormatBool(v.Value)
+	case *rdsdatatypes.FieldMemberIsNull:
+		return "NULL"
+	case *rdsdatatypes.FieldMemberBlobValue:
+		return fmt.Sprintf("<%d bytes>", len(v.Value))
+	case *rdsdatatypes.FieldMemberArrayValue:
+		return "<array>"
+	default:
+		return ""
+	}
+}
+
+// parseDBCredentials extracts username and password from an RDS credentials secret
+func parseDBCredentials(secretValue string) (dbCredentials, error) {
+	var secret struct {
+		Username string `json:"username"`
+		Password string `json:"password"`
+	}
+	if err := json.Unmarshal([]byte(secretValue), &secret); err != nil {
+		return dbCredentials{}, fmt.Errorf("secret is not a JSON username/password secret")
+	}
+	if secret.Username == "" || secret.Password == "" {
+		return dbCredentials{}, fmt.Errorf("secret does not contain a username and password")
+	}
+
+	return dbCredentials{Username: secret.Username, Password: secret.Password}, nil
+}
+
+// selectRDSInstance resolves the instance by name or falls back to interactive selection.
+// When requireIAM is set, only instances with IAM database authentication can be chosen.
+func (r *RDSManager) selectRDSInstance(instances []RDSInstance, instanceName string, requireIAM bool) (RDSInstance, error) {
+	// If instance name provided, try to connect directly
+	if instanceName != "" {
+		var targetInstance *RDSInstance
+		for _, instance := range instances {
+			if instance.Identifier == instanceName {
+				targetInstance = &instance
+				break
+			}
+		}
+
+		if targetInstance == nil {
+			fmt.Fprintf(os.Stderr, "RDS instance '%s' not found. Available instances:\n\n", instanceName)
+		} else if !targetInstance.IsAvailable() {
+			fmt.Fprintf(os.Stderr, "RDS instance '%s' is not available (status: %s). Available instances:\n\n", instanceName, targetInstance.Status)
+		} else if requireIAM && !targetInstance.IAMAuthEnabled {
+			fmt.Fprintf(os.Stderr, "IAM authentication is not enabled for RDS instance '%s'. Available instances:\n\n", instanceName)
+		} else {
+			fmt.Fprintf(os.Stderr, "Connecting to RDS instance: %s\n", targetInstance.Identifier)
+			fmt.Fprintf(os.Stderr, "✓ Selected: %s\n", targetInstance.Identifier)
+			return *targetInstance, nil
+		}
+	}
+
+	// Create instance options for selection
+	instanceOptions := make([]string, len(instances))
+	selectableOptions := make([]bool, len(instances))
+	hasSelectable := false
+	for i, instance := range instances {
+		switch instance.EndpointType {
+		case "cluster-writer":
+			instanceOptions[i] = fmt.Sprintf("%s (%s:%d) [Writer]", instance.Identifier, instance.Engine, instance.Port)
+		case "cluster-reader":
+			instanceOptions[i] = fmt.Sprintf("%s (%s:%d) [Reader]", instance.Identifier, instance.Engine, instance.Port)
+		case "cluster-custom":
+			instanceOptions[i] = fmt.Sprintf("%s (%s:%d) [Custom]", instance.Identifier, instance.Engine, instance.Port)
+		case "cluster-instance":
+			instanceOptions[i] = fmt.Sprintf("%s (%s:%d) [Member of %s]", instance.Identifier, instance.Engine, instance.Port, instance.ClusterName)
+		case "proxy":
+			instanceOptions[i] = fmt.Sprintf("%s (%s:%d) [Proxy]", instance.Identifier, instance.Engine, instance.Port)
+		case "proxy-endpoint":
+			instanceOptions[i] = fmt.Sprintf("%s (%s:%d) [Proxy Endpoint]", instance.Identifier, instance.Engine, instance.Port)
+		default:
+			instanceOptions[i] = fmt.Sprintf("%s (%s:%d)", instance.Identifier, instance.Engine, instance.Port)
+		}
+		if !instance.IsAvailable() {
+			instanceOptions[i] += fmt.Sprintf(" - %s", instance.Status)
+		}
+		selectableOptions[i] = instance.IsAvailable() && (!requireIAM || instance.IAMAuthEnabled)
+		hasSelectable = hasSelectable || selectableOptions[i]
+	}
+
+	if !hasSelectable {
+		if requireIAM {
+			return RDSInstance{}, fmt.Errorf("no available RDS instances with IAM authentication enabled found")
+		}
+		return RDSInstance{}, fmt.Errorf("no available RDS instances found")
+	}
+
+	// Interactive instance selection
+	selectedIndex, err := ui.RunSelectorWithSelectability("Select RDS Instance:", instanceOptions, selectableOptions)
+	if err != nil {
+		return RDSInstance{}, fmt.Errorf("error selecting instance: %v", err)
+	}
+	if selectedIndex == -1 {
+		return RDSInstance{}, fmt.Errorf("no instance selected")
+	}
+
+	selectedInstance := instances[selectedIndex]
+	fmt.Fprintf(os.Stderr, "✓ Selected: %s\n", selectedInstance.Identifier)
+	return selectedInstance, nil
+}
+
 func (r *RDSManager) ListRDSInstances(ctx context.Context) ([]RDSInstance, error) {
 	var instances []RDSInstance
 
@@ -184,6 +668,27 @@ func (r *RDSManager) ListRDSInstances(ctx context.Context) ([]RDSInstance, error
 	}
 	instances = append(instances, clusterEndpoints...)
 
+	// The managed master secret of an Aurora cluster lives on the cluster, not its members
+	clusterSecrets := map[string]string{}
+	for _, endpoint := range clusterEndpoints {
+		if endpoint.MasterSecret != "" {
+			clusterSecrets[endpoint.ClusterName] = endpoint.MasterSecret
+		}
+	}
+	for i := range instances {
+		if instances[i].EndpointType == "cluster-instance" && instances[i].MasterSecret == "" {
+			instances[i].MasterSecret = clusterSecrets[instances[i].ClusterName]
+		}
+	}
+
+	// Get RDS Proxy endpoints; without rds:DescribeDBProxies, or where RDS Proxy isn't
+	// offered, the instances and clusters are still listed
+	proxyEndpoints, err := r.getProxyEndpoints(ctx)
+	if err != nil {
+		debug.Printf("Error listing RDS proxies: %v\n", err)
+	}
+	instances = append(instances, proxyEndpoints...)
+
 	return instances, nil
 }
 
@@ -225,16 +730,32 @@ func (r *RDSManager) getDBInstances(ctx context.Context) ([]RDSInstance, error)
 
 	var instances []RDSInstance
 	for _, db := range allDBInstances {
-		if db.DBInstanceStatus != nil && *db.DBInstanceStatus == "available" && db.DBClusterIdentifier == nil {
-			// Only include standalone instances (not part of a cluster)
-			instances = append(instances, RDSInstance{
-				Identifier:   *db.DBInstanceIdentifier,
-				Endpoint:     *db.Endpoint.Address,
-				Port:         *db.Endpoint.Port,
-				Engine:       *db.Engine,
-				EndpointType: "instance",
-			})
+		if db.DBInstanceIdentifier == nil || db.Engine == nil {
+			continue
 		}
+
+		instance := RDSInstance{
+			Identifier:     *db.DBInstanceIdentifier,
+			Engine:         *db.Engine,
+			EndpointType:   "instance",
+			Status:         aws.ToString(db.DBInstanceStatus),
+			IAMAuthEnabled: aws.ToBool(db.IAMDatabaseAuthenticationEnabled),
+			MasterSecret:   masterSecretArn(db.MasterUserSecret),
+		}
+
+		// Instances that are still being created have no endpoint yet
+		if db.Endpoint != nil {
+			instance.Endpoint = aws.ToString(db.Endpoint.Address)
+			instance.Port = aws.ToInt32(db.Endpoint.Port)
+		}
+
+		// Cluster members can be targeted individually, e.g. to pin to a specific reader
+		if db.DBClusterIdentifier != nil {
+			instance.EndpointType = "cluster-instance"
+			instance.ClusterName = *db.DBClusterIdentifier
+		}
+
+		instances = append(instances, instance)
 	}
 
 	return instances, nil
@@ -278,63 +799,74 @@ func (r *RDSManager) getClusterEndpoints(ctx context.Context) ([]RDSInstance, er
 
 	var instances []RDSInstance
 	for _, cluster := range allClusters {
-		if cluster.Status != nil && *cluster.Status == "available" {
-			// Add cluster writer endpoint
-			if cluster.Endpoint != nil {
-				instances = append(instances, RDSInstance{
-					Identifier:   *cluster.DBClusterIdentifier + " (writer)",
-					Endpoint:     *cluster.Endpoint,
-					Port:         *cluster.Port,
-					Engine:       *cluster.Engine,
-					EndpointType: "cluster-writer",
-					ClusterName:  *cluster.DBClusterIdentifier,
-				})
-			}
+		if cluster.DBClusterIdentifier == nil || cluster.Engine == nil || cluster.Port == nil {
+			continue
+		}
+		status := aws.ToString(cluster.Status)
 
-			// Add cluster reader endpoint
-			if cluster.ReaderEndpoint != nil {
-				instances = append(instances, RDSInstance{
-					Identifier:   *cluster.DBClusterIdentifier + " (reader)",
-					Endpoint:     *cluster.ReaderEndpoint,
-					Port:         *cluster.Port,
-					Engine:       *cluster.Engine,
-					EndpointType: "cluster-reader",
-					ClusterName:  *cluster.DBClusterIdentifier,
-				})
-			}
+		// Add cluster writer endpoint
+		if cluster.Endpoint != nil {
+			instances = append(instances, RDSInstance{
+				Identifier:     *cluster.DBClusterIdentifier + " (writer)",
+				Endpoint:       *cluster.Endpoint,
+				Port:           *cluster.Port,
+				Engine:         *cluster.Engine,
+				EndpointType:   "cluster-writer",
+				ClusterName:    *cluster.DBClusterIdentifier,
+				Status:         status,
+				IAMAuthEnabled: aws.ToBool(cluster.IAMDatabaseAuthenticationEnabled),
+				MasterSecret:   masterSecretArn(cluster.MasterUserSecret),
+				ClusterArn:     aws.ToString(cluster.DBClusterArn),
+				DataAPIEnabled: aws.ToBool(cluster.HttpEndpointEnabled),
+			})
+		}
+
+		// Add cluster reader endpoint
+		if cluster.ReaderEndpoint != nil {
+			instances = append(instances, RDSInstance{
+				Identifier:     *cluster.DBClusterIdentifier + " (reader)",
+				Endpoint:       *cluster.ReaderEndpoint,
+				Port:           *cluster.Port,
+				Engine:         *cluster.Engine,
+				EndpointType:   "cluster-reader",
+				ClusterName:    *cluster.DBClusterIdentifier,
+				Status:         status,
+				IAMAuthEnabled: aws.ToBool(cluster.IAMDatabaseAuthenticationEnabled),
+				MasterSecret:   masterSecretArn(cluster.MasterUserSecret),
+			})
 		}
 	}
 
-	return instances, nil
-}
+	if len(allClusters) == 0 {
+		return instances, nil
+	}
 
-func (r *RDSManager) FindBastionHosts(ctx context.Context, rdsInstance RDSInstance) ([]BastionHost, error) {
-	// Get RDS security groups
-	rdsSecurityGroups, err := r.getRDSSecurityGroups(ctx, rdsInstance)
+	// Add custom endpoints; without rds:DescribeDBClusterEndpoints the clusters are still listed
+	customEndpoints, err := r.getCustomEndpoints(ctx, allClusters)
 	if err != nil {
-		return nil, err
+		debug.Printf("Error listing custom cluster endpoints: %v\n", err)
 	}
+	instances = append(instances, customEndpoints...)
 
-	debug.Printf("RDS %s security groups: %v\n", rdsInstance.Identifier, rdsSecurityGroups)
+	return instances, nil
+}
 
-	// Find all EC2 instances (running and stopped) that can connect to RDS
-	var allReservations []types.Reservation
-	var nextToken *string
+func (r *RDSManager) getCustomEndpoints(ctx context.Context, clusters []rdstypes.DBCluster) ([]RDSInstance, error) {
+	var allEndpoints []rdstypes.DBClusterEndpoint
+	var marker *string
 
 	for {
-		result, err := r.ec2Client.DescribeInstances(ctx, &ec2.DescribeInstancesInput{
-			NextToken: nextToken,
+		result, err := r.rdsClient.DescribeDBClusterEndpoints(ctx, &rds.DescribeDBClusterEndpointsInput{
+			Marker: marker,
 		})
 		if err != nil {
 			if IsAuthError(err) {
 				if shouldReauth, reAuthErr := PromptForReauth(ctx); shouldReauth && reAuthErr == nil {
-					// Reload all clients with fresh credentials
 					if reloadErr := r.reloadClients(ctx); reloadErr != nil {
 						return nil, reloadErr
 					}
-					// Retry after re-authentication
-					result, err = r.ec2Client.DescribeInstances(ctx, &ec2.DescribeInstancesInput{
-						NextToken: nextToken,
+					result, err = r.rdsClient.DescribeDBClusterEndpoints(ctx, &rds.DescribeDBClusterEndpointsInput{
+						Marker: marker,
 					})
 					if err != nil {
 						return nil, err
@@ -347,90 +879,233 @@ func (r *RDSManager) FindBastionHosts(ctx context.Context, rdsInstance RDSInstan
 			}
 		}
 
-		allReservations = append(allReservations, result.Reservations...)
+		allEndpoints = append(allEndpoints, result.DBClusterEndpoints...)
 
-		// Check if there are more pages
-		if result.NextToken == nil {
+		if result.Marker == nil {
 			break
 		}
-		nextToken = result.NextToken
+		marker = result.Marker
 	}
 
-	// Count and categorize instances
-	totalInstances := 0
-	runningInstances := 0
-	stoppedInstances := 0
-	var stoppedInstanceNames []string
+	clustersById := make(map[string]rdstypes.DBCluster)
+	for _, cluster := range clusters {
+		if cluster.DBClusterIdentifier != nil {
+			clustersById[*cluster.DBClusterIdentifier] = cluster
+		}
+	}
 
-	for _, reservation := range allReservations {
-		totalInstances += len(reservation.Instances)
-		for _, instance := range reservation.Instances {
-			if instance.State != nil {
-				if instance.State.Name == "running" {
-					runningInstances++
-				} else if instance.State.Name == "stopped" {
-					stoppedInstances++
-					stoppedInstanceNames = append(stoppedInstanceNames, r.getInstanceName(instance.Tags))
-				}
-			}
+	var instances []RDSInstance
+	for _, endpoint := range allEndpoints {
+		// Writer and reader endpoints are already listed from the cluster itself
+		if aws.ToString(endpoint.EndpointType) != "CUSTOM" || endpoint.Endpoint == nil {
+			continue
 		}
+
+		cluster, ok := clustersById[aws.ToString(endpoint.DBClusterIdentifier)]
+		if !ok || cluster.Engine == nil || cluster.Port == nil {
+			continue
+		}
+
+		instances = append(instances, RDSInstance{
+			Identifier:     fmt.Sprintf("%s (%s)", *cluster.DBClusterIdentifier, aws.ToString(endpoint.DBClusterEndpointIdentifier)),
+			Endpoint:       *endpoint.Endpoint,
+			Port:           *cluster.Port,
+			Engine:         *cluster.Engine,
+			EndpointType:   "cluster-custom",
+			ClusterName:    *cluster.DBClusterIdentifier,
+			Status:         aws.ToString(endpoint.Status),
+			IAMAuthEnabled: aws.ToBool(cluster.IAMDatabaseAuthenticationEnabled),
+			MasterSecret:   masterSecretArn(cluster.MasterUserSecret),
+		})
 	}
-	debug.Printf("Found %d total EC2 instances (%d running, %d stopped)\n", totalInstances, runningInstances, stoppedInstances)
 
-	var bastions []BastionHost
-	for _, reservation := range allReservations {
-		for _, instance := range reservation.Instances {
-			// Only check running instances for bastion capability
-			if instance.State == nil || instance.State.Name != "running" {
-				continue
-			}
+	return instances, nil
+}
 
-			name := r.getInstanceName(instance.Tags)
-			ec2SgIds := r.getSecurityGroupIds(instance.SecurityGroups)
-			debug.Printf("Checking instance %s (%s) with security groups: %v\n", name, *instance.InstanceId, ec2SgIds)
+func (r *RDSManager) getProxyEndpoints(ctx context.Context) ([]RDSInstance, error) {
+	var allProxies []rdstypes.DBProxy
+	var marker *string
 
-			if r.canConnectToRDS(ctx, instance.SecurityGroups, rdsSecurityGroups, rdsInstance.Port) {
-				debug.Printf("✓ Instance %s can connect to RDS\n", name)
-				bastions = append(bastions, BastionHost{
-					InstanceId:       *instance.InstanceId,
-					Name:             name,
-					SecurityGroupIds: ec2SgIds,
-				})
+	for {
+		result, err := r.rdsClient.DescribeDBProxies(ctx, &rds.DescribeDBProxiesInput{
+			Marker: marker,
+		})
+		if err != nil {
+			if IsAuthError(err) {
+				if shouldReauth, reAuthErr := PromptForReauth(ctx); shouldReauth && reAuthErr == nil {
+					if reloadErr := r.reloadClients(ctx); reloadErr != nil {
+						return nil, reloadErr
+					}
+					result, err = r.rdsClient.DescribeDBProxies(ctx, &rds.DescribeDBProxiesInput{
+						Marker: marker,
+					})
+					if err != nil {
+						return nil, err
+					}
+				} else {
+					return nil, err
+				}
 			} else {
-				debug.Printf("✗ Instance %s cannot connect to RDS\n", name)
+				return nil, err
 			}
 		}
+
+		allProxies = append(allProxies, result.DBProxies...)
+
+		if result.Marker == nil {
+			break
+		}
+		marker = result.Marker
 	}
 
-	if len(bastions) == 0 {
-		// Show stopped instances if any exist
-		if stoppedInstances > 0 {
-			fmt.Printf("\nFound %d stopped EC2 instance(s):\n", stoppedInstances)
-			for _, name := range stoppedInstanceNames {
-				fmt.Printf("- %s (stopped)\n", name)
+	proxiesByName := make(map[string]RDSInstance)
+	var instances []RDSInstance
+	for _, proxy := range allProxies {
+		if proxy.DBProxyName == nil || proxy.Endpoint == nil || proxy.EngineFamily == nil {
+			continue
+		}
+		port, err := proxyPort(*proxy.EngineFamily)
+		if err != nil {
+			debug.Printf("Skipping RDS proxy %s: %v\n", *proxy.DBProxyName, err)
+			continue
+		}
+
+		instance := RDSInstance{
+			Identifier:   *proxy.DBProxyName,
+			Endpoint:     *proxy.Endpoint,
+			Port:         port,
+			Engine:       strings.ToLower(*proxy.EngineFamily),
+			EndpointType: "proxy",
+			ProxyName:    *proxy.DBProxyName,
+			Status:       string(proxy.Status),
+		}
+
+		// Proxies authenticate with secrets; IAM auth is configured per secret
+		for _, auth := range proxy.Auth {
+			if auth.IAMAuth != "" && auth.IAMAuth != rdstypes.IAMAuthModeDisabled {
+				instance.IAMAuthEnabled = true
+			}
+			if instance.MasterSecret == "" && auth.SecretArn != nil {
+				instance.MasterSecret = *auth.SecretArn
 			}
-			fmt.Printf("\n")
 		}
 
-		if runningInstances == 0 {
-			fmt.Printf("No running EC2 instances found in region %s.\n", r.region)
-			fmt.Printf("To use RDS port forwarding, you need a running EC2 instance with:\n")
-			fmt.Printf("- SSM agent installed and configured\n")
-			fmt.Printf("- Network access to the RDS instance\n")
-			if stoppedInstances > 0 {
-				fmt.Printf("\nYou can start one of the stopped instances above and try again.\n")
-				return nil, fmt.Errorf("no running bastion hosts found - %d stopped instances available", stoppedInstances)
+		proxiesByName[instance.ProxyName] = instance
+		instances = append(instances, instance)
+	}
+
+	if len(instances) == 0 {
+		return instances, nil
+	}
+
+	// Add additional proxy endpoints, e.g. read-only endpoints
+	allEndpoints, err := r.describeProxyEndpoints(ctx)
+	if err != nil {
+		debug.Printf("Error listing RDS Proxy endpoints: %v\n", err)
+		return instances, nil
+	}
+
+	for _, endpoint := range allEndpoints {
+		// The default endpoint is already listed from the proxy itself
+		if aws.ToBool(endpoint.IsDefault) || endpoint.Endpoint == nil {
+			continue
+		}
+
+		proxy, ok := proxiesByName[aws.ToString(endpoint.DBProxyName)]
+		if !ok {
+			continue
+		}
+
+		instances = append(instances, RDSInstance{
+			Identifier:     fmt.Sprintf("%s (%s)", proxy.ProxyName, aws.ToString(endpoint.DBProxyEndpointName)),
+			Endpoint:       *endpoint.Endpoint,
+			Port:           proxy.Port,
+			Engine:         proxy.Engine,
+			EndpointType:   "proxy-endpoint",
+			ProxyName:      proxy.ProxyName,
+			Status:         string(endpoint.Status),
+			IAMAuthEnabled: proxy.IAMAuthEnabled,
+			MasterSecret:   proxy.MasterSecret,
+		})
+	}
+
+	return instances, nil
+}
+
+// describeProxyEndpoints lists the endpoints of every proxy, including the default ones
+func (r *RDSManager) describeProxyEndpoints(ctx context.Context) ([]rdstypes.DBProxyEndpoint, error) {
+	var allEndpoints []rdstypes.DBProxyEndpoint
+	var marker *string
+
+	for {
+		result, err := r.rdsClient.DescribeDBProxyEndpoints(ctx, &rds.DescribeDBProxyEndpointsInput{
+			Marker: marker,
+		})
+		if err != nil {
+			if IsAuthError(err) {
+				if shouldReauth, reAuthErr := PromptForReauth(ctx); shouldReauth && reAuthErr == nil {
+					if reloadErr := r.reloadClients(ctx); reloadErr != nil {
+						return nil, reloadErr
+					}
+					result, err = r.rdsClient.DescribeDBProxyEndpoints(ctx, &rds.DescribeDBProxyEndpointsInput{
+						Marker: marker,
+					})
+					if err != nil {
+						return nil, err
+					}
+				} else {
+					return nil, err
+				}
+			} else {
+				return nil, err
 			}
-			fmt.Printf("\nAlternatively, you can connect directly if your RDS is publicly accessible.\n")
-			return nil, fmt.Errorf("no running EC2 instances found in region %s", r.region)
-		} else {
-			fmt.Printf("Found %d running EC2 instances but none can connect to RDS %s.\n", runningInstances, rdsInstance.Identifier)
-			fmt.Printf("This usually means the security groups don't allow the connection.\n")
-			return nil, fmt.Errorf("no suitable bastion hosts found - security groups may not allow connection")
 		}
+
+		allEndpoints = append(allEndpoints, result.DBProxyEndpoints...)
+
+		if result.Marker == nil {
+			break
+		}
+		marker = result.Marker
+	}
+
+	return allEndpoints, nil
+}
+
+// proxyPort returns the port RDS Proxy listens on for an engine family
+func proxyPort(engineFamily string) (int32, error) {
+	switch rdstypes.EngineFamily(engineFamily) {
+	case rdstypes.EngineFamilyMysql:
+		return 3306, nil
+	case rdstypes.EngineFamilyPostgresql:
+		return 5432, nil
+	case rdstypes.EngineFamilySqlserver:
+		return 1433, nil
+	default:
+		return 0, fmt.Errorf("unknown RDS Proxy engine family %q", engineFamily)
+	}
+}
+
+func masterSecretArn(secret *rdstypes.MasterUserSecret) string {
+	if secret == nil {
+		return ""
+	}
+	return aws.ToString(secret.SecretArn)
+}
+
+func (r *RDSManager) FindBastionHosts(ctx context.Context, rdsInstance RDSInstance) ([]BastionHost, error) {
+	// Get RDS security groups
+	rdsSecurityGroups, err := r.getRDSSecurityGroups(ctx, rdsInstance)
+	if err != nil {
+		return nil, err
 	}
 
-	return bastions, nil
+	return r.bastionFinder.FindBastionHosts(ctx, BastionTarget{
+		Name:             rdsInstance.Identifier,
+		Service:          "RDS",
+		SecurityGroupIds: rdsSecurityGroups,
+		Port:             rdsInstance.Port,
+	})
 }
 
 func (r *RDSManager) StartPortForwarding(ctx context.Context, bastionId, rdsEndpoint string, rdsPort, localPort int32) error {
@@ -448,8 +1123,119 @@ func (r *RDSManager) StartPortForwarding(ctx context.Context, bastionId, rdsEndp
 	return pf.StartPortForwardingToRemoteHost(ctx, bastionId, rdsEndpoint, int(rdsPort), int(localPort))
 }
 
+// RunClientWithPortForwarding opens the tunnel in the background and runs the
+// engine's command line client against it until the client exits
+func (r *RDSManager) RunClientWithPortForwarding(ctx context.Context, bastionId string, rdsInstance RDSInstance, localPort int32, creds dbCredentials) error {
+	clientCmd, err := r.lookupClient(rdsInstance, localPort, creds)
+	if err != nil {
+		return err
+	}
+
+	cfg, err := awscconfig.LoadAWSConfigWithProfile(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load AWS config: %w", err)
+	}
+
+	pf := NewExternalPluginForwarder(cfg)
+
+	fmt.Printf("Starting port forwarding via %s...\n", bastionId)
+
+	return pf.RunWithPortForwarding(ctx, bastionId, rdsInstance.Endpoint, int(rdsInstance.Port), int(localPort), func() error {
+		fmt.Printf("Launching %s on localhost:%d...\n", filepath.Base(clientCmd.Path), localPort)
+		return clientCmd.Run()
+	})
+}
+
+// RunClientWithInstanceConnect is RunClientWithPortForwarding through an EC2 Instance
+// Connect Endpoint instead of a bastion
+func (r *RDSManager) RunClientWithInstanceConnect(ctx context.Context, endpoint InstanceConnectEndpoint, rdsInstance RDSInstance, localPort int32, creds dbCredentials) error {
+	clientCmd, err := r.lookupClient(rdsInstance, localPort, creds)
+	if err != nil {
+		return err
+	}
+
+	cfg, err := awscconfig.LoadAWSConfigWithProfile(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load AWS config: %w", err)
+	}
+
+	forwarder := NewEICEForwarder(cfg, endpoint)
+
+	return forwarder.RunWithPortForwarding(ctx, rdsInstance.Endpoint, int(rdsInstance.Port), int(localPort), func() error {
+		fmt.Printf("Launching %s on localhost:%d...\n", filepath.Base(clientCmd.Path), localPort)
+		return clientCmd.Run()
+	})
+}
+
+func (r *RDSManager) lookupClient(rdsInstance RDSInstance, localPort int32, creds dbCredentials) (*exec.Cmd, error) {
+	clientCmd, err := dbClientCommand(rdsInstance.Engine, localPort, creds)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := exec.LookPath(clientCmd.Path); err != nil {
+		return nil, fmt.Errorf("%s not found in PATH", clientCmd.Path)
+	}
+
+	return clientCmd, nil
+}
+
+// dbCredentials are passed to a launched database client
+type dbCredentials struct {
+	Username string
+	Password string
+	IAM      bool // Password is an IAM auth token, which requires TLS
+}
+
+// dbClientCommand builds the psql/mysql command for the engine, pointed at the local
+// end of the tunnel. The password is passed via environment, never on the command line.
+func dbClientCommand(engine string, localPort int32, creds dbCredentials) (*exec.Cmd, error) {
+	port := strconv.Itoa(int(localPort))
+	env := os.Environ()
+
+	var cmd *exec.Cmd
+	switch {
+	case strings.Contains(engine, "postgres"):
+		args := []string{"-h", "127.0.0.1", "-p", port}
+		if creds.Username != "" {
+			args = append(args, "-U", creds.Username)
+		}
+		cmd = exec.Command("psql", args...)
+		if creds.Password != "" {
+			env = append(env, "PGPASSWORD="+creds.Password)
+		}
+		if creds.IAM {
+			env = append(env, "PGSSLMODE=require")
+		}
+	case strings.Contains(engine, "mysql") || strings.Contains(engine, "mariadb"):
+		args := []string{"-h", "127.0.0.1", "-P", port}
+		if creds.Username != "" {
+			args = append(args, "-u", creds.Username)
+		}
+		if creds.IAM {
+			args = append(args, "--enable-cleartext-plugin", "--ssl-mode=REQUIRED")
+		}
+		cmd = exec.Command("mysql", args...)
+		if creds.Password != "" {
+			env = append(env, "MYSQL_PWD="+creds.Password)
+		}
+	default:
+		return nil, fmt.Errorf("launching a client is not supported for engine %s", engine)
+	}
+
+	cmd.Env = env
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd, nil
+}
+
 func (r *RDSManager) getRDSSecurityGroups(ctx context.Context, rdsInstance RDSInstance) ([]string, error) {
-	if rdsInstance.EndpointType == "cluster-writer" || rdsInstance.EndpointType == "cluster-reader" {
+	if strings.HasPrefix(rdsInstance.EndpointType, "proxy") {
+		return r.getProxySecurityGroups(ctx, rdsInstance)
+	}
+
+	if strings.HasPrefix(rdsInstance.EndpointType, "cluster-") {
 		// Get security groups from cluster
 		result, err := r.rdsClient.DescribeDBClusters(ctx, &rds.DescribeDBClustersInput{
 			DBClusterIdentifier: aws.String(rdsInstance.ClusterName),
@@ -520,115 +1306,69 @@ func (r *RDSManager) getRDSSecurityGroups(ctx context.Context, rdsInstance RDSIn
 	}
 }
 
-func (r *RDSManager) canConnectToRDS(ctx context.Context, ec2SecurityGroups []types.GroupIdentifier, rdsSecurityGroups []string, rdsPort int32) bool {
-	ec2SgIds := make(map[string]bool)
-	for _, sg := range ec2SecurityGroups {
-		ec2SgIds[*sg.GroupId] = true
-	}
+// getProxySecurityGroups returns the VPC security groups of a proxy, or of the
+// additional proxy endpoint since those can have their own security groups
+func (r *RDSManager) getProxySecurityGroups(ctx context.Context, rdsInstance RDSInstance) ([]string, error) {
+	if rdsInstance.EndpointType == "proxy-endpoint" {
+		result, err := r.rdsClient.DescribeDBProxyEndpoints(ctx, &rds.DescribeDBProxyEndpointsInput{
+			DBProxyName: aws.String(rdsInstance.ProxyName),
+		})
+		if err != nil {
+			if IsAuthError(err) {
+				if shouldReauth, reAuthErr := PromptForReauth(ctx); shouldReauth && reAuthErr == nil {
+					if reloadErr := r.reloadClients(ctx); reloadErr != nil {
+						return nil, reloadErr
+					}
+					result, err = r.rdsClient.DescribeDBProxyEndpoints(ctx, &rds.DescribeDBProxyEndpointsInput{
+						DBProxyName: aws.String(rdsInstance.ProxyName),
+					})
+					if err != nil {
+						return nil, err
+					}
+				} else {
+					return nil, err
+				}
+			} else {
+				return nil, err
+			}
+		}
 
-	// Check if any RDS security group allows inbound from EC2 security groups
-	for _, rdsSgId := range rdsSecurityGroups {
-		if r.checkSecurityGroupRules(ctx, rdsSgId, ec2SgIds, rdsPort) {
-			return true
+		for _, endpoint := range result.DBProxyEndpoints {
+			if aws.ToString(endpoint.Endpoint) == rdsInstance.Endpoint {
+				return endpoint.VpcSecurityGroupIds, nil
+			}
 		}
+		return nil, fmt.Errorf("RDS proxy endpoint not found")
 	}
 
-	return false
-}
-
-func (r *RDSManager) checkSecurityGroupRules(ctx context.Context, rdsSgId string, ec2SgIds map[string]bool, port int32) bool {
-	result, err := r.ec2Client.DescribeSecurityGroups(ctx, &ec2.DescribeSecurityGroupsInput{
-		GroupIds: []string{rdsSgId},
+	result, err := r.rdsClient.DescribeDBProxies(ctx, &rds.DescribeDBProxiesInput{
+		DBProxyName: aws.String(rdsInstance.ProxyName),
 	})
 	if err != nil {
 		if IsAuthError(err) {
 			if shouldReauth, reAuthErr := PromptForReauth(ctx); shouldReauth && reAuthErr == nil {
-				// Reload all clients with fresh credentials
 				if reloadErr := r.reloadClients(ctx); reloadErr != nil {
-					debug.Printf("  Error reloading clients: %v\n", reloadErr)
-					return false
+					return nil, reloadErr
 				}
-				// Retry after re-authentication
-				result, err = r.ec2Client.DescribeSecurityGroups(ctx, &ec2.DescribeSecurityGroupsInput{
-					GroupIds: []string{rdsSgId},
+				result, err = r.rdsClient.DescribeDBProxies(ctx, &rds.DescribeDBProxiesInput{
+					DBProxyName: aws.String(rdsInstance.ProxyName),
 				})
 				if err != nil {
-					debug.Printf("  Error describing security group %s after retry: %v\n", rdsSgId, err)
-					return false
+					return nil, err
 				}
 			} else {
-				return false
-			}
-		} else {
-			debug.Printf("  Error describing security group %s: %v\n", rdsSgId, err)
-			return false
-		}
-	}
-
-	if len(result.SecurityGroups) == 0 {
-		debug.Printf("  No security group found for %s\n", rdsSgId)
-		return false
-	}
-
-	debug.Printf("  Checking security group %s rules for port %d\n", rdsSgId, port)
-
-	for _, rule := range result.SecurityGroups[0].IpPermissions {
-		if r.ruleMatchesPort(rule, port) {
-			debug.Printf("    Rule matches port %d\n", port)
-			// Check if rule allows access from EC2 security groups
-			for _, userIdGroupPair := range rule.UserIdGroupPairs {
-				if userIdGroupPair.GroupId != nil {
-					var ec2SgList []string
-					for sgId := range ec2SgIds {
-						ec2SgList = append(ec2SgList, sgId)
-					}
-					debug.Printf("      Checking if EC2 SG %s is allowed (EC2 has: %s)\n", *userIdGroupPair.GroupId, strings.Join(ec2SgList, ", "))
-					if ec2SgIds[*userIdGroupPair.GroupId] {
-						debug.Printf("      ✓ Match found!\n")
-						return true
-					}
-				}
-			}
-			// Check for open access (0.0.0.0/0)
-			for _, ipRange := range rule.IpRanges {
-				if ipRange.CidrIp != nil {
-					debug.Printf("      Checking IP range: %s\n", *ipRange.CidrIp)
-					if *ipRange.CidrIp == "0.0.0.0/0" {
-						debug.Printf("      ✓ Open access found!\n")
-						return true
-					}
-				}
+				return nil, err
 			}
 		} else {
-			debug.Printf("    Rule does not match port %d (from:%v to:%v)\n", port, rule.FromPort, rule.ToPort)
+			return nil, err
 		}
 	}
 
-	return false
-}
-
-func (r *RDSManager) ruleMatchesPort(rule types.IpPermission, port int32) bool {
-	if rule.FromPort == nil || rule.ToPort == nil {
-		return false
-	}
-	return *rule.FromPort <= port && port <= *rule.ToPort
-}
-
-func (r *RDSManager) getInstanceName(tags []types.Tag) string {
-	for _, tag := range tags {
-		if tag.Key != nil && *tag.Key == "Name" && tag.Value != nil {
-			return *tag.Value
-		}
+	if len(result.DBProxies) == 0 {
+		return nil, fmt.Errorf("RDS proxy not found")
 	}
-	return "Unnamed"
-}
 
-func (r *RDSManager) getSecurityGroupIds(sgs []types.GroupIdentifier) []string {
-	var ids []string
-	for _, sg := range sgs {
-		ids = append(ids, *sg.GroupId)
-	}
-	return ids
+	return result.DBProxies[0].VpcSecurityGroupIds, nil
 }
 
 func (r *RDSManager) reloadClients(ctx context.Context) error {
@@ -638,8 +1378,15 @@ func (r *RDSManager) reloadClients(ctx context.Context) error {
 	}
 
 	r.rdsClient = rds.NewFromConfig(cfg)
+	r.rdsDataClient = rdsdata.NewFromConfig(cfg)
 	r.ec2Client = ec2.NewFromConfig(cfg)
 	r.ssmClient = ssmservice.NewFromConfig(cfg)
+	r.secretsManager = &SecretsManager{
+		client: secretsmanager.NewFromConfig(cfg),
+		region: cfg.Region,
+	}
+	r.bastionFinder = NewBastionFinder(r.ec2Client, cfg.Region)
+	r.credentials = cfg.Credentials
 	r.region = cfg.Region
 
 	return nil
@@ -1,7 +1,9 @@
 package aws
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"strings"
 	"testing"
 
@@ -10,6 +12,10 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
 	"github.com/aws/aws-sdk-go-v2/service/rds"
 	rdstypes "github.com/aws/aws-sdk-go-v2/service/rds/types"
+	"github.com/aws/aws-sdk-go-v2/service/rdsdata"
+	rdsdatatypes "github.com/aws/aws-sdk-go-v2/service/rdsdata/types"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	secretstypes "github.com/aws/aws-sdk-go-v2/service/secretsmanager/types"
 	"github.com/blontic/awsc/internal/aws/mocks"
 	"go.uber.org/mock/gomock"
 )
@@ -130,7 +136,7 @@ func TestRDSManager_ListRDSInstances(t *testing.T) {
 					},
 				},
 			},
-			expectedCount: 0,
+			expectedCount: 1, // Listed with its status so the selector can disable it
 			expectedError: false,
 		},
 		{
@@ -154,6 +160,12 @@ func TestRDSManager_ListRDSInstances(t *testing.T) {
 				Return(&rds.DescribeDBClustersOutput{DBClusters: []rdstypes.DBCluster{}}, nil).
 				Times(1)
 
+			// Mock DescribeDBProxies call for proxy endpoints
+			mockRDS.EXPECT().
+				DescribeDBProxies(gomock.Any(), gomock.Any()).
+				Return(&rds.DescribeDBProxiesOutput{}, nil).
+				Times(1)
+
 			instances, err := manager.ListRDSInstances(context.Background())
 
 			if tt.expectedError && err == nil {
@@ -265,313 +277,6 @@ func TestRDSManager_getRDSSecurityGroups(t *testing.T) {
 	}
 }
 
-func TestRDSManager_checkSecurityGroupRules(t *testing.T) {
-	ctrl := gomock.NewController(t)
-	defer ctrl.Finish()
-
-	mockRDS := mocks.NewMockRDSClient(ctrl)
-	mockEC2 := mocks.NewMockEC2Client(ctrl)
-
-	manager, err := NewRDSManager(context.Background(), RDSManagerOptions{
-		RDSClient: mockRDS,
-		EC2Client: mockEC2,
-		SSMClient: nil,
-		Region:    "us-east-1",
-	})
-	if err != nil {
-		t.Fatalf("Unexpected error creating manager: %v", err)
-	}
-
-	tests := []struct {
-		name         string
-		rdsSgId      string
-		ec2SgIds     map[string]bool
-		port         int32
-		mockResponse *ec2.DescribeSecurityGroupsOutput
-		mockError    error
-		expected     bool
-	}{
-		{
-			name:     "security group allows access from EC2 SG",
-			rdsSgId:  "sg-rds-123",
-			ec2SgIds: map[string]bool{"sg-ec2-456": true},
-			port:     3306,
-			mockResponse: &ec2.DescribeSecurityGroupsOutput{
-				SecurityGroups: []types.SecurityGroup{
-					{
-						IpPermissions: []types.IpPermission{
-							{
-								FromPort: aws.Int32(3306),
-								ToPort:   aws.Int32(3306),
-								UserIdGroupPairs: []types.UserIdGroupPair{
-									{GroupId: aws.String("sg-ec2-456")},
-								},
-							},
-						},
-					},
-				},
-			},
-			expected: true,
-		},
-		{
-			name:     "security group allows open access",
-			rdsSgId:  "sg-rds-123",
-			ec2SgIds: map[string]bool{"sg-ec2-456": true},
-			port:     3306,
-			mockResponse: &ec2.DescribeSecurityGroupsOutput{
-				SecurityGroups: []types.SecurityGroup{
-					{
-						IpPermissions: []types.IpPermission{
-							{
-								FromPort: aws.Int32(3306),
-								ToPort:   aws.Int32(3306),
-								IpRanges: []types.IpRange{
-									{CidrIp: aws.String("0.0.0.0/0")},
-								},
-							},
-						},
-					},
-				},
-			},
-			expected: true,
-		},
-		{
-			name:     "security group denies access",
-			rdsSgId:  "sg-rds-123",
-			ec2SgIds: map[string]bool{"sg-ec2-456": true},
-			port:     3306,
-			mockResponse: &ec2.DescribeSecurityGroupsOutput{
-				SecurityGroups: []types.SecurityGroup{
-					{
-						IpPermissions: []types.IpPermission{
-							{
-								FromPort: aws.Int32(5432),
-								ToPort:   aws.Int32(5432),
-								UserIdGroupPairs: []types.UserIdGroupPair{
-									{GroupId: aws.String("sg-ec2-different")},
-								},
-							},
-						},
-					},
-				},
-			},
-			expected: false,
-		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			mockEC2.EXPECT().
-				DescribeSecurityGroups(gomock.Any(), &ec2.DescribeSecurityGroupsInput{
-					GroupIds: []string{tt.rdsSgId},
-				}).
-				Return(tt.mockResponse, tt.mockError).
-				Times(1)
-
-			result := manager.checkSecurityGroupRules(context.Background(), tt.rdsSgId, tt.ec2SgIds, tt.port)
-
-			if result != tt.expected {
-				t.Errorf("Expected %v, got %v", tt.expected, result)
-			}
-		})
-	}
-}
-
-func TestRDSManager_ruleMatchesPort(t *testing.T) {
-	manager := &RDSManager{}
-
-	tests := []struct {
-		name     string
-		rule     types.IpPermission
-		port     int32
-		expected bool
-	}{
-		{
-			name: "port matches exactly",
-			rule: types.IpPermission{
-				FromPort: aws.Int32(3306),
-				ToPort:   aws.Int32(3306),
-			},
-			port:     3306,
-			expected: true,
-		},
-		{
-			name: "port within range",
-			rule: types.IpPermission{
-				FromPort: aws.Int32(3000),
-				ToPort:   aws.Int32(4000),
-			},
-			port:     3306,
-			expected: true,
-		},
-		{
-			name: "port outside range",
-			rule: types.IpPermission{
-				FromPort: aws.Int32(5000),
-				ToPort:   aws.Int32(6000),
-			},
-			port:     3306,
-			expected: false,
-		},
-		{
-			name: "nil ports",
-			rule: types.IpPermission{
-				FromPort: nil,
-				ToPort:   nil,
-			},
-			port:     3306,
-			expected: false,
-		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			result := manager.ruleMatchesPort(tt.rule, tt.port)
-			if result != tt.expected {
-				t.Errorf("Expected %v, got %v", tt.expected, result)
-			}
-		})
-	}
-}
-
-func TestRDSManager_getInstanceName(t *testing.T) {
-	manager := &RDSManager{}
-
-	tests := []struct {
-		name     string
-		tags     []types.Tag
-		expected string
-	}{
-		{
-			name: "has name tag",
-			tags: []types.Tag{
-				{Key: aws.String("Name"), Value: aws.String("MyInstance")},
-				{Key: aws.String("Environment"), Value: aws.String("prod")},
-			},
-			expected: "MyInstance",
-		},
-		{
-			name: "no name tag",
-			tags: []types.Tag{
-				{Key: aws.String("Environment"), Value: aws.String("prod")},
-			},
-			expected: "Unnamed",
-		},
-		{
-			name:     "nil tags",
-			tags:     nil,
-			expected: "Unnamed",
-		},
-		{
-			name:     "empty tags",
-			tags:     []types.Tag{},
-			expected: "Unnamed",
-		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			result := manager.getInstanceName(tt.tags)
-			if result != tt.expected {
-				t.Errorf("Expected %s, got %s", tt.expected, result)
-			}
-		})
-	}
-}
-
-func TestRDSManager_getSecurityGroupIds(t *testing.T) {
-	manager := &RDSManager{}
-
-	tests := []struct {
-		name     string
-		sgs      []types.GroupIdentifier
-		expected []string
-	}{
-		{
-			name: "multiple security groups",
-			sgs: []types.GroupIdentifier{
-				{GroupId: aws.String("sg-123")},
-				{GroupId: aws.String("sg-456")},
-			},
-			expected: []string{"sg-123", "sg-456"},
-		},
-		{
-			name:     "nil security groups",
-			sgs:      nil,
-			expected: []string{},
-		},
-		{
-			name:     "empty security groups",
-			sgs:      []types.GroupIdentifier{},
-			expected: []string{},
-		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			result := manager.getSecurityGroupIds(tt.sgs)
-			if len(result) != len(tt.expected) {
-				t.Errorf("Expected %d security groups, got %d", len(tt.expected), len(result))
-			}
-			for i, sg := range result {
-				if i < len(tt.expected) && sg != tt.expected[i] {
-					t.Errorf("Expected security group %s, got %s", tt.expected[i], sg)
-				}
-			}
-		})
-	}
-}
-
-func TestRDSManager_canConnectToRDS(t *testing.T) {
-	ctrl := gomock.NewController(t)
-	defer ctrl.Finish()
-
-	mockRDS := mocks.NewMockRDSClient(ctrl)
-	mockEC2 := mocks.NewMockEC2Client(ctrl)
-
-	manager, err := NewRDSManager(context.Background(), RDSManagerOptions{
-		RDSClient: mockRDS,
-		EC2Client: mockEC2,
-		SSMClient: nil,
-		Region:    "us-east-1",
-	})
-	if err != nil {
-		t.Fatalf("Unexpected error creating manager: %v", err)
-	}
-
-	ec2SecurityGroups := []types.GroupIdentifier{
-		{GroupId: aws.String("sg-ec2-123")},
-	}
-	rdsSecurityGroups := []string{"sg-rds-456"}
-
-	// Mock the security group rules check
-	mockEC2.EXPECT().
-		DescribeSecurityGroups(gomock.Any(), &ec2.DescribeSecurityGroupsInput{
-			GroupIds: []string{"sg-rds-456"},
-		}).
-		Return(&ec2.DescribeSecurityGroupsOutput{
-			SecurityGroups: []types.SecurityGroup{
-				{
-					IpPermissions: []types.IpPermission{
-						{
-							FromPort: aws.Int32(3306),
-							ToPort:   aws.Int32(3306),
-							UserIdGroupPairs: []types.UserIdGroupPair{
-								{GroupId: aws.String("sg-ec2-123")},
-							},
-						},
-					},
-				},
-			},
-		}, nil).
-		Times(1)
-
-	result := manager.canConnectToRDS(context.Background(), ec2SecurityGroups, rdsSecurityGroups, 3306)
-	if !result {
-		t.Error("Expected canConnectToRDS to return true")
-	}
-}
-
 func TestRDSManager_FindBastionHosts_EmptyResponse(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -688,7 +393,7 @@ func TestRDSManager_getClusterEndpoints(t *testing.T) {
 			expectedTypes: []string{"cluster-writer"},
 		},
 		{
-			name: "No available clusters",
+			name: "Stopped cluster is listed with its status",
 			mockResponse: &rds.DescribeDBClustersOutput{
 				DBClusters: []rdstypes.DBCluster{
 					{
@@ -700,8 +405,8 @@ func TestRDSManager_getClusterEndpoints(t *testing.T) {
 					},
 				},
 			},
-			expectedCount: 0,
-			expectedTypes: []string{},
+			expectedCount: 1,
+			expectedTypes: []string{"cluster-writer"},
 		},
 	}
 
@@ -712,6 +417,11 @@ func TestRDSManager_getClusterEndpoints(t *testing.T) {
 				Return(tt.mockResponse, nil).
 				Times(1)
 
+			mockRDS.EXPECT().
+				DescribeDBClusterEndpoints(gomock.Any(), gomock.Any()).
+				Return(&rds.DescribeDBClusterEndpointsOutput{}, nil).
+				Times(1)
+
 			instances, err := manager.getClusterEndpoints(context.Background())
 
 			if err != nil {
@@ -883,15 +593,44 @@ func TestRDSManager_ListRDSInstances_WithClusters(t *testing.T) {
 		}, nil).
 		Times(1)
 
+	// Mock cluster endpoints response (writer/reader are skipped, custom is listed)
+	mockRDS.EXPECT().
+		DescribeDBClusterEndpoints(gomock.Any(), gomock.Any()).
+		Return(&rds.DescribeDBClusterEndpointsOutput{
+			DBClusterEndpoints: []rdstypes.DBClusterEndpoint{
+				{
+					DBClusterIdentifier:         aws.String("aurora-cluster"),
+					DBClusterEndpointIdentifier: aws.String("aurora-cluster"),
+					Endpoint:                    aws.String("aurora-cluster.cluster-xyz.us-east-1.rds.amazonaws.com"),
+					EndpointType:                aws.String("WRITER"),
+					Status:                      aws.String("available"),
+				},
+				{
+					DBClusterIdentifier:         aws.String("aurora-cluster"),
+					DBClusterEndpointIdentifier: aws.String("analytics"),
+					Endpoint:                    aws.String("analytics.cluster-custom-xyz.us-east-1.rds.amazonaws.com"),
+					EndpointType:                aws.String("CUSTOM"),
+					Status:                      aws.String("available"),
+				},
+			},
+		}, nil).
+		Times(1)
+
+	// Mock proxies response
+	mockRDS.EXPECT().
+		DescribeDBProxies(gomock.Any(), gomock.Any()).
+		Return(&rds.DescribeDBProxiesOutput{}, nil).
+		Times(1)
+
 	instances, err := manager.ListRDSInstances(context.Background())
 
 	if err != nil {
 		t.Errorf("Unexpected error: %v", err)
 	}
 
-	// Should have 1 standalone + 2 cluster endpoints = 3 total
-	if len(instances) != 3 {
-		t.Errorf("Expected 3 instances, got %d", len(instances))
+	// Should have 1 standalone + 2 cluster endpoints + 1 custom endpoint = 4 total
+	if len(instances) != 4 {
+		t.Errorf("Expected 4 instances, got %d", len(instances))
 	}
 
 	// Verify we have the right mix of endpoint types
@@ -909,6 +648,9 @@ func TestRDSManager_ListRDSInstances_WithClusters(t *testing.T) {
 	if endpointTypes["cluster-reader"] != 1 {
 		t.Errorf("Expected 1 cluster reader, got %d", endpointTypes["cluster-reader"])
 	}
+	if endpointTypes["cluster-custom"] != 1 {
+		t.Errorf("Expected 1 custom endpoint, got %d", endpointTypes["cluster-custom"])
+	}
 }
 func TestRDSManager_FindBastionHosts_WithStoppedInstances(t *testing.T) {
 	ctrl := gomock.NewController(t)
@@ -996,3 +738,863 @@ func TestRDSManager_FindBastionHosts_WithStoppedInstances(t *testing.T) {
 		t.Errorf("Expected error about stopped instances, got: %v", err)
 	}
 }
+
+func TestRDSManager_ListRDSInstances_IAMAuth(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRDS := mocks.NewMockRDSClient(ctrl)
+	mockEC2 := mocks.NewMockEC2Client(ctrl)
+
+	manager, err := NewRDSManager(context.Background(), RDSManagerOptions{
+		RDSClient: mockRDS,
+		EC2Client: mockEC2,
+		Region:    "us-east-1",
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error creating manager: %v", err)
+	}
+
+	mockRDS.EXPECT().
+		DescribeDBInstances(gomock.Any(), gomock.Any()).
+		Return(&rds.DescribeDBInstancesOutput{
+			DBInstances: []rdstypes.DBInstance{
+				{
+					DBInstanceIdentifier:             aws.String("iam-db"),
+					DBInstanceStatus:                 aws.String("available"),
+					Engine:                           aws.String("postgres"),
+					IAMDatabaseAuthenticationEnabled: aws.Bool(true),
+					Endpoint: &rdstypes.Endpoint{
+						Address: aws.String("iam-db.xyz.us-east-1.rds.amazonaws.com"),
+						Port:    aws.Int32(5432),
+					},
+				},
+			},
+		}, nil).
+		Times(1)
+
+	mockRDS.EXPECT().
+		DescribeDBClusters(gomock.Any(), gomock.Any()).
+		Return(&rds.DescribeDBClustersOutput{
+			DBClusters: []rdstypes.DBCluster{
+				{
+					DBClusterIdentifier: aws.String("password-cluster"),
+					Status:              aws.String("available"),
+					Engine:              aws.String("aurora-mysql"),
+					Endpoint:            aws.String("password-cluster.cluster-xyz.us-east-1.rds.amazonaws.com"),
+					Port:                aws.Int32(3306),
+				},
+			},
+		}, nil).
+		Times(1)
+
+	mockRDS.EXPECT().
+		DescribeDBClusterEndpoints(gomock.Any(), gomock.Any()).
+		Return(&rds.DescribeDBClusterEndpointsOutput{}, nil).
+		Times(1)
+
+	mockRDS.EXPECT().
+		DescribeDBProxies(gomock.Any(), gomock.Any()).
+		Return(&rds.DescribeDBProxiesOutput{}, nil).
+		Times(1)
+
+	instances, err := manager.ListRDSInstances(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(instances) != 2 {
+		t.Fatalf("Expected 2 instances, got %d", len(instances))
+	}
+	if !instances[0].IAMAuthEnabled {
+		t.Error("Expected IAM auth to be enabled for iam-db")
+	}
+	if instances[1].IAMAuthEnabled {
+		t.Error("Expected IAM auth to be disabled for password-cluster writer")
+	}
+}
+
+func TestRDSManager_ListRDSInstances_ClusterMemberSecret(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRDS := mocks.NewMockRDSClient(ctrl)
+
+	manager, err := NewRDSManager(context.Background(), RDSManagerOptions{
+		RDSClient: mockRDS,
+		Region:    "us-east-1",
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error creating manager: %v", err)
+	}
+
+	secretArn := "arn:aws:secretsmanager:us-east-1:123456789012:secret:rds!cluster-abc"
+
+	mockRDS.EXPECT().
+		DescribeDBInstances(gomock.Any(), gomock.Any()).
+		Return(&rds.DescribeDBInstancesOutput{
+			DBInstances: []rdstypes.DBInstance{
+				{
+					DBInstanceIdentifier: aws.String("app-cluster-1"),
+					DBClusterIdentifier:  aws.String("app-cluster"),
+					DBInstanceStatus:     aws.String("available"),
+					Engine:               aws.String("aurora-postgresql"),
+					Endpoint: &rdstypes.Endpoint{
+						Address: aws.String("app-cluster-1.xyz.us-east-1.rds.amazonaws.com"),
+						Port:    aws.Int32(5432),
+					},
+				},
+			},
+		}, nil).
+		Times(1)
+	mockRDS.EXPECT().
+		DescribeDBClusters(gomock.Any(), gomock.Any()).
+		Return(&rds.DescribeDBClustersOutput{
+			DBClusters: []rdstypes.DBCluster{
+				{
+					DBClusterIdentifier: aws.String("app-cluster"),
+					Status:              aws.String("available"),
+					Engine:              aws.String("aurora-postgresql"),
+					Endpoint:            aws.String("app-cluster.cluster-xyz.us-east-1.rds.amazonaws.com"),
+					Port:                aws.Int32(5432),
+					MasterUserSecret:    &rdstypes.MasterUserSecret{SecretArn: aws.String(secretArn)},
+				},
+			},
+		}, nil).
+		Times(1)
+	mockRDS.EXPECT().
+		DescribeDBClusterEndpoints(gomock.Any(), gomock.Any()).
+		Return(&rds.DescribeDBClusterEndpointsOutput{}, nil).
+		Times(1)
+
+	mockRDS.EXPECT().
+		DescribeDBProxies(gomock.Any(), gomock.Any()).
+		Return(&rds.DescribeDBProxiesOutput{}, nil).
+		Times(1)
+
+	instances, err := manager.ListRDSInstances(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(instances) != 2 {
+		t.Fatalf("Expected member and writer, got %+v", instances)
+	}
+	if instances[0].EndpointType != "cluster-instance" || instances[0].MasterSecret != secretArn {
+		t.Errorf("Expected the member to use the cluster's master secret, got %+v", instances[0])
+	}
+}
+
+func TestRDSManager_ListRDSInstances_OptionalEndpointsDenied(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRDS := mocks.NewMockRDSClient(ctrl)
+
+	manager, err := NewRDSManager(context.Background(), RDSManagerOptions{
+		RDSClient: mockRDS,
+		Region:    "us-east-1",
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error creating manager: %v", err)
+	}
+
+	mockRDS.EXPECT().
+		DescribeDBInstances(gomock.Any(), gomock.Any()).
+		Return(&rds.DescribeDBInstancesOutput{}, nil).
+		Times(1)
+	mockRDS.EXPECT().
+		DescribeDBClusters(gomock.Any(), gomock.Any()).
+		Return(&rds.DescribeDBClustersOutput{
+			DBClusters: []rdstypes.DBCluster{
+				{
+					DBClusterIdentifier: aws.String("app-cluster"),
+					Status:              aws.String("available"),
+					Engine:              aws.String("aurora-postgresql"),
+					Endpoint:            aws.String("app-cluster.cluster-xyz.us-east-1.rds.amazonaws.com"),
+					Port:                aws.Int32(5432),
+				},
+			},
+		}, nil).
+		Times(1)
+	denied := errors.New("api error AccessDenied: User is not authorized to perform this operation")
+	mockRDS.EXPECT().
+		DescribeDBClusterEndpoints(gomock.Any(), gomock.Any()).
+		Return(nil, denied).
+		Times(1)
+	mockRDS.EXPECT().
+		DescribeDBProxies(gomock.Any(), gomock.Any()).
+		Return(nil, denied).
+		Times(1)
+
+	instances, err := manager.ListRDSInstances(context.Background())
+	if err != nil {
+		t.Fatalf("Expected the cluster to be listed without custom endpoints and proxies, got: %v", err)
+	}
+	if len(instances) != 1 || instances[0].EndpointType != "cluster-writer" {
+		t.Errorf("Expected only the cluster writer endpoint, got %+v", instances)
+	}
+}
+
+func TestRDSManager_selectRDSInstance_ByName(t *testing.T) {
+	manager := &RDSManager{}
+
+	instances := []RDSInstance{
+		{Identifier: "app-db", Engine: "postgres", Port: 5432, EndpointType: "instance", Status: "available", IAMAuthEnabled: true},
+	}
+
+	selected, err := manager.selectRDSInstance(instances, "app-db", true)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if selected.Identifier != "app-db" {
+		t.Errorf("Expected app-db, got %s", selected.Identifier)
+	}
+}
+
+func TestDBClientCommand(t *testing.T) {
+	tests := []struct {
+		name         string
+		engine       string
+		creds        dbCredentials
+		expectedBin  string
+		expectedArgs []string
+		expectedEnv  []string
+		expectError  bool
+	}{
+		{
+			name:         "postgres with IAM token",
+			engine:       "aurora-postgresql",
+			creds:        dbCredentials{Username: "app_ro", Password: "token", IAM: true},
+			expectedBin:  "psql",
+			expectedArgs: []string{"-h", "127.0.0.1", "-p", "15432", "-U", "app_ro"},
+			expectedEnv:  []string{"PGPASSWORD=token", "PGSSLMODE=require"},
+		},
+		{
+			name:         "mysql with IAM token",
+			engine:       "mysql",
+			creds:        dbCredentials{Username: "app_ro", Password: "token", IAM: true},
+			expectedBin:  "mysql",
+			expectedArgs: []string{"-h", "127.0.0.1", "-P", "15432", "-u", "app_ro", "--enable-cleartext-plugin", "--ssl-mode=REQUIRED"},
+			expectedEnv:  []string{"MYSQL_PWD=token"},
+		},
+		{
+			name:         "mariadb without credentials",
+			engine:       "mariadb",
+			expectedBin:  "mysql",
+			expectedArgs: []string{"-h", "127.0.0.1", "-P", "15432"},
+		},
+		{
+			name:        "unsupported engine",
+			engine:      "sqlserver-se",
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd, err := dbClientCommand(tt.engine, 15432, tt.creds)
+			if tt.expectError {
+				if err == nil {
+					t.Error("Expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if cmd.Args[0] != tt.expectedBin {
+				t.Errorf("Expected binary %s, got %s", tt.expectedBin, cmd.Args[0])
+			}
+			if strings.Join(cmd.Args[1:], " ") != strings.Join(tt.expectedArgs, " ") {
+				t.Errorf("Expected args %v, got %v", tt.expectedArgs, cmd.Args[1:])
+			}
+			for _, expected := range tt.expectedEnv {
+				found := false
+				for _, env := range cmd.Env {
+					if env == expected {
+						found = true
+						break
+					}
+				}
+				if !found {
+					t.Errorf("Expected environment to contain %s", expected)
+				}
+			}
+			// Password must never appear on the command line
+			for _, arg := range cmd.Args {
+				if tt.creds.Password != "" && strings.Contains(arg, tt.creds.Password) {
+					t.Errorf("Password leaked into command line argument %q", arg)
+				}
+			}
+		})
+	}
+}
+
+func TestRDSManager_GenerateAuthToken(t *testing.T) {
+	manager, err := NewRDSManager(context.Background(), RDSManagerOptions{
+		RDSClient: mocks.NewMockRDSClient(gomock.NewController(t)),
+		Credentials: aws.CredentialsProviderFunc(func(ctx context.Context) (aws.Credentials, error) {
+			return aws.Credentials{AccessKeyID: "AKIDEXAMPLE", SecretAccessKey: "secret"}, nil
+		}),
+		Region: "eu-west-1",
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error creating manager: %v", err)
+	}
+
+	token, err := manager.GenerateAuthToken(context.Background(), RDSInstance{
+		Endpoint: "db.abc123.eu-west-1.rds.amazonaws.com",
+		Port:     5432,
+	}, "app_user")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if !strings.HasPrefix(token, "db.abc123.eu-west-1.rds.amazonaws.com:5432?") {
+		t.Errorf("Expected token for the instance endpoint, got %s", token)
+	}
+	for _, want := range []string{"DBUser=app_user", "AKIDEXAMPLE%2F", "%2Feu-west-1%2Frds-db%2F"} {
+		if !strings.Contains(token, want) {
+			t.Errorf("Expected token to contain %s, got %s", want, token)
+		}
+	}
+}
+
+func TestRDSManager_GetCredentials(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRDS := mocks.NewMockRDSClient(ctrl)
+	mockSecrets := mocks.NewMockSecretsManagerClient(ctrl)
+
+	manager, err := NewRDSManager(context.Background(), RDSManagerOptions{
+		RDSClient:     mockRDS,
+		SecretsClient: mockSecrets,
+		Region:        "us-east-1",
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error creating manager: %v", err)
+	}
+
+	secretJSON := `{"username":"admin","password":"s3cret"}`
+
+	t.Run("managed master user secret", func(t *testing.T) {
+		secretArn := "arn:aws:secretsmanager:us-east-1:123456789012:secret:rds!db-abc"
+		mockSecrets.EXPECT().
+			GetSecretValue(gomock.Any(), &secretsmanager.GetSecretValueInput{SecretId: aws.String(secretArn)}).
+			Return(&secretsmanager.GetSecretValueOutput{SecretString: aws.String(secretJSON)}, nil).
+			Times(1)
+
+		creds, err := manager.GetCredentials(context.Background(), RDSInstance{Identifier: "app-db", MasterSecret: secretArn})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if creds.Username != "admin" || creds.Password != "s3cret" {
+			t.Errorf("Unexpected credentials: %+v", creds)
+		}
+	})
+
+	t.Run("secret tagged with cluster identifier", func(t *testing.T) {
+		secretArn := "arn:aws:secretsmanager:us-east-1:123456789012:secret:analytics-creds"
+		mockSecrets.EXPECT().
+			ListSecrets(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, input *secretsmanager.ListSecretsInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.ListSecretsOutput, error) {
+				if len(input.Filters) != 2 || input.Filters[0].Values[0] != credentialsSecretTag || input.Filters[1].Values[0] != "analytics" {
+					t.Errorf("Expected tag-key and tag-value filters for analytics, got %+v", input.Filters)
+				}
+				// Tag values are matched by prefix, so analytics-staging comes back too
+				return &secretsmanager.ListSecretsOutput{
+					SecretList: []secretstypes.SecretListEntry{
+						{
+							Name: aws.String("analytics-staging-creds"),
+							ARN:  aws.String(secretArn + "-staging"),
+							Tags: []secretstypes.Tag{{Key: aws.String(credentialsSecretTag), Value: aws.String("analytics-staging")}},
+						},
+						{
+							Name: aws.String("analytics-creds"),
+							ARN:  aws.String(secretArn),
+							Tags: []secretstypes.Tag{{Key: aws.String(credentialsSecretTag), Value: aws.String("analytics")}},
+						},
+					},
+				}, nil
+			}).
+			Times(1)
+		mockSecrets.EXPECT().
+			GetSecretValue(gomock.Any(), &secretsmanager.GetSecretValueInput{SecretId: aws.String(secretArn)}).
+			Return(&secretsmanager.GetSecretValueOutput{SecretString: aws.String(secretJSON)}, nil).
+			Times(1)
+
+		creds, err := manager.GetCredentials(context.Background(), RDSInstance{
+			Identifier:   "analytics (reader)",
+			EndpointType: "cluster-reader",
+			ClusterName:  "analytics",
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if creds.Username != "admin" {
+			t.Errorf("Expected username admin, got %s", creds.Username)
+		}
+	})
+
+	t.Run("several secrets tagged with the identifier", func(t *testing.T) {
+		tag := []secretstypes.Tag{{Key: aws.String(credentialsSecretTag), Value: aws.String("app-db")}}
+		mockSecrets.EXPECT().
+			ListSecrets(gomock.Any(), gomock.Any()).
+			Return(&secretsmanager.ListSecretsOutput{
+				SecretList: []secretstypes.SecretListEntry{
+					{Name: aws.String("app-db-admin"), ARN: aws.String("arn:admin"), Tags: tag},
+					{Name: aws.String("app-db-readonly"), ARN: aws.String("arn:readonly"), Tags: tag},
+				},
+			}, nil).
+			Times(1)
+
+		_, err := manager.GetCredentials(context.Background(), RDSInstance{Identifier: "app-db"})
+		if err == nil || !strings.Contains(err.Error(), "app-db-admin, app-db-readonly") {
+			t.Errorf("Expected error naming both secrets, got: %v", err)
+		}
+	})
+
+	t.Run("no secret found", func(t *testing.T) {
+		mockSecrets.EXPECT().
+			ListSecrets(gomock.Any(), gomock.Any()).
+			Return(&secretsmanager.ListSecretsOutput{}, nil).
+			Times(1)
+
+		if _, err := manager.GetCredentials(context.Background(), RDSInstance{Identifier: "orphan-db"}); err == nil {
+			t.Error("Expected error when no secret is found")
+		}
+	})
+}
+
+func TestParseDBCredentials(t *testing.T) {
+	tests := []struct {
+		name        string
+		secret      string
+		expectError bool
+	}{
+		{name: "valid secret", secret: `{"username":"admin","password":"pw","engine":"postgres"}`},
+		{name: "plain text", secret: "not-json", expectError: true},
+		{name: "missing password", secret: `{"username":"admin"}`, expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			creds, err := parseDBCredentials(tt.secret)
+			if tt.expectError {
+				if err == nil {
+					t.Error("Expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if creds.Username != "admin" || creds.Password != "pw" {
+				t.Errorf("Unexpected credentials: %+v", creds)
+			}
+		})
+	}
+}
+
+func TestRDSManager_getDBInstances_ClusterMembers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRDS := mocks.NewMockRDSClient(ctrl)
+
+	manager, err := NewRDSManager(context.Background(), RDSManagerOptions{
+		RDSClient: mockRDS,
+		Region:    "us-east-1",
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error creating manager: %v", err)
+	}
+
+	mockRDS.EXPECT().
+		DescribeDBInstances(gomock.Any(), gomock.Any()).
+		Return(&rds.DescribeDBInstancesOutput{
+			DBInstances: []rdstypes.DBInstance{
+				{
+					DBInstanceIdentifier: aws.String("aurora-cluster-reader-2"),
+					DBInstanceStatus:     aws.String("available"),
+					DBClusterIdentifier:  aws.String("aurora-cluster"),
+					Engine:               aws.String("aurora-postgresql"),
+					Endpoint: &rdstypes.Endpoint{
+						Address: aws.String("aurora-cluster-reader-2.xyz.us-east-1.rds.amazonaws.com"),
+						Port:    aws.Int32(5432),
+					},
+				},
+				{
+					DBInstanceIdentifier: aws.String("new-db"),
+					DBInstanceStatus:     aws.String("creating"),
+					Engine:               aws.String("postgres"),
+				},
+			},
+		}, nil).
+		Times(1)
+
+	instances, err := manager.getDBInstances(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(instances) != 2 {
+		t.Fatalf("Expected 2 instances, got %d", len(instances))
+	}
+
+	member := instances[0]
+	if member.EndpointType != "cluster-instance" || member.ClusterName != "aurora-cluster" {
+		t.Errorf("Expected cluster member of aurora-cluster, got %s/%s", member.EndpointType, member.ClusterName)
+	}
+	if !member.IsAvailable() {
+		t.Error("Expected cluster member to be available")
+	}
+
+	creating := instances[1]
+	if creating.IsAvailable() {
+		t.Error("Expected creating instance to be unavailable")
+	}
+	if creating.Endpoint != "" || creating.Port != 0 {
+		t.Errorf("Expected no endpoint for creating instance, got %s:%d", creating.Endpoint, creating.Port)
+	}
+}
+
+func TestRDSManager_selectRDSInstance_NoneAvailable(t *testing.T) {
+	manager := &RDSManager{}
+
+	instances := []RDSInstance{
+		{Identifier: "stopped-db", Engine: "mysql", Port: 3306, EndpointType: "instance", Status: "stopped"},
+	}
+
+	if _, err := manager.selectRDSInstance(instances, "", false); err == nil {
+		t.Error("Expected error when no instances are available")
+	}
+}
+
+func TestRDSManager_getProxyEndpoints(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRDS := mocks.NewMockRDSClient(ctrl)
+
+	manager, err := NewRDSManager(context.Background(), RDSManagerOptions{
+		RDSClient: mockRDS,
+		Region:    "us-east-1",
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error creating manager: %v", err)
+	}
+
+	mockRDS.EXPECT().
+		DescribeDBProxies(gomock.Any(), gomock.Any()).
+		Return(&rds.DescribeDBProxiesOutput{
+			DBProxies: []rdstypes.DBProxy{
+				{
+					DBProxyName:  aws.String("app-proxy"),
+					Endpoint:     aws.String("app-proxy.proxy-xyz.us-east-1.rds.amazonaws.com"),
+					EngineFamily: aws.String("POSTGRESQL"),
+					Status:       rdstypes.DBProxyStatusAvailable,
+					Auth: []rdstypes.UserAuthConfigInfo{
+						{
+							IAMAuth:   rdstypes.IAMAuthModeRequired,
+							SecretArn: aws.String("arn:aws:secretsmanager:us-east-1:123456789012:secret:app-proxy-creds"),
+						},
+					},
+				},
+			},
+		}, nil).
+		Times(1)
+
+	mockRDS.EXPECT().
+		DescribeDBProxyEndpoints(gomock.Any(), gomock.Any()).
+		Return(&rds.DescribeDBProxyEndpointsOutput{
+			DBProxyEndpoints: []rdstypes.DBProxyEndpoint{
+				{
+					DBProxyName:         aws.String("app-proxy"),
+					DBProxyEndpointName: aws.String("default"),
+					Endpoint:            aws.String("app-proxy.proxy-xyz.us-east-1.rds.amazonaws.com"),
+					IsDefault:           aws.Bool(true),
+					Status:              rdstypes.DBProxyEndpointStatusAvailable,
+				},
+				{
+					DBProxyName:         aws.String("app-proxy"),
+					DBProxyEndpointName: aws.String("read-only"),
+					Endpoint:            aws.String("read-only.endpoint.proxy-xyz.us-east-1.rds.amazonaws.com"),
+					IsDefault:           aws.Bool(false),
+					Status:              rdstypes.DBProxyEndpointStatusAvailable,
+					TargetRole:          rdstypes.DBProxyEndpointTargetRoleReadOnly,
+				},
+			},
+		}, nil).
+		Times(1)
+
+	instances, err := manager.getProxyEndpoints(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(instances) != 2 {
+		t.Fatalf("Expected 2 proxy endpoints, got %d", len(instances))
+	}
+
+	proxy := instances[0]
+	if proxy.EndpointType != "proxy" || proxy.Port != 5432 || proxy.Engine != "postgresql" {
+		t.Errorf("Unexpected proxy: %+v", proxy)
+	}
+	if !proxy.IAMAuthEnabled {
+		t.Error("Expected IAM auth to be enabled for proxy")
+	}
+	if proxy.MasterSecret != "arn:aws:secretsmanager:us-east-1:123456789012:secret:app-proxy-creds" {
+		t.Errorf("Expected proxy secret, got %s", proxy.MasterSecret)
+	}
+
+	endpoint := instances[1]
+	if endpoint.Identifier != "app-proxy (read-only)" || endpoint.EndpointType != "proxy-endpoint" {
+		t.Errorf("Unexpected proxy endpoint: %+v", endpoint)
+	}
+	if endpoint.ProxyName != "app-proxy" || !endpoint.IsAvailable() {
+		t.Errorf("Expected available endpoint of app-proxy, got %+v", endpoint)
+	}
+}
+
+func TestProxyPort(t *testing.T) {
+	tests := []struct {
+		engineFamily string
+		expected     int32
+		expectError  bool
+	}{
+		{"MYSQL", 3306, false},
+		{"POSTGRESQL", 5432, false},
+		{"SQLSERVER", 1433, false},
+		{"ORACLE", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.engineFamily, func(t *testing.T) {
+			port, err := proxyPort(tt.engineFamily)
+			if (err != nil) != tt.expectError {
+				t.Fatalf("Expected error %v, got %v", tt.expectError, err)
+			}
+			if port != tt.expected {
+				t.Errorf("Expected port %d, got %d", tt.expected, port)
+			}
+		})
+	}
+}
+
+func TestRDSManager_getRDSSecurityGroups_Proxy(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRDS := mocks.NewMockRDSClient(ctrl)
+
+	manager, err := NewRDSManager(context.Background(), RDSManagerOptions{
+		RDSClient: mockRDS,
+		Region:    "us-east-1",
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error creating manager: %v", err)
+	}
+
+	mockRDS.EXPECT().
+		DescribeDBProxies(gomock.Any(), &rds.DescribeDBProxiesInput{DBProxyName: aws.String("app-proxy")}).
+		Return(&rds.DescribeDBProxiesOutput{
+			DBProxies: []rdstypes.DBProxy{
+				{VpcSecurityGroupIds: []string{"sg-proxy-123"}},
+			},
+		}, nil).
+		Times(1)
+
+	sgs, err := manager.getRDSSecurityGroups(context.Background(), RDSInstance{
+		Identifier:   "app-proxy",
+		EndpointType: "proxy",
+		ProxyName:    "app-proxy",
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(sgs) != 1 || sgs[0] != "sg-proxy-123" {
+		t.Errorf("Expected [sg-proxy-123], got %v", sgs)
+	}
+
+	mockRDS.EXPECT().
+		DescribeDBProxyEndpoints(gomock.Any(), &rds.DescribeDBProxyEndpointsInput{DBProxyName: aws.String("app-proxy")}).
+		Return(&rds.DescribeDBProxyEndpointsOutput{
+			DBProxyEndpoints: []rdstypes.DBProxyEndpoint{
+				{
+					Endpoint:            aws.String("read-only.endpoint.proxy-xyz.us-east-1.rds.amazonaws.com"),
+					VpcSecurityGroupIds: []string{"sg-endpoint-456"},
+				},
+			},
+		}, nil).
+		Times(1)
+
+	sgs, err = manager.getRDSSecurityGroups(context.Background(), RDSInstance{
+		Identifier:   "app-proxy (read-only)",
+		Endpoint:     "read-only.endpoint.proxy-xyz.us-east-1.rds.amazonaws.com",
+		EndpointType: "proxy-endpoint",
+		ProxyName:    "app-proxy",
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(sgs) != 1 || sgs[0] != "sg-endpoint-456" {
+		t.Errorf("Expected [sg-endpoint-456], got %v", sgs)
+	}
+}
+
+func TestRDSManager_RunQuery(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRDS := mocks.NewMockRDSClient(ctrl)
+	mockData := mocks.NewMockRDSDataClient(ctrl)
+
+	manager, err := NewRDSManager(context.Background(), RDSManagerOptions{
+		RDSClient:     mockRDS,
+		RDSDataClient: mockData,
+		Region:        "us-east-1",
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error creating manager: %v", err)
+	}
+
+	clusterArn := "arn:aws:rds:us-east-1:123456789012:cluster:reporting"
+	secretArn := "arn:aws:secretsmanager:us-east-1:123456789012:secret:rds!cluster-abc"
+
+	mockRDS.EXPECT().
+		DescribeDBInstances(gomock.Any(), gomock.Any()).
+		Return(&rds.DescribeDBInstancesOutput{}, nil).
+		Times(1)
+
+	mockRDS.EXPECT().
+		DescribeDBClusters(gomock.Any(), gomock.Any()).
+		Return(&rds.DescribeDBClustersOutput{
+			DBClusters: []rdstypes.DBCluster{
+				{
+					DBClusterIdentifier: aws.String("reporting"),
+					DBClusterArn:        aws.String(clusterArn),
+					Status:              aws.String("available"),
+					Engine:              aws.String("aurora-postgresql"),
+					Port:                aws.Int32(5432),
+					Endpoint:            aws.String("reporting.cluster-xyz.us-east-1.rds.amazonaws.com"),
+					HttpEndpointEnabled: aws.Bool(true),
+					MasterUserSecret:    &rdstypes.MasterUserSecret{SecretArn: aws.String(secretArn)},
+				},
+			},
+		}, nil).
+		Times(1)
+
+	mockRDS.EXPECT().
+		DescribeDBClusterEndpoints(gomock.Any(), gomock.Any()).
+		Return(&rds.DescribeDBClusterEndpointsOutput{}, nil).
+		Times(1)
+
+	mockRDS.EXPECT().
+		DescribeDBProxies(gomock.Any(), gomock.Any()).
+		Return(&rds.DescribeDBProxiesOutput{}, nil).
+		Times(1)
+
+	mockData.EXPECT().
+		ExecuteStatement(gomock.Any(), &rdsdata.ExecuteStatementInput{
+			ResourceArn:           aws.String(clusterArn),
+			SecretArn:             aws.String(secretArn),
+			Sql:                   aws.String("SELECT 1"),
+			Database:              aws.String("app"),
+			IncludeResultMetadata: true,
+		}).
+		Return(&rdsdata.ExecuteStatementOutput{}, nil).
+		Times(1)
+
+	if err := manager.RunQuery(context.Background(), "reporting", "SELECT 1", "app"); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	if err := manager.RunQuery(context.Background(), "reporting", "  ", ""); err == nil {
+		t.Error("Expected error for empty SQL statement")
+	}
+}
+
+func TestPrintQueryResult(t *testing.T) {
+	var buf bytes.Buffer
+	printQueryResult(&buf, &rdsdata.ExecuteStatementOutput{
+		ColumnMetadata: []rdsdatatypes.ColumnMetadata{
+			{Label: aws.String("id")},
+			{Name: aws.String("name")},
+			{Label: aws.String("active")},
+		},
+		Records: [][]rdsdatatypes.Field{
+			{
+				&rdsdatatypes.FieldMemberLongValue{Value: 1},
+				&rdsdatatypes.FieldMemberStringValue{Value: "alice"},
+				&rdsdatatypes.FieldMemberBooleanValue{Value: true},
+			},
+			{
+				&rdsdatatypes.FieldMemberLongValue{Value: 2},
+				&rdsdatatypes.FieldMemberIsNull{Value: true},
+				&rdsdatatypes.FieldMemberBooleanValue{Value: false},
+			},
+		},
+	})
+
+	expected := "id  name   active\n1   alice  true\n2   NULL   false\n(2 row(s))\n"
+	if buf.String() != expected {
+		t.Errorf("Expected:\n%q\ngot:\n%q", expected, buf.String())
+	}
+
+	buf.Reset()
+	printQueryResult(&buf, &rdsdata.ExecuteStatementOutput{NumberOfRecordsUpdated: 3})
+	if buf.String() != "3 row(s) affected\n" {
+		t.Errorf("Unexpected output for update: %q", buf.String())
+	}
+}
+
+func TestRDSManager_RunConnectEngine_NoClusters(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRDS := mocks.NewMockRDSClient(ctrl)
+
+	manager, err := NewRDSManager(context.Background(), RDSManagerOptions{
+		RDSClient: mockRDS,
+		Region:    "us-east-1",
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error creating manager: %v", err)
+	}
+
+	mockRDS.EXPECT().
+		DescribeDBInstances(gomock.Any(), gomock.Any()).
+		Return(&rds.DescribeDBInstancesOutput{}, nil).
+		Times(1)
+
+	// Only a DocumentDB cluster exists
+	mockRDS.EXPECT().
+		DescribeDBClusters(gomock.Any(), gomock.Any()).
+		Return(&rds.DescribeDBClustersOutput{
+			DBClusters: []rdstypes.DBCluster{
+				{
+					DBClusterIdentifier: aws.String("docs"),
+					Status:              aws.String("available"),
+					Engine:              aws.String("docdb"),
+					Port:                aws.Int32(27017),
+					Endpoint:            aws.String("docs.cluster-xyz.us-east-1.docdb.amazonaws.com"),
+				},
+			},
+		}, nil).
+		Times(1)
+
+	mockRDS.EXPECT().
+		DescribeDBClusterEndpoints(gomock.Any(), gomock.Any()).
+		Return(&rds.DescribeDBClusterEndpointsOutput{}, nil).
+		Times(1)
+
+	mockRDS.EXPECT().
+		DescribeDBProxies(gomock.Any(), gomock.Any()).
+		Return(&rds.DescribeDBProxiesOutput{}, nil).
+		Times(1)
+
+	err = manager.RunConnectEngine(context.Background(), NeptuneEngine, "", 0)
+	if err == nil || err.Error() != "no Neptune clusters found" {
+		t.Errorf("Expected no Neptune clusters error, got: %v", err)
+	}
+}
@@ -0,0 +1,352 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/redshift"
+	redshifttypes "github.com/aws/aws-sdk-go-v2/service/redshift/types"
+	"github.com/aws/aws-sdk-go-v2/service/redshiftserverless"
+	redshiftserverlesstypes "github.com/aws/aws-sdk-go-v2/service/redshiftserverless/types"
+	awscconfig "github.com/blontic/awsc/internal/config"
+	"github.com/blontic/awsc/internal/debug"
+	"github.com/blontic/awsc/internal/ui"
+)
+
+// RedshiftClient interface for mocking
+type RedshiftClient interface {
+	DescribeClusters(ctx context.Context, params *redshift.DescribeClustersInput, optFns ...func(*redshift.Options)) (*redshift.DescribeClustersOutput, error)
+}
+
+// RedshiftServerlessClient interface for mocking
+type RedshiftServerlessClient interface {
+	ListWorkgroups(ctx context.Context, params *redshiftserverless.ListWorkgroupsInput, optFns ...func(*redshiftserverless.Options)) (*redshiftserverless.ListWorkgroupsOutput, error)
+}
+
+const redshiftDefaultPort = 5439
+
+type RedshiftManager struct {
+	redshiftClient   RedshiftClient
+	serverlessClient RedshiftServerlessClient
+	bastionFinder    *BastionFinder
+	region           string
+}
+
+type RedshiftEndpoint struct {
+	Identifier       string
+	Endpoint         string
+	Port             int32
+	Type             string // "cluster" or "serverless"
+	Status           string
+	SecurityGroupIds []string
+}
+
+// IsAvailable reports whether the endpoint can currently accept connections
+func (e RedshiftEndpoint) IsAvailable() bool {
+	// Provisioned clusters report lower case, serverless workgroups upper case
+	return e.Status == "available" || e.Status == string(redshiftserverlesstypes.WorkgroupStatusAvailable)
+}
+
+type RedshiftManagerOptions struct {
+	RedshiftClient           RedshiftClient
+	RedshiftServerlessClient RedshiftServerlessClient
+	EC2Client                EC2Client
+	Region                   string
+}
+
+func NewRedshiftManager(ctx context.Context, opts ...RedshiftManagerOptions) (*RedshiftManager, error) {
+	if len(opts) > 0 && opts[0].RedshiftClient != nil {
+		// Use provided clients (for testing)
+		return &RedshiftManager{
+			redshiftClient:   opts[0].RedshiftClient,
+			serverlessClient: opts[0].RedshiftServerlessClient,
+			bastionFinder:    NewBastionFinder(opts[0].EC2Client, opts[0].Region),
+			region:           opts[0].Region,
+		}, nil
+	}
+
+	// Production path
+	cfg, err := awscconfig.LoadAWSConfigWithProfile(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &RedshiftManager{
+		redshiftClient:   redshift.NewFromConfig(cfg),
+		serverlessClient: redshiftserverless.NewFromConfig(cfg),
+		bastionFinder:    NewBastionFinder(ec2.NewFromConfig(cfg), cfg.Region),
+		region:           cfg.Region,
+	}, nil
+}
+
+func (r *RedshiftManager) RunConnect(ctx context.Context, name string, localPort int32) error {
+	endpoints, err := r.ListRedshiftEndpoints(ctx)
+	if err != nil {
+		return fmt.Errorf("error listing Redshift clusters: %v", err)
+	}
+
+	if len(endpoints) == 0 {
+		return fmt.Errorf("no Redshift clusters or serverless workgroups found")
+	}
+
+	selectedEndpoint, err := r.selectRedshiftEndpoint(endpoints, name)
+	if err != nil {
+		return err
+	}
+
+	// Find bastion hosts
+	bastions, err := r.bastionFinder.FindBastionHosts(ctx, BastionTarget{
+		Name:             selectedEndpoint.Identifier,
+		Service:          "Redshift",
+		SecurityGroupIds: selectedEndpoint.SecurityGroupIds,
+		Port:             selectedEndpoint.Port,
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(bastions) == 0 {
+		return fmt.Errorf("no bastion hosts available for %s", selectedEndpoint.Identifier)
+	}
+
+	// Use first available bastion
+	bastion := bastions[0]
+	fmt.Printf("Using bastion: %s\n", bastion.Name)
+
+	// Use default local port if not specified
+	if localPort == 0 {
+		localPort = selectedEndpoint.Port
+	}
+
+	return r.StartPortForwarding(ctx, bastion.InstanceId, selectedEndpoint.Endpoint, selectedEndpoint.Port, localPort)
+}
+
+func (r *RedshiftManager) selectRedshiftEndpoint(endpoints []RedshiftEndpoint, name string) (RedshiftEndpoint, error) {
+	// If name provided, try to connect directly
+	if name != "" {
+		var target *RedshiftEndpoint
+		for _, endpoint := range endpoints {
+			if endpoint.Identifier == name {
+				target = &endpoint
+				break
+			}
+		}
+
+		if target == nil {
+			fmt.Printf("Redshift cluster or workgroup '%s' not found. Available clusters and workgroups:\n\n", name)
+		} else if !target.IsAvailable() {
+			fmt.Printf("Redshift cluster or workgroup '%s' is not available (status: %s). Available clusters and workgroups:\n\n", name, target.Status)
+		} else {
+			fmt.Printf("Connecting to Redshift: %s\n", target.Identifier)
+			fmt.Printf("✓ Selected: %s\n", target.Identifier)
+			return *target, nil
+		}
+	}
+
+	options := make([]string, len(endpoints))
+	selectable := make([]bool, len(endpoints))
+	hasSelectable := false
+	for i, endpoint := range endpoints {
+		options[i] = fmt.Sprintf("%s (redshift:%d)", endpoint.Identifier, endpoint.Port)
+		if endpoint.Type == "serverless" {
+			options[i] += " [Serverless]"
+		}
+		if !endpoint.IsAvailable() {
+			options[i] += fmt.Sprintf(" - %s", endpoint.Status)
+		}
+		selectable[i] = endpoint.IsAvailable()
+		hasSelectable = hasSelectable || selectable[i]
+	}
+
+	if !hasSelectable {
+		return RedshiftEndpoint{}, fmt.Errorf("no available Redshift clusters or workgroups found")
+	}
+
+	selectedIndex, err := ui.RunSelectorWithSelectability("Select Redshift Cluster:", options, selectable)
+	if err != nil {
+		return RedshiftEndpoint{}, fmt.Errorf("error selecting cluster: %v", err)
+	}
+	if selectedIndex == -1 {
+		return RedshiftEndpoint{}, fmt.Errorf("no cluster selected")
+	}
+
+	selected := endpoints[selectedIndex]
+	fmt.Printf("✓ Selected: %s\n", selected.Identifier)
+	return selected, nil
+}
+
+// ListRedshiftEndpoints returns provisioned clusters followed by serverless workgroups
+func (r *RedshiftManager) ListRedshiftEndpoints(ctx context.Context) ([]RedshiftEndpoint, error) {
+	clusters, err := r.getClusters(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Redshift Serverless isn't offered in every region or allowed for every role, so
+	// clusters are still listed when workgroups can't be
+	workgroups, err := r.getWorkgroups(ctx)
+	if err != nil {
+		debug.Printf("Error listing Redshift Serverless workgroups: %v\n", err)
+	}
+
+	var endpoints []RedshiftEndpoint
+	for _, cluster := range clusters {
+		if cluster.ClusterIdentifier == nil {
+			continue
+		}
+
+		endpoint := RedshiftEndpoint{
+			Identifier: *cluster.ClusterIdentifier,
+			Port:       redshiftDefaultPort,
+			Type:       "cluster",
+			Status:     aws.ToString(cluster.ClusterStatus),
+		}
+		// Clusters that are still being created have no endpoint yet
+		if cluster.Endpoint != nil {
+			endpoint.Endpoint = aws.ToString(cluster.Endpoint.Address)
+			if cluster.Endpoint.Port != nil {
+				endpoint.Port = *cluster.Endpoint.Port
+			}
+		}
+		for _, sg := range cluster.VpcSecurityGroups {
+			if sg.VpcSecurityGroupId != nil {
+				endpoint.SecurityGroupIds = append(endpoint.SecurityGroupIds, *sg.VpcSecurityGroupId)
+			}
+		}
+
+		endpoints = append(endpoints, endpoint)
+	}
+
+	for _, workgroup := range workgroups {
+		if workgroup.WorkgroupName == nil {
+			continue
+		}
+
+		endpoint := RedshiftEndpoint{
+			Identifier:       *workgroup.WorkgroupName,
+			Port:             redshiftDefaultPort,
+			Type:             "serverless",
+			Status:           string(workgroup.Status),
+			SecurityGroupIds: workgroup.SecurityGroupIds,
+		}
+		if workgroup.Endpoint != nil {
+			endpoint.Endpoint = aws.ToString(workgroup.Endpoint.Address)
+			if workgroup.Endpoint.Port != nil {
+				endpoint.Port = *workgroup.Endpoint.Port
+			}
+		}
+
+		endpoints = append(endpoints, endpoint)
+	}
+
+	return endpoints, nil
+}
+
+func (r *RedshiftManager) getClusters(ctx context.Context) ([]redshifttypes.Cluster, error) {
+	var allClusters []redshifttypes.Cluster
+	var marker *string
+
+	for {
+		result, err := r.redshiftClient.DescribeClusters(ctx, &redshift.DescribeClustersInput{
+			Marker: marker,
+		})
+		if err != nil {
+			if IsAuthError(err) {
+				if shouldReauth, reAuthErr := PromptForReauth(ctx); shouldReauth && reAuthErr == nil {
+					if reloadErr := r.reloadClients(ctx); reloadErr != nil {
+						return nil, reloadErr
+					}
+					result, err = r.redshiftClient.DescribeClusters(ctx, &redshift.DescribeClustersInput{
+						Marker: marker,
+					})
+					if err != nil {
+						return nil, err
+					}
+				} else {
+					return nil, err
+				}
+			} else {
+				return nil, err
+			}
+		}
+
+		allClusters = append(allClusters, result.Clusters...)
+
+		if result.Marker == nil {
+			break
+		}
+		marker = result.Marker
+	}
+
+	return allClusters, nil
+}
+
+func (r *RedshiftManager) getWorkgroups(ctx context.Context) ([]redshiftserverlesstypes.Workgroup, error) {
+	var allWorkgroups []redshiftserverlesstypes.Workgroup
+	var nextToken *string
+
+	for {
+		result, err := r.serverlessClient.ListWorkgroups(ctx, &redshiftserverless.ListWorkgroupsInput{
+			NextToken: nextToken,
+		})
+		if err != nil {
+			if IsAuthError(err) {
+				if shouldReauth, reAuthErr := PromptForReauth(ctx); shouldReauth && reAuthErr == nil {
+					if reloadErr := r.reloadClients(ctx); reloadErr != nil {
+						return nil, reloadErr
+					}
+					result, err = r.serverlessClient.ListWorkgroups(ctx, &redshiftserverless.ListWorkgroupsInput{
+						NextToken: nextToken,
+					})
+					if err != nil {
+						return nil, err
+					}
+				} else {
+					return nil, err
+				}
+			} else {
+				return nil, err
+			}
+		}
+
+		allWorkgroups = append(allWorkgroups, result.Workgroups...)
+
+		if result.NextToken == nil {
+			break
+		}
+		nextToken = result.NextToken
+	}
+
+	return allWorkgroups, nil
+}
+
+func (r *RedshiftManager) StartPortForwarding(ctx context.Context, bastionId, redshiftEndpoint string, redshiftPort, localPort int32) error {
+	// Create port forwarder
+	cfg, err := awscconfig.LoadAWSConfigWithProfile(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load AWS config: %w", err)
+	}
+
+	pf := NewExternalPluginForwarder(cfg)
+
+	fmt.Printf("Starting port forwarding via %s...\n", bastionId)
+
+	// Start port forwarding to remote host through bastion
+	return pf.StartPortForwardingToRemoteHost(ctx, bastionId, redshiftEndpoint, int(redshiftPort), int(localPort))
+}
+
+func (r *RedshiftManager) reloadClients(ctx context.Context) error {
+	cfg, err := awscconfig.LoadAWSConfigWithProfile(ctx)
+	if err != nil {
+		return err
+	}
+
+	r.redshiftClient = redshift.NewFromConfig(cfg)
+	r.serverlessClient = redshiftserverless.NewFromConfig(cfg)
+	r.bastionFinder = NewBastionFinder(ec2.NewFromConfig(cfg), cfg.Region)
+	r.region = cfg.Region
+
+	return nil
+}
@@ -0,0 +1,173 @@
+package aws
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/redshift"
+	redshifttypes "github.com/aws/aws-sdk-go-v2/service/redshift/types"
+	"github.com/aws/aws-sdk-go-v2/service/redshiftserverless"
+	redshiftserverlesstypes "github.com/aws/aws-sdk-go-v2/service/redshiftserverless/types"
+	"github.com/blontic/awsc/internal/aws/mocks"
+	"go.uber.org/mock/gomock"
+)
+
+func TestNewRedshiftManager(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	manager, err := NewRedshiftManager(context.Background(), RedshiftManagerOptions{
+		RedshiftClient:           mocks.NewMockRedshiftClient(ctrl),
+		RedshiftServerlessClient: mocks.NewMockRedshiftServerlessClient(ctrl),
+		EC2Client:                mocks.NewMockEC2Client(ctrl),
+		Region:                   "us-east-1",
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if manager.region != "us-east-1" {
+		t.Errorf("Expected region us-east-1, got %s", manager.region)
+	}
+}
+
+func TestRedshiftManager_ListRedshiftEndpoints(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRedshift := mocks.NewMockRedshiftClient(ctrl)
+	mockServerless := mocks.NewMockRedshiftServerlessClient(ctrl)
+
+	manager, err := NewRedshiftManager(context.Background(), RedshiftManagerOptions{
+		RedshiftClient:           mockRedshift,
+		RedshiftServerlessClient: mockServerless,
+		Region:                   "us-east-1",
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error creating manager: %v", err)
+	}
+
+	mockRedshift.EXPECT().
+		DescribeClusters(gomock.Any(), gomock.Any()).
+		Return(&redshift.DescribeClustersOutput{
+			Clusters: []redshifttypes.Cluster{
+				{
+					ClusterIdentifier: aws.String("warehouse"),
+					ClusterStatus:     aws.String("available"),
+					Endpoint: &redshifttypes.Endpoint{
+						Address: aws.String("warehouse.abc.us-east-1.redshift.amazonaws.com"),
+						Port:    aws.Int32(5439),
+					},
+					VpcSecurityGroups: []redshifttypes.VpcSecurityGroupMembership{
+						{VpcSecurityGroupId: aws.String("sg-warehouse")},
+					},
+				},
+				{
+					ClusterIdentifier: aws.String("paused"),
+					ClusterStatus:     aws.String("paused"),
+				},
+			},
+		}, nil).
+		Times(1)
+
+	mockServerless.EXPECT().
+		ListWorkgroups(gomock.Any(), gomock.Any()).
+		Return(&redshiftserverless.ListWorkgroupsOutput{
+			Workgroups: []redshiftserverlesstypes.Workgroup{
+				{
+					WorkgroupName: aws.String("adhoc"),
+					Status:        redshiftserverlesstypes.WorkgroupStatusAvailable,
+					Endpoint: &redshiftserverlesstypes.Endpoint{
+						Address: aws.String("adhoc.123456789012.us-east-1.redshift-serverless.amazonaws.com"),
+						Port:    aws.Int32(5440),
+					},
+					SecurityGroupIds: []string{"sg-adhoc"},
+				},
+			},
+		}, nil).
+		Times(1)
+
+	endpoints, err := manager.ListRedshiftEndpoints(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(endpoints) != 3 {
+		t.Fatalf("Expected 3 endpoints, got %d", len(endpoints))
+	}
+
+	if endpoints[0].Identifier != "warehouse" || endpoints[0].Type != "cluster" || !endpoints[0].IsAvailable() {
+		t.Errorf("Unexpected cluster endpoint: %+v", endpoints[0])
+	}
+	if len(endpoints[0].SecurityGroupIds) != 1 || endpoints[0].SecurityGroupIds[0] != "sg-warehouse" {
+		t.Errorf("Expected cluster security groups, got %v", endpoints[0].SecurityGroupIds)
+	}
+
+	if endpoints[1].IsAvailable() || endpoints[1].Port != 5439 {
+		t.Errorf("Expected paused cluster to be unavailable on the default port, got %+v", endpoints[1])
+	}
+
+	serverless := endpoints[2]
+	if serverless.Type != "serverless" || serverless.Port != 5440 || !serverless.IsAvailable() {
+		t.Errorf("Unexpected serverless endpoint: %+v", serverless)
+	}
+}
+
+func TestRedshiftManager_ListRedshiftEndpoints_ServerlessDenied(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRedshift := mocks.NewMockRedshiftClient(ctrl)
+	mockServerless := mocks.NewMockRedshiftServerlessClient(ctrl)
+
+	manager, err := NewRedshiftManager(context.Background(), RedshiftManagerOptions{
+		RedshiftClient:           mockRedshift,
+		RedshiftServerlessClient: mockServerless,
+		Region:                   "us-east-1",
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error creating manager: %v", err)
+	}
+
+	mockRedshift.EXPECT().
+		DescribeClusters(gomock.Any(), gomock.Any()).
+		Return(&redshift.DescribeClustersOutput{
+			Clusters: []redshifttypes.Cluster{
+				{ClusterIdentifier: aws.String("warehouse"), ClusterStatus: aws.String("available")},
+			},
+		}, nil).
+		Times(1)
+	mockServerless.EXPECT().
+		ListWorkgroups(gomock.Any(), gomock.Any()).
+		Return(nil, errors.New("api error AccessDeniedException: User is not authorized to perform: redshift-serverless:ListWorkgroups")).
+		Times(1)
+
+	endpoints, err := manager.ListRedshiftEndpoints(context.Background())
+	if err != nil {
+		t.Fatalf("Expected clusters to be listed without workgroups, got: %v", err)
+	}
+	if len(endpoints) != 1 || endpoints[0].Identifier != "warehouse" {
+		t.Errorf("Expected only the warehouse cluster, got %+v", endpoints)
+	}
+}
+
+func TestRedshiftManager_selectRedshiftEndpoint_ByName(t *testing.T) {
+	manager := &RedshiftManager{}
+
+	endpoints := []RedshiftEndpoint{
+		{Identifier: "warehouse", Port: 5439, Type: "cluster", Status: "available"},
+		{Identifier: "adhoc", Port: 5439, Type: "serverless", Status: "MODIFYING"},
+	}
+
+	selected, err := manager.selectRedshiftEndpoint(endpoints, "warehouse")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if selected.Identifier != "warehouse" {
+		t.Errorf("Expected warehouse, got %s", selected.Identifier)
+	}
+
+	if _, err := manager.selectRedshiftEndpoint(endpoints[1:], ""); err == nil {
+		t.Error("Expected error when no endpoints are available")
+	}
+}
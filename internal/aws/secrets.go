@@ -27,6 +27,7 @@ type Secret struct {
 	Name        string
 	Description string
 	ARN         string
+	Tags        map[string]string
 }
 
 type SecretsManagerOptions struct {
@@ -55,13 +56,15 @@ func NewSecretsManager(ctx context.Context, opts ...SecretsManagerOptions) (*Sec
 	}, nil
 }
 
-func (s *SecretsManager) ListSecrets(ctx context.Context) ([]Secret, error) {
+// ListSecrets lists all secrets, optionally narrowed by server-side filters
+func (s *SecretsManager) ListSecrets(ctx context.Context, filters ...secretstypes.Filter) ([]Secret, error) {
 	var allSecrets []secretstypes.SecretListEntry
 	var nextToken *string
 
 	for {
 		result, err := s.client.ListSecrets(ctx, &secretsmanager.ListSecretsInput{
 			NextToken: nextToken,
+			Filters:   filters,
 		})
 		if err != nil {
 			if IsAuthError(err) {
@@ -73,6 +76,7 @@ func (s *SecretsManager) ListSecrets(ctx context.Context) ([]Secret, error) {
 					// Retry after re-authentication
 					result, err = s.client.ListSecrets(ctx, &secretsmanager.ListSecretsInput{
 						NextToken: nextToken,
+						Filters:   filters,
 					})
 					if err != nil {
 						return nil, err
@@ -101,10 +105,16 @@ func (s *SecretsManager) ListSecrets(ctx context.Context) ([]Secret, error) {
 			description = *secret.Description
 		}
 
+		tags := make(map[string]string, len(secret.Tags))
+		for _, tag := range secret.Tags {
+			tags[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+		}
+
 		secrets = append(secrets, Secret{
 			Name:        *secret.Name,
 			Description: description,
 			ARN:         *secret.ARN,
+			Tags:        tags,
 		})
 	}
 
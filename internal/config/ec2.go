@@ -0,0 +1,83 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// EC2SessionRule sets defaults for ec2 connect sessions. A rule without an account or tag
+// applies to every instance.
+//
+//	ec2:
+//	  sessions:
+//	    - account: production        # account ID or name
+//	      tag: Role=db               # Key=Value on the instance
+//	      document: Custom-BashLogin
+//	      user: dba
+//	      parameters: ["shell=bash"]
+type EC2SessionRule struct {
+	Account    string   `mapstructure:"account"`
+	Tag        string   `mapstructure:"tag"`
+	Document   string   `mapstructure:"document"`
+	User       string   `mapstructure:"user"`
+	Parameters []string `mapstructure:"parameters"` // key=value, since config keys lose their case
+}
+
+// EC2SessionRules returns the ec2.sessions rules in the order they're configured
+func EC2SessionRules() ([]EC2SessionRule, error) {
+	var rules []EC2SessionRule
+	if err := viper.UnmarshalKey("ec2.sessions", &rules); err != nil {
+		return nil, fmt.Errorf("invalid ec2.sessions config: %w", err)
+	}
+	return rules, nil
+}
+
+// Matches reports whether the rule applies to an instance with tags in the given account
+func (r EC2SessionRule) Matches(accountId, accountName string, tags map[string]string) bool {
+	if !accountMatches(r.Account, accountId, accountName) {
+		return false
+	}
+	if r.Tag != "" {
+		key, value, _ := strings.Cut(r.Tag, "=")
+		if tagValue, ok := tags[key]; !ok || tagValue != value {
+			return false
+		}
+	}
+	return true
+}
+
+// EC2ListDefaults sets the instance filters and selector tag columns used when none are
+// given on the command line, for every account or only one
+//
+//	ec2:
+//	  lists:
+//	    - account: production
+//	      filters: ["tag:Team=platform", "instance-state-name=running"]
+//	      tag_columns: [Env, Role]
+type EC2ListDefaults struct {
+	Account    string   `mapstructure:"account"`
+	Filters    []string `mapstructure:"filters"` // Name=Value[,Value], as for --filter
+	TagColumns []string `mapstructure:"tag_columns"`
+}
+
+// EC2ListDefaultRules returns the ec2.lists entries in the order they're configured
+func EC2ListDefaultRules() ([]EC2ListDefaults, error) {
+	var rules []EC2ListDefaults
+	if err := viper.UnmarshalKey("ec2.lists", &rules); err != nil {
+		return nil, fmt.Errorf("invalid ec2.lists config: %w", err)
+	}
+	return rules, nil
+}
+
+// Matches reports whether the defaults apply to the given account
+func (d EC2ListDefaults) Matches(accountId, accountName string) bool {
+	return accountMatches(d.Account, accountId, accountName)
+}
+
+// accountMatches reports whether a configured account ID or name is the given account;
+// an empty one matches every account
+func accountMatches(account, accountId, accountName string) bool {
+	return account == "" || account == accountId || strings.EqualFold(account, accountName)
+}
@@ -0,0 +1,75 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+func TestEC2SessionRules(t *testing.T) {
+	viper.Set("ec2.sessions", []map[string]interface{}{
+		{"account": "123456789012", "document": "Custom-BashLogin", "parameters": []string{"shell=bash"}},
+		{"tag": "Role=db", "user": "dba"},
+	})
+	defer viper.Reset()
+
+	rules, err := EC2SessionRules()
+	if err != nil {
+		t.Fatalf("EC2SessionRules failed: %v", err)
+	}
+	if len(rules) != 2 {
+		t.Fatalf("Expected 2 rules, got %d", len(rules))
+	}
+	if rules[0].Document != "Custom-BashLogin" || len(rules[0].Parameters) != 1 || rules[1].User != "dba" {
+		t.Errorf("Unexpected rules %+v", rules)
+	}
+}
+
+func TestEC2SessionRule_Matches(t *testing.T) {
+	tags := map[string]string{"Role": "db", "Env": "prod"}
+
+	tests := []struct {
+		name string
+		rule EC2SessionRule
+		want bool
+	}{
+		{"catch-all", EC2SessionRule{}, true},
+		{"account ID", EC2SessionRule{Account: "123456789012"}, true},
+		{"account name ignores case", EC2SessionRule{Account: "PRODUCTION"}, true},
+		{"other account", EC2SessionRule{Account: "staging"}, false},
+		{"tag", EC2SessionRule{Tag: "Role=db"}, true},
+		{"tag with other value", EC2SessionRule{Tag: "Role=web"}, false},
+		{"missing tag", EC2SessionRule{Tag: "Team="}, false},
+		{"account and tag", EC2SessionRule{Account: "production", Tag: "Env=prod"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.rule.Matches("123456789012", "Production", tags); got != tt.want {
+				t.Errorf("Expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestEC2ListDefaultRules(t *testing.T) {
+	viper.Set("ec2.lists", []map[string]interface{}{
+		{"account": "production", "filters": []string{"tag:Team=platform"}, "tag_columns": []string{"Env"}},
+	})
+	defer viper.Reset()
+
+	rules, err := EC2ListDefaultRules()
+	if err != nil {
+		t.Fatalf("EC2ListDefaultRules failed: %v", err)
+	}
+	if len(rules) != 1 || rules[0].Filters[0] != "tag:Team=platform" || rules[0].TagColumns[0] != "Env" {
+		t.Fatalf("Unexpected rules %+v", rules)
+	}
+
+	if !rules[0].Matches("123456789012", "Production") {
+		t.Error("Expected the rule to match the account by name")
+	}
+	if rules[0].Matches("123456789012", "Staging") {
+		t.Error("Expected the rule not to match another account")
+	}
+}
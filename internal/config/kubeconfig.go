@@ -0,0 +1,178 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+// KubeconfigEntry is a cluster, user and context written together under one name
+type KubeconfigEntry struct {
+	Name                     string
+	Server                   string
+	TLSServerName            string
+	CertificateAuthorityData string
+	ExecCommand              string
+	ExecArgs                 []string
+	ExecEnv                  map[string]string
+}
+
+// DefaultKubeconfigPath returns the first path in $KUBECONFIG, or ~/.kube/config
+func DefaultKubeconfigPath() (string, error) {
+	if env := os.Getenv("KUBECONFIG"); env != "" {
+		return filepath.SplitList(env)[0], nil
+	}
+
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return filepath.Join(homeDir, ".kube", "config"), nil
+}
+
+// WriteKubeconfigEntry merges the entry into the kubeconfig at path, replacing any cluster,
+// user or context with the same name, and makes it the current context. The file is edited
+// as a YAML node tree so other entries, settings and comments keep their order.
+func WriteKubeconfigEntry(path string, entry KubeconfigEntry) error {
+	var doc yaml.Node
+
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read kubeconfig: %w", err)
+	}
+	if err == nil {
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			return fmt.Errorf("failed to parse kubeconfig %s: %w", path, err)
+		}
+	}
+	if len(doc.Content) == 0 {
+		doc = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode, Tag: "!!map"}}}
+	}
+	kubeconfig := doc.Content[0]
+	if kubeconfig.Kind != yaml.MappingNode {
+		return fmt.Errorf("failed to parse kubeconfig %s: not a YAML mapping", path)
+	}
+
+	names := make([]string, 0, len(entry.ExecEnv))
+	for name := range entry.ExecEnv {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var env []map[string]string
+	for _, name := range names {
+		env = append(env, map[string]string{"name": name, "value": entry.ExecEnv[name]})
+	}
+
+	setKey(kubeconfig, "apiVersion", scalarNode("v1"))
+	setKey(kubeconfig, "kind", scalarNode("Config"))
+
+	if err := upsertNamed(kubeconfig, "clusters", entry.Name, "cluster", map[string]interface{}{
+		"server":                     entry.Server,
+		"tls-server-name":            entry.TLSServerName,
+		"certificate-authority-data": entry.CertificateAuthorityData,
+	}); err != nil {
+		return err
+	}
+	if err := upsertNamed(kubeconfig, "users", entry.Name, "user", map[string]interface{}{
+		"exec": map[string]interface{}{
+			"apiVersion":         "client.authentication.k8s.io/v1beta1",
+			"command":            entry.ExecCommand,
+			"args":               entry.ExecArgs,
+			"env":                env,
+			"interactiveMode":    "Never",
+			"provideClusterInfo": false,
+		},
+	}); err != nil {
+		return err
+	}
+	if err := upsertNamed(kubeconfig, "contexts", entry.Name, "context", map[string]interface{}{
+		"cluster": entry.Name,
+		"user":    entry.Name,
+	}); err != nil {
+		return err
+	}
+	setKey(kubeconfig, "current-context", scalarNode(entry.Name))
+
+	var out bytes.Buffer
+	encoder := yaml.NewEncoder(&out)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&doc); err != nil {
+		return fmt.Errorf("failed to encode kubeconfig: %w", err)
+	}
+	if err := encoder.Close(); err != nil {
+		return fmt.Errorf("failed to encode kubeconfig: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create kubeconfig directory: %w", err)
+	}
+
+	if err := os.WriteFile(path, out.Bytes(), 0600); err != nil {
+		return fmt.Errorf("failed to write kubeconfig: %w", err)
+	}
+
+	return nil
+}
+
+// upsertNamed replaces or appends {name: name, key: value} in the named list, keeping the
+// comment above a replaced item
+func upsertNamed(kubeconfig *yaml.Node, list, name, key string, value map[string]interface{}) error {
+	valueNode := &yaml.Node{}
+	if err := valueNode.Encode(value); err != nil {
+		return fmt.Errorf("failed to encode kubeconfig %s entry: %w", key, err)
+	}
+	item := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	setKey(item, "name", scalarNode(name))
+	setKey(item, key, valueNode)
+
+	items := getKey(kubeconfig, list)
+	if items == nil || items.Kind != yaml.SequenceNode {
+		items = &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+		setKey(kubeconfig, list, items)
+	}
+
+	for i, existing := range items.Content {
+		if existingName := getKey(existing, "name"); existingName != nil && existingName.Value == name {
+			item.HeadComment = existing.HeadComment
+			items.Content[i] = item
+			return nil
+		}
+	}
+
+	items.Content = append(items.Content, item)
+	return nil
+}
+
+// getKey returns the value of key in a mapping node, or nil
+func getKey(mapping *yaml.Node, key string) *yaml.Node {
+	if mapping.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return mapping.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// setKey replaces the value of key in a mapping node in place, or appends the key
+func setKey(mapping *yaml.Node, key string, value *yaml.Node) {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			value.LineComment = mapping.Content[i+1].LineComment
+			mapping.Content[i+1] = value
+			return
+		}
+	}
+	mapping.Content = append(mapping.Content, scalarNode(key), value)
+}
+
+func scalarNode(value string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value}
+}
@@ -0,0 +1,185 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestWriteKubeconfigEntry_Merge(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config")
+
+	existing := `apiVersion: v1
+kind: Config
+current-context: other
+clusters:
+- name: other
+  cluster:
+    server: https://other.example
+- name: awsc-prod
+  cluster:
+    server: https://localhost:9999
+contexts:
+- name: other
+  context:
+    cluster: other
+    user: other
+users:
+- name: other
+  user:
+    token: abc
+preferences: {}
+`
+	if err := os.WriteFile(path, []byte(existing), 0600); err != nil {
+		t.Fatalf("Failed to write kubeconfig: %v", err)
+	}
+
+	err := WriteKubeconfigEntry(path, KubeconfigEntry{
+		Name:                     "awsc-prod",
+		Server:                   "https://localhost:8443",
+		TLSServerName:            "ABC123.gr7.us-east-1.eks.amazonaws.com",
+		CertificateAuthorityData: "Y2EtZGF0YQ==",
+		ExecCommand:              "/usr/local/bin/awsc",
+		ExecArgs:                 []string{"eks", "token", "--cluster-name", "prod"},
+		ExecEnv:                  map[string]string{"AWSC_PROFILE": "awsc-prod-account"},
+	})
+	if err != nil {
+		t.Fatalf("WriteKubeconfigEntry failed: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read kubeconfig: %v", err)
+	}
+
+	var result struct {
+		CurrentContext string `yaml:"current-context"`
+		Clusters       []struct {
+			Name    string
+			Cluster map[string]string
+		}
+		Contexts []struct{ Name string }
+		Users    []struct {
+			Name string
+			User struct {
+				Token string
+				Exec  struct {
+					Command string
+					Args    []string
+					Env     []map[string]string
+				}
+			}
+		}
+		Preferences map[string]interface{}
+	}
+	if err := yaml.Unmarshal(data, &result); err != nil {
+		t.Fatalf("Failed to parse kubeconfig: %v", err)
+	}
+
+	if result.CurrentContext != "awsc-prod" {
+		t.Errorf("Expected current-context awsc-prod, got %s", result.CurrentContext)
+	}
+
+	if len(result.Clusters) != 2 {
+		t.Fatalf("Expected existing cluster to be replaced rather than duplicated, got %d clusters", len(result.Clusters))
+	}
+	prod := result.Clusters[1].Cluster
+	if prod["server"] != "https://localhost:8443" || prod["tls-server-name"] != "ABC123.gr7.us-east-1.eks.amazonaws.com" {
+		t.Errorf("Unexpected cluster entry: %v", prod)
+	}
+
+	if len(result.Contexts) != 2 || len(result.Users) != 2 {
+		t.Errorf("Expected 2 contexts and users, got %d and %d", len(result.Contexts), len(result.Users))
+	}
+
+	if result.Users[0].User.Token != "abc" {
+		t.Error("Expected unrelated user to be preserved")
+	}
+	exec := result.Users[1].User.Exec
+	if exec.Command != "/usr/local/bin/awsc" || len(exec.Args) != 4 {
+		t.Errorf("Unexpected exec config: %+v", exec)
+	}
+	if len(exec.Env) != 1 || exec.Env[0]["value"] != "awsc-prod-account" {
+		t.Errorf("Expected AWSC_PROFILE env, got %v", exec.Env)
+	}
+
+	if result.Preferences == nil {
+		t.Error("Expected other top-level settings to be preserved")
+	}
+}
+
+func TestWriteKubeconfigEntry_KeepsCommentsAndOrder(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config")
+
+	existing := `# Managed by hand
+preferences: {}
+clusters:
+  # Staging cluster
+  - name: other
+    cluster:
+      server: https://other.example # behind the VPN
+apiVersion: v1
+kind: Config
+current-context: other
+`
+	if err := os.WriteFile(path, []byte(existing), 0600); err != nil {
+		t.Fatalf("Failed to write kubeconfig: %v", err)
+	}
+
+	if err := WriteKubeconfigEntry(path, KubeconfigEntry{Name: "awsc-prod", Server: "https://localhost:8443"}); err != nil {
+		t.Fatalf("WriteKubeconfigEntry failed: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read kubeconfig: %v", err)
+	}
+	out := string(data)
+
+	for _, comment := range []string{"# Managed by hand", "# Staging cluster", "# behind the VPN"} {
+		if !strings.Contains(out, comment) {
+			t.Errorf("Expected comment %q to be kept, got:\n%s", comment, out)
+		}
+	}
+
+	order := []string{"preferences:", "clusters:", "apiVersion:", "kind:", "current-context: awsc-prod", "users:", "contexts:"}
+	last := -1
+	for _, key := range order {
+		i := strings.Index(out, key)
+		if i <= last {
+			t.Fatalf("Expected keys in order %v, got:\n%s", order, out)
+		}
+		last = i
+	}
+}
+
+func TestWriteKubeconfigEntry_NewFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".kube", "config")
+
+	if err := WriteKubeconfigEntry(path, KubeconfigEntry{Name: "awsc-dev", Server: "https://localhost:8443"}); err != nil {
+		t.Fatalf("WriteKubeconfigEntry failed: %v", err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("Expected kubeconfig to be created: %v", err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("Expected 0600 permissions, got %v", info.Mode().Perm())
+	}
+}
+
+func TestDefaultKubeconfigPath(t *testing.T) {
+	t.Setenv("KUBECONFIG", "/tmp/a"+string(os.PathListSeparator)+"/tmp/b")
+
+	path, err := DefaultKubeconfigPath()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if path != "/tmp/a" {
+		t.Errorf("Expected first KUBECONFIG entry, got %s", path)
+	}
+}
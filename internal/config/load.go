@@ -39,20 +39,9 @@ func LoadAWSConfigWithProfile(ctx context.Context) (aws.Config, error) {
 	// Use region override if provided, otherwise use default region from config
 	region := viper.GetString("default_region")
 
-	var profileName string
-
-	// Priority 1: Check AWSC_PROFILE environment variable
-	envProfile := os.Getenv("AWSC_PROFILE")
-	if envProfile != "" {
-		profileName = envProfile
-	} else {
-		// Priority 2: Check PPID session
-		session, err := GetCurrentSession()
-		if err != nil {
-			// No session found
-			return aws.Config{}, fmt.Errorf("no active session")
-		}
-		profileName = session.ProfileName
+	profileName, err := CurrentProfileName()
+	if err != nil {
+		return aws.Config{}, err
 	}
 
 	// Load config with the determined profile
@@ -66,3 +55,20 @@ func LoadAWSConfigWithProfile(ctx context.Context) (aws.Config, error) {
 
 	return config.LoadDefaultConfig(ctx, options...)
 }
+
+// CurrentProfileName returns the profile LoadAWSConfigWithProfile would use, so it can be
+// passed on to child processes via AWSC_PROFILE
+func CurrentProfileName() (string, error) {
+	// Priority 1: Check AWSC_PROFILE environment variable
+	if envProfile := os.Getenv("AWSC_PROFILE"); envProfile != "" {
+		return envProfile, nil
+	}
+
+	// Priority 2: Check PPID session
+	session, err := GetCurrentSession()
+	if err != nil {
+		// No session found
+		return "", fmt.Errorf("no active session")
+	}
+	return session.ProfileName, nil
+}
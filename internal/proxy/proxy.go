@@ -0,0 +1,245 @@
+// Package proxy implements a local SOCKS5 and HTTP CONNECT proxy whose upstream
+// connections are opened by a caller-supplied dial function
+package proxy
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"github.com/blontic/awsc/internal/debug"
+)
+
+// DialFunc opens a connection to host:port on behalf of a proxy client
+type DialFunc func(ctx context.Context, host string, port int) (net.Conn, error)
+
+// Server accepts SOCKS5 clients and, when AllowHTTP is set, HTTP CONNECT clients on the same port
+type Server struct {
+	Dial      DialFunc
+	AllowHTTP bool
+}
+
+const (
+	socksVersion = 0x05
+
+	socksCmdConnect = 0x01
+
+	socksAddrIPv4   = 0x01
+	socksAddrDomain = 0x03
+	socksAddrIPv6   = 0x04
+
+	socksReplySucceeded           = 0x00
+	socksReplyGeneralFailure      = 0x01
+	socksReplyCommandNotSupported = 0x07
+	socksReplyAddressNotSupported = 0x08
+)
+
+// Serve accepts connections until ctx is cancelled or the listener fails
+func (s *Server) Serve(ctx context.Context, ln net.Listener) error {
+	return serve(ctx, ln, s.handle)
+}
+
+// serve runs handle for every accepted connection until ctx is cancelled or the
+// listener fails, and waits for open connections before returning
+func serve(ctx context.Context, ln net.Listener, handle func(ctx context.Context, conn net.Conn) error) error {
+	go func() {
+		<-ctx.Done()
+		ln.Close()
+	}()
+
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer conn.Close()
+			// Closing the client on shutdown also ends its upstream through pipe
+			stop := context.AfterFunc(ctx, func() { conn.Close() })
+			defer stop()
+			if err := handle(ctx, conn); err != nil {
+				debug.Printf("proxy: %v\n", err)
+			}
+		}()
+	}
+}
+
+func (s *Server) handle(ctx context.Context, conn net.Conn) error {
+	reader := bufio.NewReader(conn)
+
+	first, err := reader.Peek(1)
+	if err != nil {
+		return err
+	}
+
+	if first[0] == socksVersion {
+		return s.handleSOCKS(ctx, conn, reader)
+	}
+	if s.AllowHTTP {
+		return s.handleHTTP(ctx, conn, reader)
+	}
+	return fmt.Errorf("unsupported protocol from %s (first byte 0x%02x)", conn.RemoteAddr(), first[0])
+}
+
+func (s *Server) handleSOCKS(ctx context.Context, conn net.Conn, reader *bufio.Reader) error {
+	// Greeting: VER NMETHODS METHODS...
+	header := make([]byte, 2)
+	if _, err := io.ReadFull(reader, header); err != nil {
+		return err
+	}
+	methods := make([]byte, header[1])
+	if _, err := io.ReadFull(reader, methods); err != nil {
+		return err
+	}
+
+	// Only "no authentication" is offered; the proxy only listens on localhost
+	noAuth := false
+	for _, m := range methods {
+		if m == 0x00 {
+			noAuth = true
+		}
+	}
+	if !noAuth {
+		conn.Write([]byte{socksVersion, 0xff})
+		return errors.New("SOCKS client does not support unauthenticated connections")
+	}
+	if _, err := conn.Write([]byte{socksVersion, 0x00}); err != nil {
+		return err
+	}
+
+	// Request: VER CMD RSV ATYP DST.ADDR DST.PORT
+	request := make([]byte, 4)
+	if _, err := io.ReadFull(reader, request); err != nil {
+		return err
+	}
+	if request[1] != socksCmdConnect {
+		writeSOCKSReply(conn, socksReplyCommandNotSupported)
+		return fmt.Errorf("unsupported SOCKS command %d", request[1])
+	}
+
+	var host string
+	switch request[3] {
+	case socksAddrIPv4, socksAddrIPv6:
+		size := net.IPv4len
+		if request[3] == socksAddrIPv6 {
+			size = net.IPv6len
+		}
+		addr := make([]byte, size)
+		if _, err := io.ReadFull(reader, addr); err != nil {
+			return err
+		}
+		host = net.IP(addr).String()
+	case socksAddrDomain:
+		length, err := reader.ReadByte()
+		if err != nil {
+			return err
+		}
+		domain := make([]byte, length)
+		if _, err := io.ReadFull(reader, domain); err != nil {
+			return err
+		}
+		host = string(domain)
+	default:
+		writeSOCKSReply(conn, socksReplyAddressNotSupported)
+		return fmt.Errorf("unsupported SOCKS address type %d", request[3])
+	}
+
+	portBytes := make([]byte, 2)
+	if _, err := io.ReadFull(reader, portBytes); err != nil {
+		return err
+	}
+	port := int(binary.BigEndian.Uint16(portBytes))
+
+	upstream, err := s.Dial(ctx, host, port)
+	if err != nil {
+		writeSOCKSReply(conn, socksReplyGeneralFailure)
+		return fmt.Errorf("connecting to %s: %w", net.JoinHostPort(host, strconv.Itoa(port)), err)
+	}
+	defer upstream.Close()
+
+	if err := writeSOCKSReply(conn, socksReplySucceeded); err != nil {
+		return err
+	}
+
+	pipe(conn, reader, upstream)
+	return nil
+}
+
+func writeSOCKSReply(conn net.Conn, code byte) error {
+	// The bound address isn't meaningful for a tunnelled connection, so report 0.0.0.0:0
+	_, err := conn.Write([]byte{socksVersion, code, 0x00, socksAddrIPv4, 0, 0, 0, 0, 0, 0})
+	return err
+}
+
+func (s *Server) handleHTTP(ctx context.Context, conn net.Conn, reader *bufio.Reader) error {
+	req, err := http.ReadRequest(reader)
+	if err != nil {
+		return err
+	}
+
+	if req.Method != http.MethodConnect {
+		fmt.Fprintf(conn, "HTTP/1.1 405 Method Not Allowed\r\nContent-Length: 0\r\nConnection: close\r\n\r\n")
+		return fmt.Errorf("unsupported HTTP method %s", req.Method)
+	}
+
+	host, portStr, err := net.SplitHostPort(req.Host)
+	if err != nil {
+		fmt.Fprintf(conn, "HTTP/1.1 400 Bad Request\r\nContent-Length: 0\r\nConnection: close\r\n\r\n")
+		return fmt.Errorf("invalid CONNECT target %q", req.Host)
+	}
+	port, err := strconv.Atoi(portStr)
+	if err != nil {
+		fmt.Fprintf(conn, "HTTP/1.1 400 Bad Request\r\nContent-Length: 0\r\nConnection: close\r\n\r\n")
+		return fmt.Errorf("invalid CONNECT port %q", portStr)
+	}
+
+	upstream, err := s.Dial(ctx, host, port)
+	if err != nil {
+		fmt.Fprintf(conn, "HTTP/1.1 502 Bad Gateway\r\nContent-Length: 0\r\nConnection: close\r\n\r\n")
+		return fmt.Errorf("connecting to %s: %w", req.Host, err)
+	}
+	defer upstream.Close()
+
+	if _, err := fmt.Fprintf(conn, "HTTP/1.1 200 Connection Established\r\n\r\n"); err != nil {
+		return err
+	}
+
+	pipe(conn, reader, upstream)
+	return nil
+}
+
+// pipe copies in both directions until either side closes. Client bytes are read through
+// the buffered reader so anything already peeked is not lost.
+func pipe(client net.Conn, clientReader io.Reader, upstream net.Conn) {
+	done := make(chan struct{}, 2)
+
+	go func() {
+		io.Copy(upstream, clientReader)
+		done <- struct{}{}
+	}()
+	go func() {
+		io.Copy(client, upstream)
+		done <- struct{}{}
+	}()
+
+	<-done
+	client.Close()
+	upstream.Close()
+	<-done
+}
@@ -0,0 +1,209 @@
+package proxy
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// startEchoServer returns the address of a TCP server that echoes everything it receives
+func startEchoServer(t *testing.T) string {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				io.Copy(conn, conn)
+			}()
+		}
+	}()
+
+	return ln.Addr().String()
+}
+
+// startProxy runs a Server whose dial records the requested target and connects to echoAddr
+func startProxy(t *testing.T, allowHTTP bool, echoAddr string, targets chan<- string) string {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	server := &Server{
+		AllowHTTP: allowHTTP,
+		Dial: func(ctx context.Context, host string, port int) (net.Conn, error) {
+			targets <- net.JoinHostPort(host, strconv.Itoa(port))
+			return net.Dial("tcp", echoAddr)
+		},
+	}
+	go server.Serve(ctx, ln)
+
+	return ln.Addr().String()
+}
+
+func assertEcho(t *testing.T, conn net.Conn, reader io.Reader) {
+	t.Helper()
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+	if _, err := conn.Write([]byte("ping")); err != nil {
+		t.Fatalf("Failed to write: %v", err)
+	}
+	buf := make([]byte, 4)
+	if _, err := io.ReadFull(reader, buf); err != nil {
+		t.Fatalf("Failed to read echo: %v", err)
+	}
+	if string(buf) != "ping" {
+		t.Errorf("Expected echo 'ping', got %q", buf)
+	}
+}
+
+func TestServer_SOCKS5Domain(t *testing.T) {
+	targets := make(chan string, 1)
+	proxyAddr := startProxy(t, false, startEchoServer(t), targets)
+
+	conn, err := net.Dial("tcp", proxyAddr)
+	if err != nil {
+		t.Fatalf("Failed to connect to proxy: %v", err)
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+
+	conn.Write([]byte{0x05, 0x01, 0x00})
+	greeting := make([]byte, 2)
+	if _, err := io.ReadFull(conn, greeting); err != nil || greeting[1] != 0x00 {
+		t.Fatalf("Unexpected greeting reply %v (%v)", greeting, err)
+	}
+
+	host := "grafana.internal"
+	request := []byte{0x05, 0x01, 0x00, 0x03, byte(len(host))}
+	request = append(request, host...)
+	request = append(request, 0x0b, 0xb8) // 3000
+	conn.Write(request)
+
+	reply := make([]byte, 10)
+	if _, err := io.ReadFull(conn, reply); err != nil {
+		t.Fatalf("Failed to read reply: %v", err)
+	}
+	if reply[1] != socksReplySucceeded {
+		t.Fatalf("Expected success reply, got %d", reply[1])
+	}
+
+	if target := <-targets; target != "grafana.internal:3000" {
+		t.Errorf("Expected dial to grafana.internal:3000, got %s", target)
+	}
+
+	assertEcho(t, conn, conn)
+}
+
+func TestServer_SOCKS5IPv4(t *testing.T) {
+	targets := make(chan string, 1)
+	proxyAddr := startProxy(t, false, startEchoServer(t), targets)
+
+	conn, err := net.Dial("tcp", proxyAddr)
+	if err != nil {
+		t.Fatalf("Failed to connect to proxy: %v", err)
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+
+	conn.Write([]byte{0x05, 0x01, 0x00})
+	io.ReadFull(conn, make([]byte, 2))
+	conn.Write([]byte{0x05, 0x01, 0x00, 0x01, 10, 0, 1, 5, 0x01, 0xbb})
+
+	reply := make([]byte, 10)
+	if _, err := io.ReadFull(conn, reply); err != nil || reply[1] != socksReplySucceeded {
+		t.Fatalf("Unexpected reply %v (%v)", reply, err)
+	}
+
+	if target := <-targets; target != "10.0.1.5:443" {
+		t.Errorf("Expected dial to 10.0.1.5:443, got %s", target)
+	}
+}
+
+func TestServer_SOCKS5UnsupportedCommand(t *testing.T) {
+	proxyAddr := startProxy(t, false, startEchoServer(t), make(chan string, 1))
+
+	conn, err := net.Dial("tcp", proxyAddr)
+	if err != nil {
+		t.Fatalf("Failed to connect to proxy: %v", err)
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+
+	conn.Write([]byte{0x05, 0x01, 0x00})
+	io.ReadFull(conn, make([]byte, 2))
+	// BIND is not supported
+	conn.Write([]byte{0x05, 0x02, 0x00, 0x01, 10, 0, 1, 5, 0x01, 0xbb})
+
+	reply := make([]byte, 10)
+	if _, err := io.ReadFull(conn, reply); err != nil {
+		t.Fatalf("Failed to read reply: %v", err)
+	}
+	if reply[1] != socksReplyCommandNotSupported {
+		t.Errorf("Expected command not supported reply, got %d", reply[1])
+	}
+}
+
+func TestServer_HTTPConnect(t *testing.T) {
+	targets := make(chan string, 1)
+	proxyAddr := startProxy(t, true, startEchoServer(t), targets)
+
+	conn, err := net.Dial("tcp", proxyAddr)
+	if err != nil {
+		t.Fatalf("Failed to connect to proxy: %v", err)
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+
+	conn.Write([]byte("CONNECT airflow.internal:8080 HTTP/1.1\r\nHost: airflow.internal:8080\r\n\r\n"))
+
+	reader := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(reader, nil)
+	if err != nil {
+		t.Fatalf("Failed to read response: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", resp.StatusCode)
+	}
+
+	if target := <-targets; target != "airflow.internal:8080" {
+		t.Errorf("Expected dial to airflow.internal:8080, got %s", target)
+	}
+
+	assertEcho(t, conn, reader)
+}
+
+func TestServer_HTTPDisabled(t *testing.T) {
+	proxyAddr := startProxy(t, false, startEchoServer(t), make(chan string, 1))
+
+	conn, err := net.Dial("tcp", proxyAddr)
+	if err != nil {
+		t.Fatalf("Failed to connect to proxy: %v", err)
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+
+	conn.Write([]byte("CONNECT airflow.internal:8080 HTTP/1.1\r\n\r\n"))
+
+	// The server closes the connection without a reply
+	if n, _ := conn.Read(make([]byte, 1)); n != 0 {
+		t.Error("Expected connection to be closed when HTTP CONNECT is disabled")
+	}
+}
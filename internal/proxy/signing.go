@@ -0,0 +1,121 @@
+package proxy
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	"github.com/blontic/awsc/internal/debug"
+)
+
+// SigningProxy is a reverse proxy that signs each request with SigV4 and sends it over TLS
+// to Host. When Upstream is set the connection is dialed there instead (e.g. a local tunnel
+// port), while SNI, certificate verification and the Host header still use Host.
+type SigningProxy struct {
+	Host        string
+	Upstream    string
+	Region      string
+	Service     string
+	Credentials aws.CredentialsProvider
+	TLSConfig   *tls.Config // Optional; ServerName defaults to Host
+}
+
+// Handler returns the http.Handler serving the proxy
+func (p *SigningProxy) Handler() http.Handler {
+	target := &url.URL{Scheme: "https", Host: p.Host}
+
+	tlsConfig := &tls.Config{}
+	if p.TLSConfig != nil {
+		tlsConfig = p.TLSConfig.Clone()
+	}
+	if tlsConfig.ServerName == "" {
+		tlsConfig.ServerName = p.Host
+	}
+
+	dialer := &net.Dialer{Timeout: 30 * time.Second}
+	transport := &http.Transport{
+		TLSClientConfig:     tlsConfig,
+		TLSHandshakeTimeout: 10 * time.Second,
+		IdleConnTimeout:     90 * time.Second,
+	}
+	if p.Upstream != "" {
+		transport.DialContext = func(ctx context.Context, network, addr string) (net.Conn, error) {
+			return dialer.DialContext(ctx, network, p.Upstream)
+		}
+	} else {
+		transport.DialContext = dialer.DialContext
+	}
+
+	return &httputil.ReverseProxy{
+		Rewrite: func(pr *httputil.ProxyRequest) {
+			pr.SetURL(target)
+		},
+		Transport: &signingTransport{
+			next:        transport,
+			signer:      v4.NewSigner(),
+			credentials: p.Credentials,
+			region:      p.Region,
+			service:     p.Service,
+		},
+		ErrorHandler: func(w http.ResponseWriter, r *http.Request, err error) {
+			debug.Printf("signing proxy: %s %s: %v\n", r.Method, r.URL.Path, err)
+			http.Error(w, fmt.Sprintf("awsc signing proxy: %v", err), http.StatusBadGateway)
+		},
+	}
+}
+
+// signingTransport signs requests just before they are sent, after the reverse proxy has
+// finished rewriting them, so every header that reaches the service is covered
+type signingTransport struct {
+	next        http.RoundTripper
+	signer      *v4.Signer
+	credentials aws.CredentialsProvider
+	region      string
+	service     string
+}
+
+func (t *signingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil && req.Body != http.NoBody {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("reading request body: %w", err)
+		}
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	req.ContentLength = int64(len(body))
+	if len(body) == 0 {
+		req.Body = http.NoBody
+	}
+
+	hash := sha256.Sum256(body)
+	payloadHash := hex.EncodeToString(hash[:])
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	// Browser headers that would otherwise be signed but may be altered or dropped in transit
+	req.Header.Del("Connection")
+
+	creds, err := t.credentials.Retrieve(req.Context())
+	if err != nil {
+		return nil, fmt.Errorf("retrieving AWS credentials: %w", err)
+	}
+
+	if err := t.signer.SignHTTP(req.Context(), creds, req, payloadHash, t.service, t.region, time.Now()); err != nil {
+		return nil, fmt.Errorf("signing request: %w", err)
+	}
+
+	return t.next.RoundTrip(req)
+}
@@ -0,0 +1,105 @@
+package proxy
+
+import (
+	"context"
+	"crypto/tls"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+)
+
+func TestSigningProxy_SignsAndForwards(t *testing.T) {
+	type seenRequest struct {
+		host, path, query, auth, payloadHash, body, sni string
+	}
+	seen := make(chan seenRequest, 1)
+
+	upstream := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		seen <- seenRequest{
+			host:        r.Host,
+			path:        r.URL.Path,
+			query:       r.URL.RawQuery,
+			auth:        r.Header.Get("Authorization"),
+			payloadHash: r.Header.Get("X-Amz-Content-Sha256"),
+			body:        string(body),
+			sni:         r.TLS.ServerName,
+		}
+		w.Write([]byte(`{"acknowledged":true}`))
+	}))
+	upstream.StartTLS()
+	defer upstream.Close()
+
+	// The test certificate is issued for example.com, standing in for the domain endpoint
+	signingProxy := &SigningProxy{
+		Host:        "example.com",
+		Upstream:    upstream.Listener.Addr().String(),
+		Region:      "us-east-1",
+		Service:     "es",
+		Credentials: credentials.NewStaticCredentialsProvider("AKIDEXAMPLE", "secret", "token"),
+		TLSConfig:   &tls.Config{RootCAs: upstream.Client().Transport.(*http.Transport).TLSClientConfig.RootCAs},
+	}
+
+	local := httptest.NewServer(signingProxy.Handler())
+	defer local.Close()
+
+	resp, err := http.Post(local.URL+"/my-index/_search?size=5", "application/json", strings.NewReader(`{"query":{"match_all":{}}}`))
+	if err != nil {
+		t.Fatalf("Request through proxy failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", resp.StatusCode)
+	}
+
+	got := <-seen
+	if got.host != "example.com" {
+		t.Errorf("Expected Host example.com, got %s", got.host)
+	}
+	if got.sni != "example.com" {
+		t.Errorf("Expected SNI example.com, got %s", got.sni)
+	}
+	if got.path != "/my-index/_search" || got.query != "size=5" {
+		t.Errorf("Expected path and query to be preserved, got %s?%s", got.path, got.query)
+	}
+	if !strings.HasPrefix(got.auth, "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/") || !strings.Contains(got.auth, "/us-east-1/es/aws4_request") {
+		t.Errorf("Expected SigV4 authorization for es in us-east-1, got %q", got.auth)
+	}
+	if got.payloadHash == "" {
+		t.Error("Expected X-Amz-Content-Sha256 header")
+	}
+	if got.body != `{"query":{"match_all":{}}}` {
+		t.Errorf("Expected body to be forwarded, got %q", got.body)
+	}
+}
+
+func TestSigningProxy_CredentialError(t *testing.T) {
+	signingProxy := &SigningProxy{
+		Host:     "example.com",
+		Upstream: "127.0.0.1:1",
+		Region:   "us-east-1",
+		Service:  "es",
+		Credentials: aws.CredentialsProviderFunc(func(ctx context.Context) (aws.Credentials, error) {
+			return aws.Credentials{}, io.ErrUnexpectedEOF
+		}),
+	}
+
+	local := httptest.NewServer(signingProxy.Handler())
+	defer local.Close()
+
+	resp, err := http.Get(local.URL + "/")
+	if err != nil {
+		t.Fatalf("Request through proxy failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusBadGateway {
+		t.Errorf("Expected 502 when credentials are unavailable, got %d", resp.StatusCode)
+	}
+}
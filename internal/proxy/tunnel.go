@@ -0,0 +1,22 @@
+package proxy
+
+import (
+	"context"
+	"net"
+)
+
+// TunnelFunc opens a new upstream connection for one accepted client
+type TunnelFunc func(ctx context.Context) (net.Conn, error)
+
+// ServeTunnel forwards every connection accepted on ln to its own upstream connection
+// from open, until ctx is cancelled or the listener fails
+func ServeTunnel(ctx context.Context, ln net.Listener, open TunnelFunc) error {
+	return serve(ctx, ln, func(ctx context.Context, conn net.Conn) error {
+		upstream, err := open(ctx)
+		if err != nil {
+			return err
+		}
+		pipe(conn, conn, upstream)
+		return nil
+	})
+}
@@ -0,0 +1,98 @@
+package proxy
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/gorilla/websocket"
+)
+
+// startWebSocketEcho returns the ws:// URL of a server that echoes every binary message,
+// splitting each one in two to exercise reads that span messages
+func startWebSocketEcho(t *testing.T) string {
+	t.Helper()
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ws, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer ws.Close()
+		for {
+			_, data, err := ws.ReadMessage()
+			if err != nil {
+				return
+			}
+			half := len(data) / 2
+			ws.WriteMessage(websocket.BinaryMessage, data[:half])
+			ws.WriteMessage(websocket.BinaryMessage, data[half:])
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	return "ws" + strings.TrimPrefix(server.URL, "http")
+}
+
+func TestServeTunnel_WebSocket(t *testing.T) {
+	wsURL := startWebSocketEcho(t)
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	var opened atomic.Int32
+	go ServeTunnel(ctx, ln, func(ctx context.Context) (net.Conn, error) {
+		opened.Add(1)
+		ws, _, err := websocket.DefaultDialer.DialContext(ctx, wsURL, nil)
+		if err != nil {
+			return nil, err
+		}
+		return NewWebSocketConn(ws), nil
+	})
+
+	conn, err := net.Dial("tcp", ln.Addr().String())
+	if err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	defer conn.Close()
+
+	assertEcho(t, conn, conn)
+	assertEcho(t, conn, conn)
+
+	if n := opened.Load(); n != 1 {
+		t.Errorf("Expected one tunnel per client connection, got %d", n)
+	}
+}
+
+func TestWebSocketConn_CloseEndsRead(t *testing.T) {
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ws, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		NewWebSocketConn(ws).Close()
+	}))
+	defer server.Close()
+
+	ws, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), nil)
+	if err != nil {
+		t.Fatalf("Failed to dial: %v", err)
+	}
+	conn := NewWebSocketConn(ws)
+	defer conn.Close()
+
+	if _, err := conn.Read(make([]byte, 1)); err != io.EOF {
+		t.Errorf("Expected io.EOF after a normal close, got %v", err)
+	}
+}
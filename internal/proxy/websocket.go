@@ -0,0 +1,74 @@
+package proxy
+
+import (
+	"errors"
+	"io"
+	"net"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// WebSocketConn adapts a websocket that carries a TCP stream in binary messages,
+// such as an EC2 Instance Connect Endpoint tunnel, to a net.Conn
+type WebSocketConn struct {
+	ws     *websocket.Conn
+	reader io.Reader
+}
+
+func NewWebSocketConn(ws *websocket.Conn) *WebSocketConn {
+	return &WebSocketConn{ws: ws}
+}
+
+func (c *WebSocketConn) Read(p []byte) (int, error) {
+	for {
+		if c.reader == nil {
+			_, reader, err := c.ws.NextReader()
+			if err != nil {
+				var closeErr *websocket.CloseError
+				if errors.As(err, &closeErr) {
+					return 0, io.EOF
+				}
+				return 0, err
+			}
+			c.reader = reader
+		}
+
+		n, err := c.reader.Read(p)
+		if err == io.EOF {
+			// End of this message; the stream continues in the next one
+			c.reader = nil
+			if n > 0 {
+				return n, nil
+			}
+			continue
+		}
+		return n, err
+	}
+}
+
+func (c *WebSocketConn) Write(p []byte) (int, error) {
+	if err := c.ws.WriteMessage(websocket.BinaryMessage, p); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+func (c *WebSocketConn) Close() error {
+	// WriteControl is safe to call while another goroutine is writing
+	c.ws.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""), time.Now().Add(time.Second))
+	return c.ws.Close()
+}
+
+func (c *WebSocketConn) LocalAddr() net.Addr  { return c.ws.LocalAddr() }
+func (c *WebSocketConn) RemoteAddr() net.Addr { return c.ws.RemoteAddr() }
+
+func (c *WebSocketConn) SetDeadline(t time.Time) error {
+	if err := c.ws.SetReadDeadline(t); err != nil {
+		return err
+	}
+	return c.ws.SetWriteDeadline(t)
+}
+
+func (c *WebSocketConn) SetReadDeadline(t time.Time) error  { return c.ws.SetReadDeadline(t) }
+func (c *WebSocketConn) SetWriteDeadline(t time.Time) error { return c.ws.SetWriteDeadline(t) }
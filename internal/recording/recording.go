@@ -0,0 +1,356 @@
+// Package recording writes and replays terminal sessions in the asciicast v2 format
+// (https://docs.asciinema.org/manual/asciicast/v2/), so recordings also play in asciinema
+package recording
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// headerSize is the space reserved for the header line, so Close can rewrite it in place
+// with the end time once the session is over
+const headerSize = 2048
+
+// Metadata describes who recorded a session and where. It is stored under "awsc" in the
+// header, which asciicast players ignore.
+type Metadata struct {
+	Profile      string    `json:"profile,omitempty"`
+	AccountId    string    `json:"account_id,omitempty"`
+	AccountName  string    `json:"account_name,omitempty"`
+	RoleName     string    `json:"role_name,omitempty"`
+	Region       string    `json:"region,omitempty"`
+	InstanceId   string    `json:"instance_id,omitempty"`
+	InstanceName string    `json:"instance_name,omitempty"`
+	User         string    `json:"user,omitempty"`
+	Start        time.Time `json:"start"`
+	End          time.Time `json:"end,omitzero"`
+}
+
+// Header is the first line of an asciicast v2 file
+type Header struct {
+	Version   int               `json:"version"`
+	Width     int               `json:"width"`
+	Height    int               `json:"height"`
+	Timestamp int64             `json:"timestamp"`
+	Duration  float64           `json:"duration,omitempty"`
+	Title     string            `json:"title,omitempty"`
+	Env       map[string]string `json:"env,omitempty"`
+	Awsc      *Metadata         `json:"awsc,omitempty"`
+}
+
+// Event is one line after the header: seconds since the start, a type ("o" output,
+// "i" input, "r" resize) and the data
+type Event struct {
+	Time float64
+	Type string
+	Data string
+}
+
+// Recorder appends events to an asciicast file. It is safe for concurrent use.
+type Recorder struct {
+	mu      sync.Mutex
+	file    *os.File
+	writer  *bufio.Writer
+	header  Header
+	start   time.Time
+	pending map[string][]byte // Incomplete trailing UTF-8 sequence of each stream
+	err     error
+}
+
+// Dir returns the directory recordings are saved in, ~/.awsc/recordings
+func Dir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return filepath.Join(home, ".awsc", "recordings"), nil
+}
+
+// NewPath returns a new file path in Dir named after the target and the current time
+func NewPath(target string) (string, error) {
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", fmt.Errorf("failed to create %s: %w", dir, err)
+	}
+	name := fmt.Sprintf("%s-%s.cast", time.Now().Format("20060102-150405"), strings.NewReplacer("/", "_", ":", "_").Replace(target))
+	return filepath.Join(dir, name), nil
+}
+
+// Create starts a recording at path. The file is only readable by the current user,
+// since recordings can contain anything that was typed or printed.
+func Create(path string, width, height int, metadata Metadata) (*Recorder, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create recording: %w", err)
+	}
+
+	start := time.Now()
+	metadata.Start = start
+	r := &Recorder{
+		file:    file,
+		writer:  bufio.NewWriter(file),
+		start:   start,
+		pending: map[string][]byte{},
+		header: Header{
+			Version:   2,
+			Width:     width,
+			Height:    height,
+			Timestamp: start.Unix(),
+			Title:     metadata.InstanceName,
+			Env: map[string]string{
+				"TERM":  os.Getenv("TERM"),
+				"SHELL": os.Getenv("SHELL"),
+			},
+			Awsc: &metadata,
+		},
+	}
+
+	line, err := r.headerLine()
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	if _, err := r.writer.Write(line); err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// Path returns the file the recording is written to
+func (r *Recorder) Path() string {
+	return r.file.Name()
+}
+
+// Output records data written to the terminal
+func (r *Recorder) Output(data []byte) {
+	r.stream("o", data)
+}
+
+// Input records data typed into the terminal
+func (r *Recorder) Input(data []byte) {
+	r.stream("i", data)
+}
+
+// Resize records a terminal size change
+func (r *Recorder) Resize(width, height int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.event("r", fmt.Sprintf("%dx%d", width, height))
+}
+
+// stream records data read from a terminal stream. Reads can end in the middle of a
+// multi-byte character, which JSON would turn into U+FFFD, so the incomplete end is held
+// back until the next read completes it, as asciinema does.
+func (r *Recorder) stream(kind string, data []byte) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	buf := append(r.pending[kind], data...)
+	n := completeUTF8(buf)
+	r.pending[kind] = append([]byte(nil), buf[n:]...)
+	if n > 0 {
+		r.event(kind, string(buf[:n]))
+	}
+}
+
+// completeUTF8 returns the length of b without an incomplete UTF-8 sequence at its end
+func completeUTF8(b []byte) int {
+	for i := len(b) - 1; i >= 0 && i > len(b)-utf8.UTFMax; i-- {
+		if utf8.RuneStart(b[i]) {
+			if !utf8.FullRune(b[i:]) {
+				return i
+			}
+			break
+		}
+	}
+	return len(b)
+}
+
+// event writes an event line; the caller holds r.mu
+func (r *Recorder) event(kind, data string) {
+	if r.err != nil {
+		return
+	}
+
+	line, err := json.Marshal([]interface{}{time.Since(r.start).Seconds(), kind, data})
+	if err != nil {
+		r.err = err
+		return
+	}
+	line = append(line, '\n')
+	if _, err := r.writer.Write(line); err != nil {
+		r.err = err
+	}
+}
+
+// Close flushes the events and rewrites the header with the end time and duration
+func (r *Recorder) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Whatever is still held back won't be completed any more
+	for _, kind := range []string{"o", "i"} {
+		if len(r.pending[kind]) > 0 {
+			r.event(kind, string(r.pending[kind]))
+			r.pending[kind] = nil
+		}
+	}
+
+	end := time.Now()
+	r.header.Awsc.End = end
+	r.header.Duration = end.Sub(r.start).Seconds()
+
+	err := r.writer.Flush()
+	if err == nil {
+		var line []byte
+		if line, err = r.headerLine(); err == nil {
+			_, err = r.file.WriteAt(line, 0)
+		}
+	}
+	if closeErr := r.file.Close(); err == nil {
+		err = closeErr
+	}
+	if r.err != nil {
+		return r.err
+	}
+	return err
+}
+
+// headerLine returns the header padded with spaces to headerSize, which JSON ignores
+func (r *Recorder) headerLine() ([]byte, error) {
+	line, err := json.Marshal(r.header)
+	if err != nil {
+		return nil, err
+	}
+	if len(line) >= headerSize {
+		return nil, fmt.Errorf("recording header is too large")
+	}
+	line = append(line, bytes.Repeat([]byte{' '}, headerSize-len(line)-1)...)
+	return append(line, '\n'), nil
+}
+
+// Read parses an asciicast v2 file
+func Read(r io.Reader) (Header, []Event, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+
+	var header Header
+	if !scanner.Scan() {
+		if err := scanner.Err(); err != nil {
+			return header, nil, err
+		}
+		return header, nil, fmt.Errorf("empty recording")
+	}
+	if err := json.Unmarshal(scanner.Bytes(), &header); err != nil {
+		return header, nil, fmt.Errorf("invalid recording header: %w", err)
+	}
+	if header.Version != 2 {
+		return header, nil, fmt.Errorf("unsupported asciicast version %d", header.Version)
+	}
+
+	var events []Event
+	for scanner.Scan() {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		var raw []interface{}
+		if err := json.Unmarshal(scanner.Bytes(), &raw); err != nil || len(raw) != 3 {
+			return header, nil, fmt.Errorf("invalid event on line %d", len(events)+2)
+		}
+		seconds, _ := raw[0].(float64)
+		kind, _ := raw[1].(string)
+		data, _ := raw[2].(string)
+		events = append(events, Event{Time: seconds, Type: kind, Data: data})
+	}
+
+	return header, events, scanner.Err()
+}
+
+// Play writes the output events of a recording to w in real time, divided by speed.
+// Pauses longer than idleLimit are shortened to it when idleLimit is positive.
+func Play(w io.Writer, events []Event, speed float64, idleLimit time.Duration, sleep func(time.Duration)) error {
+	if speed <= 0 {
+		speed = 1
+	}
+	if sleep == nil {
+		sleep = time.Sleep
+	}
+
+	previous := 0.0
+	for _, event := range events {
+		if event.Type != "o" {
+			continue
+		}
+
+		wait := time.Duration((event.Time - previous) / speed * float64(time.Second))
+		if idleLimit > 0 && wait > idleLimit {
+			wait = idleLimit
+		}
+		if wait > 0 {
+			sleep(wait)
+		}
+		previous = event.Time
+
+		if _, err := io.WriteString(w, event.Data); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Info summarises a recording for listing
+type Info struct {
+	Path   string
+	Header Header
+}
+
+// List returns the recordings in Dir, newest first
+func List() ([]Info, error) {
+	dir, err := Dir()
+	if err != nil {
+		return nil, err
+	}
+
+	paths, err := filepath.Glob(filepath.Join(dir, "*.cast"))
+	if err != nil {
+		return nil, err
+	}
+
+	var recordings []Info
+	for _, path := range paths {
+		file, err := os.Open(path)
+		if err != nil {
+			continue
+		}
+		var header Header
+		line, _ := bufio.NewReader(file).ReadBytes('\n')
+		file.Close()
+		if json.Unmarshal(line, &header) != nil {
+			continue
+		}
+		recordings = append(recordings, Info{Path: path, Header: header})
+	}
+
+	sort.Slice(recordings, func(i, j int) bool {
+		return recordings[i].Header.Timestamp > recordings[j].Header.Timestamp
+	})
+
+	return recordings, nil
+}
@@ -0,0 +1,235 @@
+package recording
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRecorder_RoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session.cast")
+
+	rec, err := Create(path, 120, 40, Metadata{
+		Profile:      "awsc-prod",
+		AccountId:    "123456789012",
+		RoleName:     "Admin",
+		InstanceId:   "i-1234567890abcdef0",
+		InstanceName: "web-1",
+	})
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	rec.Output([]byte("$ "))
+	rec.Input([]byte("whoami\r"))
+	rec.Resize(100, 30)
+	rec.Output([]byte("ssm-user\r\n"))
+	if err := rec.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("Stat failed: %v", err)
+	}
+	if perm := info.Mode().Perm(); perm != 0600 {
+		t.Errorf("Expected mode 0600, got %o", perm)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer file.Close()
+
+	header, events, err := Read(file)
+	if err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+
+	if header.Version != 2 || header.Width != 120 || header.Height != 40 {
+		t.Errorf("Unexpected header %+v", header)
+	}
+	if header.Awsc == nil || header.Awsc.AccountId != "123456789012" || header.Awsc.InstanceName != "web-1" {
+		t.Fatalf("Expected metadata in header, got %+v", header.Awsc)
+	}
+	if header.Awsc.Start.IsZero() || header.Awsc.End.IsZero() {
+		t.Errorf("Expected start and end times, got %+v", header.Awsc)
+	}
+	if header.Awsc.End.Before(header.Awsc.Start) {
+		t.Errorf("End %v is before start %v", header.Awsc.End, header.Awsc.Start)
+	}
+
+	want := []Event{
+		{Type: "o", Data: "$ "},
+		{Type: "i", Data: "whoami\r"},
+		{Type: "r", Data: "100x30"},
+		{Type: "o", Data: "ssm-user\r\n"},
+	}
+	if len(events) != len(want) {
+		t.Fatalf("Expected %d events, got %d", len(want), len(events))
+	}
+	for i, event := range events {
+		if event.Type != want[i].Type || event.Data != want[i].Data {
+			t.Errorf("Event %d: expected %+v, got %+v", i, want[i], event)
+		}
+	}
+}
+
+func TestRecorder_SplitUTF8(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session.cast")
+
+	rec, err := Create(path, 80, 24, Metadata{})
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	// "é" and "€" split across reads, and a truncated "€" still pending at the end
+	rec.Output([]byte("caf\xc3"))
+	rec.Output([]byte("\xa9 \xe2\x82"))
+	rec.Output([]byte("\xac"))
+	rec.Output([]byte("\xe2\x82"))
+	if err := rec.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer file.Close()
+
+	_, events, err := Read(file)
+	if err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+
+	want := []string{"caf", "é ", "€", "\ufffd\ufffd"}
+	if len(events) != len(want) {
+		t.Fatalf("Expected %d events, got %+v", len(want), events)
+	}
+	for i, event := range events {
+		if event.Data != want[i] {
+			t.Errorf("Event %d: expected %q, got %q", i, want[i], event.Data)
+		}
+	}
+}
+
+func TestCreate_DoesNotOverwrite(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session.cast")
+	if err := os.WriteFile(path, []byte("existing"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := Create(path, 80, 24, Metadata{}); err == nil {
+		t.Error("Expected an error for an existing file")
+	}
+}
+
+func TestRead_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr string
+	}{
+		{"empty", "", "empty recording"},
+		{"version 1", `{"version": 1}`, "unsupported asciicast version"},
+		{"bad event", "{\"version\": 2}\n[1.0, \"o\"]\n", "invalid event"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := Read(strings.NewReader(tt.input))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestPlay(t *testing.T) {
+	events := []Event{
+		{Time: 0.5, Type: "o", Data: "a"},
+		{Time: 0.7, Type: "i", Data: "typed"},
+		{Time: 1.5, Type: "o", Data: "b"},
+		{Time: 61.5, Type: "o", Data: "c"},
+	}
+
+	var slept []time.Duration
+	var out bytes.Buffer
+	err := Play(&out, events, 2, 5*time.Second, func(d time.Duration) { slept = append(slept, d) })
+	if err != nil {
+		t.Fatalf("Play failed: %v", err)
+	}
+
+	if out.String() != "abc" {
+		t.Errorf("Expected only output events, got %q", out.String())
+	}
+
+	want := []time.Duration{250 * time.Millisecond, 500 * time.Millisecond, 5 * time.Second}
+	if len(slept) != len(want) {
+		t.Fatalf("Expected sleeps %v, got %v", want, slept)
+	}
+	for i := range want {
+		if slept[i] != want[i] {
+			t.Errorf("Sleep %d: expected %v, got %v", i, want[i], slept[i])
+		}
+	}
+}
+
+func TestList(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	dir, err := Dir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		t.Fatal(err)
+	}
+
+	files := map[string]string{
+		"old.cast":    `{"version": 2, "width": 80, "height": 24, "timestamp": 1000}`,
+		"new.cast":    `{"version": 2, "width": 80, "height": 24, "timestamp": 2000}`,
+		"broken.cast": `not json`,
+		"notes.txt":   `{"version": 2, "timestamp": 3000}`,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content+"\n"), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	recordings, err := List()
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if len(recordings) != 2 {
+		t.Fatalf("Expected 2 recordings, got %d", len(recordings))
+	}
+	if filepath.Base(recordings[0].Path) != "new.cast" || filepath.Base(recordings[1].Path) != "old.cast" {
+		t.Errorf("Expected newest first, got %s, %s", recordings[0].Path, recordings[1].Path)
+	}
+}
+
+func TestNewPath(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	path, err := NewPath("i-1234567890abcdef0")
+	if err != nil {
+		t.Fatalf("NewPath failed: %v", err)
+	}
+
+	dir, _ := Dir()
+	if filepath.Dir(path) != dir {
+		t.Errorf("Expected path in %s, got %s", dir, path)
+	}
+	if !strings.HasSuffix(path, "-i-1234567890abcdef0.cast") {
+		t.Errorf("Unexpected file name %s", path)
+	}
+	if info, err := os.Stat(dir); err != nil || info.Mode().Perm() != 0700 {
+		t.Errorf("Expected %s to be created with mode 0700", dir)
+	}
+}
@@ -0,0 +1,72 @@
+//go:build !windows
+
+package recording
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+	"syscall"
+
+	"github.com/charmbracelet/x/term"
+	"github.com/creack/pty"
+)
+
+// Run runs cmd in a new pseudo-terminal relayed to this terminal, recording everything
+// typed and printed until it exits
+func Run(cmd *exec.Cmd, rec *Recorder) error {
+	width, height := TerminalSize()
+	ptmx, err := pty.StartWithSize(cmd, &pty.Winsize{Cols: uint16(width), Rows: uint16(height)})
+	if err != nil {
+		return fmt.Errorf("failed to start session in a pseudo-terminal: %w", err)
+	}
+	defer ptmx.Close()
+
+	// Keep the pseudo-terminal the same size as this one
+	resize := make(chan os.Signal, 1)
+	signal.Notify(resize, syscall.SIGWINCH)
+	defer func() {
+		signal.Stop(resize)
+		close(resize)
+	}()
+	go func() {
+		for range resize {
+			if err := pty.InheritSize(os.Stdin, ptmx); err == nil {
+				rec.Resize(TerminalSize())
+			}
+		}
+	}()
+
+	// The remote shell does its own echo and line editing
+	if term.IsTerminal(os.Stdin.Fd()) {
+		if state, err := term.MakeRaw(os.Stdin.Fd()); err == nil {
+			defer term.Restore(os.Stdin.Fd(), state)
+		}
+	}
+
+	go io.Copy(ptmx, io.TeeReader(os.Stdin, recordWriter(rec.Input)))
+
+	// Reading fails with EIO once the command exits and closes its side
+	io.Copy(io.MultiWriter(os.Stdout, recordWriter(rec.Output)), ptmx)
+
+	return cmd.Wait()
+}
+
+// TerminalSize returns the size of this terminal, or 80x24 when it isn't one
+func TerminalSize() (int, int) {
+	width, height, err := term.GetSize(os.Stdout.Fd())
+	if err != nil || width <= 0 || height <= 0 {
+		return 80, 24
+	}
+	return width, height
+}
+
+// recordWriter adapts a Recorder method to an io.Writer
+type recordWriter func([]byte)
+
+func (w recordWriter) Write(p []byte) (int, error) {
+	w(p)
+	return len(p), nil
+}
@@ -0,0 +1,16 @@
+package recording
+
+import (
+	"fmt"
+	"os/exec"
+)
+
+// Run is not supported on Windows, which has no pseudo-terminals to relay through
+func Run(cmd *exec.Cmd, rec *Recorder) error {
+	return fmt.Errorf("session recording is not supported on Windows")
+}
+
+// TerminalSize returns the default size, since recording is not supported on Windows
+func TerminalSize() (int, int) {
+	return 80, 24
+}
@@ -25,6 +25,18 @@ type SelectorModel struct {
 	title              string
 	done               bool
 	awsContext         *AWSContext
+	actions            []SelectorAction
+	action             string
+}
+
+// SelectorAction is a key that acts on the item under the cursor instead of selecting it.
+// Items the action is enabled for can be focused even when they aren't selectable. Use a
+// key that can't be typed, like ctrl+s, so the action also works while filtering; a
+// printable key only acts while the filter is empty.
+type SelectorAction struct {
+	Key     string
+	Label   string
+	Enabled []bool
 }
 
 type AWSContext struct {
@@ -83,14 +95,14 @@ func (m SelectorModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, tea.Quit
 		case "up", "k":
 			for i := m.cursor - 1; i >= 0; i-- {
-				if m.filteredSelectable[i] {
+				if m.focusable(i) {
 					m.cursor = i
 					break
 				}
 			}
 		case "down", "j":
 			for i := m.cursor + 1; i < len(m.filteredChoices); i++ {
-				if m.filteredSelectable[i] {
+				if m.focusable(i) {
 					m.cursor = i
 					break
 				}
@@ -112,8 +124,14 @@ func (m SelectorModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.updateFilter()
 			m.resetCursor()
 		default:
+			if action, ok := m.actionAt(msg.String(), m.cursor); ok && (m.filter == "" || !isFilterKey(msg.String())) {
+				m.selected = m.filterIndices[m.cursor]
+				m.action = action.Key
+				m.done = true
+				return m, tea.Quit
+			}
 			// Handle typing for filtering
-			if len(msg.String()) == 1 && msg.String() >= " " && msg.String() <= "~" {
+			if isFilterKey(msg.String()) {
 				m.filter += msg.String()
 				m.updateFilter()
 				m.resetCursor()
@@ -155,7 +173,9 @@ func (m SelectorModel) View() string {
 		s.WriteString("No matches found\n")
 	} else {
 		for i, choice := range m.filteredChoices {
-			if !m.filteredSelectable[i] {
+			if !m.filteredSelectable[i] && m.cursor == i && m.focusable(i) {
+				s.WriteString(fmt.Sprintf("▶ %s (disabled)\n", choice))
+			} else if !m.filteredSelectable[i] {
 				s.WriteString(fmt.Sprintf("  %s (disabled)\n", choice))
 			} else if m.cursor == i {
 				boldStyle := lipgloss.NewStyle().Bold(true)
@@ -167,9 +187,53 @@ func (m SelectorModel) View() string {
 	}
 
 	s.WriteString("\nPress ↑/↓ to navigate, Enter to select, type to filter, ESC to clear filter, q to quit\n")
+	for _, action := range m.actions {
+		s.WriteString(fmt.Sprintf("Press %s to %s\n", action.Key, action.Label))
+	}
 	return s.String()
 }
 
+// isFilterKey reports whether key is typed into the filter
+func isFilterKey(key string) bool {
+	return len(key) == 1 && key >= " " && key <= "~"
+}
+
+// focusable reports whether the cursor may rest on filtered item i
+func (m SelectorModel) focusable(i int) bool {
+	if m.filteredSelectable[i] {
+		return true
+	}
+	for _, action := range m.actions {
+		if action.Enabled[m.filterIndices[i]] {
+			return true
+		}
+	}
+	return false
+}
+
+// actionFor returns the first action enabled for item i
+func (m SelectorModel) actionFor(i int) SelectorAction {
+	for _, action := range m.actions {
+		if action.Enabled[i] {
+			return action
+		}
+	}
+	return SelectorAction{}
+}
+
+// actionAt returns the action bound to key if it is enabled for filtered item i
+func (m SelectorModel) actionAt(key string, i int) (SelectorAction, bool) {
+	if i >= len(m.filterIndices) {
+		return SelectorAction{}, false
+	}
+	for _, action := range m.actions {
+		if action.Key == key && action.Enabled[m.filterIndices[i]] {
+			return action, true
+		}
+	}
+	return SelectorAction{}, false
+}
+
 func (m *SelectorModel) updateFilter() {
 	m.filteredChoices = nil
 	m.filteredSelectable = nil
@@ -191,7 +255,14 @@ func (m *SelectorModel) resetCursor() {
 	for i, sel := range m.filteredSelectable {
 		if sel {
 			m.cursor = i
-			break
+			return
+		}
+	}
+	// Otherwise the first item an action applies to
+	for i := range m.filteredChoices {
+		if m.focusable(i) {
+			m.cursor = i
+			return
 		}
 	}
 }
@@ -200,6 +271,12 @@ func (m SelectorModel) Selected() int {
 	return m.selected
 }
 
+// Action returns the key of the action chosen for the selected item, or "" if the item
+// was selected with Enter
+func (m SelectorModel) Action() string {
+	return m.action
+}
+
 func RunSelector(title string, choices []string) (int, error) {
 	// Try interactive mode first
 	model := NewSelector(title, choices)
@@ -236,6 +313,41 @@ func RunSelectorWithSelectability(title string, choices []string, selectable []b
 	return -1, fmt.Errorf("unexpected model type")
 }
 
+// RunSelectorWithActions is RunSelectorWithSelectability with action keys. It returns the
+// chosen index and the key of the action, or "" when the item was selected normally.
+// Actions aren't available in the non-interactive fallback.
+func RunSelectorWithActions(title string, choices []string, selectable []bool, actions []SelectorAction) (int, string, error) {
+	model := NewSelectorWithSelectability(title, choices, selectable)
+	model.actions = actions
+	model.resetCursor()
+	p := tea.NewProgram(model)
+
+	finalModel, err := p.Run()
+	if err != nil {
+		// Without key bindings, items an action applies to are offered with the action
+		offered := make([]bool, len(choices))
+		labels := make([]string, len(choices))
+		for i := range choices {
+			offered[i] = model.focusable(i)
+			labels[i] = choices[i]
+			if !selectable[i] && offered[i] {
+				labels[i] += " (" + model.actionFor(i).Label + ")"
+			}
+		}
+		index, err := runSimpleSelectorWithSelectability(title, labels, offered)
+		if err != nil || index == -1 || selectable[index] {
+			return index, "", err
+		}
+		return index, model.actionFor(index).Key, nil
+	}
+
+	if m, ok := finalModel.(SelectorModel); ok {
+		return m.Selected(), m.Action(), nil
+	}
+
+	return -1, "", fmt.Errorf("unexpected model type")
+}
+
 func runSimpleSelector(title string, choices []string) (int, error) {
 	fmt.Println(title)
 	for i, choice := range choices {
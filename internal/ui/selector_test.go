@@ -4,8 +4,10 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
+	tea "github.com/charmbracelet/bubbletea"
 	"github.com/spf13/viper"
 )
 
@@ -175,6 +177,95 @@ func TestSelectorModel_FilteringWithSelectability(t *testing.T) {
 	}
 }
 
+func TestSelectorModel_Actions(t *testing.T) {
+	choices := []string{"running", "stopped", "terminated"}
+	model := NewSelectorWithSelectability("Test", choices, []bool{true, false, false})
+	model.actions = []SelectorAction{{Key: "ctrl+s", Label: "start", Enabled: []bool{false, true, false}}}
+
+	key := func(m SelectorModel, k string) SelectorModel {
+		var msg tea.KeyMsg
+		switch k {
+		case "down":
+			msg = tea.KeyMsg{Type: tea.KeyDown}
+		case "ctrl+s":
+			msg = tea.KeyMsg{Type: tea.KeyCtrlS}
+		default:
+			msg = tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)}
+		}
+		updated, _ := m.Update(msg)
+		return updated.(SelectorModel)
+	}
+
+	// The action does nothing when the item under the cursor doesn't support it
+	ignored := key(model, "ctrl+s")
+	if ignored.done || ignored.filter != "" {
+		t.Errorf("Expected ctrl+s to be ignored on a running item, got filter %q done %v", ignored.filter, ignored.done)
+	}
+
+	// The cursor can rest on the unselectable item the action applies to, but not past it
+	moved := key(key(model, "down"), "down")
+	if moved.cursor != 1 {
+		t.Fatalf("Expected cursor on the stopped item, got %d", moved.cursor)
+	}
+	if !strings.Contains(moved.View(), "Press ctrl+s to start") {
+		t.Error("Expected the view to list the action key")
+	}
+
+	chosen := key(moved, "ctrl+s")
+	if !chosen.done || chosen.Selected() != 1 || chosen.Action() != "ctrl+s" {
+		t.Errorf("Expected action ctrl+s on item 1, got done %v selected %d action %q", chosen.done, chosen.Selected(), chosen.Action())
+	}
+
+	// The action still works after typing a filter, and s is typed into it
+	filtered := key(key(model, "s"), "t")
+	if filtered.filter != "st" || filtered.done {
+		t.Fatalf("Expected s and t to filter, got filter %q done %v", filtered.filter, filtered.done)
+	}
+	chosen = key(filtered, "ctrl+s")
+	if !chosen.done || chosen.Selected() != 1 || chosen.Action() != "ctrl+s" {
+		t.Errorf("Expected action ctrl+s on the filtered stopped item, got done %v selected %d action %q", chosen.done, chosen.Selected(), chosen.Action())
+	}
+}
+
+func TestSelectorModel_PrintableAction(t *testing.T) {
+	model := NewSelectorWithSelectability("Test", []string{"stopped web", "stopped db"}, []bool{false, false})
+	model.actions = []SelectorAction{
+		{Key: "s", Label: "start", Enabled: []bool{true, true}},
+		{Key: "ctrl+s", Label: "start while filtering", Enabled: []bool{true, true}},
+	}
+	model.resetCursor()
+
+	typeKey := func(m SelectorModel, k string) SelectorModel {
+		updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)})
+		return updated.(SelectorModel)
+	}
+
+	// With an empty filter, s acts on the item under the cursor
+	chosen := typeKey(model, "s")
+	if !chosen.done || chosen.Selected() != 0 || chosen.Action() != "s" {
+		t.Errorf("Expected action s on item 0, got done %v selected %d action %q", chosen.done, chosen.Selected(), chosen.Action())
+	}
+
+	// Once a filter is typed, s goes into the filter instead
+	filtered := typeKey(typeKey(model, "d"), "s")
+	if filtered.done || filtered.filter != "ds" {
+		t.Errorf("Expected s to be typed into the filter, got filter %q done %v", filtered.filter, filtered.done)
+	}
+}
+
+func TestSelectorModel_ActionsWithoutSelectableItems(t *testing.T) {
+	model := NewSelectorWithSelectability("Test", []string{"terminated", "stopped"}, []bool{false, false})
+	model.actions = []SelectorAction{{Key: "ctrl+s", Label: "start", Enabled: []bool{false, true}}}
+	model.resetCursor()
+
+	if model.cursor != 1 {
+		t.Fatalf("Expected cursor on the stopped item, got %d", model.cursor)
+	}
+	if model.actionFor(1).Key != "ctrl+s" || model.actionFor(0).Key != "" {
+		t.Error("Expected action ctrl+s for the stopped item only")
+	}
+}
+
 func TestSelectorModel_FilterIndices(t *testing.T) {
 	choices := []string{"First", "Second", "Third"}
 	model := NewSelector("Test", choices)